@@ -0,0 +1,313 @@
+package accounts
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/helper/keystore"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+// DefaultKeystoreDir is the keystore directory under the data directory
+const DefaultKeystoreDir = "keystore"
+
+var (
+	ErrAccountNotFound = errors.New("unknown account")
+	ErrAccountExists   = errors.New("account already exists")
+	ErrAccountLocked   = errors.New("account is locked")
+)
+
+// Manager manages the encrypted accounts of a keystore directory, and the
+// private keys of the unlocked ones
+type Manager struct {
+	dir     string
+	scryptN int
+	scryptP int
+
+	lock     sync.RWMutex
+	unlocked map[types.Address]*unlockedKey
+}
+
+// unlockedKey is the decrypted private key, which is locked again once the
+// timer fires
+type unlockedKey struct {
+	key   *ecdsa.PrivateKey
+	timer *time.Timer // nil if unlocked until the manager is closed
+}
+
+// NewManager returns the account manager of the keystore directory, the key
+// files are encrypted with the scrypt parameters
+func NewManager(dir string, scryptN, scryptP int) *Manager {
+	return &Manager{
+		dir:      dir,
+		scryptN:  scryptN,
+		scryptP:  scryptP,
+		unlocked: make(map[types.Address]*unlockedKey),
+	}
+}
+
+// Dir returns the keystore directory
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// Accounts returns the addresses of the key files, in ascending order
+func (m *Manager) Accounts() ([]types.Address, error) {
+	files, err := m.keyFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]types.Address, 0, len(files))
+	for addr := range files {
+		addresses = append(addresses, addr)
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i].Bytes(), addresses[j].Bytes()) < 0
+	})
+
+	return addresses, nil
+}
+
+// NewAccount generates a new private key, and stores it encrypted by the password
+func (m *Manager) NewAccount(password string) (types.Address, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	return m.ImportKey(key, password)
+}
+
+// ImportKey stores the private key encrypted by the password
+func (m *Manager) ImportKey(key *ecdsa.PrivateKey, password string) (types.Address, error) {
+	addr := crypto.PubKeyToAddress(&key.PublicKey)
+
+	files, err := m.keyFiles()
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	if _, ok := files[addr]; ok {
+		return types.ZeroAddress, ErrAccountExists
+	}
+
+	raw, err := crypto.MarshalPrivateKey(key)
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	keyJSON, err := keystore.EncryptKey(raw, addr, password, m.scryptN, m.scryptP)
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return types.ZeroAddress, err
+	}
+
+	if err := os.WriteFile(filepath.Join(m.dir, keyFileName(addr)), keyJSON, 0600); err != nil {
+		return types.ZeroAddress, err
+	}
+
+	return addr, nil
+}
+
+// ExportKey decrypts the private key of the account by the password
+func (m *Manager) ExportKey(addr types.Address, password string) (*ecdsa.PrivateKey, error) {
+	files, err := m.keyFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	path, ok := files[addr]
+	if !ok {
+		return nil, ErrAccountNotFound
+	}
+
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := crypto.ParsePrivateKey(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// the key file is not the one of the account
+	if crypto.PubKeyToAddress(&key.PublicKey) != addr {
+		return nil, fmt.Errorf("key file of %s holds a different key", addr)
+	}
+
+	return key, nil
+}
+
+// Unlock decrypts the private key of the account by the password, and keeps it
+// for the signing until the timeout. It is unlocked until Lock or Close if the
+// timeout is zero. Unlocking an unlocked account resets its timeout.
+func (m *Manager) Unlock(addr types.Address, password string, timeout time.Duration) error {
+	key, err := m.ExportKey(addr, password)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if prev, ok := m.unlocked[addr]; ok && prev.timer != nil {
+		prev.timer.Stop()
+	}
+
+	unlocked := &unlockedKey{key: key}
+
+	if timeout > 0 {
+		unlocked.timer = time.AfterFunc(timeout, func() {
+			m.expire(addr, unlocked)
+		})
+	}
+
+	m.unlocked[addr] = unlocked
+
+	return nil
+}
+
+// Lock removes the private key of the account from the memory
+func (m *Manager) Lock(addr types.Address) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if unlocked, ok := m.unlocked[addr]; ok {
+		if unlocked.timer != nil {
+			unlocked.timer.Stop()
+		}
+
+		delete(m.unlocked, addr)
+	}
+}
+
+// IsUnlocked returns whether the account is unlocked
+func (m *Manager) IsUnlocked(addr types.Address) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	_, ok := m.unlocked[addr]
+
+	return ok
+}
+
+// Close locks all the accounts
+func (m *Manager) Close() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for addr, unlocked := range m.unlocked {
+		if unlocked.timer != nil {
+			unlocked.timer.Stop()
+		}
+
+		delete(m.unlocked, addr)
+	}
+}
+
+// SignHash signs the hash by the unlocked account, the signature is in the
+// [R || S || V] format where V is 0 or 1
+func (m *Manager) SignHash(addr types.Address, hash []byte) ([]byte, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	unlocked, ok := m.unlocked[addr]
+	if !ok {
+		return nil, ErrAccountLocked
+	}
+
+	return crypto.Sign(unlocked.key, hash)
+}
+
+// SignTx signs the transaction by the unlocked account
+func (m *Manager) SignTx(
+	addr types.Address,
+	tx *types.Transaction,
+	signer crypto.TxSigner,
+) (*types.Transaction, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	unlocked, ok := m.unlocked[addr]
+	if !ok {
+		return nil, ErrAccountLocked
+	}
+
+	return signer.SignTx(tx, unlocked.key)
+}
+
+// expire locks the account if it is not unlocked again
+func (m *Manager) expire(addr types.Address, unlocked *unlockedKey) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.unlocked[addr] == unlocked {
+		delete(m.unlocked, addr)
+	}
+}
+
+// keyFiles returns the paths of the key files by the addresses, the other files
+// of the keystore directory are skipped
+func (m *Manager) keyFiles() (map[types.Address]string, error) {
+	files := make(map[types.Address]string)
+
+	entries, err := os.ReadDir(m.dir)
+	if os.IsNotExist(err) {
+		return files, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+
+		path := filepath.Join(m.dir, name)
+
+		keyJSON, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		addr, err := keystore.ReadAddress(keyJSON)
+		if err != nil {
+			continue
+		}
+
+		files[addr] = path
+	}
+
+	return files, nil
+}
+
+// keyFileName returns the key file name of the account, the same as the other
+// ethereum clients do
+func keyFileName(addr types.Address) string {
+	return fmt.Sprintf("UTC--%s--%s",
+		time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z"),
+		hex.EncodeToString(addr.Bytes()),
+	)
+}
@@ -0,0 +1,101 @@
+package accounts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/helper/keystore"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	return NewManager(filepath.Join(t.TempDir(), "keystore"), keystore.LightScryptN, keystore.LightScryptP)
+}
+
+func TestManager_Accounts(t *testing.T) {
+	m := newTestManager(t)
+
+	// the keystore directory is created on demand
+	accounts, err := m.Accounts()
+	assert.NoError(t, err)
+	assert.Empty(t, accounts)
+
+	addr, err := m.NewAccount("foo")
+	assert.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+
+	imported, err := m.ImportKey(key, "bar")
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubKeyToAddress(&key.PublicKey), imported)
+
+	_, err = m.ImportKey(key, "bar")
+	assert.ErrorIs(t, err, ErrAccountExists)
+
+	// the other files are skipped
+	assert.NoError(t, os.WriteFile(filepath.Join(m.Dir(), "README"), []byte("not a key"), 0600))
+
+	accounts, err = m.Accounts()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []types.Address{addr, imported}, accounts)
+
+	exported, err := m.ExportKey(imported, "bar")
+	assert.NoError(t, err)
+	assert.Equal(t, key.D, exported.D)
+
+	_, err = m.ExportKey(imported, "foo")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	_, err = m.ExportKey(types.StringToAddress("1"), "foo")
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestManager_Unlock(t *testing.T) {
+	m := newTestManager(t)
+	defer m.Close()
+
+	addr, err := m.NewAccount("foo")
+	assert.NoError(t, err)
+
+	hash := crypto.Keccak256([]byte("hello"))
+
+	_, err = m.SignHash(addr, hash)
+	assert.ErrorIs(t, err, ErrAccountLocked)
+
+	assert.ErrorIs(t, m.Unlock(addr, "bar", 0), keystore.ErrDecrypt)
+	assert.False(t, m.IsUnlocked(addr))
+
+	assert.NoError(t, m.Unlock(addr, "foo", 0))
+
+	sig, err := m.SignHash(addr, hash)
+	assert.NoError(t, err)
+
+	pub, err := crypto.SigToPub(hash, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, crypto.PubKeyToAddress(pub))
+
+	m.Lock(addr)
+	assert.False(t, m.IsUnlocked(addr))
+
+	// locked again once timeout
+	assert.NoError(t, m.Unlock(addr, "foo", 50*time.Millisecond))
+	assert.True(t, m.IsUnlocked(addr))
+
+	assert.Eventually(t, func() bool {
+		return !m.IsUnlocked(addr)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// unlocking again drops the timeout
+	assert.NoError(t, m.Unlock(addr, "foo", 50*time.Millisecond))
+	assert.NoError(t, m.Unlock(addr, "foo", 0))
+
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, m.IsUnlocked(addr))
+}
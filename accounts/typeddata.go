@@ -0,0 +1,410 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/helper/keccak"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+// typedDataDomain is the struct type of the domain separator
+const typedDataDomain = "EIP712Domain"
+
+var (
+	ErrTypedDataType  = errors.New("invalid typed data type")
+	ErrTypedDataValue = errors.New("invalid typed data value")
+)
+
+var (
+	tt256 = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// TypedDataField is a field of a struct type of the typed data
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is the typed structured data of eip-712
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// ParseTypedData decodes the typed data, which might be encoded as a json string
+// as well. The numbers are kept in their decimal strings to avoid the loss of
+// the precision.
+func ParseTypedData(raw []byte) (*TypedData, error) {
+	raw = bytes.TrimSpace(raw)
+
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+
+		raw = []byte(s)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	data := &TypedData{}
+	if err := decoder.Decode(data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Hash returns the hash of the typed data to be signed, which is
+// keccak256("\x19\x01" || domainSeparator || hashStruct(message))
+func (t *TypedData) Hash() ([]byte, error) {
+	if _, ok := t.Types[typedDataDomain]; !ok {
+		return nil, fmt.Errorf("%w: %s is not defined", ErrTypedDataType, typedDataDomain)
+	}
+
+	domainSeparator, err := t.HashStruct(typedDataDomain, t.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	data := append([]byte{0x19, 0x01}, domainSeparator...)
+
+	// the message is omitted if the domain is the primary type
+	if t.PrimaryType != typedDataDomain {
+		messageHash, err := t.HashStruct(t.PrimaryType, t.Message)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, messageHash...)
+	}
+
+	return keccak.Keccak256(nil, data), nil
+}
+
+// HashStruct returns the hash of the struct value of the type
+func (t *TypedData) HashStruct(typ string, value map[string]interface{}) ([]byte, error) {
+	enc, err := t.encodeData(typ, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak.Keccak256(nil, enc), nil
+}
+
+// EncodeType returns the type encoding of the struct type, which is followed by
+// the referenced struct types in the alphabetical order
+func (t *TypedData) EncodeType(typ string) (string, error) {
+	if _, ok := t.Types[typ]; !ok {
+		return "", fmt.Errorf("%w: %s is not defined", ErrTypedDataType, typ)
+	}
+
+	deps := make(map[string]bool)
+	t.dependencies(typ, deps)
+	delete(deps, typ)
+
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var b strings.Builder
+
+	for _, name := range append([]string{typ}, names...) {
+		b.WriteString(name)
+		b.WriteString("(")
+
+		for i, field := range t.Types[name] {
+			if i > 0 {
+				b.WriteString(",")
+			}
+
+			b.WriteString(field.Type)
+			b.WriteString(" ")
+			b.WriteString(field.Name)
+		}
+
+		b.WriteString(")")
+	}
+
+	return b.String(), nil
+}
+
+// dependencies collects the struct types referenced by the type
+func (t *TypedData) dependencies(typ string, found map[string]bool) {
+	typ = baseType(typ)
+
+	fields, ok := t.Types[typ]
+	if !ok || found[typ] {
+		return
+	}
+
+	found[typ] = true
+
+	for _, field := range fields {
+		t.dependencies(field.Type, found)
+	}
+}
+
+// encodeData returns typeHash || encodeData(field)... of the struct value
+func (t *TypedData) encodeData(typ string, value map[string]interface{}) ([]byte, error) {
+	encType, err := t.EncodeType(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := t.Types[typ]
+
+	buf := make([]byte, 0, 32*(len(fields)+1))
+	buf = append(buf, keccak.Keccak256(nil, []byte(encType))...)
+
+	for _, field := range fields {
+		v, ok := value[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s.%s is missing", ErrTypedDataValue, typ, field.Name)
+		}
+
+		enc, err := t.encodeValue(field.Type, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typ, field.Name, err)
+		}
+
+		buf = append(buf, enc...)
+	}
+
+	return buf, nil
+}
+
+// encodeValue returns the 32 bytes encoding of the value, the dynamic values
+// and the structs are hashed
+func (t *TypedData) encodeValue(typ string, v interface{}) ([]byte, error) {
+	// arrays
+	if strings.HasSuffix(typ, "]") {
+		idx := strings.LastIndex(typ, "[")
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataType, typ)
+		}
+
+		items, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is not an array", ErrTypedDataValue, v)
+		}
+
+		if size := typ[idx+1 : len(typ)-1]; size != "" {
+			n, err := strconv.Atoi(size)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrTypedDataType, typ)
+			}
+
+			if n != len(items) {
+				return nil, fmt.Errorf("%w: %d items of %s", ErrTypedDataValue, len(items), typ)
+			}
+		}
+
+		buf := make([]byte, 0, 32*len(items))
+
+		for _, item := range items {
+			enc, err := t.encodeValue(typ[:idx], item)
+			if err != nil {
+				return nil, err
+			}
+
+			buf = append(buf, enc...)
+		}
+
+		return keccak.Keccak256(nil, buf), nil
+	}
+
+	// structs
+	if _, ok := t.Types[typ]; ok {
+		value, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is not a %s", ErrTypedDataValue, v, typ)
+		}
+
+		return t.HashStruct(typ, value)
+	}
+
+	return encodeAtomicValue(typ, v)
+}
+
+// encodeAtomicValue returns the 32 bytes encoding of the atomic value
+func encodeAtomicValue(typ string, v interface{}) ([]byte, error) {
+	switch {
+	case typ == "string":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is not a string", ErrTypedDataValue, v)
+		}
+
+		return keccak.Keccak256(nil, []byte(s)), nil
+	case typ == "bytes":
+		b, err := parseBytes(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return keccak.Keccak256(nil, b), nil
+	case typ == "bool":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is not a bool", ErrTypedDataValue, v)
+		}
+
+		buf := make([]byte, 32)
+		if b {
+			buf[31] = 1
+		}
+
+		return buf, nil
+	case typ == "address":
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %v is not an address", ErrTypedDataValue, v)
+		}
+
+		var addr types.Address
+		if err := addr.UnmarshalText([]byte(s)); err != nil {
+			return nil, fmt.Errorf("%w: %s is not an address", ErrTypedDataValue, s)
+		}
+
+		return leftPad(addr.Bytes()), nil
+	case strings.HasPrefix(typ, "bytes"):
+		n, err := strconv.Atoi(strings.TrimPrefix(typ, "bytes"))
+		if err != nil || n < 1 || n > 32 {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataType, typ)
+		}
+
+		b, err := parseBytes(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(b) != n {
+			return nil, fmt.Errorf("%w: %d bytes of %s", ErrTypedDataValue, len(b), typ)
+		}
+
+		buf := make([]byte, 32)
+		copy(buf, b)
+
+		return buf, nil
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		return encodeInteger(typ, v)
+	}
+
+	return nil, fmt.Errorf("%w: %s is not defined", ErrTypedDataType, typ)
+}
+
+// encodeInteger returns the two's complement of the integer in 32 bytes
+func encodeInteger(typ string, v interface{}) ([]byte, error) {
+	signed := strings.HasPrefix(typ, "int")
+
+	bits := 256
+
+	if size := strings.TrimPrefix(strings.TrimPrefix(typ, "u"), "int"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < 8 || n > 256 || n%8 != 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataType, typ)
+		}
+
+		bits = n
+	}
+
+	value, err := parseInteger(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// the range of the type
+	max := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	min := big.NewInt(0)
+
+	if signed {
+		max.Rsh(max, 1)
+		min.Neg(max)
+	}
+
+	if value.Cmp(min) < 0 || value.Cmp(max) >= 0 {
+		return nil, fmt.Errorf("%w: %s overflows %s", ErrTypedDataValue, value, typ)
+	}
+
+	if value.Sign() < 0 {
+		value = new(big.Int).Add(value, tt256)
+	}
+
+	return leftPad(value.Bytes()), nil
+}
+
+// parseInteger parses the decimal or the hex integer
+func parseInteger(v interface{}) (*big.Int, error) {
+	var s string
+
+	switch value := v.(type) {
+	case json.Number:
+		s = value.String()
+	case string:
+		s = value
+	case float64:
+		s = strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("%w: %v is not an integer", ErrTypedDataValue, v)
+	}
+
+	n, ok := new(big.Int), false
+
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		n, ok = n.SetString(s[2:], 16)
+	} else {
+		n, ok = n.SetString(s, 10)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not an integer", ErrTypedDataValue, s)
+	}
+
+	return n, nil
+}
+
+func parseBytes(v interface{}) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v is not a hex string", ErrTypedDataValue, v)
+	}
+
+	b, err := hex.DecodeHex(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not a hex string", ErrTypedDataValue, s)
+	}
+
+	return b, nil
+}
+
+// baseType strips the array suffixes of the type
+func baseType(typ string) string {
+	if idx := strings.Index(typ, "["); idx >= 0 {
+		return typ[:idx]
+	}
+
+	return typ
+}
+
+func leftPad(b []byte) []byte {
+	buf := make([]byte, 32)
+	copy(buf[32-len(b):], b)
+
+	return buf
+}
@@ -0,0 +1,150 @@
+package accounts
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// the example of eip-712
+const testMailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedData_Hash(t *testing.T) {
+	data, err := ParseTypedData([]byte(testMailTypedData))
+	assert.NoError(t, err)
+
+	encType, err := data.EncodeType("Mail")
+	assert.NoError(t, err)
+	assert.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", encType)
+
+	domainSeparator, err := data.HashStruct(typedDataDomain, data.Domain)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+		hex.EncodeToString(domainSeparator),
+	)
+
+	messageHash, err := data.HashStruct(data.PrimaryType, data.Message)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e",
+		hex.EncodeToString(messageHash),
+	)
+
+	hash, err := data.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2",
+		hex.EncodeToString(hash),
+	)
+
+	// signed by the key of keccak256("cow")
+	key, err := crypto.ParsePrivateKey(crypto.Keccak256([]byte("cow")))
+	assert.NoError(t, err)
+
+	sig, err := crypto.Sign(key, hash)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d"+
+			"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562"+"01",
+		hex.EncodeToString(sig),
+	)
+}
+
+func TestTypedData_JSONString(t *testing.T) {
+	quoted, err := ParseTypedData([]byte(`"{\"types\":{\"EIP712Domain\":[]},\"primaryType\":\"EIP712Domain\"}"`))
+	assert.NoError(t, err)
+	assert.Equal(t, typedDataDomain, quoted.PrimaryType)
+
+	_, err = quoted.Hash()
+	assert.NoError(t, err)
+}
+
+func TestTypedData_Values(t *testing.T) {
+	data := &TypedData{
+		Types: map[string][]TypedDataField{
+			"EIP712Domain": {},
+			"Values": {
+				{Name: "a", Type: "int8"},
+				{Name: "b", Type: "uint16[2]"},
+				{Name: "c", Type: "bytes4"},
+				{Name: "d", Type: "bool"},
+				{Name: "e", Type: "bytes"},
+			},
+		},
+		PrimaryType: "Values",
+		Domain:      map[string]interface{}{},
+	}
+
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"a": "-128",
+			"b": []interface{}{"0xffff", float64(1)},
+			"c": "0x01020304",
+			"d": true,
+			"e": "0x",
+		}
+	}
+
+	data.Message = valid()
+	_, err := data.Hash()
+	assert.NoError(t, err)
+
+	for field, value := range map[string]interface{}{
+		"a": "128",
+		"b": []interface{}{"0x10000", "1"},
+		"c": "0x010203",
+		"d": "true",
+		"e": "0xzz",
+	} {
+		data.Message = valid()
+		data.Message[field] = value
+
+		_, err := data.Hash()
+		assert.ErrorIs(t, err, ErrTypedDataValue, field)
+	}
+
+	data.Message = valid()
+	delete(data.Message, "a")
+
+	_, err = data.Hash()
+	assert.ErrorIs(t, err, ErrTypedDataValue)
+
+	data.Types["Values"][0].Type = "int7"
+	data.Message = valid()
+
+	_, err = data.Hash()
+	assert.ErrorIs(t, err, ErrTypedDataType)
+}
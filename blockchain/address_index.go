@@ -0,0 +1,235 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dogechain-lab/dogechain/blockchain/addrindex"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// addressIndexBatchBlocks is the number of the blocks indexed between the checks
+	// of the closing, so that the history is indexed gradually when the index is
+	// enabled on an existing chain
+	addressIndexBatchBlocks = 128
+)
+
+var (
+	ErrAddressIndexDisabled = errors.New("address index disabled")
+)
+
+// SetAddressIndex enables searching the transactions of the addresses, the index
+// is built by the AddressIndexer
+func (b *Blockchain) SetAddressIndex(index AddressIndex) {
+	b.addressIndex = index
+}
+
+// SearchAddressTransactions returns a page of the transactions touching the address
+// from the given block on, in the ascending order, or the descending order if
+// backward. It also reports whether there are more transactions after the page.
+func (b *Blockchain) SearchAddressTransactions(
+	addr types.Address,
+	from uint64,
+	backward bool,
+	pageSize int,
+) ([]addrindex.TxRef, bool, error) {
+	if b.addressIndex == nil {
+		return nil, false, ErrAddressIndexDisabled
+	}
+
+	return b.addressIndex.Search(addr, from, backward, pageSize)
+}
+
+// AddressIndexer builds the address index of the canonical chain in background.
+// It keeps up with the new heads through the blockchain events, and unwinds the
+// blocks reorged out of the canonical chain, so that the block import never waits
+// for the index.
+type AddressIndexer struct {
+	logger     hclog.Logger
+	blockchain *Blockchain
+	index      AddressIndex
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewAddressIndexer creates the address indexer of the blockchain
+func NewAddressIndexer(logger hclog.Logger, b *Blockchain, index AddressIndex) *AddressIndexer {
+	return &AddressIndexer{
+		logger:     logger.Named("address_indexer"),
+		blockchain: b,
+		index:      index,
+		closeCh:    make(chan struct{}),
+	}
+}
+
+// Start starts indexing in background
+func (i *AddressIndexer) Start() error {
+	sub := i.blockchain.SubscribeEvents()
+	if sub == nil {
+		return ErrClosed
+	}
+
+	i.wg.Add(1)
+
+	go i.run(sub)
+
+	return nil
+}
+
+// Close stops the indexer
+func (i *AddressIndexer) Close() {
+	close(i.closeCh)
+	i.wg.Wait()
+}
+
+func (i *AddressIndexer) run(sub Subscription) {
+	defer i.wg.Done()
+	defer sub.Unsubscribe()
+
+	i.update()
+
+	for {
+		select {
+		case <-i.closeCh:
+			return
+		case _, ok := <-sub.GetEvent():
+			if !ok {
+				return
+			}
+
+			// the reorgs are found by the canonical hashes, since the events
+			// might be dropped when the indexer is busy
+			i.update()
+		}
+	}
+}
+
+// update indexes the canonical blocks up to the chain head by batches
+func (i *AddressIndexer) update() {
+	for {
+		select {
+		case <-i.closeCh:
+			return
+		default:
+		}
+
+		done, err := i.updateBatch()
+		if err != nil {
+			i.logger.Error("failed to update address index", "err", err)
+
+			return
+		}
+
+		if done {
+			return
+		}
+	}
+}
+
+// updateBatch unwinds the indexed blocks which are no longer canonical, then
+// indexes a batch of the canonical blocks. It returns whether the index reaches
+// the chain head.
+func (i *AddressIndexer) updateBatch() (bool, error) {
+	head := i.blockchain.Header().Number
+
+	for {
+		indexed, ok := i.index.Head()
+		if !ok {
+			break
+		}
+
+		hash, _ := i.index.BlockHash(indexed)
+		canonical, canonicalOk := i.blockchain.db.ReadCanonicalHash(indexed)
+
+		if indexed <= head && canonicalOk && hash == canonical {
+			break
+		}
+
+		if err := i.index.UnwindBlock(indexed); err != nil {
+			return false, err
+		}
+	}
+
+	next := uint64(0)
+	if indexed, ok := i.index.Head(); ok {
+		next = indexed + 1
+	}
+
+	for n := 0; n < addressIndexBatchBlocks && next <= head; n, next = n+1, next+1 {
+		if err := i.indexBlock(next); err != nil {
+			return false, err
+		}
+	}
+
+	return next > head, nil
+}
+
+// indexBlock indexes the addresses touched by the canonical block
+func (i *AddressIndexer) indexBlock(number uint64) error {
+	b := i.blockchain
+
+	hash, ok := b.db.ReadCanonicalHash(number)
+	if !ok {
+		return fmt.Errorf("canonical hash of block %d not found", number)
+	}
+
+	// nothing executed in the genesis
+	if number == 0 {
+		return i.index.IndexBlock(number, hash, nil)
+	}
+
+	body, ok := b.readBody(hash)
+	if !ok {
+		return fmt.Errorf("body of block %d not found", number)
+	}
+
+	receipts, err := b.db.ReadReceipts(hash)
+	if err != nil {
+		return err
+	}
+
+	if len(receipts) != len(body.Transactions) {
+		return fmt.Errorf("receipts of block %d mismatch", number)
+	}
+
+	return i.index.IndexBlock(number, hash, blockAddressEntries(body.Transactions, receipts))
+}
+
+// blockAddressEntries returns the addresses touched by the transactions, which are
+// the senders, the recipients, the created contracts and the log emitters
+func blockAddressEntries(txs []*types.Transaction, receipts []*types.Receipt) []addrindex.Entry {
+	entries := make([]addrindex.Entry, 0, 2*len(txs))
+
+	for i, tx := range txs {
+		seen := make(map[types.Address]struct{})
+
+		add := func(addr types.Address) {
+			if _, ok := seen[addr]; ok {
+				return
+			}
+
+			seen[addr] = struct{}{}
+			entries = append(entries, addrindex.Entry{Address: addr, TxIndex: uint64(i)})
+		}
+
+		add(tx.From)
+
+		if tx.To != nil {
+			add(*tx.To)
+		}
+
+		if receipts[i].ContractAddress != nil {
+			add(*receipts[i].ContractAddress)
+		}
+
+		for _, log := range receipts[i].Logs {
+			add(log.Address)
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,213 @@
+package blockchain
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/blockchain/addrindex"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockIndexedBlock struct {
+	hash    types.Hash
+	entries []addrindex.Entry
+}
+
+type mockAddressIndex struct {
+	sync.Mutex
+
+	blocks []*mockIndexedBlock
+}
+
+func (m *mockAddressIndex) len() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return len(m.blocks)
+}
+
+func (m *mockAddressIndex) Head() (uint64, bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.head()
+}
+
+func (m *mockAddressIndex) head() (uint64, bool) {
+	if len(m.blocks) == 0 {
+		return 0, false
+	}
+
+	return uint64(len(m.blocks) - 1), true
+}
+
+func (m *mockAddressIndex) BlockHash(number uint64) (types.Hash, bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	if number >= uint64(len(m.blocks)) {
+		return types.ZeroHash, false
+	}
+
+	return m.blocks[number].hash, true
+}
+
+func (m *mockAddressIndex) IndexBlock(number uint64, hash types.Hash, entries []addrindex.Entry) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if number != uint64(len(m.blocks)) {
+		return addrindex.ErrInvalidBlockData
+	}
+
+	m.blocks = append(m.blocks, &mockIndexedBlock{hash, entries})
+
+	return nil
+}
+
+func (m *mockAddressIndex) UnwindBlock(number uint64) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if head, ok := m.head(); !ok || head != number {
+		return addrindex.ErrNotHead
+	}
+
+	m.blocks = m.blocks[:number]
+
+	return nil
+}
+
+func (m *mockAddressIndex) Search(types.Address, uint64, bool, int) ([]addrindex.TxRef, bool, error) {
+	return nil, false, nil
+}
+
+func TestBlockchain_BlockAddressEntries(t *testing.T) {
+	sender := types.StringToAddress("0x1")
+	receiver := types.StringToAddress("0x2")
+	created := types.StringToAddress("0x3")
+	emitter := types.StringToAddress("0x4")
+
+	txs := []*types.Transaction{
+		{From: sender, To: &receiver},
+		{From: sender},
+		{From: sender, To: &sender},
+	}
+	receipts := []*types.Receipt{
+		{Logs: []*types.Log{{Address: emitter}, {Address: receiver}, {Address: emitter}}},
+		{ContractAddress: &created},
+		{},
+	}
+
+	assert.Equal(t, []addrindex.Entry{
+		{Address: sender, TxIndex: 0},
+		{Address: receiver, TxIndex: 0},
+		{Address: emitter, TxIndex: 0},
+		{Address: sender, TxIndex: 1},
+		{Address: created, TxIndex: 1},
+		{Address: sender, TxIndex: 2},
+	}, blockAddressEntries(txs, receipts))
+}
+
+func TestAddressIndexer_Update(t *testing.T) {
+	headers := NewTestHeaders(4)
+	b := NewTestBlockchain(t, headers)
+
+	writeBodies := func(t *testing.T, headers []*types.Header, from types.Address) {
+		t.Helper()
+
+		for _, header := range headers {
+			tx := &types.Transaction{
+				Nonce:    header.Number,
+				From:     from,
+				GasPrice: big.NewInt(0),
+				Value:    big.NewInt(0),
+			}
+
+			assert.NoError(t, b.db.WriteBody(header.Hash, &types.Body{Transactions: []*types.Transaction{tx}}))
+			assert.NoError(t, b.db.WriteReceipts(header.Hash, []*types.Receipt{{}}))
+		}
+	}
+
+	writeBodies(t, headers[1:], types.StringToAddress("0x1"))
+
+	// the index is disabled by default
+	_, _, err := b.SearchAddressTransactions(types.StringToAddress("0x1"), 0, false, 1)
+	assert.ErrorIs(t, err, ErrAddressIndexDisabled)
+
+	index := &mockAddressIndex{}
+	b.SetAddressIndex(index)
+
+	indexer := NewAddressIndexer(hclog.NewNullLogger(), b, index)
+	indexer.update()
+
+	if assert.Len(t, index.blocks, 4) {
+		assert.Empty(t, index.blocks[0].entries)
+
+		for i, header := range headers {
+			assert.Equal(t, header.Hash, index.blocks[i].hash)
+		}
+
+		assert.Equal(t, []addrindex.Entry{{Address: types.StringToAddress("0x1")}}, index.blocks[3].entries)
+	}
+
+	// reorg from the block 2 on
+	forked := AppendNewTestheadersWithSeed(headers[:2], 3, 10)
+	writeBodies(t, forked[2:], types.StringToAddress("0x2"))
+
+	assert.NoError(t, b.WriteHeaders(forked[2:]))
+	assert.Equal(t, forked[4].Hash, b.Header().Hash)
+
+	indexer.update()
+
+	if assert.Len(t, index.blocks, 5) {
+		for i, header := range forked {
+			assert.Equal(t, header.Hash, index.blocks[i].hash)
+		}
+
+		assert.Equal(t, []addrindex.Entry{{Address: types.StringToAddress("0x1")}}, index.blocks[1].entries)
+		assert.Equal(t, []addrindex.Entry{{Address: types.StringToAddress("0x2")}}, index.blocks[2].entries)
+	}
+}
+
+func TestAddressIndexer_Start(t *testing.T) {
+	headers := NewTestHeaders(2*addressIndexBatchBlocks + 1)
+	b := NewTestBlockchain(t, headers)
+
+	defer b.Close()
+
+	writeBodies := func(headers []*types.Header) {
+		for _, header := range headers {
+			assert.NoError(t, b.db.WriteBody(header.Hash, &types.Body{}))
+			assert.NoError(t, b.db.WriteReceipts(header.Hash, []*types.Receipt{}))
+		}
+	}
+
+	writeBodies(headers[1:])
+
+	index := &mockAddressIndex{}
+
+	indexer := NewAddressIndexer(hclog.NewNullLogger(), b, index)
+	assert.NoError(t, indexer.Start())
+
+	defer indexer.Close()
+
+	// the history is indexed by batches
+	assert.Eventually(t, func() bool {
+		return index.len() == len(headers)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the new heads are indexed in background
+	headers = AppendNewTestHeaders(headers, 2)
+	writeBodies(headers[len(headers)-2:])
+
+	assert.NoError(t, b.WriteHeaders(headers[len(headers)-2:]))
+
+	assert.Eventually(t, func() bool {
+		return index.len() == len(headers)
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,214 @@
+package addrindex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/dogechain-lab/dogechain/helper/kvdb"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	ErrNotHead          = errors.New("block is not the head of the index")
+	ErrInvalidBlockData = errors.New("invalid indexed block data")
+)
+
+// Prefixes for the key-value store
+var (
+	// entryPrefix is the prefix of the entries, keyed by the address, the block
+	// number and the transaction index, so that the transactions of an address are
+	// iterated in the chain order
+	entryPrefix = []byte("a")
+
+	// blockPrefix is the prefix of the indexed blocks, keyed by the block number. The
+	// value is the block hash followed by the entries of the block, which are needed
+	// to unwind it.
+	blockPrefix = []byte("n")
+
+	// headKey is the key of the number of the last indexed block
+	headKey = []byte("h")
+)
+
+const (
+	// the length of an entry in the block value, address + transaction index
+	blockEntryLength = types.AddressLength + 4
+)
+
+// Entry is an address touched by a transaction of a block
+type Entry struct {
+	Address types.Address
+	TxIndex uint64
+}
+
+// TxRef locates an indexed transaction
+type TxRef struct {
+	BlockNumber uint64
+	TxIndex     uint64
+}
+
+// Store persists the address to transaction index of the canonical chain in a
+// dedicated kv database. The blocks are indexed in order, and unwound in the
+// reverse order on reorgs.
+type Store struct {
+	db kvdb.KVBatchStorage
+}
+
+// NewStore creates the index on top of the kv database
+func NewStore(db kvdb.KVBatchStorage) *Store {
+	return &Store{db: db}
+}
+
+func encodeUint(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+
+	return b
+}
+
+func entryKey(addr types.Address, number, txIndex uint64) []byte {
+	key := make([]byte, 0, len(entryPrefix)+types.AddressLength+12)
+	key = append(key, entryPrefix...)
+	key = append(key, addr.Bytes()...)
+	key = append(key, encodeUint(number)...)
+
+	return binary.BigEndian.AppendUint32(key, uint32(txIndex))
+}
+
+func blockKey(number uint64) []byte {
+	return bytes.Join([][]byte{blockPrefix, encodeUint(number)}, nil)
+}
+
+// Head returns the number of the last indexed block
+func (s *Store) Head() (uint64, bool) {
+	data, ok, err := s.db.Get(headKey)
+	if err != nil || !ok || len(data) != 8 {
+		return 0, false
+	}
+
+	return binary.BigEndian.Uint64(data), true
+}
+
+// BlockHash returns the hash of the indexed block
+func (s *Store) BlockHash(number uint64) (types.Hash, bool) {
+	data, ok, err := s.db.Get(blockKey(number))
+	if err != nil || !ok || len(data) < types.HashLength {
+		return types.ZeroHash, false
+	}
+
+	return types.BytesToHash(data[:types.HashLength]), true
+}
+
+// IndexBlock writes the entries of the block, which becomes the new head
+func (s *Store) IndexBlock(number uint64, hash types.Hash, entries []Entry) error {
+	batch := s.db.Batch()
+	value := make([]byte, 0, types.HashLength+len(entries)*blockEntryLength)
+	value = append(value, hash.Bytes()...)
+
+	for _, entry := range entries {
+		batch.Set(entryKey(entry.Address, number, entry.TxIndex), []byte{})
+
+		value = append(value, entry.Address.Bytes()...)
+		value = binary.BigEndian.AppendUint32(value, uint32(entry.TxIndex))
+	}
+
+	batch.Set(blockKey(number), value)
+	batch.Set(headKey, encodeUint(number))
+
+	return batch.Write()
+}
+
+// UnwindBlock removes the entries of the head block, its parent becomes the new head
+func (s *Store) UnwindBlock(number uint64) error {
+	if head, ok := s.Head(); !ok || head != number {
+		return ErrNotHead
+	}
+
+	value, ok, err := s.db.Get(blockKey(number))
+	if err != nil {
+		return err
+	}
+
+	if !ok || len(value) < types.HashLength || (len(value)-types.HashLength)%blockEntryLength != 0 {
+		return ErrInvalidBlockData
+	}
+
+	batch := s.db.Batch()
+
+	for data := value[types.HashLength:]; len(data) > 0; data = data[blockEntryLength:] {
+		addr := types.BytesToAddress(data[:types.AddressLength])
+		txIndex := binary.BigEndian.Uint32(data[types.AddressLength:blockEntryLength])
+
+		batch.Delete(entryKey(addr, number, uint64(txIndex)))
+	}
+
+	batch.Delete(blockKey(number))
+
+	if number == 0 {
+		batch.Delete(headKey)
+	} else {
+		batch.Set(headKey, encodeUint(number-1))
+	}
+
+	return batch.Write()
+}
+
+// Search returns the transactions of the address from the given block on, in the
+// ascending order, or the descending order if backward. A page holds at least
+// pageSize transactions unless exhausted, and never splits the transactions of a
+// block. It also reports whether there are more transactions after the page.
+func (s *Store) Search(addr types.Address, from uint64, backward bool, pageSize int) ([]TxRef, bool, error) {
+	prefix := bytes.Join([][]byte{entryPrefix, addr.Bytes()}, nil)
+	rng := &kvdb.KVIteratorRange{}
+
+	if backward {
+		// all the entries up to the block, inclusive
+		rng.Start = prefix
+		rng.Limit = bytes.Join([][]byte{prefix, encodeUint(from), bytes.Repeat([]byte{0xff}, 5)}, nil)
+	} else {
+		// all the entries from the block on, the limit is above any number and index
+		rng.Start = bytes.Join([][]byte{prefix, encodeUint(from)}, nil)
+		rng.Limit = bytes.Join([][]byte{prefix, bytes.Repeat([]byte{0xff}, 13)}, nil)
+	}
+
+	it := s.db.Iterator(rng)
+	defer it.Release()
+
+	var (
+		ok   bool
+		move func() bool
+	)
+
+	if backward {
+		ok, move = it.Last(), it.Prev
+	} else {
+		ok, move = it.First(), it.Next
+	}
+
+	refs := make([]TxRef, 0, pageSize)
+
+	for ; ok; ok = move() {
+		key := it.Key()
+		if len(key) != len(prefix)+12 {
+			continue
+		}
+
+		ref := TxRef{
+			BlockNumber: binary.BigEndian.Uint64(key[len(prefix):]),
+			TxIndex:     uint64(binary.BigEndian.Uint32(key[len(prefix)+8:])),
+		}
+
+		if len(refs) >= pageSize && refs[len(refs)-1].BlockNumber != ref.BlockNumber {
+			return refs, true, it.Error()
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, false, it.Error()
+}
+
+// Close closes the kv database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,132 @@
+package addrindex
+
+import (
+	"os"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/helper/kvdb"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	tempDir, err := os.MkdirTemp("/tmp", "addrindex-")
+	assert.NoError(t, err)
+
+	db, err := kvdb.NewLevelDBBuilder(hclog.NewNullLogger(), tempDir).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	return NewStore(db)
+}
+
+func TestStore_IndexAndUnwind(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	addr1 := types.StringToAddress("0x1")
+	addr2 := types.StringToAddress("0x2")
+
+	_, ok := store.Head()
+	assert.False(t, ok)
+
+	assert.NoError(t, store.IndexBlock(0, types.StringToHash("0x10"), nil))
+	assert.NoError(t, store.IndexBlock(1, types.StringToHash("0x11"), []Entry{
+		{Address: addr1, TxIndex: 0},
+		{Address: addr2, TxIndex: 0},
+		{Address: addr1, TxIndex: 1},
+	}))
+
+	head, ok := store.Head()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), head)
+
+	hash, ok := store.BlockHash(1)
+	assert.True(t, ok)
+	assert.Equal(t, types.StringToHash("0x11"), hash)
+
+	refs, more, err := store.Search(addr1, 1, true, 10)
+	assert.NoError(t, err)
+	assert.False(t, more)
+	assert.Equal(t, []TxRef{{1, 1}, {1, 0}}, refs)
+
+	// only the head could be unwound
+	assert.ErrorIs(t, store.UnwindBlock(0), ErrNotHead)
+
+	assert.NoError(t, store.UnwindBlock(1))
+
+	head, ok = store.Head()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), head)
+
+	_, ok = store.BlockHash(1)
+	assert.False(t, ok)
+
+	refs, _, err = store.Search(addr1, 1, true, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, refs)
+
+	assert.NoError(t, store.UnwindBlock(0))
+
+	_, ok = store.Head()
+	assert.False(t, ok)
+}
+
+func TestStore_Search(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	addr := types.StringToAddress("0x1")
+	other := types.StringToAddress("0x2")
+
+	// two transactions of the address per block, except the block 3
+	for number := uint64(1); number <= 5; number++ {
+		entries := []Entry{{Address: other, TxIndex: 0}}
+
+		if number != 3 {
+			entries = append(entries, Entry{Address: addr, TxIndex: 0}, Entry{Address: addr, TxIndex: 2})
+		}
+
+		assert.NoError(t, store.IndexBlock(number, types.BytesToHash([]byte{byte(number)}), entries))
+	}
+
+	// the blocks are never split
+	refs, more, err := store.Search(addr, 5, true, 3)
+	assert.NoError(t, err)
+	assert.True(t, more)
+	assert.Equal(t, []TxRef{{5, 2}, {5, 0}, {4, 2}, {4, 0}}, refs)
+
+	refs, more, err = store.Search(addr, 3, true, 3)
+	assert.NoError(t, err)
+	assert.False(t, more)
+	assert.Equal(t, []TxRef{{2, 2}, {2, 0}, {1, 2}, {1, 0}}, refs)
+
+	refs, more, err = store.Search(addr, 2, false, 1)
+	assert.NoError(t, err)
+	assert.True(t, more)
+	assert.Equal(t, []TxRef{{2, 0}, {2, 2}}, refs)
+
+	refs, more, err = store.Search(addr, 3, false, 10)
+	assert.NoError(t, err)
+	assert.False(t, more)
+	assert.Equal(t, []TxRef{{4, 0}, {4, 2}, {5, 0}, {5, 2}}, refs)
+
+	// nothing after the head
+	refs, more, err = store.Search(addr, 6, false, 10)
+	assert.NoError(t, err)
+	assert.False(t, more)
+	assert.Empty(t, refs)
+
+	// unknown address
+	refs, _, err = store.Search(types.StringToAddress("0x3"), 5, true, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, refs)
+}
@@ -10,6 +10,7 @@ import (
 
 	"go.uber.org/atomic"
 
+	"github.com/dogechain-lab/dogechain/blockchain/addrindex"
 	"github.com/dogechain-lab/dogechain/blockchain/storage"
 	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/contracts/upgrader"
@@ -17,6 +18,7 @@ import (
 	"github.com/dogechain-lab/dogechain/crypto"
 	"github.com/dogechain-lab/dogechain/helper/common"
 	"github.com/dogechain-lab/dogechain/state"
+	"github.com/dogechain-lab/dogechain/state/tracer/flat"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/dogechain-lab/dogechain/types/buildroot"
 	"github.com/hashicorp/go-hclog"
@@ -26,6 +28,10 @@ import (
 const (
 	BlockGasTargetDivisor uint64 = 1024 // The bound divisor of the gas limit, used in update calculations
 	defaultCacheSize      int    = 100  // The default size for Blockchain LRU cache structures
+
+	InitialBaseFee           uint64 = 1000000000 // The base fee of the first london block
+	BaseFeeChangeDenominator uint64 = 8          // The bound divisor of the base fee, used in update calculations
+	ElasticityMultiplier     uint64 = 2          // The bound multiplier of the gas limit to the gas target
 )
 
 var (
@@ -40,6 +46,7 @@ var (
 	ErrInvalidReceiptsSize  = errors.New("invalid number of receipts")
 	ErrInvalidStateRoot     = errors.New("invalid block state root")
 	ErrInvalidGasUsed       = errors.New("invalid block gas used")
+	ErrInvalidBaseFee       = errors.New("invalid block base fee")
 	ErrInvalidReceiptsRoot  = errors.New("invalid block receipts root")
 	ErrNilStorageBuilder    = errors.New("nil storage builder")
 	ErrClosed               = errors.New("blockchain is closed")
@@ -72,6 +79,15 @@ type Blockchain struct {
 	// any new fields from being added
 	receiptsCache *lru.Cache // LRU cache for the block receipts
 
+	// the flat call traces are recorded during the execution like the receipts,
+	// and persisted in the trace store when the block is written
+	traceStore  TraceStore        // nil if the traces are not recorded
+	tracesCache *lru.Cache        // LRU cache for the block traces
+	tracesCh    chan *types.Block // the written blocks missing the traces, indexed in background
+	tracesWg    sync.WaitGroup    // for the traces indexing shutdown sync
+
+	addressIndex AddressIndex // nil if the address index is disabled, built by the AddressIndexer
+
 	currentHeader     atomic.Value // The current header
 	currentDifficulty atomic.Value // The current difficulty of the chain (total difficulty)
 
@@ -110,6 +126,21 @@ type Executor interface {
 	Stop()
 }
 
+// TraceStore persists the flat call traces of the blocks
+type TraceStore interface {
+	WriteBlockTraces(hash types.Hash, traces []*flat.TxTraces) error
+	ReadBlockTraces(hash types.Hash) ([]*flat.TxTraces, bool)
+}
+
+// AddressIndex is the address to transaction index of the canonical chain
+type AddressIndex interface {
+	Head() (uint64, bool)
+	BlockHash(number uint64) (types.Hash, bool)
+	IndexBlock(number uint64, hash types.Hash, entries []addrindex.Entry) error
+	UnwindBlock(number uint64) error
+	Search(addr types.Address, from uint64, backward bool, pageSize int) ([]addrindex.TxRef, bool, error)
+}
+
 type BlockResult struct {
 	Root     types.Hash
 	Receipts []*types.Receipt
@@ -225,6 +256,11 @@ func (b *Blockchain) initCaches(size int) error {
 		return fmt.Errorf("unable to create receipts cache, %w", err)
 	}
 
+	b.tracesCache, err = lru.New(size)
+	if err != nil {
+		return fmt.Errorf("unable to create traces cache, %w", err)
+	}
+
 	return nil
 }
 
@@ -306,6 +342,14 @@ func (b *Blockchain) Header() *types.Header {
 	return header
 }
 
+// FinalizedHeader returns the header of the last block with a verified commit
+// quorum. A block is only written once the consensus verified its header, which
+// includes the committed seals for ibft, so the blocks are final as soon as they
+// become canonical.
+func (b *Blockchain) FinalizedHeader() *types.Header {
+	return b.Header()
+}
+
 // CurrentTD returns the current total difficulty (atomic)
 func (b *Blockchain) CurrentTD() *big.Int {
 	td, ok := b.currentDifficulty.Load().(*big.Int)
@@ -382,6 +426,56 @@ func (b *Blockchain) calculateGasLimit(parentGasLimit uint64) uint64 {
 	return common.MaxUint64(blockGasTarget, common.MaxUint64(parentGasLimit-delta, 0))
 }
 
+// CalculateBaseFee returns the base fee of the next block after parent,
+// nil if the london hardfork is not enabled yet (eip-1559)
+func (b *Blockchain) CalculateBaseFee(parent *types.Header) *big.Int {
+	if !b.Config().Forks.IsLondon(parent.Number + 1) {
+		return nil
+	}
+
+	// the first london block uses the initial base fee
+	if parent.BaseFee == nil {
+		return new(big.Int).SetUint64(InitialBaseFee)
+	}
+
+	parentGasTarget := parent.GasLimit / ElasticityMultiplier
+	if parentGasTarget == 0 || parent.GasUsed == parentGasTarget {
+		return new(big.Int).Set(parent.BaseFee)
+	}
+
+	var (
+		gasDelta    uint64
+		increase    = parent.GasUsed > parentGasTarget
+		denominator = new(big.Int).SetUint64(parentGasTarget * BaseFeeChangeDenominator)
+	)
+
+	if increase {
+		gasDelta = parent.GasUsed - parentGasTarget
+	} else {
+		gasDelta = parentGasTarget - parent.GasUsed
+	}
+
+	// baseFeeDelta = parentBaseFee * gasDelta / parentGasTarget / BaseFeeChangeDenominator
+	baseFeeDelta := new(big.Int).Mul(parent.BaseFee, new(big.Int).SetUint64(gasDelta))
+	baseFeeDelta.Div(baseFeeDelta, denominator)
+
+	if increase {
+		// the base fee increases by 1 at least
+		if baseFeeDelta.Sign() == 0 {
+			baseFeeDelta.SetUint64(1)
+		}
+
+		return baseFeeDelta.Add(parent.BaseFee, baseFeeDelta)
+	}
+
+	baseFee := baseFeeDelta.Sub(parent.BaseFee, baseFeeDelta)
+	if baseFee.Sign() < 0 {
+		baseFee.SetUint64(0)
+	}
+
+	return baseFee
+}
+
 // writeGenesis wrapper for the genesis write function
 func (b *Blockchain) writeGenesis(genesis *chain.Genesis) error {
 	header := genesis.GenesisHeader()
@@ -773,9 +867,30 @@ func (b *Blockchain) verifyBlockParent(childBlock *types.Block) error {
 		return fmt.Errorf("invalid gas limit, %w", gasLimitErr)
 	}
 
+	// Make sure the base fee is correct
+	if expected := b.CalculateBaseFee(parent); !isBaseFeeEqual(expected, childBlock.Header.BaseFee) {
+		b.logger.Error(fmt.Sprintf(
+			"base fee mismatch at %d: have %s, want %s",
+			childBlock.Number(),
+			childBlock.Header.BaseFee,
+			expected,
+		))
+
+		return ErrInvalidBaseFee
+	}
+
 	return nil
 }
 
+// isBaseFeeEqual checks whether the base fees are equal, a nil base fee only equals nil
+func isBaseFeeEqual(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Cmp(b) == 0
+}
+
 // verifyBlockBody verifies that the block body is valid. This means checking:
 // - The trie roots match up (state, transactions, receipts, uncles)
 // - The receipts match up
@@ -866,24 +981,91 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 		b.metrics.BlockExecutionSecondsObserve(time.Since(begin).Seconds())
 	}()
 
+	var tracer *flat.Tracer
+	if b.traceStore != nil {
+		tracer = flat.NewTracer()
+	}
+
+	txn, executed, err := b.processBlockTransactions(block, tracer)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.isStopped() {
+		// execute stop, should not commit
+		return nil, ErrClosed
+	}
+
+	_, root, err := txn.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	header := block.Header
+
+	// Append the receipts to the receipts cache
+	b.receiptsCache.Add(header.Hash, txn.Receipts())
+
+	if tracer != nil {
+		b.tracesCache.Add(header.Hash, b.collectBlockTraces(block, executed, tracer))
+	}
+
+	return &BlockResult{
+		Root:     root,
+		Receipts: txn.Receipts(),
+		TotalGas: txn.TotalGas(),
+	}, nil
+}
+
+// traceBlockTransactions replays the transactions in the block for the traces.
+// The replay never commits, and it is not observed as a block execution.
+func (b *Blockchain) traceBlockTransactions(block *types.Block) ([]*flat.TxTraces, error) {
+	if b.isStopped() {
+		return nil, ErrClosed
+	}
+
+	b.wg.Add(1)
+	defer b.wg.Done()
+
+	tracer := flat.NewTracer()
+
+	_, executed, err := b.processBlockTransactions(block, tracer)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.collectBlockTraces(block, executed, tracer), nil
+}
+
+// processBlockTransactions executes the transactions in the block on top of the
+// parent state, and returns the uncommitted transition along with the executed
+// transactions in the execution order
+func (b *Blockchain) processBlockTransactions(
+	block *types.Block,
+	tracer *flat.Tracer,
+) (*state.Transition, []*types.Transaction, error) {
 	header := block.Header
 
 	parent, ok := b.readHeader(header.ParentHash)
 	if !ok {
-		return nil, ErrParentNotFound
+		return nil, nil, ErrParentNotFound
 	}
 
 	height := header.Number
 
 	blockCreator, err := b.consensus.GetBlockCreator(header)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// prepare execution
 	txn, err := b.executor.BeginTxn(parent.StateRoot, block.Header, blockCreator)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if tracer != nil {
+		txn.SetEVMLogger(tracer)
 	}
 
 	// upgrade system contract first if needed
@@ -913,31 +1095,19 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 
 	// execute normal transaction first
 	if _, err := b.executor.ProcessTransactions(txn, header.GasLimit, normalTxs); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if _, err := b.executor.ProcessTransactions(txn, header.GasLimit, systemTxs); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if b.isStopped() {
-		// execute stop, should not commit
-		return nil, ErrClosed
-	}
+	// same order as the execution
+	executed := make([]*types.Transaction, 0, len(block.Transactions))
+	executed = append(executed, normalTxs...)
+	executed = append(executed, systemTxs...)
 
-	_, root, err := txn.Commit()
-	if err != nil {
-		return nil, err
-	}
-
-	// Append the receipts to the receipts cache
-	b.receiptsCache.Add(header.Hash, txn.Receipts())
-
-	return &BlockResult{
-		Root:     root,
-		Receipts: txn.Receipts(),
-		TotalGas: txn.TotalGas(),
-	}, nil
+	return txn, executed, nil
 }
 
 // WriteBlock writes a single block
@@ -987,6 +1157,13 @@ func (b *Blockchain) WriteBlock(block *types.Block, source string) error {
 		return err
 	}
 
+	if b.traceStore != nil {
+		// the traces are optional, never fail the block on them
+		if err := b.writeBlockTraces(block); err != nil {
+			b.logger.Error("failed to write block traces", "number", block.Number(), "err", err)
+		}
+	}
+
 	//	update snapshot
 	if err := b.consensus.ProcessHeaders([]*types.Header{header}); err != nil {
 		return err
@@ -1359,6 +1536,13 @@ func (b *Blockchain) handleReorg(
 		}
 
 		oldChain = append(oldChain, oldHeader)
+
+		// the new headers below the old head are canonical as well, except the
+		// common ancestor, otherwise the canonical hashes of the divergent branch
+		// below the old head would still point to the old chain
+		if newHeader.Hash != oldHeader.Hash {
+			newChain = append(newChain, newHeader)
+		}
 	}
 
 	for _, b := range oldChain[:len(oldChain)-1] {
@@ -1469,6 +1653,12 @@ func (b *Blockchain) Close() error {
 
 	b.wg.Wait()
 
+	if b.tracesCh != nil {
+		// no more blocks written
+		close(b.tracesCh)
+		b.tracesWg.Wait()
+	}
+
 	// close db at last
 	return b.db.Close()
 }
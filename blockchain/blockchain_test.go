@@ -527,6 +527,56 @@ func TestInsertHeaders(t *testing.T) {
 	}
 }
 
+func TestHandleReorg_CanonicalHashes(t *testing.T) {
+	b := NewTestBlockchain(t, nil)
+
+	chain := dummyChain{
+		headers: map[byte]*types.Header{},
+	}
+
+	for _, h := range []*header{
+		mock(0x0),
+		mock(0x1),
+		mock(0x2),
+		mock(0x3),
+		// the fork from block 1 is lighter, until block 6 arrives
+		mock(0x4).Parent(0x1).Diff(1).Number(2),
+		mock(0x5).Parent(0x4).Diff(1).Number(3),
+		mock(0x6).Parent(0x5).Diff(20).Number(4),
+	} {
+		assert.NoError(t, chain.add(h))
+	}
+
+	assert.NoError(t, b.writeGenesisImpl(chain.headers[0x0]))
+
+	for _, i := range []byte{0x1, 0x2, 0x3, 0x4, 0x5} {
+		assert.NoError(t, b.WriteHeaders([]*types.Header{chain.headers[i]}))
+	}
+
+	assert.Equal(t, chain.headers[0x3].Hash, b.Header().Hash)
+
+	sub := b.SubscribeEvents()
+	t.Cleanup(func() {
+		sub.Unsubscribe()
+	})
+
+	// the reorg deeper than a block
+	assert.NoError(t, b.WriteHeaders([]*types.Header{chain.headers[0x6]}))
+
+	evnt := <-sub.GetEvent()
+	assert.Equal(t, EventReorg, evnt.Type)
+	assert.Len(t, evnt.NewChain, 3)
+	assert.Len(t, evnt.OldChain, 2)
+
+	// all the headers of the divergent branch are canonical, not only the head
+	// and its parent
+	for number, i := range map[uint64]byte{1: 0x1, 2: 0x4, 3: 0x5, 4: 0x6} {
+		hash, ok := b.db.ReadCanonicalHash(number)
+		assert.True(t, ok)
+		assert.Equal(t, chain.headers[i].Hash, hash, "canonical hash of block %d", number)
+	}
+}
+
 func TestForkUnknownParents(t *testing.T) {
 	b := NewTestBlockchain(t, nil)
 
@@ -637,6 +687,96 @@ func TestCalculateGasLimit(t *testing.T) {
 	}
 }
 
+func TestCalculateBaseFee(t *testing.T) {
+	var (
+		londonParams = &chain.Params{
+			Forks: &chain.Forks{London: chain.NewFork(1)},
+		}
+		initialBaseFee = new(big.Int).SetUint64(InitialBaseFee)
+	)
+
+	tests := []struct {
+		name            string
+		params          *chain.Params
+		parent          *types.Header
+		expectedBaseFee *big.Int
+	}{
+		{
+			name:   "should not set base fee before london",
+			params: &chain.Params{Forks: &chain.Forks{London: chain.NewFork(10)}},
+			parent: &types.Header{
+				Number: 1,
+			},
+			expectedBaseFee: nil,
+		},
+		{
+			name:   "should use initial base fee at the london block",
+			params: londonParams,
+			parent: &types.Header{
+				Number: 0,
+			},
+			expectedBaseFee: initialBaseFee,
+		},
+		{
+			name:   "should not alter base fee when gas used equals gas target",
+			params: londonParams,
+			parent: &types.Header{
+				Number:   1,
+				GasLimit: 20000000,
+				GasUsed:  10000000,
+				BaseFee:  initialBaseFee,
+			},
+			expectedBaseFee: initialBaseFee,
+		},
+		{
+			name:   "should increase base fee by 1/8 when block is full",
+			params: londonParams,
+			parent: &types.Header{
+				Number:   1,
+				GasLimit: 20000000,
+				GasUsed:  20000000,
+				BaseFee:  initialBaseFee,
+			},
+			expectedBaseFee: big.NewInt(1125000000),
+		},
+		{
+			name:   "should decrease base fee by 1/8 when block is empty",
+			params: londonParams,
+			parent: &types.Header{
+				Number:   1,
+				GasLimit: 20000000,
+				GasUsed:  0,
+				BaseFee:  initialBaseFee,
+			},
+			expectedBaseFee: big.NewInt(875000000),
+		},
+		{
+			name:   "should increase base fee by 1 at least",
+			params: londonParams,
+			parent: &types.Header{
+				Number:   1,
+				GasLimit: 20000000,
+				GasUsed:  10000001,
+				BaseFee:  big.NewInt(7),
+			},
+			expectedBaseFee: big.NewInt(8),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, blockchainErr := NewMockBlockchain(nil)
+			if blockchainErr != nil {
+				t.Fatalf("unable to construct the blockchain, %v", blockchainErr)
+			}
+
+			b.config.Params = tt.params
+
+			assert.Equal(t, tt.expectedBaseFee, b.CalculateBaseFee(tt.parent))
+		})
+	}
+}
+
 // TestGasPriceAverage tests the average gas price of the
 // blockchain
 func TestGasPriceAverage(t *testing.T) {
@@ -0,0 +1,301 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dogechain-lab/dogechain/blockchain/bloombits"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"go.uber.org/atomic"
+)
+
+const (
+	// BloomBitsSectionSize is the number of the blocks in a bloom bits section
+	BloomBitsSectionSize = 4096
+
+	// BloomBitsConfirms is the number of the blocks to wait after a section before
+	// indexing it, so that the shallow reorgs never touch the index
+	BloomBitsConfirms = 256
+)
+
+var (
+	ErrBloomBitsNotFound = errors.New("bloom bits not found")
+)
+
+// BloomIndexer builds the bloom bits index of the canonical chain in background.
+// It keeps up with the new blocks through the blockchain events, and rolls back
+// the sections touched by a reorg, which are rebuilt once the chain is confirmed.
+type BloomIndexer struct {
+	logger     hclog.Logger
+	blockchain *Blockchain
+
+	sectionSize uint64
+	confirms    uint64
+	sections    atomic.Uint64 // number of the valid sections
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewBloomIndexer creates the bloom bits indexer of the blockchain
+func NewBloomIndexer(logger hclog.Logger, b *Blockchain, sectionSize, confirms uint64) *BloomIndexer {
+	return &BloomIndexer{
+		logger:      logger.Named("bloom_indexer"),
+		blockchain:  b,
+		sectionSize: sectionSize,
+		confirms:    confirms,
+		closeCh:     make(chan struct{}),
+	}
+}
+
+// Start loads the indexed sections and starts indexing in background
+func (i *BloomIndexer) Start() error {
+	if _, err := bloombits.NewGenerator(i.sectionSize); err != nil {
+		return err
+	}
+
+	sections, _ := i.blockchain.db.ReadBloomSections()
+	i.sections.Store(sections)
+
+	// the chain might be reorged without the indexer running
+	if err := i.verify(); err != nil {
+		return err
+	}
+
+	sub := i.blockchain.SubscribeEvents()
+	if sub == nil {
+		return ErrClosed
+	}
+
+	i.wg.Add(1)
+
+	go i.run(sub)
+
+	return nil
+}
+
+// Close stops the indexer
+func (i *BloomIndexer) Close() {
+	close(i.closeCh)
+	i.wg.Wait()
+}
+
+// SectionSize returns the number of the blocks in a section
+func (i *BloomIndexer) SectionSize() uint64 {
+	return i.sectionSize
+}
+
+// Sections returns the number of the indexed sections
+func (i *BloomIndexer) Sections() uint64 {
+	return i.sections.Load()
+}
+
+// FilterBlocks returns the numbers of the blocks in the range which might match the
+// filters by the bloom bits, and the first block not covered by the index, from
+// which on every block is a candidate. The filters are the same as the ones of the
+// bloombits matcher.
+func (i *BloomIndexer) FilterBlocks(from, to uint64, filters [][][]byte) ([]uint64, uint64, error) {
+	matcher := bloombits.NewMatcher(i.sectionSize, filters)
+	if matcher.Empty() || to < from {
+		return nil, from, nil
+	}
+
+	sections := i.Sections()
+	blocks := make([]uint64, 0)
+	section := from / i.sectionSize
+
+	for ; section < sections && section*i.sectionSize <= to; section++ {
+		head, ok := i.blockchain.db.ReadBloomSectionHead(section)
+		if !ok {
+			// rolled back in the meantime
+			break
+		}
+
+		matched, err := matcher.Match(section, func(bit uint, section uint64) ([]byte, error) {
+			data, ok := i.blockchain.db.ReadBloomBits(bit, section, head)
+			if !ok {
+				return nil, ErrBloomBitsNotFound
+			}
+
+			return data, nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, num := range matched {
+			if num >= from && num <= to {
+				blocks = append(blocks, num)
+			}
+		}
+	}
+
+	next := section * i.sectionSize
+	if next < from {
+		next = from
+	}
+
+	return blocks, next, nil
+}
+
+func (i *BloomIndexer) run(sub Subscription) {
+	defer i.wg.Done()
+	defer sub.Unsubscribe()
+
+	i.update()
+
+	for {
+		select {
+		case <-i.closeCh:
+			return
+		case evnt, ok := <-sub.GetEvent():
+			if !ok {
+				return
+			}
+
+			if len(evnt.OldChain) > 0 {
+				fork := evnt.OldChain[0].Number
+				for _, header := range evnt.OldChain {
+					if header.Number < fork {
+						fork = header.Number
+					}
+				}
+
+				if err := i.rollback(fork / i.sectionSize); err != nil {
+					i.logger.Error("failed to roll back sections", "err", err)
+				}
+			}
+
+			i.update()
+		}
+	}
+}
+
+// update indexes the confirmed sections up to the chain head. The index is verified
+// first, since the reorg events might be dropped when the indexer is busy.
+func (i *BloomIndexer) update() {
+	if err := i.verify(); err != nil {
+		i.logger.Error("failed to verify sections", "err", err)
+
+		return
+	}
+
+	for {
+		select {
+		case <-i.closeCh:
+			return
+		default:
+		}
+
+		section := i.Sections()
+		last := (section+1)*i.sectionSize - 1
+
+		if i.blockchain.Header().Number < last+i.confirms {
+			return
+		}
+
+		if err := i.processSection(section); err != nil {
+			i.logger.Error("failed to index section", "section", section, "err", err)
+
+			return
+		}
+
+		i.logger.Debug("section indexed", "section", section)
+	}
+}
+
+// verify rolls back the sections whose heads are no longer canonical
+func (i *BloomIndexer) verify() error {
+	sections := i.Sections()
+
+	for sections > 0 {
+		head, ok := i.blockchain.db.ReadBloomSectionHead(sections - 1)
+		canonical, canonicalOk := i.blockchain.db.ReadCanonicalHash(sections*i.sectionSize - 1)
+
+		if ok && canonicalOk && head == canonical {
+			break
+		}
+
+		sections--
+	}
+
+	return i.rollback(sections)
+}
+
+// rollback drops the sections from the given one on
+func (i *BloomIndexer) rollback(sections uint64) error {
+	if sections >= i.Sections() {
+		return nil
+	}
+
+	if err := i.blockchain.db.WriteBloomSections(sections); err != nil {
+		return err
+	}
+
+	i.logger.Info("sections rolled back", "sections", sections)
+
+	i.sections.Store(sections)
+
+	return nil
+}
+
+// processSection generates and stores the bloom bits of the section
+func (i *BloomIndexer) processSection(section uint64) error {
+	gen, err := bloombits.NewGenerator(i.sectionSize)
+	if err != nil {
+		return err
+	}
+
+	var head types.Hash
+
+	for index := uint64(0); index < i.sectionSize; index++ {
+		num := section*i.sectionSize + index
+
+		hash, ok := i.blockchain.db.ReadCanonicalHash(num)
+		if !ok {
+			return fmt.Errorf("canonical hash of block %d not found", num)
+		}
+
+		// read from the db directly, never flush the headers cache
+		header, err := i.blockchain.db.ReadHeader(hash)
+		if err != nil {
+			return err
+		}
+
+		if err := gen.AddBloom(index, &header.LogsBloom); err != nil {
+			return err
+		}
+
+		head = hash
+	}
+
+	for bit := uint(0); bit < types.BloomBitLength; bit++ {
+		bits, err := gen.Bitset(bit)
+		if err != nil {
+			return err
+		}
+
+		if err := i.blockchain.db.WriteBloomBits(bit, section, head, bits); err != nil {
+			return err
+		}
+	}
+
+	// reorged during the generation
+	if hash, ok := i.blockchain.db.ReadCanonicalHash((section+1)*i.sectionSize - 1); !ok || hash != head {
+		return fmt.Errorf("section %d reorged while indexing", section)
+	}
+
+	if err := i.blockchain.db.WriteBloomSectionHead(section, head); err != nil {
+		return err
+	}
+
+	if err := i.blockchain.db.WriteBloomSections(section + 1); err != nil {
+		return err
+	}
+
+	i.sections.Store(section + 1)
+
+	return nil
+}
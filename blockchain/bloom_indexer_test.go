@@ -0,0 +1,130 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestBloomHeaders creates n headers after the parent, the blocks of the numbers
+// in the blooms have the address in their blooms
+func newTestBloomHeaders(parent *types.Header, n int, seed uint64, blooms map[uint64]types.Address) []*types.Header {
+	headers := make([]*types.Header, 0, n)
+
+	for i := 0; i < n; i++ {
+		header := &types.Header{
+			Number:       parent.Number + 1,
+			ParentHash:   parent.Hash,
+			GasLimit:     seed,
+			TxRoot:       types.EmptyRootHash,
+			Sha3Uncles:   types.EmptyUncleHash,
+			ReceiptsRoot: types.EmptyRootHash,
+			Difficulty:   parent.Number + 1,
+		}
+
+		if addr, ok := blooms[header.Number]; ok {
+			header.LogsBloom = types.CreateBloom([]*types.Receipt{{Logs: []*types.Log{{Address: addr}}}})
+		}
+
+		header.ComputeHash()
+		headers = append(headers, header)
+		parent = header
+	}
+
+	return headers
+}
+
+func TestBloomIndexer(t *testing.T) {
+	addr := types.StringToAddress("1")
+
+	genesis := NewTestHeaders(1)
+	headers := append(genesis, newTestBloomHeaders(genesis[0], 40, 0, map[uint64]types.Address{ //nolint:gocritic
+		5:  addr,
+		17: addr,
+		25: addr,
+	})...)
+
+	b := NewTestBlockchain(t, headers)
+	defer b.Close()
+
+	// the test chain does not store the genesis header
+	assert.NoError(t, b.db.WriteHeader(headers[0]))
+
+	indexer := NewBloomIndexer(hclog.NewNullLogger(), b, 8, 2)
+	assert.NoError(t, indexer.Start())
+
+	defer indexer.Close()
+
+	// blocks up to 31 are indexed, since 39 is not confirmed yet
+	assert.Eventually(t, func() bool {
+		return indexer.Sections() == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	filters := [][][]byte{{addr.Bytes()}}
+
+	blocks, next, err := indexer.FilterBlocks(1, 40, filters)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{5, 17, 25}, blocks)
+	assert.Equal(t, uint64(32), next)
+
+	blocks, next, err = indexer.FilterBlocks(10, 20, filters)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{17}, blocks)
+	assert.Equal(t, uint64(24), next)
+
+	// nothing to narrow without the filters
+	blocks, next, err = indexer.FilterBlocks(1, 40, [][][]byte{{}})
+	assert.NoError(t, err)
+	assert.Empty(t, blocks)
+	assert.Equal(t, uint64(1), next)
+
+	// reorg from block 21 on, block 25 is replaced by 30
+	fork := newTestBloomHeaders(headers[20], 30, 1, map[uint64]types.Address{
+		30: addr,
+	})
+	assert.NoError(t, b.WriteHeaders(fork))
+
+	// the fork blocks below the old head are canonical as well
+	header, ok := b.GetHeaderByNumber(25)
+	assert.True(t, ok)
+	assert.Equal(t, fork[25-21].Hash, header.Hash)
+
+	assert.Eventually(t, func() bool {
+		hash, ok := b.db.ReadBloomSectionHead(5)
+
+		return indexer.Sections() == 6 && ok && hash == fork[47-21].Hash
+	}, 5*time.Second, 10*time.Millisecond)
+
+	blocks, next, err = indexer.FilterBlocks(1, 50, filters)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{5, 17, 30}, blocks)
+	assert.Equal(t, uint64(48), next)
+}
+
+func TestBloomIndexer_VerifyOnStart(t *testing.T) {
+	headers := NewTestHeaders(20)
+
+	b := NewTestBlockchain(t, headers)
+	defer b.Close()
+
+	// the test chain does not store the genesis header
+	assert.NoError(t, b.db.WriteHeader(headers[0]))
+
+	// the section head of a chain no longer canonical
+	assert.NoError(t, b.db.WriteBloomSectionHead(0, types.StringToHash("1")))
+	assert.NoError(t, b.db.WriteBloomSections(1))
+
+	indexer := NewBloomIndexer(hclog.NewNullLogger(), b, 8, 2)
+	assert.NoError(t, indexer.Start())
+
+	defer indexer.Close()
+
+	assert.Eventually(t, func() bool {
+		hash, ok := b.db.ReadBloomSectionHead(0)
+
+		return indexer.Sections() == 2 && ok && hash == headers[7].Hash
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,125 @@
+package bloombits
+
+import (
+	"errors"
+)
+
+var (
+	errMissingData    = errors.New("missing bytes on input")
+	errUnreferenced   = errors.New("extra bytes on input")
+	errExceededTarget = errors.New("target data size exceeded")
+	errZeroContent    = errors.New("zero byte in input content")
+)
+
+// compressBits compresses the mostly zero bit vector. The non-zero bytes are kept
+// along with the bitset of their positions, which is compressed the same way
+// recursively. The data is kept as is if it could not be shortened.
+func compressBits(data []byte) []byte {
+	if out := bitsetEncode(data); len(out) < len(data) {
+		return out
+	}
+
+	cpy := make([]byte, len(data))
+	copy(cpy, data)
+
+	return cpy
+}
+
+func bitsetEncode(data []byte) []byte {
+	// a single byte is the bitset of itself
+	if len(data) == 1 {
+		if data[0] == 0 {
+			return nil
+		}
+
+		return []byte{data[0]}
+	}
+
+	nonZeroBitset := make([]byte, (len(data)+7)/8)
+	nonZeroBytes := make([]byte, 0, len(data))
+
+	for i, b := range data {
+		if b != 0 {
+			nonZeroBytes = append(nonZeroBytes, b)
+			nonZeroBitset[i/8] |= 1 << byte(7-i%8)
+		}
+	}
+
+	if len(nonZeroBytes) == 0 {
+		return nil
+	}
+
+	return append(bitsetEncode(nonZeroBitset), nonZeroBytes...)
+}
+
+// decompressBits decompresses the bit vector of the target size
+func decompressBits(data []byte, target int) ([]byte, error) {
+	if len(data) > target {
+		return nil, errExceededTarget
+	}
+
+	// not compressed
+	if len(data) == target {
+		cpy := make([]byte, len(data))
+		copy(cpy, data)
+
+		return cpy, nil
+	}
+
+	out, size, err := bitsetDecode(data, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if size != len(data) {
+		return nil, errUnreferenced
+	}
+
+	return out, nil
+}
+
+// bitsetDecode decodes the data of the target size, returning the number of the
+// bytes consumed
+func bitsetDecode(data []byte, target int) ([]byte, int, error) {
+	out := make([]byte, target)
+	if len(data) == 0 {
+		return out, 0, nil
+	}
+
+	if target == 1 {
+		out[0] = data[0]
+		if data[0] != 0 {
+			return out, 1, nil
+		}
+
+		return out, 0, nil
+	}
+
+	nonZeroBitset, ptr, err := bitsetDecode(data, (target+7)/8)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := 0; i < 8*len(nonZeroBitset); i++ {
+		if nonZeroBitset[i/8]&(1<<byte(7-i%8)) == 0 {
+			continue
+		}
+
+		if ptr >= len(data) {
+			return nil, 0, errMissingData
+		}
+
+		if i >= len(out) {
+			return nil, 0, errExceededTarget
+		}
+
+		if data[ptr] == 0 {
+			return nil, 0, errZeroContent
+		}
+
+		out[i] = data[ptr]
+		ptr++
+	}
+
+	return out, ptr, nil
+}
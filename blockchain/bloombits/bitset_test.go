@@ -0,0 +1,63 @@
+package bloombits
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressBits(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte{}},
+		{"single zero", []byte{0x0}},
+		{"single byte", []byte{0x1}},
+		{"all zeros", make([]byte, 512)},
+		{"sparse", append(make([]byte, 100), 0x10, 0x0, 0x0, 0x80)},
+		{"dense", []byte{0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8, 0x9}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			compressed := compressBits(c.data)
+			assert.LessOrEqual(t, len(compressed), len(c.data))
+
+			decompressed, err := decompressBits(compressed, len(c.data))
+			assert.NoError(t, err)
+			assert.Equal(t, c.data, decompressed)
+		})
+	}
+}
+
+func TestCompressBits_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1)) //nolint:gosec
+
+	for i := 0; i < 100; i++ {
+		data := make([]byte, 512)
+
+		// a few set bits as the bloom bits do
+		for j := r.Intn(64); j > 0; j-- {
+			data[r.Intn(len(data))] |= 1 << r.Intn(8)
+		}
+
+		decompressed, err := decompressBits(compressBits(data), len(data))
+		assert.NoError(t, err)
+		assert.Equal(t, data, decompressed)
+	}
+}
+
+func TestDecompressBits_Invalid(t *testing.T) {
+	_, err := decompressBits([]byte{0x1, 0x2, 0x3}, 2)
+	assert.ErrorIs(t, err, errExceededTarget)
+
+	// the bitset refers to a byte which is missing
+	_, err = decompressBits([]byte{0x80}, 16)
+	assert.ErrorIs(t, err, errMissingData)
+
+	// the referred byte is zero
+	_, err = decompressBits([]byte{0x80, 0x0}, 16)
+	assert.ErrorIs(t, err, errZeroContent)
+}
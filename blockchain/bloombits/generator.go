@@ -0,0 +1,73 @@
+package bloombits
+
+import (
+	"errors"
+
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	ErrInvalidSectionSize = errors.New("section size must be a positive multiple of 8")
+	ErrBloomOutOfOrder    = errors.New("bloom added out of order")
+	ErrSectionIncomplete  = errors.New("section not complete yet")
+	ErrBitOutOfRange      = errors.New("bloom bit out of range")
+)
+
+// Generator rotates the blooms of the blocks in a section into the bloom bits,
+// the n-th bit vector holds the n-th bloom bit of every block in the section
+type Generator struct {
+	vectors     [types.BloomBitLength][]byte
+	sectionSize uint64
+	next        uint64 // index of the next bloom in the section
+}
+
+// NewGenerator creates a generator of the section size
+func NewGenerator(sectionSize uint64) (*Generator, error) {
+	if sectionSize == 0 || sectionSize%8 != 0 {
+		return nil, ErrInvalidSectionSize
+	}
+
+	g := &Generator{
+		sectionSize: sectionSize,
+	}
+
+	for i := range g.vectors {
+		g.vectors[i] = make([]byte, sectionSize/8)
+	}
+
+	return g, nil
+}
+
+// AddBloom adds the bloom of the block at the index of the section, the blooms
+// must be added in order
+func (g *Generator) AddBloom(index uint64, bloom *types.Bloom) error {
+	if index != g.next || index >= g.sectionSize {
+		return ErrBloomOutOfOrder
+	}
+
+	byteIndex, bitMask := index/8, byte(1<<(7-index%8))
+
+	for bit := uint(0); bit < types.BloomBitLength; bit++ {
+		if bloom.HasBit(bit) {
+			g.vectors[bit][byteIndex] |= bitMask
+		}
+	}
+
+	g.next++
+
+	return nil
+}
+
+// Bitset returns the compressed bit vector of the bloom bit once the section
+// is complete
+func (g *Generator) Bitset(bit uint) ([]byte, error) {
+	if g.next != g.sectionSize {
+		return nil, ErrSectionIncomplete
+	}
+
+	if bit >= types.BloomBitLength {
+		return nil, ErrBitOutOfRange
+	}
+
+	return compressBits(g.vectors[bit]), nil
+}
@@ -0,0 +1,124 @@
+package bloombits
+
+import (
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+// Retriever returns the compressed bit vector of the bloom bit of the section
+type Retriever func(bit uint, section uint64) ([]byte, error)
+
+// Matcher matches the blocks of the sections against the filter criteria by their
+// bloom bits. Every criterion is a list of alternatives, a block matches if any
+// alternative of every criterion might be in its bloom, the same way as the logs
+// are filtered by the addresses and the topics.
+type Matcher struct {
+	sectionSize uint64
+	criteria    [][][3]uint
+}
+
+// NewMatcher creates a matcher of the filters. The empty filters are the wildcards,
+// which are skipped.
+func NewMatcher(sectionSize uint64, filters [][][]byte) *Matcher {
+	m := &Matcher{
+		sectionSize: sectionSize,
+	}
+
+	for _, filter := range filters {
+		if len(filter) == 0 {
+			continue
+		}
+
+		alternatives := make([][3]uint, 0, len(filter))
+		for _, data := range filter {
+			alternatives = append(alternatives, types.BloomBits(data))
+		}
+
+		m.criteria = append(m.criteria, alternatives)
+	}
+
+	return m
+}
+
+// Empty returns whether the matcher has no criteria, in which case every block
+// matches and there is no need to match at all
+func (m *Matcher) Empty() bool {
+	return len(m.criteria) == 0
+}
+
+// Match returns the numbers of the blocks in the section which might match the
+// criteria, in ascending order
+func (m *Matcher) Match(section uint64, retrieve Retriever) ([]uint64, error) {
+	size := int(m.sectionSize / 8)
+	vectors := make(map[uint][]byte)
+
+	vector := func(bit uint) ([]byte, error) {
+		if v, ok := vectors[bit]; ok {
+			return v, nil
+		}
+
+		data, err := retrieve(bit, section)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := decompressBits(data, size)
+		if err != nil {
+			return nil, err
+		}
+
+		vectors[bit] = v
+
+		return v, nil
+	}
+
+	result := make([]byte, size)
+	for i := range result {
+		result[i] = 0xff
+	}
+
+	for _, alternatives := range m.criteria {
+		matched := make([]byte, size)
+
+		for _, bits := range alternatives {
+			alternative := make([]byte, size)
+			for i := range alternative {
+				alternative[i] = 0xff
+			}
+
+			for _, bit := range bits {
+				v, err := vector(bit)
+				if err != nil {
+					return nil, err
+				}
+
+				for i := range alternative {
+					alternative[i] &= v[i]
+				}
+			}
+
+			for i := range matched {
+				matched[i] |= alternative[i]
+			}
+		}
+
+		for i := range result {
+			result[i] &= matched[i]
+		}
+	}
+
+	blocks := make([]uint64, 0)
+
+	for i, b := range result {
+		if b == 0 {
+			continue
+		}
+
+		for j := 0; j < 8; j++ {
+			if b&(1<<(7-j)) != 0 {
+				blocks = append(blocks, section*m.sectionSize+uint64(8*i+j))
+			}
+		}
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,120 @@
+package bloombits
+
+import (
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	addr1  = types.StringToAddress("1")
+	addr2  = types.StringToAddress("2")
+	topic1 = types.StringToHash("1")
+	topic2 = types.StringToHash("2")
+)
+
+// newTestSection returns the bit vectors of a section with the logs in the blocks
+func newTestSection(t *testing.T, sectionSize uint64, logs map[uint64][]*types.Log) map[uint][]byte {
+	t.Helper()
+
+	gen, err := NewGenerator(sectionSize)
+	assert.NoError(t, err)
+
+	for i := uint64(0); i < sectionSize; i++ {
+		bloom := types.CreateBloom([]*types.Receipt{{Logs: logs[i]}})
+		assert.NoError(t, gen.AddBloom(i, &bloom))
+	}
+
+	vectors := make(map[uint][]byte)
+
+	for bit := uint(0); bit < types.BloomBitLength; bit++ {
+		vectors[bit], err = gen.Bitset(bit)
+		assert.NoError(t, err)
+	}
+
+	return vectors
+}
+
+func TestGenerator_Errors(t *testing.T) {
+	_, err := NewGenerator(12)
+	assert.ErrorIs(t, err, ErrInvalidSectionSize)
+
+	gen, err := NewGenerator(8)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, gen.AddBloom(1, &types.Bloom{}), ErrBloomOutOfOrder)
+	assert.NoError(t, gen.AddBloom(0, &types.Bloom{}))
+
+	_, err = gen.Bitset(0)
+	assert.ErrorIs(t, err, ErrSectionIncomplete)
+}
+
+func TestBloomBits(t *testing.T) {
+	bloom := types.CreateBloom([]*types.Receipt{{Logs: []*types.Log{{Address: addr1}}}})
+
+	for _, bit := range types.BloomBits(addr1.Bytes()) {
+		assert.True(t, bloom.HasBit(bit))
+	}
+}
+
+func TestMatcher_Match(t *testing.T) {
+	const sectionSize = 16
+
+	vectors := newTestSection(t, sectionSize, map[uint64][]*types.Log{
+		1: {{Address: addr1, Topics: []types.Hash{topic1}}},
+		5: {{Address: addr2, Topics: []types.Hash{topic1}}},
+		9: {{Address: addr1, Topics: []types.Hash{topic2}}},
+	})
+
+	retrieve := func(bit uint, section uint64) ([]byte, error) {
+		assert.Equal(t, uint64(3), section)
+
+		return vectors[bit], nil
+	}
+
+	cases := []struct {
+		name    string
+		filters [][][]byte
+		blocks  []uint64
+	}{
+		{
+			"single address",
+			[][][]byte{{addr1.Bytes()}},
+			[]uint64{49, 57},
+		},
+		{
+			"any of the addresses",
+			[][][]byte{{addr1.Bytes(), addr2.Bytes()}},
+			[]uint64{49, 53, 57},
+		},
+		{
+			"address and topic",
+			[][][]byte{{addr1.Bytes()}, {topic1.Bytes()}},
+			[]uint64{49},
+		},
+		{
+			"wildcard address",
+			[][][]byte{{}, {topic1.Bytes()}},
+			[]uint64{49, 53},
+		},
+		{
+			"no match",
+			[][][]byte{{addr2.Bytes()}, {topic2.Bytes()}},
+			[]uint64{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := NewMatcher(sectionSize, c.filters)
+			assert.False(t, m.Empty())
+
+			blocks, err := m.Match(3, retrieve)
+			assert.NoError(t, err)
+			assert.Equal(t, c.blocks, blocks)
+		})
+	}
+
+	assert.True(t, NewMatcher(sectionSize, [][][]byte{{}, {}}).Empty())
+}
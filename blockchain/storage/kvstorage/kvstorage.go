@@ -39,6 +39,13 @@ var (
 
 	// TX_LOOKUP_PREFIX is the prefix for transaction lookups
 	TX_LOOKUP_PREFIX = []byte("l")
+
+	// BLOOM_BITS is the prefix for the bloom bits of the sections
+	BLOOM_BITS = []byte("B")
+
+	// BLOOM_SECTIONS is the prefix for the section heads and the number of the
+	// indexed sections of the bloom bits
+	BLOOM_SECTIONS = []byte("S")
 )
 
 // Sub-prefixes
@@ -265,6 +272,59 @@ func (s *KeyValueStorage) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
 	return types.BytesToHash(blockHash), true
 }
 
+// BLOOM BITS //
+
+// bloomBitsKey returns the key of the bloom bits, the bits of a section are keyed
+// by its head as well, so that the stale ones of a reorged section are never read
+func (s *KeyValueStorage) bloomBitsKey(bit uint, section uint64, head types.Hash) []byte {
+	key := make([]byte, 2, 2+8+types.HashLength)
+	binary.BigEndian.PutUint16(key, uint16(bit))
+
+	key = append(key, s.encodeUint(section)...)
+
+	return append(key, head.Bytes()...)
+}
+
+// WriteBloomBits writes the compressed bit vector of the section
+func (s *KeyValueStorage) WriteBloomBits(bit uint, section uint64, head types.Hash, bits []byte) error {
+	return s.set(BLOOM_BITS, s.bloomBitsKey(bit, section, head), bits)
+}
+
+// ReadBloomBits reads the compressed bit vector of the section
+func (s *KeyValueStorage) ReadBloomBits(bit uint, section uint64, head types.Hash) ([]byte, bool) {
+	return s.get(BLOOM_BITS, s.bloomBitsKey(bit, section, head))
+}
+
+// WriteBloomSectionHead writes the head hash of the indexed section
+func (s *KeyValueStorage) WriteBloomSectionHead(section uint64, head types.Hash) error {
+	return s.set(BLOOM_SECTIONS, s.encodeUint(section), head.Bytes())
+}
+
+// ReadBloomSectionHead reads the head hash of the indexed section
+func (s *KeyValueStorage) ReadBloomSectionHead(section uint64) (types.Hash, bool) {
+	data, ok := s.get(BLOOM_SECTIONS, s.encodeUint(section))
+	if !ok {
+		return types.Hash{}, false
+	}
+
+	return types.BytesToHash(data), true
+}
+
+// WriteBloomSections writes the number of the indexed sections
+func (s *KeyValueStorage) WriteBloomSections(sections uint64) error {
+	return s.set(BLOOM_SECTIONS, EMPTY, s.encodeUint(sections))
+}
+
+// ReadBloomSections reads the number of the indexed sections
+func (s *KeyValueStorage) ReadBloomSections() (uint64, bool) {
+	data, ok := s.get(BLOOM_SECTIONS, EMPTY)
+	if !ok || len(data) != 8 {
+		return 0, false
+	}
+
+	return s.decodeUint(data), true
+}
+
 // WRITE OPERATIONS //
 
 func (s *KeyValueStorage) writeRLP(p, k []byte, raw types.RLPMarshaler) error {
@@ -279,8 +339,7 @@ func (s *KeyValueStorage) writeRLP(p, k []byte, raw types.RLPMarshaler) error {
 }
 
 func (s *KeyValueStorage) readRLP(p, k []byte, raw types.RLPUnmarshaler) error {
-	p = append(p, k...)
-	data, ok, err := s.db.Get(p)
+	data, ok, err := s.db.Get(s.key(p, k))
 
 	if err != nil {
 		return err
@@ -325,15 +384,21 @@ func (s *KeyValueStorage) write2(p, k []byte, v *fastrlp.Value) error {
 	return s.set(p, k, dst)
 }
 
-func (s *KeyValueStorage) set(p []byte, k []byte, v []byte) error {
-	p = append(p, k...)
+// key joins the prefix and the key into a new slice, never appending to the shared
+// prefix, which is not safe for the concurrent reads and writes
+func (s *KeyValueStorage) key(p []byte, k []byte) []byte {
+	key := make([]byte, 0, len(p)+len(k))
+	key = append(key, p...)
+
+	return append(key, k...)
+}
 
-	return s.db.Set(p, v)
+func (s *KeyValueStorage) set(p []byte, k []byte, v []byte) error {
+	return s.db.Set(s.key(p, k), v)
 }
 
 func (s *KeyValueStorage) get(p []byte, k []byte) ([]byte, bool) {
-	p = append(p, k...)
-	data, ok, err := s.db.Get(p)
+	data, ok, err := s.db.Get(s.key(p, k))
 
 	if err != nil {
 		return nil, false
@@ -1,6 +1,8 @@
 package kvstorage
 
 import (
+	"sync"
+
 	"github.com/dogechain-lab/dogechain/blockchain/storage"
 	"github.com/dogechain-lab/dogechain/helper/hex"
 	"github.com/hashicorp/go-hclog"
@@ -11,7 +13,7 @@ type memoryStorageBuilder struct {
 }
 
 func (builder *memoryStorageBuilder) Build() (storage.Storage, error) {
-	db := &memoryKV{map[string][]byte{}}
+	db := &memoryKV{db: map[string][]byte{}}
 
 	return newKeyValueStorage(builder.logger, db), nil
 }
@@ -25,16 +27,23 @@ func NewMemoryStorageBuilder(logger hclog.Logger) storage.StorageBuilder {
 
 // memoryKV is an in memory implementation of the kv storage
 type memoryKV struct {
-	db map[string][]byte
+	lock sync.RWMutex
+	db   map[string][]byte
 }
 
 func (m *memoryKV) Set(p []byte, v []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	m.db[hex.EncodeToHex(p)] = v
 
 	return nil
 }
 
 func (m *memoryKV) Get(p []byte) ([]byte, bool, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
 	v, ok := m.db[hex.EncodeToHex(p)]
 	if !ok {
 		return nil, false, nil
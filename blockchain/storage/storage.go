@@ -41,6 +41,13 @@ type Storage interface {
 	WriteTxLookup(hash types.Hash, blockHash types.Hash) error
 	ReadTxLookup(hash types.Hash) (types.Hash, bool)
 
+	WriteBloomBits(bit uint, section uint64, head types.Hash, bits []byte) error
+	ReadBloomBits(bit uint, section uint64, head types.Hash) ([]byte, bool)
+	WriteBloomSectionHead(section uint64, head types.Hash) error
+	ReadBloomSectionHead(section uint64) (types.Hash, bool)
+	WriteBloomSections(sections uint64) error
+	ReadBloomSections() (uint64, bool)
+
 	Close() error
 }
 
@@ -49,6 +49,9 @@ func TestStorage(t *testing.T, m PlaceholderStorage) {
 	t.Run("", func(t *testing.T) {
 		testReceipts(t, m)
 	})
+	t.Run("", func(t *testing.T) {
+		testBloomBits(t, m)
+	})
 }
 
 func testCanonicalChain(t *testing.T, m PlaceholderStorage) {
@@ -453,6 +456,39 @@ func testWriteCanonicalHeader(t *testing.T, m PlaceholderStorage) {
 	}
 }
 
+func testBloomBits(t *testing.T, m PlaceholderStorage) {
+	t.Helper()
+
+	s, closeFn := m(t)
+	defer closeFn()
+
+	_, ok := s.ReadBloomSections()
+	assert.False(t, ok)
+
+	assert.NoError(t, s.WriteBloomBits(7, 1, hash1, []byte{0x1, 0x2}))
+	assert.NoError(t, s.WriteBloomSectionHead(1, hash1))
+	assert.NoError(t, s.WriteBloomSections(2))
+
+	bits, ok := s.ReadBloomBits(7, 1, hash1)
+	assert.True(t, ok)
+	assert.Equal(t, []byte{0x1, 0x2}, bits)
+
+	// the bits of the other heads are not found
+	_, ok = s.ReadBloomBits(7, 1, hash2)
+	assert.False(t, ok)
+
+	head, ok := s.ReadBloomSectionHead(1)
+	assert.True(t, ok)
+	assert.Equal(t, hash1, head)
+
+	_, ok = s.ReadBloomSectionHead(0)
+	assert.False(t, ok)
+
+	sections, ok := s.ReadBloomSections()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(2), sections)
+}
+
 // Storage delegators
 
 type readCanonicalHashDelegate func(uint64) (types.Hash, bool)
@@ -474,29 +510,41 @@ type writeReceiptsDelegate func(types.Hash, []*types.Receipt) error
 type readReceiptsDelegate func(types.Hash) ([]*types.Receipt, error)
 type writeTxLookupDelegate func(types.Hash, types.Hash) error
 type readTxLookupDelegate func(types.Hash) (types.Hash, bool)
+type writeBloomBitsDelegate func(uint, uint64, types.Hash, []byte) error
+type readBloomBitsDelegate func(uint, uint64, types.Hash) ([]byte, bool)
+type writeBloomSectionHeadDelegate func(uint64, types.Hash) error
+type readBloomSectionHeadDelegate func(uint64) (types.Hash, bool)
+type writeBloomSectionsDelegate func(uint64) error
+type readBloomSectionsDelegate func() (uint64, bool)
 type closeDelegate func() error
 
 type MockStorage struct {
-	readCanonicalHashFn    readCanonicalHashDelegate
-	writeCanonicalHashFn   writeCanonicalHashDelegate
-	readHeadHashFn         readHeadHashDelegate
-	readHeadNumberFn       readHeadNumberDelegate
-	writeHeadHashFn        writeHeadHashDelegate
-	writeHeadNumberFn      writeHeadNumberDelegate
-	writeForksFn           writeForksDelegate
-	readForksFn            readForksDelegate
-	writeTotalDifficultyFn writeTotalDifficultyDelegate
-	readTotalDifficultyFn  readTotalDifficultyDelegate
-	writeHeaderFn          writeHeaderDelegate
-	readHeaderFn           readHeaderDelegate
-	writeCanonicalHeaderFn writeCanonicalHeaderDelegate
-	writeBodyFn            writeBodyDelegate
-	readBodyFn             readBodyDelegate
-	writeReceiptsFn        writeReceiptsDelegate
-	readReceiptsFn         readReceiptsDelegate
-	writeTxLookupFn        writeTxLookupDelegate
-	readTxLookupFn         readTxLookupDelegate
-	closeFn                closeDelegate
+	readCanonicalHashFn     readCanonicalHashDelegate
+	writeCanonicalHashFn    writeCanonicalHashDelegate
+	readHeadHashFn          readHeadHashDelegate
+	readHeadNumberFn        readHeadNumberDelegate
+	writeHeadHashFn         writeHeadHashDelegate
+	writeHeadNumberFn       writeHeadNumberDelegate
+	writeForksFn            writeForksDelegate
+	readForksFn             readForksDelegate
+	writeTotalDifficultyFn  writeTotalDifficultyDelegate
+	readTotalDifficultyFn   readTotalDifficultyDelegate
+	writeHeaderFn           writeHeaderDelegate
+	readHeaderFn            readHeaderDelegate
+	writeCanonicalHeaderFn  writeCanonicalHeaderDelegate
+	writeBodyFn             writeBodyDelegate
+	readBodyFn              readBodyDelegate
+	writeReceiptsFn         writeReceiptsDelegate
+	readReceiptsFn          readReceiptsDelegate
+	writeTxLookupFn         writeTxLookupDelegate
+	readTxLookupFn          readTxLookupDelegate
+	writeBloomBitsFn        writeBloomBitsDelegate
+	readBloomBitsFn         readBloomBitsDelegate
+	writeBloomSectionHeadFn writeBloomSectionHeadDelegate
+	readBloomSectionHeadFn  readBloomSectionHeadDelegate
+	writeBloomSectionsFn    writeBloomSectionsDelegate
+	readBloomSectionsFn     readBloomSectionsDelegate
+	closeFn                 closeDelegate
 }
 
 func NewMockStorage() *MockStorage {
@@ -731,6 +779,78 @@ func (m *MockStorage) HookReadTxLookup(fn readTxLookupDelegate) {
 	m.readTxLookupFn = fn
 }
 
+func (m *MockStorage) WriteBloomBits(bit uint, section uint64, head types.Hash, bits []byte) error {
+	if m.writeBloomBitsFn != nil {
+		return m.writeBloomBitsFn(bit, section, head, bits)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteBloomBits(fn writeBloomBitsDelegate) {
+	m.writeBloomBitsFn = fn
+}
+
+func (m *MockStorage) ReadBloomBits(bit uint, section uint64, head types.Hash) ([]byte, bool) {
+	if m.readBloomBitsFn != nil {
+		return m.readBloomBitsFn(bit, section, head)
+	}
+
+	return nil, false
+}
+
+func (m *MockStorage) HookReadBloomBits(fn readBloomBitsDelegate) {
+	m.readBloomBitsFn = fn
+}
+
+func (m *MockStorage) WriteBloomSectionHead(section uint64, head types.Hash) error {
+	if m.writeBloomSectionHeadFn != nil {
+		return m.writeBloomSectionHeadFn(section, head)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteBloomSectionHead(fn writeBloomSectionHeadDelegate) {
+	m.writeBloomSectionHeadFn = fn
+}
+
+func (m *MockStorage) ReadBloomSectionHead(section uint64) (types.Hash, bool) {
+	if m.readBloomSectionHeadFn != nil {
+		return m.readBloomSectionHeadFn(section)
+	}
+
+	return types.Hash{}, false
+}
+
+func (m *MockStorage) HookReadBloomSectionHead(fn readBloomSectionHeadDelegate) {
+	m.readBloomSectionHeadFn = fn
+}
+
+func (m *MockStorage) WriteBloomSections(sections uint64) error {
+	if m.writeBloomSectionsFn != nil {
+		return m.writeBloomSectionsFn(sections)
+	}
+
+	return nil
+}
+
+func (m *MockStorage) HookWriteBloomSections(fn writeBloomSectionsDelegate) {
+	m.writeBloomSectionsFn = fn
+}
+
+func (m *MockStorage) ReadBloomSections() (uint64, bool) {
+	if m.readBloomSectionsFn != nil {
+		return m.readBloomSectionsFn()
+	}
+
+	return 0, false
+}
+
+func (m *MockStorage) HookReadBloomSections(fn readBloomSectionsDelegate) {
+	m.readBloomSectionsFn = fn
+}
+
 func (m *MockStorage) Close() error {
 	if m.closeFn != nil {
 		return m.closeFn()
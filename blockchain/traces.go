@@ -0,0 +1,153 @@
+package blockchain
+
+import (
+	"errors"
+
+	"github.com/dogechain-lab/dogechain/state/tracer/flat"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+// tracesQueueSize is the maximum number of the written blocks waiting for
+// the traces indexing, the blocks out of it are replayed on demand
+const tracesQueueSize = 64
+
+// SetTraceStore enables recording the flat call traces of the written blocks
+func (b *Blockchain) SetTraceStore(store TraceStore) {
+	if store == nil {
+		return
+	}
+
+	b.traceStore = store
+	b.tracesCh = make(chan *types.Block, tracesQueueSize)
+
+	b.tracesWg.Add(1)
+
+	go b.indexBlockTraces()
+}
+
+// ReadBlockTraces returns the recorded flat call traces of the block
+func (b *Blockchain) ReadBlockTraces(hash types.Hash) ([]*flat.TxTraces, bool) {
+	if b.traceStore == nil {
+		return nil, false
+	}
+
+	return b.traceStore.ReadBlockTraces(hash)
+}
+
+// collectBlockTraces maps the traces of the tracer to the executed transactions.
+// The transactions exceeding the block gas limit are never executed, so that they
+// have no traces at all.
+func (b *Blockchain) collectBlockTraces(
+	block *types.Block,
+	executed []*types.Transaction,
+	tracer *flat.Tracer,
+) []*flat.TxTraces {
+	positions := make(map[types.Hash]uint64, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		positions[tx.Hash()] = uint64(i)
+	}
+
+	traced := make([]*types.Transaction, 0, len(executed))
+
+	for _, tx := range executed {
+		if !tx.ExceedsBlockGasLimit(block.Header.GasLimit) {
+			traced = append(traced, tx)
+		}
+	}
+
+	traces := tracer.Traces()
+	if len(traces) != len(traced) {
+		b.logger.Warn("block traces mismatch",
+			"number", block.Number(), "txs", len(traced), "traces", len(traces))
+
+		return nil
+	}
+
+	txTraces := make([]*flat.TxTraces, 0, len(traced))
+
+	for i, tx := range traced {
+		hash := tx.Hash()
+
+		txTraces = append(txTraces, &flat.TxTraces{
+			TxHash:     hash,
+			TxPosition: positions[hash],
+			Traces:     traces[i],
+		})
+	}
+
+	return txTraces
+}
+
+// writeBlockTraces persists the traces of the block. The traces missing in the
+// cache, such as the ones of the blocks built by this node, are indexed in
+// background, so that the block is never executed again in the write.
+func (b *Blockchain) writeBlockTraces(block *types.Block) error {
+	if found, err := b.writeCachedBlockTraces(block); found || err != nil {
+		return err
+	}
+
+	select {
+	case b.tracesCh <- block:
+	default:
+		b.logger.Warn("block traces queue is full, replayed on demand", "number", block.Number())
+	}
+
+	return nil
+}
+
+// indexBlockTraces replays the queued blocks for the traces, until the queue
+// is closed. The replay never commits the state.
+func (b *Blockchain) indexBlockTraces() {
+	defer b.tracesWg.Done()
+
+	for block := range b.tracesCh {
+		if b.isStopped() {
+			continue
+		}
+
+		found, err := b.writeCachedBlockTraces(block)
+		if !found && err == nil {
+			err = b.replayBlockTraces(block)
+		}
+
+		if err != nil && !errors.Is(err, ErrClosed) {
+			b.logger.Error("failed to index block traces", "number", block.Number(), "err", err)
+		}
+	}
+}
+
+// replayBlockTraces replays the block, and persists its traces
+func (b *Blockchain) replayBlockTraces(block *types.Block) error {
+	traces, err := b.traceBlockTransactions(block)
+	if err != nil {
+		return err
+	}
+
+	if traces == nil {
+		// mismatched, replayed on demand
+		return nil
+	}
+
+	return b.traceStore.WriteBlockTraces(block.Hash(), traces)
+}
+
+// writeCachedBlockTraces persists the cached traces of the block, and returns
+// whether they are found in the cache
+func (b *Blockchain) writeCachedBlockTraces(block *types.Block) (bool, error) {
+	cached, ok := b.tracesCache.Get(block.Hash())
+	if !ok {
+		return false, nil
+	}
+
+	traces, ok := cached.([]*flat.TxTraces)
+	if !ok {
+		return true, errors.New("invalid type assertion for traces")
+	}
+
+	if traces == nil {
+		// mismatched, replayed on demand
+		return true, nil
+	}
+
+	return true, b.traceStore.WriteBlockTraces(block.Hash(), traces)
+}
@@ -0,0 +1,186 @@
+package blockchain
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/state"
+	"github.com/dogechain-lab/dogechain/state/tracer/flat"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTraceStore struct {
+	sync.Mutex
+
+	traces map[types.Hash][]*flat.TxTraces
+}
+
+func (m *mockTraceStore) WriteBlockTraces(hash types.Hash, traces []*flat.TxTraces) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.traces[hash] = traces
+
+	return nil
+}
+
+func (m *mockTraceStore) ReadBlockTraces(hash types.Hash) ([]*flat.TxTraces, bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	traces, ok := m.traces[hash]
+
+	return traces, ok
+}
+
+func TestBlockchain_BlockTraces(t *testing.T) {
+	b, err := NewMockBlockchain(nil)
+	assert.NoError(t, err)
+
+	newTx := func(nonce, gas uint64) *types.Transaction {
+		return &types.Transaction{
+			Nonce:    nonce,
+			Gas:      gas,
+			GasPrice: big.NewInt(0),
+			Value:    big.NewInt(0),
+		}
+	}
+
+	block := &types.Block{
+		Header: &types.Header{
+			Number:   1,
+			GasLimit: 100000,
+		},
+		Transactions: []*types.Transaction{
+			newTx(0, 21000),
+			newTx(1, 200000), // exceeds the block gas limit
+			newTx(2, 21000),
+		},
+	}
+	block.Header.ComputeHash()
+
+	newTracer := func(txs int) *flat.Tracer {
+		tracer := flat.NewTracer()
+		for i := 0; i < txs; i++ {
+			tracer.CaptureTxStart(nil, nil, 0)
+		}
+
+		return tracer
+	}
+
+	// the traces are disabled by default
+	_, ok := b.ReadBlockTraces(block.Hash())
+	assert.False(t, ok)
+
+	store := &mockTraceStore{traces: make(map[types.Hash][]*flat.TxTraces)}
+	b.SetTraceStore(store)
+
+	// the transactions exceeding the block gas limit are never executed
+	traces := b.collectBlockTraces(block, block.Transactions, newTracer(2))
+	if assert.Len(t, traces, 2) {
+		assert.Equal(t, block.Transactions[0].Hash(), traces[0].TxHash)
+		assert.Equal(t, uint64(0), traces[0].TxPosition)
+		assert.Equal(t, block.Transactions[2].Hash(), traces[1].TxHash)
+		assert.Equal(t, uint64(2), traces[1].TxPosition)
+	}
+
+	// the mismatched traces are dropped
+	assert.Nil(t, b.collectBlockTraces(block, block.Transactions, newTracer(3)))
+
+	b.tracesCache.Add(block.Hash(), traces)
+	assert.NoError(t, b.writeBlockTraces(block))
+
+	read, ok := b.ReadBlockTraces(block.Hash())
+	assert.True(t, ok)
+	assert.Equal(t, traces, read)
+}
+
+func TestBlockchain_IndexBlockTraces(t *testing.T) {
+	b, err := NewMockBlockchain(nil)
+	assert.NoError(t, err)
+
+	store := &mockTraceStore{traces: make(map[types.Hash][]*flat.TxTraces)}
+
+	// the worker is not running, so that the queue is never drained
+	b.traceStore = store
+	b.tracesCh = make(chan *types.Block, 1)
+
+	block := &types.Block{
+		Header: &types.Header{
+			Number: 1,
+		},
+	}
+	block.Header.ComputeHash()
+
+	// the traces missing in the cache are queued instead of executing the block
+	assert.NoError(t, b.writeBlockTraces(block))
+	assert.Len(t, b.tracesCh, 1)
+
+	_, ok := b.ReadBlockTraces(block.Hash())
+	assert.False(t, ok)
+
+	// the block out of the queue is replayed on demand
+	assert.NoError(t, b.writeBlockTraces(block))
+	assert.Len(t, b.tracesCh, 1)
+
+	// the queued block is indexed in background
+	traces := []*flat.TxTraces{}
+	b.tracesCache.Add(block.Hash(), traces)
+
+	b.tracesWg.Add(1)
+
+	go b.indexBlockTraces()
+
+	close(b.tracesCh)
+	b.tracesWg.Wait()
+
+	read, ok := b.ReadBlockTraces(block.Hash())
+	assert.True(t, ok)
+	assert.Equal(t, traces, read)
+}
+
+func TestBlockchain_ReplayBlockTraces(t *testing.T) {
+	executions := 0
+
+	b, err := NewMockBlockchain(map[TestCallbackType]interface{}{
+		ExecutorCallback: func(executor *mockExecutor) {
+			executor.HookProcessTransction(
+				func(txn *state.Transition, _ uint64, _ []*types.Transaction) (*state.Transition, error) {
+					executions++
+
+					return txn, nil
+				},
+			)
+		},
+	})
+	assert.NoError(t, err)
+
+	parent := &types.Header{Number: 0}
+	parent.ComputeHash()
+
+	assert.NoError(t, b.db.WriteHeader(parent))
+
+	block := &types.Block{
+		Header: &types.Header{
+			Number:     1,
+			ParentHash: parent.Hash,
+		},
+	}
+	block.Header.ComputeHash()
+
+	store := &mockTraceStore{traces: make(map[types.Hash][]*flat.TxTraces)}
+	b.traceStore = store
+
+	// the block is replayed without committing, the receipts are never cached
+	assert.NoError(t, b.replayBlockTraces(block))
+	assert.Equal(t, 2, executions)
+
+	_, ok := b.receiptsCache.Get(block.Hash())
+	assert.False(t, ok)
+
+	read, ok := b.ReadBlockTraces(block.Hash())
+	assert.True(t, ok)
+	assert.Empty(t, read)
+}
@@ -0,0 +1,63 @@
+package tracestore
+
+import (
+	"encoding/json"
+
+	"github.com/dogechain-lab/dogechain/helper/kvdb"
+	"github.com/dogechain-lab/dogechain/state/tracer/flat"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	// blockTracesPrefix is the prefix for the flat call traces of the blocks
+	blockTracesPrefix = []byte("t")
+)
+
+// Store persists the flat call traces of the blocks in a dedicated kv database,
+// keyed by the block hash, so that the traces of the reorged blocks are never read
+// through the canonical chain
+type Store struct {
+	db kvdb.KVStorage
+}
+
+// NewStore creates the trace store on top of the kv database
+func NewStore(db kvdb.KVStorage) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) key(hash types.Hash) []byte {
+	key := make([]byte, 0, len(blockTracesPrefix)+types.HashLength)
+	key = append(key, blockTracesPrefix...)
+
+	return append(key, hash.Bytes()...)
+}
+
+// WriteBlockTraces writes the traces of the transactions of the block
+func (s *Store) WriteBlockTraces(hash types.Hash, traces []*flat.TxTraces) error {
+	data, err := json.Marshal(traces)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Set(s.key(hash), data)
+}
+
+// ReadBlockTraces reads the traces of the transactions of the block
+func (s *Store) ReadBlockTraces(hash types.Hash) ([]*flat.TxTraces, bool) {
+	data, ok, err := s.db.Get(s.key(hash))
+	if err != nil || !ok {
+		return nil, false
+	}
+
+	var traces []*flat.TxTraces
+	if err := json.Unmarshal(data, &traces); err != nil {
+		return nil, false
+	}
+
+	return traces, true
+}
+
+// Close closes the kv database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,100 @@
+package tracestore
+
+import (
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/helper/kvdb"
+	"github.com/dogechain-lab/dogechain/state/tracer/flat"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	tempDir, err := os.MkdirTemp("/tmp", "tracestore-")
+	assert.NoError(t, err)
+
+	db, err := kvdb.NewLevelDBBuilder(hclog.NewNullLogger(), tempDir).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	return NewStore(db)
+}
+
+func TestStore_BlockTraces(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	from := types.StringToAddress("0x1")
+	to := types.StringToAddress("0x2")
+	created := types.StringToAddress("0x3")
+
+	traces := []*flat.TxTraces{
+		{
+			TxHash:     types.StringToHash("0x10"),
+			TxPosition: 0,
+			Traces: []*flat.Trace{
+				{
+					Type: flat.TraceTypeCall,
+					Action: flat.Action{
+						CallType: "call",
+						From:     from,
+						To:       &to,
+						Gas:      1000,
+						Input:    []byte{0x1},
+						Value:    big.NewInt(1),
+					},
+					Result:       &flat.Result{GasUsed: 100, Output: []byte{0x2}},
+					Subtraces:    1,
+					TraceAddress: []int{},
+				},
+				{
+					Type: flat.TraceTypeCreate,
+					Action: flat.Action{
+						From:  to,
+						Gas:   500,
+						Value: big.NewInt(0),
+					},
+					Result:       &flat.Result{GasUsed: 50, Address: &created},
+					TraceAddress: []int{0},
+				},
+			},
+		},
+		{
+			TxHash:     types.StringToHash("0x20"),
+			TxPosition: 2,
+			Traces: []*flat.Trace{
+				{
+					Type:         flat.TraceTypeCall,
+					Action:       flat.Action{CallType: "call", From: from, To: &to, Value: big.NewInt(0)},
+					Error:        "Reverted",
+					TraceAddress: []int{},
+				},
+			},
+		},
+	}
+
+	hash := types.StringToHash("0x100")
+
+	_, ok := store.ReadBlockTraces(hash)
+	assert.False(t, ok)
+
+	assert.NoError(t, store.WriteBlockTraces(hash, traces))
+
+	read, ok := store.ReadBlockTraces(hash)
+	assert.True(t, ok)
+	assert.Equal(t, traces, read)
+
+	// keyed by the block hash
+	_, ok = store.ReadBlockTraces(types.StringToHash("0x200"))
+	assert.False(t, ok)
+}
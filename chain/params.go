@@ -13,6 +13,8 @@ type Params struct {
 	BlackList            []string               `json:"blackList,omitempty"`
 	DDOSProtection       bool                   `json:"ddosProtection,omitempty"`
 	DestructiveContracts []string               `json:"destructiveContracts,omitempty"`
+	// BaseFeeToVault sends the eip-1559 base fee to the vault system contract instead of burning it
+	BaseFeeToVault bool `json:"baseFeeToVault,omitempty"`
 }
 
 func (p *Params) GetEngine() string {
@@ -37,6 +39,9 @@ type Forks struct {
 	Preportland    *Fork `json:"pre-portland,omitempty"` // test hardfork only in some test networks
 	Portland       *Fork `json:"portland,omitempty"`     // bridge hardfork
 	Detroit        *Fork `json:"detroit,omitempty"`      // pos hardfork
+	Berlin         *Fork `json:"berlin,omitempty"`       // typed transactions and access lists hardfork
+	London         *Fork `json:"london,omitempty"`       // eip-1559, eip-3529 and eip-3541 hardfork
+	Shanghai       *Fork `json:"shanghai,omitempty"`     // push0, warm coinbase and initcode limit hardfork
 }
 
 func (f *Forks) on(ff *Fork, block uint64) bool {
@@ -91,6 +96,18 @@ func (f *Forks) IsDetroit(block uint64) bool {
 	return f.active(f.Detroit, block)
 }
 
+func (f *Forks) IsBerlin(block uint64) bool {
+	return f.active(f.Berlin, block)
+}
+
+func (f *Forks) IsLondon(block uint64) bool {
+	return f.active(f.London, block)
+}
+
+func (f *Forks) IsShanghai(block uint64) bool {
+	return f.active(f.Shanghai, block)
+}
+
 func (f *Forks) At(block uint64) ForksInTime {
 	return ForksInTime{
 		Homestead:      f.active(f.Homestead, block),
@@ -104,6 +121,9 @@ func (f *Forks) At(block uint64) ForksInTime {
 		Preportland:    f.active(f.Preportland, block),
 		Portland:       f.active(f.Portland, block),
 		Detroit:        f.active(f.Detroit, block),
+		Berlin:         f.active(f.Berlin, block),
+		London:         f.active(f.London, block),
+		Shanghai:       f.active(f.Shanghai, block),
 	}
 }
 
@@ -150,7 +170,10 @@ type ForksInTime struct {
 	EIP155,
 	Preportland,
 	Portland,
-	Detroit bool
+	Detroit,
+	Berlin,
+	London,
+	Shanghai bool
 }
 
 var AllForksEnabled = &Forks{
@@ -165,4 +188,7 @@ var AllForksEnabled = &Forks{
 	Preportland:    NewFork(10000),
 	Portland:       NewFork(10222),
 	Detroit:        NewFork(40562),
+	Berlin:         NewFork(0),
+	London:         NewFork(0),
+	Shanghai:       NewFork(0),
 }
@@ -0,0 +1,33 @@
+package account
+
+import (
+	"github.com/dogechain-lab/dogechain/command/account/export"
+	"github.com/dogechain-lab/dogechain/command/account/importkey"
+	"github.com/dogechain-lab/dogechain/command/account/list"
+	"github.com/dogechain-lab/dogechain/command/account/newaccount"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	accountCmd := &cobra.Command{
+		Use:   "account",
+		Short: "Top level command for managing the encrypted keystore accounts. Only accepts subcommands.",
+	}
+
+	registerSubcommands(accountCmd)
+
+	return accountCmd
+}
+
+func registerSubcommands(baseCmd *cobra.Command) {
+	baseCmd.AddCommand(
+		// account new
+		newaccount.GetCommand(),
+		// account list
+		list.GetCommand(),
+		// account import
+		importkey.GetCommand(),
+		// account export
+		export.GetCommand(),
+	)
+}
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"github.com/dogechain-lab/dogechain/command"
+	"github.com/dogechain-lab/dogechain/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	accountExportCmd := &cobra.Command{
+		Use:     "export",
+		Short:   "Exports the decrypted private key of a keystore account to a hex encoded key file",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	params.SetFlags(accountExportCmd)
+	params.SetPasswordFlags(accountExportCmd)
+	setFlags(accountExportCmd)
+	helper.SetRequiredFlags(accountExportCmd, params.getRequiredFlags())
+
+	return accountExportCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.addressRaw,
+		addressFlag,
+		"",
+		"the address of the account to be exported",
+	)
+
+	cmd.Flags().StringVar(
+		&params.out,
+		outFlag,
+		"",
+		"the path of the exported private key file",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.validateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.exportKey(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
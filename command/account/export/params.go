@@ -0,0 +1,86 @@
+package export
+
+import (
+	"encoding/hex"
+	"errors"
+	"os"
+
+	"github.com/dogechain-lab/dogechain/command"
+	"github.com/dogechain-lab/dogechain/command/account/helper"
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+const (
+	addressFlag = "address"
+	outFlag     = "out"
+)
+
+var (
+	params = &exportParams{}
+)
+
+var (
+	errInvalidAddressFormat = errors.New("invalid address format")
+	errKeyFileExists        = errors.New("the output file already exists")
+)
+
+type exportParams struct {
+	helper.KeystoreParams
+
+	addressRaw string
+	out        string
+
+	address types.Address
+}
+
+func (p *exportParams) getRequiredFlags() []string {
+	return []string{
+		addressFlag,
+		outFlag,
+	}
+}
+
+func (p *exportParams) validateFlags() error {
+	if err := p.ValidateFlags(); err != nil {
+		return err
+	}
+
+	if err := p.address.UnmarshalText([]byte(p.addressRaw)); err != nil {
+		return errInvalidAddressFormat
+	}
+
+	return nil
+}
+
+// exportKey writes the decrypted private key in hex to the output file, which
+// could be imported again
+func (p *exportParams) exportKey() error {
+	if _, err := os.Stat(p.out); err == nil {
+		return errKeyFileExists
+	}
+
+	password, err := p.ReadPassword(false)
+	if err != nil {
+		return err
+	}
+
+	key, err := p.NewManager().ExportKey(p.address, password)
+	if err != nil {
+		return err
+	}
+
+	raw, err := crypto.MarshalPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(p.out, []byte(hex.EncodeToString(raw)), 0600)
+}
+
+func (p *exportParams) getResult() command.CommandResult {
+	return &AccountExportResult{
+		Address: p.address,
+		Out:     p.out,
+	}
+}
@@ -0,0 +1,27 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/command/helper"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+type AccountExportResult struct {
+	Address types.Address `json:"address"`
+	Out     string        `json:"out"`
+}
+
+func (r *AccountExportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[ACCOUNT EXPORT]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Address|%s", r.Address),
+		fmt.Sprintf("Private key file|%s", r.Out),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
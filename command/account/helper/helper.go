@@ -0,0 +1,126 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dogechain-lab/dogechain/accounts"
+	"github.com/dogechain-lab/dogechain/helper/keystore"
+	"github.com/howeyc/gopass"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DataDirFlag      = "data-dir"
+	KeystoreFlag     = "keystore"
+	PasswordFileFlag = "password-file"
+	LightKDFFlag     = "lightkdf"
+)
+
+var (
+	ErrInvalidParams    = errors.New("no keystore or data directory passed in")
+	ErrPasswordMismatch = errors.New("passwords do not match")
+)
+
+// KeystoreParams are the common params of the account commands
+type KeystoreParams struct {
+	DataDir      string
+	KeystoreDir  string
+	PasswordFile string
+	LightKDF     bool
+}
+
+// SetFlags registers the keystore directory flags of the command
+func (p *KeystoreParams) SetFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&p.DataDir,
+		DataDirFlag,
+		"",
+		"the data directory of the node, whose keystore directory is used",
+	)
+
+	cmd.Flags().StringVar(
+		&p.KeystoreDir,
+		KeystoreFlag,
+		"",
+		"the keystore directory of the accounts, which overrides the one under the data directory",
+	)
+}
+
+// SetPasswordFlags registers the password flags of the command
+func (p *KeystoreParams) SetPasswordFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&p.PasswordFile,
+		PasswordFileFlag,
+		"",
+		"the file containing the password of the account, the password is prompted if omitted",
+	)
+}
+
+// SetKDFFlags registers the key derivation flags of the command which encrypts the keys
+func (p *KeystoreParams) SetKDFFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&p.LightKDF,
+		LightKDFFlag,
+		false,
+		"encrypt the key files with the weaker but faster scrypt parameters, for the test networks only",
+	)
+}
+
+// ValidateFlags checks whether the keystore directory is given
+func (p *KeystoreParams) ValidateFlags() error {
+	if p.DataDir == "" && p.KeystoreDir == "" {
+		return ErrInvalidParams
+	}
+
+	return nil
+}
+
+// NewManager returns the account manager of the keystore directory
+func (p *KeystoreParams) NewManager() *accounts.Manager {
+	dir := p.KeystoreDir
+	if dir == "" {
+		dir = filepath.Join(p.DataDir, accounts.DefaultKeystoreDir)
+	}
+
+	if p.LightKDF {
+		return accounts.NewManager(dir, keystore.LightScryptN, keystore.LightScryptP)
+	}
+
+	return accounts.NewManager(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+}
+
+// ReadPassword reads the password from the password file, or prompts for it.
+// The prompted password is asked twice if it should be confirmed.
+func (p *KeystoreParams) ReadPassword(confirm bool) (string, error) {
+	if p.PasswordFile != "" {
+		raw, err := os.ReadFile(p.PasswordFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the password file, %w", err)
+		}
+
+		// only the first line is the password
+		return strings.TrimRight(strings.SplitN(string(raw), "\n", 2)[0], "\r"), nil
+	}
+
+	password, err := gopass.GetPasswdPrompt("Password: ", false, os.Stdin, os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	if confirm {
+		repeated, err := gopass.GetPasswdPrompt("Repeat password: ", false, os.Stdin, os.Stderr)
+		if err != nil {
+			return "", err
+		}
+
+		if string(password) != string(repeated) {
+			return "", ErrPasswordMismatch
+		}
+	}
+
+	return string(password), nil
+}
@@ -0,0 +1,48 @@
+package importkey
+
+import (
+	"github.com/dogechain-lab/dogechain/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	accountImportCmd := &cobra.Command{
+		Use:     "import",
+		Short:   "Imports a hex encoded private key into the keystore, encrypted by the password",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	params.SetFlags(accountImportCmd)
+	params.SetPasswordFlags(accountImportCmd)
+	params.SetKDFFlags(accountImportCmd)
+	setFlags(accountImportCmd)
+
+	return accountImportCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.keyFile,
+		keyFileFlag,
+		"",
+		"the file containing the hex encoded private key, the key is prompted if omitted",
+	)
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.ValidateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.importKey(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,80 @@
+package importkey
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dogechain-lab/dogechain/command"
+	"github.com/dogechain-lab/dogechain/command/account/helper"
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/howeyc/gopass"
+)
+
+const (
+	keyFileFlag = "key-file"
+)
+
+var (
+	params = &importParams{}
+)
+
+type importParams struct {
+	helper.KeystoreParams
+
+	keyFile string
+
+	address types.Address
+}
+
+func (p *importParams) importKey() error {
+	rawKey, err := p.readKey()
+	if err != nil {
+		return err
+	}
+
+	key, err := crypto.BytesToPrivateKey([]byte(strings.TrimPrefix(strings.TrimSpace(rawKey), "0x")))
+	if err != nil {
+		return fmt.Errorf("invalid private key, %w", err)
+	}
+
+	password, err := p.ReadPassword(true)
+	if err != nil {
+		return err
+	}
+
+	address, err := p.NewManager().ImportKey(key, password)
+	if err != nil {
+		return err
+	}
+
+	p.address = address
+
+	return nil
+}
+
+// readKey reads the hex encoded private key from the key file, or prompts for it
+func (p *importParams) readKey() (string, error) {
+	if p.keyFile != "" {
+		raw, err := os.ReadFile(p.keyFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the key file, %w", err)
+		}
+
+		return string(raw), nil
+	}
+
+	raw, err := gopass.GetPasswdPrompt("Private key (hex): ", false, os.Stdin, os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
+
+func (p *importParams) getResult() command.CommandResult {
+	return &AccountImportResult{
+		Address: p.address,
+	}
+}
@@ -0,0 +1,25 @@
+package importkey
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/command/helper"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+type AccountImportResult struct {
+	Address types.Address `json:"address"`
+}
+
+func (r *AccountImportResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[ACCOUNT IMPORT]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Address|%s", r.Address),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
@@ -0,0 +1,36 @@
+package list
+
+import (
+	"github.com/dogechain-lab/dogechain/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	accountListCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "Lists the addresses of the accounts in the keystore",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	params.SetFlags(accountListCmd)
+
+	return accountListCmd
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.ValidateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.listAccounts(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
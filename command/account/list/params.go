@@ -0,0 +1,34 @@
+package list
+
+import (
+	"github.com/dogechain-lab/dogechain/command"
+	"github.com/dogechain-lab/dogechain/command/account/helper"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	params = &listParams{}
+)
+
+type listParams struct {
+	helper.KeystoreParams
+
+	addresses []types.Address
+}
+
+func (p *listParams) listAccounts() error {
+	addresses, err := p.NewManager().Accounts()
+	if err != nil {
+		return err
+	}
+
+	p.addresses = addresses
+
+	return nil
+}
+
+func (p *listParams) getResult() command.CommandResult {
+	return &AccountListResult{
+		Addresses: p.addresses,
+	}
+}
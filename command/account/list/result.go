@@ -0,0 +1,34 @@
+package list
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/command/helper"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+type AccountListResult struct {
+	Addresses []types.Address `json:"addresses"`
+}
+
+func (r *AccountListResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[ACCOUNT LIST]\n")
+
+	if len(r.Addresses) == 0 {
+		buffer.WriteString("No accounts found")
+	} else {
+		rows := make([]string, len(r.Addresses))
+		for i, address := range r.Addresses {
+			rows[i] = fmt.Sprintf("Account #%d|%s", i, address)
+		}
+
+		buffer.WriteString(helper.FormatKV(rows))
+	}
+
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
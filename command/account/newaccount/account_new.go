@@ -0,0 +1,38 @@
+package newaccount
+
+import (
+	"github.com/dogechain-lab/dogechain/command"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	accountNewCmd := &cobra.Command{
+		Use:     "new",
+		Short:   "Creates a new account, whose private key is encrypted by the password in the keystore",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	params.SetFlags(accountNewCmd)
+	params.SetPasswordFlags(accountNewCmd)
+	params.SetKDFFlags(accountNewCmd)
+
+	return accountNewCmd
+}
+
+func runPreRun(_ *cobra.Command, _ []string) error {
+	return params.ValidateFlags()
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.newAccount(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
@@ -0,0 +1,44 @@
+package newaccount
+
+import (
+	"github.com/dogechain-lab/dogechain/command"
+	"github.com/dogechain-lab/dogechain/command/account/helper"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	params = &newParams{}
+)
+
+type newParams struct {
+	helper.KeystoreParams
+
+	address types.Address
+	dir     string
+}
+
+func (p *newParams) newAccount() error {
+	password, err := p.ReadPassword(true)
+	if err != nil {
+		return err
+	}
+
+	manager := p.NewManager()
+
+	address, err := manager.NewAccount(password)
+	if err != nil {
+		return err
+	}
+
+	p.address = address
+	p.dir = manager.Dir()
+
+	return nil
+}
+
+func (p *newParams) getResult() command.CommandResult {
+	return &AccountNewResult{
+		Address:     p.address,
+		KeystoreDir: p.dir,
+	}
+}
@@ -0,0 +1,27 @@
+package newaccount
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/command/helper"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+type AccountNewResult struct {
+	Address     types.Address `json:"address"`
+	KeystoreDir string        `json:"keystore"`
+}
+
+func (r *AccountNewResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[ACCOUNT NEW]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Address|%s", r.Address),
+		fmt.Sprintf("Keystore|%s", r.KeystoreDir),
+	}))
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
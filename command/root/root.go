@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/dogechain-lab/dogechain/command/account"
 	"github.com/dogechain-lab/dogechain/command/backup"
 	"github.com/dogechain-lab/dogechain/command/genesis"
 	"github.com/dogechain-lab/dogechain/command/helper"
@@ -13,6 +14,7 @@ import (
 	"github.com/dogechain-lab/dogechain/command/monitor"
 	"github.com/dogechain-lab/dogechain/command/peers"
 	"github.com/dogechain-lab/dogechain/command/reverify"
+	"github.com/dogechain-lab/dogechain/command/rpcreplay"
 	"github.com/dogechain-lab/dogechain/command/secrets"
 	"github.com/dogechain-lab/dogechain/command/server"
 	"github.com/dogechain-lab/dogechain/command/status"
@@ -45,8 +47,10 @@ func (rc *RootCommand) registerSubCommands() {
 		txpool.GetCommand(),
 		status.GetCommand(),
 		secrets.GetCommand(),
+		account.GetCommand(),
 		peers.GetCommand(),
 		reverify.GetCommand(),
+		rpcreplay.GetCommand(),
 		monitor.GetCommand(),
 		loadbot.GetCommand(),
 		ibft.GetCommand(),
@@ -0,0 +1,248 @@
+package rpcreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/command/helper"
+	"github.com/dogechain-lab/dogechain/jsonrpc"
+)
+
+const (
+	captureFlag             = "capture"
+	methodsFlag             = "methods"
+	timeoutFlag             = "timeout"
+	maxDiffsFlag            = "max-diffs"
+	includeStateChangesFlag = "include-state-changes"
+	apiKeyFlag              = "api-key"
+)
+
+// apiKeyHeader is the http header carrying the api key
+const apiKeyHeader = "X-API-Key"
+
+const (
+	defaultTimeout  uint64 = 30
+	defaultMaxDiffs uint64 = 20
+)
+
+var (
+	params = &replayParams{}
+)
+
+var (
+	errInvalidTimeout = errors.New("timeout must be greater than 0")
+)
+
+type replayParams struct {
+	capturePath string
+	methodsRaw  string
+	timeout     uint64
+	maxDiffs    uint64
+	// whether the methods changing the target are replayed, such as sending the transactions
+	includeStateChanges bool
+	apiKey              string // the api key of the target, not sent if empty
+
+	target  string
+	methods map[string]struct{} // all of them if empty
+	client  *http.Client
+
+	result *ReplayResult
+}
+
+func (p *replayParams) getRequiredFlags() []string {
+	return []string{
+		captureFlag,
+	}
+}
+
+func (p *replayParams) validateFlags(jsonRPCAddress string) error {
+	if p.timeout == 0 {
+		return errInvalidTimeout
+	}
+
+	// the address of the json-rpc flag might be without the scheme
+	if !strings.Contains(jsonRPCAddress, "://") {
+		jsonRPCAddress = "http://" + jsonRPCAddress
+	}
+
+	if _, err := helper.ParseJSONRPCAddress(jsonRPCAddress); err != nil {
+		return err
+	}
+
+	p.target = jsonRPCAddress
+	p.methods = make(map[string]struct{})
+
+	for _, method := range strings.Split(p.methodsRaw, ",") {
+		if method = strings.TrimSpace(method); method != "" {
+			p.methods[method] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// replay sends the recorded requests in order, and diffs the responses
+func (p *replayParams) replay() error {
+	p.client = &http.Client{Timeout: time.Duration(p.timeout) * time.Second}
+	p.result = &ReplayResult{
+		Target: p.target,
+		Diffs:  make([]*ReplayDiff, 0),
+	}
+
+	var recordedDuration, replayedDuration int64
+
+	err := jsonrpc.ReadRecords(p.capturePath, func(entry *jsonrpc.RecordEntry) error {
+		if len(p.methods) > 0 {
+			if _, ok := p.methods[entry.Method]; !ok {
+				return nil
+			}
+		}
+
+		// the privileged calls are not replayed without the jwt auth, and the calls
+		// changing the target are only replayed on demand
+		if jsonrpc.IsPrivilegedMethod(entry.Method) ||
+			(!p.includeStateChanges && jsonrpc.IsStateChangingMethod(entry.Method)) {
+			p.result.Skipped++
+
+			return nil
+		}
+
+		p.result.Total++
+
+		start := time.Now()
+		resp, err := p.send(entry.Request)
+
+		recordedDuration += entry.Duration
+		replayedDuration += time.Since(start).Microseconds()
+
+		if err != nil {
+			p.result.Failed++
+			p.addDiff(&ReplayDiff{Method: entry.Method, Request: entry.Request, Error: err.Error()})
+
+			return nil
+		}
+
+		if equal, err := equalResponses(entry.Response, resp); err != nil || !equal {
+			diff := &ReplayDiff{
+				Method:   entry.Method,
+				Request:  entry.Request,
+				Recorded: entry.Response,
+				Replayed: resp,
+			}
+
+			if err != nil {
+				diff.Error = err.Error()
+			}
+
+			p.result.Mismatched++
+			p.addDiff(diff)
+
+			return nil
+		}
+
+		p.result.Matched++
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if p.result.Total > 0 {
+		p.result.RecordedAvgDuration = recordedDuration / int64(p.result.Total)
+		p.result.ReplayedAvgDuration = replayedDuration / int64(p.result.Total)
+	}
+
+	return nil
+}
+
+// send posts the recorded request to the target, and returns the raw response
+func (p *replayParams) send(request json.RawMessage) (json.RawMessage, error) {
+	// the version is not recorded, which is required by the other clients
+	req := map[string]json.RawMessage{}
+	if err := json.Unmarshal(request, &req); err != nil {
+		return nil, fmt.Errorf("invalid recorded request, %w", err)
+	}
+
+	if _, ok := req["jsonrpc"]; !ok {
+		req["jsonrpc"] = json.RawMessage(`"2.0"`)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, p.target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	if p.apiKey != "" {
+		httpReq.Header.Set(apiKeyHeader, p.apiKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d, %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	return data, nil
+}
+
+func (p *replayParams) addDiff(diff *ReplayDiff) {
+	if uint64(len(p.result.Diffs)) < p.maxDiffs {
+		p.result.Diffs = append(p.result.Diffs, diff)
+	}
+}
+
+func (p *replayParams) getResult() *ReplayResult {
+	return p.result
+}
+
+// equalResponses returns whether the results and the errors of the responses
+// are the same, regardless of the formatting and the key order
+func equalResponses(recorded, replayed json.RawMessage) (bool, error) {
+	var a, b struct {
+		Result interface{} `json:"result"`
+		Error  interface{} `json:"error"`
+	}
+
+	if err := unmarshalNumber(recorded, &a); err != nil {
+		return false, fmt.Errorf("invalid recorded response, %w", err)
+	}
+
+	if err := unmarshalNumber(replayed, &b); err != nil {
+		return false, fmt.Errorf("invalid replayed response, %w", err)
+	}
+
+	return reflect.DeepEqual(a, b), nil
+}
+
+// unmarshalNumber unmarshals the json, keeping the numbers as they are
+func unmarshalNumber(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decoder.Decode(v)
+}
@@ -0,0 +1,82 @@
+package rpcreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/command/helper"
+)
+
+// maxDiffOutputLength truncates the long responses in the cli output
+const maxDiffOutputLength = 512
+
+type ReplayResult struct {
+	Target              string        `json:"target"`
+	Total               uint64        `json:"total"`
+	Matched             uint64        `json:"matched"`
+	Mismatched          uint64        `json:"mismatched"`
+	Failed              uint64        `json:"failed"`
+	Skipped             uint64        `json:"skipped"`
+	RecordedAvgDuration int64         `json:"recorded_avg_duration_us"`
+	ReplayedAvgDuration int64         `json:"replayed_avg_duration_us"`
+	Diffs               []*ReplayDiff `json:"diffs"`
+}
+
+type ReplayDiff struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Recorded json.RawMessage `json:"recorded,omitempty"`
+	Replayed json.RawMessage `json:"replayed,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func (r *ReplayResult) GetOutput() string {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("\n[RPC REPLAY]\n")
+	buffer.WriteString(helper.FormatKV([]string{
+		fmt.Sprintf("Target|%s", r.Target),
+		fmt.Sprintf("Total Requests|%d", r.Total),
+		fmt.Sprintf("Matched|%d", r.Matched),
+		fmt.Sprintf("Mismatched|%d", r.Mismatched),
+		fmt.Sprintf("Failed|%d", r.Failed),
+		fmt.Sprintf("Skipped (privileged or state changing)|%d", r.Skipped),
+		fmt.Sprintf("Recorded Avg Duration (us)|%d", r.RecordedAvgDuration),
+		fmt.Sprintf("Replayed Avg Duration (us)|%d", r.ReplayedAvgDuration),
+	}))
+
+	for i, diff := range r.Diffs {
+		buffer.WriteString(fmt.Sprintf("\n\n[DIFF %d]\n", i+1))
+
+		kv := []string{
+			fmt.Sprintf("Method|%s", diff.Method),
+			fmt.Sprintf("Request|%s", truncate(diff.Request)),
+		}
+
+		if diff.Error != "" {
+			kv = append(kv, fmt.Sprintf("Error|%s", diff.Error))
+		}
+
+		if len(diff.Recorded) > 0 {
+			kv = append(kv,
+				fmt.Sprintf("Recorded|%s", truncate(diff.Recorded)),
+				fmt.Sprintf("Replayed|%s", truncate(diff.Replayed)),
+			)
+		}
+
+		buffer.WriteString(helper.FormatKV(kv))
+	}
+
+	buffer.WriteString("\n")
+
+	return buffer.String()
+}
+
+func truncate(data []byte) string {
+	if len(data) <= maxDiffOutputLength {
+		return string(data)
+	}
+
+	return string(data[:maxDiffOutputLength]) + "..."
+}
@@ -0,0 +1,85 @@
+package rpcreplay
+
+import (
+	"github.com/dogechain-lab/dogechain/command"
+	"github.com/dogechain-lab/dogechain/command/helper"
+	"github.com/spf13/cobra"
+)
+
+func GetCommand() *cobra.Command {
+	rpcReplayCmd := &cobra.Command{
+		Use: "rpc-replay",
+		Short: "Replays the json-rpc capture files recorded by a node against the json-rpc interface, " +
+			"and diffs the responses",
+		PreRunE: runPreRun,
+		Run:     runCommand,
+	}
+
+	helper.RegisterJSONRPCFlag(rpcReplayCmd)
+	helper.SetRequiredFlags(rpcReplayCmd, params.getRequiredFlags())
+
+	setFlags(rpcReplayCmd)
+
+	return rpcReplayCmd
+}
+
+func setFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&params.capturePath,
+		captureFlag,
+		"",
+		"the capture file, or the directory of the capture files replayed from the oldest one",
+	)
+
+	cmd.Flags().StringVar(
+		&params.methodsRaw,
+		methodsFlag,
+		"",
+		"the comma separated json-rpc methods replayed (empty to replay all of them)",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.timeout,
+		timeoutFlag,
+		defaultTimeout,
+		"the timeout of a replayed request in seconds",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.maxDiffs,
+		maxDiffsFlag,
+		defaultMaxDiffs,
+		"the max number of the listed mismatched responses",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.includeStateChanges,
+		includeStateChangesFlag,
+		false,
+		"replay the methods changing the target too, such as eth_sendRawTransaction and the filters",
+	)
+
+	cmd.Flags().StringVar(
+		&params.apiKey,
+		apiKeyFlag,
+		"",
+		"the api key of the target json-rpc interface",
+	)
+}
+
+func runPreRun(cmd *cobra.Command, _ []string) error {
+	return params.validateFlags(helper.GetJSONRPCAddress(cmd))
+}
+
+func runCommand(cmd *cobra.Command, _ []string) {
+	outputter := command.InitializeOutputter(cmd)
+	defer outputter.WriteOutput()
+
+	if err := params.replay(); err != nil {
+		outputter.SetError(err)
+
+		return
+	}
+
+	outputter.SetCommandResult(params.getResult())
+}
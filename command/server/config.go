@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
+	"github.com/dogechain-lab/dogechain/accounts"
+	"github.com/dogechain-lab/dogechain/graphql"
 	"github.com/dogechain-lab/dogechain/helper/gasprice"
 	"github.com/dogechain-lab/dogechain/jsonrpc"
 	"github.com/dogechain-lab/dogechain/network"
@@ -32,13 +35,40 @@ type Config struct {
 	LogFilePath              string          `json:"log_to"`
 	EnableGraphQL            bool            `json:"enable_graphql"`
 	GraphQLAddr              string          `json:"graphql_addr"`
+	GraphQLMaxQueryCost      uint64          `json:"graphql_max_query_cost" yaml:"graphql_max_query_cost"`
+	GraphQLMaxQueryDepth     uint64          `json:"graphql_max_query_depth" yaml:"graphql_max_query_depth"`
+	GraphQLMaxQueryBlocks    uint64          `json:"graphql_max_query_blocks" yaml:"graphql_max_query_blocks"`
+	GraphQLQueryTimeout      uint64          `json:"graphql_query_timeout_s" yaml:"graphql_query_timeout_s"`
 	JSONRPCBatchRequestLimit uint64          `json:"json_rpc_batch_request_limit" yaml:"json_rpc_batch_request_limit"`
 	JSONRPCBlockRangeLimit   uint64          `json:"json_rpc_block_range_limit" yaml:"json_rpc_block_range_limit"`
+	JSONRPCCacheEntries      uint64          `json:"json_rpc_cache_entries" yaml:"json_rpc_cache_entries"`
+	JSONRPCCacheSize         uint64          `json:"json_rpc_cache_size_mb" yaml:"json_rpc_cache_size_mb"`
+	JSONRPCRecordDir         string          `json:"json_rpc_record_dir" yaml:"json_rpc_record_dir"`
+	JSONRPCRecordSampleRate  float64         `json:"json_rpc_record_sample_rate" yaml:"json_rpc_record_sample_rate"`
+	JSONRPCRecordMethods     string          `json:"json_rpc_record_methods" yaml:"json_rpc_record_methods"`
+	JSONRPCRecordFileSize    uint64          `json:"json_rpc_record_file_size_mb" yaml:"json_rpc_record_file_size_mb"`
+	JSONRPCRecordMaxFiles    uint64          `json:"json_rpc_record_max_files" yaml:"json_rpc_record_max_files"`
 	JSONNamespace            string          `json:"json_namespace" yaml:"json_namespace"`
 	EnableWS                 bool            `json:"enable_ws" yaml:"enable_ws"`
+	WSConnLimitPerIP         uint64          `json:"ws_conn_limit_per_ip" yaml:"ws_conn_limit_per_ip"`
+	WSTrustedProxies         []string        `json:"ws_trusted_proxies" yaml:"ws_trusted_proxies"`
+	WSSubscriptionLimit      uint64          `json:"ws_subscription_limit" yaml:"ws_subscription_limit"`
+	WSSendQueueSize          uint64          `json:"ws_send_queue_size" yaml:"ws_send_queue_size"`
+	WSSlowConsumerPolicy     string          `json:"ws_slow_consumer_policy" yaml:"ws_slow_consumer_policy"`
+	WSPingInterval           uint64          `json:"ws_ping_interval_s" yaml:"ws_ping_interval_s"`
+	WSIdleTimeout            uint64          `json:"ws_idle_timeout_s" yaml:"ws_idle_timeout_s"`
+	IPCPath                  string          `json:"ipc_path" yaml:"ipc_path"`
+	DisableIPC               bool            `json:"disable_ipc" yaml:"disable_ipc"`
+	JSONRPCAPIKeys           []*APIKey       `json:"json_rpc_api_keys" yaml:"json_rpc_api_keys"`
+	JWTSecretFile            string          `json:"jwt_secret_file" yaml:"jwt_secret_file"`
+	AllowInsecureUnlock      bool            `json:"allow_insecure_unlock" yaml:"allow_insecure_unlock"`
 	EnablePprof              bool            `json:"enable_pprof" yaml:"enable_pprof"`
 	BlockBroadcast           bool            `json:"enable_block_broadcast" yaml:"enable_block_broadcast"`
 	GPO                      gasprice.Config `json:"gas_price_oracle" yaml:"gas_price_oracle"`
+	EnableTraceStore         bool            `json:"enable_trace_store" yaml:"enable_trace_store"`
+	EnableAddressIndex       bool            `json:"enable_address_index" yaml:"enable_address_index"`
+	EnableBloomIndex         bool            `json:"enable_bloom_index" yaml:"enable_bloom_index"`
+	KeystoreDir              string          `json:"keystore" yaml:"keystore"`
 }
 
 // Telemetry holds the config details for metric services.
@@ -70,6 +100,17 @@ type TxPool struct {
 	PromoteOutdateSeconds uint64 `json:"promote_outdate_seconds"`
 }
 
+// APIKey defines the api key of a json-rpc client, and its quotas. The zero quotas
+// are unlimited.
+type APIKey struct {
+	Name              string   `json:"name"`
+	Key               string   `json:"key"`
+	RequestsPerSecond float64  `json:"requests_per_second"`
+	BatchRequestLimit uint64   `json:"batch_request_limit"`
+	BlockRangeLimit   uint64   `json:"block_range_limit"`
+	Namespaces        []string `json:"namespaces"`
+}
+
 // Headers defines the HTTP response headers required to enable CORS.
 type Headers struct {
 	AccessControlAllowOrigins []string `json:"access_control_allow_origins"`
@@ -78,6 +119,9 @@ type Headers struct {
 // minimum block generation time in seconds
 const defaultBlockTime uint64 = 2
 
+// the ipc endpoint under the data directory
+const defaultIPCPath = "dogechain.ipc"
+
 // DefaultConfig returns the default server configuration
 func DefaultConfig() *Config {
 	defaultNetworkConfig := network.DefaultConfig()
@@ -112,12 +156,37 @@ func DefaultConfig() *Config {
 		},
 		LogFilePath:              "",
 		EnableGraphQL:            false,
+		GraphQLMaxQueryCost:      graphql.DefaultMaxQueryCost,
+		GraphQLMaxQueryDepth:     graphql.DefaultMaxQueryDepth,
+		GraphQLMaxQueryBlocks:    graphql.DefaultMaxQueryBlocks,
+		GraphQLQueryTimeout:      uint64(graphql.DefaultQueryTimeout / time.Second),
 		JSONRPCBatchRequestLimit: jsonrpc.DefaultJSONRPCBatchRequestLimit,
 		JSONRPCBlockRangeLimit:   jsonrpc.DefaultJSONRPCBlockRangeLimit,
+		JSONRPCCacheEntries:      jsonrpc.DefaultJSONRPCCacheEntries,
+		JSONRPCCacheSize:         jsonrpc.DefaultJSONRPCCacheSize,
+		JSONRPCRecordDir:         "",
+		JSONRPCRecordSampleRate:  jsonrpc.DefaultJSONRPCRecordSampleRate,
+		JSONRPCRecordMethods:     "",
+		JSONRPCRecordFileSize:    jsonrpc.DefaultJSONRPCRecordFileSize,
+		JSONRPCRecordMaxFiles:    jsonrpc.DefaultJSONRPCRecordMaxFiles,
 		JSONNamespace:            string(jsonrpc.NamespaceAll),
 		EnableWS:                 false,
+		WSConnLimitPerIP:         jsonrpc.DefaultWSConnLimitPerIP,
+		WSTrustedProxies:         []string{},
+		WSSubscriptionLimit:      jsonrpc.DefaultWSSubscriptionLimit,
+		WSSendQueueSize:          jsonrpc.DefaultWSSendQueueSize,
+		WSSlowConsumerPolicy:     string(jsonrpc.DefaultWSSlowConsumerPolicy),
+		WSPingInterval:           uint64(jsonrpc.DefaultWSPingInterval / time.Second),
+		WSIdleTimeout:            uint64(jsonrpc.DefaultWSIdleTimeout / time.Second),
+		IPCPath:                  defaultIPCPath,
+		DisableIPC:               false,
+		AllowInsecureUnlock:      false,
 		EnablePprof:              false,
 		GPO:                      gasprice.Defaults,
+		EnableTraceStore:         false,
+		EnableAddressIndex:       false,
+		EnableBloomIndex:         false,
+		KeystoreDir:              accounts.DefaultKeystoreDir,
 	}
 }
 
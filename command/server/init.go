@@ -6,11 +6,15 @@ import (
 	"math"
 	"math/big"
 	"net"
+	"os"
+	"strings"
 
 	"github.com/dogechain-lab/dogechain/network/common"
 
 	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/command/helper"
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/jsonrpc"
 	"github.com/dogechain-lab/dogechain/network"
 	"github.com/dogechain-lab/dogechain/secrets"
 	"github.com/dogechain-lab/dogechain/server"
@@ -20,6 +24,9 @@ import (
 var (
 	errInvalidBlockTime       = errors.New("invalid block time specified")
 	errDataDirectoryUndefined = errors.New("data directory not defined")
+	errInvalidJWTSecret       = errors.New("invalid jwt secret, 32 bytes hex expected")
+	errAPIKeyUndefined        = errors.New("json-rpc api key not defined")
+	errDuplicateAPIKey        = errors.New("duplicate json-rpc api key")
 )
 
 func (p *serverParams) initConfigFromFile() error {
@@ -62,6 +69,10 @@ func (p *serverParams) initRawParams() error {
 
 	p.initGPOConfig()
 
+	if err := p.initJSONRPCAuth(); err != nil {
+		return err
+	}
+
 	return p.initAddresses()
 }
 
@@ -357,3 +368,51 @@ func (p *serverParams) initGPOConfig() {
 		p.rawConfig.GPO.IgnorePrice = big.NewInt(p.gpoIgnoreGasPrice)
 	}
 }
+
+func (p *serverParams) initJSONRPCAuth() error {
+	keys := make(map[string]struct{}, len(p.rawConfig.JSONRPCAPIKeys))
+
+	for _, raw := range p.rawConfig.JSONRPCAPIKeys {
+		if raw.Key == "" {
+			return errAPIKeyUndefined
+		}
+
+		if _, ok := keys[raw.Key]; ok {
+			return fmt.Errorf("%w: %s", errDuplicateAPIKey, raw.Name)
+		}
+
+		keys[raw.Key] = struct{}{}
+
+		namespaces := make([]jsonrpc.Namespace, 0, len(raw.Namespaces))
+		for _, ns := range raw.Namespaces {
+			namespaces = append(namespaces, jsonrpc.Namespace(strings.TrimSpace(ns)))
+		}
+
+		p.jsonRPCAPIKeys = append(p.jsonRPCAPIKeys, &jsonrpc.APIKey{
+			Name:              raw.Name,
+			Key:               raw.Key,
+			RequestsPerSecond: raw.RequestsPerSecond,
+			BatchLengthLimit:  raw.BatchRequestLimit,
+			BlockRangeLimit:   raw.BlockRangeLimit,
+			Namespaces:        namespaces,
+		})
+	}
+
+	if p.rawConfig.JWTSecretFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(p.rawConfig.JWTSecretFile)
+	if err != nil {
+		return fmt.Errorf("unable to read jwt secret file, %w", err)
+	}
+
+	secret, err := hex.DecodeHex(strings.TrimSpace(string(data)))
+	if err != nil || len(secret) != 32 {
+		return errInvalidJWTSecret
+	}
+
+	p.jwtSecret = secret
+
+	return nil
+}
@@ -4,11 +4,16 @@ import (
 	"errors"
 	"log"
 	"net"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 
+	"github.com/dogechain-lab/dogechain/accounts"
 	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/jsonrpc"
 	"github.com/dogechain-lab/dogechain/network"
 	"github.com/dogechain-lab/dogechain/secrets"
 	"github.com/dogechain-lab/dogechain/server"
@@ -48,10 +53,36 @@ const (
 	daemonFlag                   = "daemon"
 	logFileLocationFlag          = "log-to"
 	enableGraphQLFlag            = "enable-graphql"
+	graphqlMaxQueryCostFlag      = "graphql-max-query-cost"
+	graphqlMaxQueryDepthFlag     = "graphql-max-query-depth"
+	graphqlMaxQueryBlocksFlag    = "graphql-max-query-blocks"
+	graphqlQueryTimeoutFlag      = "graphql-query-timeout"
 	jsonRPCBatchRequestLimitFlag = "json-rpc-batch-request-limit"
 	jsonRPCBlockRangeLimitFlag   = "json-rpc-block-range-limit"
+	jsonRPCCacheEntriesFlag      = "json-rpc-cache-entries"
+	jsonRPCCacheSizeFlag         = "json-rpc-cache-size"
+	jsonRPCRecordDirFlag         = "json-rpc-record-dir"
+	jsonRPCRecordSampleRateFlag  = "json-rpc-record-sample-rate"
+	jsonRPCRecordMethodsFlag     = "json-rpc-record-methods"
+	jsonRPCRecordFileSizeFlag    = "json-rpc-record-file-size"
+	jsonRPCRecordMaxFilesFlag    = "json-rpc-record-max-files"
 	jsonrpcNamespaceFlag         = "json-rpc-namespace"
 	enableWSFlag                 = "enable-ws"
+	wsConnLimitFlag              = "ws-conn-limit"
+	wsTrustedProxiesFlag         = "ws-trusted-proxies"
+	wsSubscriptionLimitFlag      = "ws-subscription-limit"
+	wsSendQueueFlag              = "ws-send-queue"
+	wsSlowConsumerFlag           = "ws-slow-consumer"
+	wsPingIntervalFlag           = "ws-ping-interval"
+	wsIdleTimeoutFlag            = "ws-idle-timeout"
+	ipcPathFlag                  = "ipc-path"
+	disableIPCFlag               = "disable-ipc"
+	jwtSecretFlag                = "jwt-secret"
+	allowInsecureUnlockFlag      = "allow-insecure-unlock"
+	enableTraceStoreFlag         = "enable-trace-store"
+	enableAddressIndexFlag       = "enable-address-index"
+	enableBloomIndexFlag         = "enable-bloom-index"
+	keystoreFlag                 = "keystore"
 	blockBroadcastFlag           = "block-broadcast"
 	gpoBlocksFlag                = "gpo.blocks"
 	gpoPercentileFlag            = "gpo.percentile"
@@ -61,6 +92,8 @@ const (
 
 const (
 	unsetPeersValue = -1
+
+	windowsPipePrefix = `\\.\pipe\`
 )
 
 var (
@@ -74,8 +107,10 @@ var (
 )
 
 var (
-	errInvalidPeerParams = errors.New("both max-peers and max-inbound/outbound flags are set")
-	errInvalidNATAddress = errors.New("could not parse NAT address (ip:port)")
+	errInvalidPeerParams  = errors.New("both max-peers and max-inbound/outbound flags are set")
+	errInvalidNATAddress  = errors.New("could not parse NAT address (ip:port)")
+	errInvalidWSPolicy    = errors.New("invalid ws-slow-consumer, it must be drop or disconnect")
+	errInvalidWSKeepalive = errors.New("invalid ws-ping-interval, it must be in (0, ws-idle-timeout) if timeout enabled")
 )
 
 type serverParams struct {
@@ -116,6 +151,10 @@ type serverParams struct {
 	// gas price oracle
 	gpoMaxGasPrice    int64
 	gpoIgnoreGasPrice int64
+
+	// json-rpc auth
+	jsonRPCAPIKeys []*jsonrpc.APIKey
+	jwtSecret      []byte
 }
 
 func (p *serverParams) validateFlags() error {
@@ -124,6 +163,20 @@ func (p *serverParams) validateFlags() error {
 		return errInvalidPeerParams
 	}
 
+	// Validate the slow consumer policy of the websocket
+	switch jsonrpc.WSSlowConsumerPolicy(p.rawConfig.WSSlowConsumerPolicy) {
+	case jsonrpc.WSSlowConsumerDrop, jsonrpc.WSSlowConsumerDisconnect:
+	default:
+		return errInvalidWSPolicy
+	}
+
+	// Validate the keepalive of the websocket, the pongs must arrive before
+	// the idle connection is closed
+	if idleTimeout := p.rawConfig.WSIdleTimeout; idleTimeout > 0 &&
+		(p.rawConfig.WSPingInterval == 0 || p.rawConfig.WSPingInterval >= idleTimeout) {
+		return errInvalidWSKeepalive
+	}
+
 	return nil
 }
 
@@ -168,6 +221,73 @@ func (p *serverParams) getRestoreFilePath() *string {
 	return nil
 }
 
+// getIPCPath returns the ipc endpoint path, empty if ipc is disabled.
+// The relative path is under the data directory, and the named pipe is used on windows.
+func (p *serverParams) getIPCPath() string {
+	ipcPath := p.rawConfig.IPCPath
+	if p.rawConfig.DisableIPC || ipcPath == "" {
+		return ""
+	}
+
+	if runtime.GOOS == "windows" {
+		if strings.HasPrefix(ipcPath, windowsPipePrefix) {
+			return ipcPath
+		}
+
+		return windowsPipePrefix + ipcPath
+	}
+
+	if filepath.IsAbs(ipcPath) {
+		return ipcPath
+	}
+
+	return filepath.Join(p.rawConfig.DataDir, ipcPath)
+}
+
+// getKeystoreDir returns the keystore directory of the accounts, the relative
+// path is under the data directory
+func (p *serverParams) getKeystoreDir() string {
+	keystoreDir := p.rawConfig.KeystoreDir
+	if keystoreDir == "" {
+		keystoreDir = accounts.DefaultKeystoreDir
+	}
+
+	if filepath.IsAbs(keystoreDir) {
+		return keystoreDir
+	}
+
+	return filepath.Join(p.rawConfig.DataDir, keystoreDir)
+}
+
+// getRecorderConfig returns the config of the json-rpc recorder, nil if disabled.
+// The relative directory is under the data directory.
+func (p *serverParams) getRecorderConfig() *jsonrpc.RecorderConfig {
+	dir := p.rawConfig.JSONRPCRecordDir
+	if dir == "" {
+		return nil
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(p.rawConfig.DataDir, dir)
+	}
+
+	var methods []string
+
+	for _, method := range strings.Split(p.rawConfig.JSONRPCRecordMethods, ",") {
+		if method = strings.TrimSpace(method); method != "" {
+			methods = append(methods, method)
+		}
+	}
+
+	return &jsonrpc.RecorderConfig{
+		Dir:         dir,
+		SampleRate:  p.rawConfig.JSONRPCRecordSampleRate,
+		Methods:     methods,
+		MaxFileSize: int64(p.rawConfig.JSONRPCRecordFileSize * 1024 * 1024),
+		MaxFiles:    int(p.rawConfig.JSONRPCRecordMaxFiles),
+	}
+}
+
 func (p *serverParams) setRawGRPCAddress(grpcAddress string) {
 	p.rawConfig.GRPCAddr = grpcAddress
 }
@@ -218,16 +338,37 @@ func (p *serverParams) generateConfig() *server.Config {
 			AccessControlAllowOrigin: p.corsAllowedOrigins,
 			BatchLengthLimit:         p.rawConfig.JSONRPCBatchRequestLimit,
 			BlockRangeLimit:          p.rawConfig.JSONRPCBlockRangeLimit,
+			CacheEntries:             int(p.rawConfig.JSONRPCCacheEntries),
+			CacheBytes:               int(p.rawConfig.JSONRPCCacheSize * 1024 * 1024),
+			Recorder:                 p.getRecorderConfig(),
 			JSONNamespace:            ns,
 			EnableWS:                 p.rawConfig.EnableWS,
+			WSConnLimitPerIP:         p.rawConfig.WSConnLimitPerIP,
+			WSTrustedProxies:         p.rawConfig.WSTrustedProxies,
+			WSSubscriptionLimit:      p.rawConfig.WSSubscriptionLimit,
+			WSSendQueueSize:          p.rawConfig.WSSendQueueSize,
+			WSSlowConsumerPolicy:     jsonrpc.WSSlowConsumerPolicy(p.rawConfig.WSSlowConsumerPolicy),
+			WSPingInterval:           time.Duration(p.rawConfig.WSPingInterval) * time.Second,
+			WSIdleTimeout:            time.Duration(p.rawConfig.WSIdleTimeout) * time.Second,
+			IPCPath:                  p.getIPCPath(),
 			EnablePprof:              p.rawConfig.EnablePprof,
+			APIKeys:                  p.jsonRPCAPIKeys,
+			JWTSecret:                p.jwtSecret,
+			AllowInsecureUnlock:      p.rawConfig.AllowInsecureUnlock,
 		},
-		EnableGraphQL: p.rawConfig.EnableGraphQL,
+		EnableGraphQL:      p.rawConfig.EnableGraphQL,
+		EnableTraceStore:   p.rawConfig.EnableTraceStore,
+		EnableAddressIndex: p.rawConfig.EnableAddressIndex,
+		EnableBloomIndex:   p.rawConfig.EnableBloomIndex,
 		GraphQL: &server.GraphQL{
 			GraphQLAddr:              p.graphqlAddress,
 			AccessControlAllowOrigin: p.corsAllowedOrigins,
 			BlockRangeLimit:          p.rawConfig.JSONRPCBlockRangeLimit,
 			EnablePprof:              p.rawConfig.EnablePprof,
+			MaxQueryCost:             p.rawConfig.GraphQLMaxQueryCost,
+			MaxQueryDepth:            p.rawConfig.GraphQLMaxQueryDepth,
+			MaxQueryBlocks:           p.rawConfig.GraphQLMaxQueryBlocks,
+			QueryTimeout:             time.Duration(p.rawConfig.GraphQLQueryTimeout) * time.Second,
 		},
 		GRPCAddr:   p.grpcAddress,
 		LibP2PAddr: p.libp2pAddress,
@@ -251,6 +392,7 @@ func (p *serverParams) generateConfig() *server.Config {
 			Chain:            p.genesisConfig,
 		},
 		DataDir:               p.rawConfig.DataDir,
+		KeystoreDir:           p.getKeystoreDir(),
 		Seal:                  p.rawConfig.ShouldSeal,
 		PriceLimit:            p.rawConfig.TxPool.PriceLimit,
 		MaxSlots:              p.rawConfig.TxPool.MaxSlots,
@@ -107,6 +107,57 @@ func setFlags(cmd *cobra.Command) {
 				"that consider fromBlock/toBlock values (e.g. eth_getLogs)",
 		)
 
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.JSONRPCCacheEntries,
+			jsonRPCCacheEntriesFlag,
+			defaultConfig.JSONRPCCacheEntries,
+			"the max number of the cached json-rpc responses of the immutable queries (0 to disable the cache)",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.JSONRPCCacheSize,
+			jsonRPCCacheSizeFlag,
+			defaultConfig.JSONRPCCacheSize,
+			"the max total size (MB) of the cached json-rpc responses (0 to disable the cache)",
+		)
+
+		cmd.Flags().StringVar(
+			&params.rawConfig.JSONRPCRecordDir,
+			jsonRPCRecordDirFlag,
+			defaultConfig.JSONRPCRecordDir,
+			"the directory of the json-rpc capture files for the rpc-replay command, the relative path is under "+
+				"the data directory (empty to disable the recording)",
+		)
+
+		cmd.Flags().Float64Var(
+			&params.rawConfig.JSONRPCRecordSampleRate,
+			jsonRPCRecordSampleRateFlag,
+			defaultConfig.JSONRPCRecordSampleRate,
+			"the ratio of the recorded json-rpc requests, in (0, 1]",
+		)
+
+		cmd.Flags().StringVar(
+			&params.rawConfig.JSONRPCRecordMethods,
+			jsonRPCRecordMethodsFlag,
+			defaultConfig.JSONRPCRecordMethods,
+			"the comma separated json-rpc methods recorded (empty to record all of them), "+
+				"the privileged methods are never recorded",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.JSONRPCRecordFileSize,
+			jsonRPCRecordFileSizeFlag,
+			defaultConfig.JSONRPCRecordFileSize,
+			"the size (MB) of a json-rpc capture file before it is rotated (0 to never rotate)",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.JSONRPCRecordMaxFiles,
+			jsonRPCRecordMaxFilesFlag,
+			defaultConfig.JSONRPCRecordMaxFiles,
+			"the max number of the kept json-rpc capture files, the oldest ones are removed (0 to keep all of them)",
+		)
+
 		cmd.Flags().BoolVar(
 			&params.rawConfig.EnableWS,
 			enableWSFlag,
@@ -114,6 +165,117 @@ func setFlags(cmd *cobra.Command) {
 			"the flag indicating that node enable websocket service",
 		)
 
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.WSConnLimitPerIP,
+			wsConnLimitFlag,
+			defaultConfig.WSConnLimitPerIP,
+			"the max websocket connections of an ip (0 for unlimited)",
+		)
+
+		cmd.Flags().StringArrayVar(
+			&params.rawConfig.WSTrustedProxies,
+			wsTrustedProxiesFlag,
+			defaultConfig.WSTrustedProxies,
+			"the proxy ips whose X-Forwarded-For header gives the client ip of the websocket connection limit",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.WSSubscriptionLimit,
+			wsSubscriptionLimitFlag,
+			defaultConfig.WSSubscriptionLimit,
+			"the max subscriptions of a websocket connection (0 for unlimited)",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.WSSendQueueSize,
+			wsSendQueueFlag,
+			defaultConfig.WSSendQueueSize,
+			"the max outbound messages queued for a websocket connection",
+		)
+
+		cmd.Flags().StringVar(
+			&params.rawConfig.WSSlowConsumerPolicy,
+			wsSlowConsumerFlag,
+			defaultConfig.WSSlowConsumerPolicy,
+			"the action once the outbound queue of a websocket connection is full "+
+				"(drop the notifications, or disconnect the consumer)",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.WSPingInterval,
+			wsPingIntervalFlag,
+			defaultConfig.WSPingInterval,
+			"the interval of the websocket keepalive pings in seconds (0 to disable)",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.WSIdleTimeout,
+			wsIdleTimeoutFlag,
+			defaultConfig.WSIdleTimeout,
+			"the websocket connection is closed once nothing is read in seconds, including the pongs, "+
+				"greater than ws-ping-interval (0 to disable)",
+		)
+
+		cmd.Flags().StringVar(
+			&params.rawConfig.IPCPath,
+			ipcPathFlag,
+			defaultConfig.IPCPath,
+			"the path of the json-rpc ipc endpoint, the relative path is under the data directory",
+		)
+
+		cmd.Flags().StringVar(
+			&params.rawConfig.KeystoreDir,
+			keystoreFlag,
+			defaultConfig.KeystoreDir,
+			"the directory of the encrypted accounts of the personal json-rpc namespace, "+
+				"the relative path is under the data directory",
+		)
+
+		cmd.Flags().BoolVar(
+			&params.rawConfig.DisableIPC,
+			disableIPCFlag,
+			false,
+			"the flag indicating that node disable json-rpc ipc service",
+		)
+
+		cmd.Flags().StringVar(
+			&params.rawConfig.JWTSecretFile,
+			jwtSecretFlag,
+			"",
+			"the path of the hex encoded jwt secret, which enables the jwt auth of "+
+				"the privileged json-rpc namespaces (debug, txpool, trace, admin, personal) and the signing eth methods. "+
+				"The tokens require an iat within 60s, and are checked once per websocket connection",
+		)
+
+		cmd.Flags().BoolVar(
+			&params.rawConfig.AllowInsecureUnlock,
+			allowInsecureUnlockFlag,
+			false,
+			"the flag indicating that the accounts are allowed to be unlocked and sign over http and websocket "+
+				"without the jwt auth",
+		)
+
+		cmd.Flags().BoolVar(
+			&params.rawConfig.EnableTraceStore,
+			enableTraceStoreFlag,
+			false,
+			"the flag indicating that node records the call traces of the written blocks for the trace namespace",
+		)
+
+		cmd.Flags().BoolVar(
+			&params.rawConfig.EnableAddressIndex,
+			enableAddressIndexFlag,
+			false,
+			"the flag indicating that node indexes the transactions of the addresses for the ots namespace",
+		)
+
+		cmd.Flags().BoolVar(
+			&params.rawConfig.EnableBloomIndex,
+			enableBloomIndexFlag,
+			false,
+			"the flag indicating that node indexes the log blooms in background to narrow the eth_getLogs scans",
+		)
+
 		cmd.Flags().BoolVar(
 			&params.rawConfig.EnableGraphQL,
 			enableGraphQLFlag,
@@ -121,12 +283,41 @@ func setFlags(cmd *cobra.Command) {
 			"the flag indicating that node enable graphql service",
 		)
 
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.GraphQLMaxQueryCost,
+			graphqlMaxQueryCostFlag,
+			defaultConfig.GraphQLMaxQueryCost,
+			"the max cost of a graphql query, the number of the resolved fields and the scanned blocks (0 for unlimited)",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.GraphQLMaxQueryDepth,
+			graphqlMaxQueryDepthFlag,
+			defaultConfig.GraphQLMaxQueryDepth,
+			"the max depth of the nested fields of a graphql query (0 for unlimited)",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.GraphQLMaxQueryBlocks,
+			graphqlMaxQueryBlocksFlag,
+			defaultConfig.GraphQLMaxQueryBlocks,
+			"the max blocks returned by the block ranges of a graphql query (0 for unlimited)",
+		)
+
+		cmd.Flags().Uint64Var(
+			&params.rawConfig.GraphQLQueryTimeout,
+			graphqlQueryTimeoutFlag,
+			defaultConfig.GraphQLQueryTimeout,
+			"the execution deadline of a graphql query in seconds (0 for unlimited)",
+		)
+
 		cmd.Flags().StringVar(
 			&params.rawConfig.JSONNamespace,
 			jsonrpcNamespaceFlag,
 			defaultConfig.JSONNamespace,
 			"the jsonrpc endpoint namespaces should be enabled "+
-				"(eth, net, web3, txpool, debug. concatenate with commas or * for all)",
+				"(eth, net, web3, txpool, debug, trace, ots, admin, personal. concatenate with commas or * "+
+				"for all except admin and personal, which are only enabled when listed)",
 		)
 	}
 
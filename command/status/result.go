@@ -8,10 +8,12 @@ import (
 )
 
 type StatusResult struct {
-	ChainID            int64  `json:"chain_id"`
-	CurrentBlockNumber int64  `json:"current_block_number"`
-	CurrentBlockHash   string `json:"current_block_hash"`
-	LibP2PAddress      string `json:"libp2p_address"`
+	ChainID              int64  `json:"chain_id"`
+	CurrentBlockNumber   int64  `json:"current_block_number"`
+	CurrentBlockHash     string `json:"current_block_hash"`
+	FinalizedBlockNumber int64  `json:"finalized_block_number"`
+	FinalizedBlockHash   string `json:"finalized_block_hash"`
+	LibP2PAddress        string `json:"libp2p_address"`
 }
 
 func (r *StatusResult) GetOutput() string {
@@ -22,6 +24,8 @@ func (r *StatusResult) GetOutput() string {
 		fmt.Sprintf("Network (Chain ID)|%d", r.ChainID),
 		fmt.Sprintf("Current Block Number (base 10)|%d", r.CurrentBlockNumber),
 		fmt.Sprintf("Current Block Hash|%s", r.CurrentBlockHash),
+		fmt.Sprintf("Finalized Block Number (base 10)|%d", r.FinalizedBlockNumber),
+		fmt.Sprintf("Finalized Block Hash|%s", r.FinalizedBlockHash),
 		fmt.Sprintf("Libp2p Address|%s", r.LibP2PAddress),
 	}))
 
@@ -37,10 +37,12 @@ func runCommand(cmd *cobra.Command, _ []string) {
 	}
 
 	outputter.SetCommandResult(&StatusResult{
-		ChainID:            statusResponse.Network,
-		CurrentBlockNumber: statusResponse.Current.Number,
-		CurrentBlockHash:   statusResponse.Current.Hash,
-		LibP2PAddress:      statusResponse.P2PAddr,
+		ChainID:              statusResponse.Network,
+		CurrentBlockNumber:   statusResponse.Current.Number,
+		CurrentBlockHash:     statusResponse.Current.Hash,
+		FinalizedBlockNumber: statusResponse.GetFinalized().GetNumber(),
+		FinalizedBlockHash:   statusResponse.GetFinalized().GetHash(),
+		LibP2PAddress:        statusResponse.P2PAddr,
 	})
 }
 
@@ -3,6 +3,7 @@ package dev
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/dogechain-lab/dogechain/blockchain"
@@ -109,13 +110,17 @@ type transitionInterface interface {
 	Write(txn *types.Transaction) error
 }
 
-func (d *Dev) writeTransactions(gasLimit uint64, transition transitionInterface) []*types.Transaction {
+func (d *Dev) writeTransactions(
+	gasLimit uint64,
+	baseFee *big.Int,
+	transition transitionInterface,
+) []*types.Transaction {
 	var includedTxs []*types.Transaction
 
 	// get all pending transactions once and for all
 	pendingTxs := d.txpool.Pending()
-	// get highest price transaction queue
-	priceTxs := types.NewTransactionsByPriceAndNonce(pendingTxs)
+	// get highest tip transaction queue
+	priceTxs := types.NewTransactionsByPriceAndNonce(pendingTxs, baseFee)
 
 	for {
 		tx := priceTxs.Peek()
@@ -198,6 +203,7 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 	}
 
 	header.GasLimit = gasLimit
+	header.BaseFee = d.blockchain.CalculateBaseFee(parent)
 
 	miner, err := d.GetBlockCreator(header)
 	if err != nil {
@@ -219,7 +225,7 @@ func (d *Dev) writeNewBlock(parent *types.Header) error {
 		d.logger,
 	)
 
-	txns := d.writeTransactions(gasLimit, transition)
+	txns := d.writeTransactions(gasLimit, header.BaseFee, transition)
 
 	// Commit the changes
 	_, root, err := transition.Commit()
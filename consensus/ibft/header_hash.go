@@ -39,6 +39,10 @@ func istanbulHeaderHash(h *types.Header) types.Hash {
 	vv.Set(arena.NewUint(h.Timestamp))
 	vv.Set(arena.NewCopyBytes(h.ExtraData))
 
+	if h.BaseFee != nil {
+		vv.Set(arena.NewBigInt(h.BaseFee))
+	}
+
 	buf := keccak.Keccak256Rlp(nil, vv)
 
 	return types.BytesToHash(buf)
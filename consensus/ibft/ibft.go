@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
@@ -59,6 +60,7 @@ type blockchainInterface interface {
 	WriteBlock(block *types.Block, source string) error
 	VerifyPotentialBlock(block *types.Block) error
 	CalculateGasLimit(number uint64) (uint64, error)
+	CalculateBaseFee(parent *types.Header) *big.Int
 	SubscribeEvents() blockchain.Subscription
 }
 
@@ -636,6 +638,9 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 	// update gas limit first
 	header.GasLimit = gasLimit
 
+	// calculate base fee based on parent header, nil before london
+	header.BaseFee = i.blockchain.CalculateBaseFee(parent)
+
 	if hookErr := i.runHook(CandidateVoteHook, header.Number, &candidateVoteHookParams{
 		header: header,
 		snap:   snap,
@@ -683,7 +688,12 @@ func (i *Ibft) buildBlock(snap *Snapshot, parent *types.Header) (*types.Block, e
 
 	// insert normal transactions
 	if i.shouldWriteTransactions(header.Number) {
-		includedTxs, dropTxs, resetTxs = i.writeTransactions(gasLimit, transition, headerTime.Add(i.blockTime))
+		includedTxs, dropTxs, resetTxs = i.writeTransactions(
+			gasLimit,
+			header.BaseFee,
+			transition,
+			headerTime.Add(i.blockTime),
+		)
 		txs = append(txs, includedTxs...)
 	}
 
@@ -936,6 +946,7 @@ type demoteTransaction struct {
 // and returns transactions that were included in the transition (new block)
 func (i *Ibft) writeTransactions(
 	gasLimit uint64,
+	baseFee *big.Int,
 	transition transitionInterface,
 	terminalTime time.Time,
 ) (
@@ -945,8 +956,8 @@ func (i *Ibft) writeTransactions(
 ) {
 	// get all pending transactions once and for all
 	pendingTxs := i.txpool.Pending()
-	// get highest price transaction queue
-	priceTxs := types.NewTransactionsByPriceAndNonce(pendingTxs)
+	// get highest tip transaction queue, transactions could not afford the base fee are excluded
+	priceTxs := types.NewTransactionsByPriceAndNonce(pendingTxs, baseFee)
 
 	for {
 		// terminate transaction executing once timeout
@@ -98,6 +98,11 @@ func (m *MockBlockchain) CalculateGasLimit(number uint64) (uint64, error) {
 	return m.CalculateGasLimitHandler(number)
 }
 
+// CalculateBaseFee returns nil, the london hardfork is not enabled in tests
+func (m *MockBlockchain) CalculateBaseFee(parent *types.Header) *big.Int {
+	return nil
+}
+
 // helper method
 func (m *MockBlockchain) SetGenesis(validators []types.Address) *types.Block {
 	m.t.Helper()
@@ -864,7 +869,7 @@ func TestIBFT_WriteTransactions(t *testing.T) {
 			mockTransition := setupMockTransition(test, mockTxPool)
 
 			endTime := time.Now().Add(time.Second)
-			included, shouldDropTxs, shouldDemoteTxs := m.writeTransactions(1000, mockTransition, endTime)
+			included, shouldDropTxs, shouldDemoteTxs := m.writeTransactions(1000, nil, mockTransition, endTime)
 
 			assert.Equal(t, test.params.expectedIncludedTxnsCount, len(included))
 			assert.Equal(t, test.params.expectedFailReceiptsWritten, len(mockTransition.failReceiptsWritten))
@@ -1052,6 +1057,10 @@ func (m *mockIbft) CalculateGasLimit(number uint64) (uint64, error) {
 	return m.blockchain.CalculateGasLimit(number)
 }
 
+func (m *mockIbft) CalculateBaseFee(parent *types.Header) *big.Int {
+	return m.blockchain.CalculateBaseFee(parent)
+}
+
 func newMockIbft(t *testing.T, accounts []string, validatorAccount string) *mockIbft {
 	t.Helper()
 
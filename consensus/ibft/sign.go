@@ -160,6 +160,10 @@ func calculateHeaderHash(h *types.Header) ([]byte, error) {
 	vv.Set(arena.NewUint(h.Timestamp))
 	vv.Set(arena.NewCopyBytes(h.ExtraData))
 
+	if h.BaseFee != nil {
+		vv.Set(arena.NewBigInt(h.BaseFee))
+	}
+
 	buf := keccak.Keccak256Rlp(nil, vv)
 
 	return buf, nil
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"math/bits"
@@ -27,11 +28,19 @@ type TxSigner interface {
 	CalculateV(parity byte) []byte
 }
 
-// NewSigner creates a new signer object (EIP155 or FrontierSigner)
+var (
+	ErrInvalidChainID = errors.New("invalid chain id for signer")
+)
+
+// NewSigner creates a new signer object (London, EIP2930, EIP155 or FrontierSigner)
 func NewSigner(forks chain.ForksInTime, chainID uint64) TxSigner {
 	var signer TxSigner
 
-	if forks.EIP155 {
+	if forks.London {
+		signer = NewLondonSigner(chainID)
+	} else if forks.Berlin {
+		signer = NewEIP2930Signer(chainID)
+	} else if forks.EIP155 {
 		signer = &EIP155Signer{chainID: chainID}
 	} else {
 		signer = &FrontierSigner{}
@@ -90,6 +99,10 @@ var (
 
 // Sender decodes the signature and returns the sender of the transaction
 func (f *FrontierSigner) Sender(tx *types.Transaction) (types.Address, error) {
+	if tx.Type.IsTyped() {
+		return types.Address{}, types.ErrTxTypeNotSupported
+	}
+
 	refV := big.NewInt(0)
 	if tx.V != nil {
 		refV.SetBytes(tx.V.Bytes())
@@ -157,6 +170,10 @@ func (e *EIP155Signer) Hash(tx *types.Transaction) types.Hash {
 
 // Sender returns the transaction sender
 func (e *EIP155Signer) Sender(tx *types.Transaction) (types.Address, error) {
+	if tx.Type.IsTyped() {
+		return types.Address{}, types.ErrTxTypeNotSupported
+	}
+
 	protected := true
 
 	// Check if v value conforms to an earlier standard (before EIP155)
@@ -227,6 +244,203 @@ func (e *EIP155Signer) CalculateV(parity byte) []byte {
 	return reference.Bytes()
 }
 
+// NewEIP2930Signer returns a new EIP2930Signer object
+func NewEIP2930Signer(chainID uint64) *EIP2930Signer {
+	return &EIP2930Signer{EIP155Signer: EIP155Signer{chainID: chainID}}
+}
+
+// EIP2930Signer accepts EIP-2930 access list transactions,
+// and falls back to EIP155Signer for the legacy ones
+type EIP2930Signer struct {
+	EIP155Signer
+}
+
+// calcAccessListTxHash calculates the signing hash of an access list transaction,
+// which is keccak256(0x01 || rlp([chainId, nonce, gasPrice, gasLimit, to, value, data, accessList]))
+func calcAccessListTxHash(tx *types.Transaction, chainID uint64) types.Hash {
+	a := signerPool.Get()
+
+	v := a.NewArray()
+	v.Set(a.NewUint(chainID))
+	v.Set(a.NewUint(tx.Nonce))
+	v.Set(a.NewBigInt(tx.GasPrice))
+	v.Set(a.NewUint(tx.Gas))
+
+	if tx.To == nil {
+		v.Set(a.NewNull())
+	} else {
+		v.Set(a.NewCopyBytes((*tx.To).Bytes()))
+	}
+
+	v.Set(a.NewBigInt(tx.Value))
+	v.Set(a.NewCopyBytes(tx.Input))
+	v.Set(tx.AccessList.MarshalRLPWith(a))
+
+	hash := keccak.Keccak256(nil, append([]byte{byte(tx.Type)}, v.MarshalTo(nil)...))
+
+	signerPool.Put(a)
+
+	return types.BytesToHash(hash)
+}
+
+// Hash returns the signing hash of the transaction
+func (e *EIP2930Signer) Hash(tx *types.Transaction) types.Hash {
+	if tx.Type == types.AccessListTx {
+		return calcAccessListTxHash(tx, e.chainID)
+	}
+
+	return e.EIP155Signer.Hash(tx)
+}
+
+// Sender returns the transaction sender
+func (e *EIP2930Signer) Sender(tx *types.Transaction) (types.Address, error) {
+	switch tx.Type {
+	case types.LegacyTx:
+		return e.EIP155Signer.Sender(tx)
+	case types.AccessListTx:
+	default:
+		return types.Address{}, types.ErrTxTypeNotSupported
+	}
+
+	return typedTxSender(tx, e.chainID, e.Hash(tx))
+}
+
+// typedTxSender recovers the sender of a typed transaction from its signing hash
+func typedTxSender(tx *types.Transaction, chainID uint64, hash types.Hash) (types.Address, error) {
+	if tx.ChainID == nil || !tx.ChainID.IsUint64() || tx.ChainID.Uint64() != chainID {
+		return types.Address{}, ErrInvalidChainID
+	}
+
+	// typed transactions use the plain parity as V
+	if tx.V == nil || tx.V.BitLen() > 1 {
+		return types.Address{}, fmt.Errorf("invalid txn signature")
+	}
+
+	sig, err := encodeSignature(tx.R, tx.S, byte(tx.V.Uint64()))
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	pub, err := Ecrecover(hash.Bytes(), sig)
+	if err != nil {
+		return types.Address{}, err
+	}
+
+	buf := Keccak256(pub[1:])[12:]
+
+	return types.BytesToAddress(buf), nil
+}
+
+// SignTx signs the transaction using the passed in private key
+func (e *EIP2930Signer) SignTx(
+	tx *types.Transaction,
+	privateKey *ecdsa.PrivateKey,
+) (*types.Transaction, error) {
+	if tx.Type == types.LegacyTx {
+		return e.EIP155Signer.SignTx(tx, privateKey)
+	}
+
+	if tx.Type != types.AccessListTx {
+		return nil, types.ErrTxTypeNotSupported
+	}
+
+	return signTypedTx(tx, e.chainID, e.Hash, privateKey)
+}
+
+// signTypedTx signs a copy of the typed transaction with its chain id set
+func signTypedTx(
+	tx *types.Transaction,
+	chainID uint64,
+	hashFn func(*types.Transaction) types.Hash,
+	privateKey *ecdsa.PrivateKey,
+) (*types.Transaction, error) {
+	tx = tx.Copy()
+	tx.ChainID = new(big.Int).SetUint64(chainID)
+
+	h := hashFn(tx)
+
+	sig, err := Sign(privateKey, h[:])
+	if err != nil {
+		return nil, err
+	}
+
+	tx.R = new(big.Int).SetBytes(sig[:32])
+	tx.S = new(big.Int).SetBytes(sig[32:64])
+	tx.V = new(big.Int).SetUint64(uint64(sig[64]))
+
+	return tx, nil
+}
+
+// NewLondonSigner returns a new LondonSigner object
+func NewLondonSigner(chainID uint64) *LondonSigner {
+	return &LondonSigner{EIP2930Signer: *NewEIP2930Signer(chainID)}
+}
+
+// LondonSigner accepts EIP-1559 dynamic fee transactions,
+// and falls back to EIP2930Signer for the other ones
+type LondonSigner struct {
+	EIP2930Signer
+}
+
+// calcDynamicFeeTxHash calculates the signing hash of a dynamic fee transaction, which is
+// keccak256(0x02 || rlp([chainId, nonce, gasTipCap, gasFeeCap, gasLimit, to, value, data, accessList]))
+func calcDynamicFeeTxHash(tx *types.Transaction, chainID uint64) types.Hash {
+	a := signerPool.Get()
+
+	v := a.NewArray()
+	v.Set(a.NewUint(chainID))
+	v.Set(a.NewUint(tx.Nonce))
+	v.Set(a.NewBigInt(tx.GasTipCap))
+	v.Set(a.NewBigInt(tx.GasFeeCap))
+	v.Set(a.NewUint(tx.Gas))
+
+	if tx.To == nil {
+		v.Set(a.NewNull())
+	} else {
+		v.Set(a.NewCopyBytes((*tx.To).Bytes()))
+	}
+
+	v.Set(a.NewBigInt(tx.Value))
+	v.Set(a.NewCopyBytes(tx.Input))
+	v.Set(tx.AccessList.MarshalRLPWith(a))
+
+	hash := keccak.Keccak256(nil, append([]byte{byte(tx.Type)}, v.MarshalTo(nil)...))
+
+	signerPool.Put(a)
+
+	return types.BytesToHash(hash)
+}
+
+// Hash returns the signing hash of the transaction
+func (e *LondonSigner) Hash(tx *types.Transaction) types.Hash {
+	if tx.Type == types.DynamicFeeTx {
+		return calcDynamicFeeTxHash(tx, e.chainID)
+	}
+
+	return e.EIP2930Signer.Hash(tx)
+}
+
+// Sender returns the transaction sender
+func (e *LondonSigner) Sender(tx *types.Transaction) (types.Address, error) {
+	if tx.Type != types.DynamicFeeTx {
+		return e.EIP2930Signer.Sender(tx)
+	}
+
+	return typedTxSender(tx, e.chainID, e.Hash(tx))
+}
+
+// SignTx signs the transaction using the passed in private key
+func (e *LondonSigner) SignTx(
+	tx *types.Transaction,
+	privateKey *ecdsa.PrivateKey,
+) (*types.Transaction, error) {
+	if tx.Type != types.DynamicFeeTx {
+		return e.EIP2930Signer.SignTx(tx, privateKey)
+	}
+
+	return signTypedTx(tx, e.chainID, e.Hash, privateKey)
+}
+
 // encodeSignature generates a signature value based on the R, S and V value
 func encodeSignature(R, S *big.Int, V byte) ([]byte, error) {
 	if !ValidateSignatureValues(V, R, S) {
@@ -138,3 +138,92 @@ func TestEIP155Signer_ChainIDMismatch(t *testing.T) {
 		}
 	}
 }
+
+func TestEIP2930Signer(t *testing.T) {
+	toAddress := types.StringToAddress("1")
+
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	signer := NewEIP2930Signer(100)
+
+	t.Run("access list transaction", func(t *testing.T) {
+		txn := &types.Transaction{
+			Type:     types.AccessListTx,
+			To:       &toAddress,
+			Value:    big.NewInt(1),
+			GasPrice: big.NewInt(0),
+			AccessList: types.AccessList{
+				{Address: toAddress, StorageKeys: []types.Hash{types.StringToHash("1")}},
+			},
+		}
+
+		signedTx, err := signer.SignTx(txn, key)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(100), signedTx.ChainID.Uint64())
+		assert.True(t, signedTx.V.Uint64() <= 1)
+
+		from, err := signer.Sender(signedTx)
+		assert.NoError(t, err)
+		assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+
+		// chain id mismatch
+		_, err = NewEIP2930Signer(1).Sender(signedTx)
+		assert.ErrorIs(t, err, ErrInvalidChainID)
+
+		// legacy signers do not know typed transactions
+		_, err = NewEIP155Signer(100).Sender(signedTx)
+		assert.ErrorIs(t, err, types.ErrTxTypeNotSupported)
+	})
+
+	t.Run("legacy transaction", func(t *testing.T) {
+		txn := &types.Transaction{
+			To:       &toAddress,
+			Value:    big.NewInt(1),
+			GasPrice: big.NewInt(0),
+		}
+
+		signedTx, err := signer.SignTx(txn, key)
+		assert.NoError(t, err)
+
+		from, err := NewEIP155Signer(100).Sender(signedTx)
+		assert.NoError(t, err)
+		assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+	})
+}
+
+func TestLondonSigner(t *testing.T) {
+	toAddress := types.StringToAddress("1")
+
+	key, err := GenerateKey()
+	assert.NoError(t, err)
+
+	signer := NewLondonSigner(100)
+
+	txn := &types.Transaction{
+		Type:      types.DynamicFeeTx,
+		To:        &toAddress,
+		Value:     big.NewInt(1),
+		GasPrice:  big.NewInt(10),
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10),
+	}
+
+	signedTx, err := signer.SignTx(txn, key)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), signedTx.ChainID.Uint64())
+
+	from, err := signer.Sender(signedTx)
+	assert.NoError(t, err)
+	assert.Equal(t, PubKeyToAddress(&key.PublicKey), from)
+
+	// the tip cap is signed
+	signedTx.GasTipCap = big.NewInt(2)
+	from, err = signer.Sender(signedTx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, PubKeyToAddress(&key.PublicKey), from)
+
+	// the eip2930 signer does not know dynamic fee transactions
+	_, err = NewEIP2930Signer(100).Sender(signedTx)
+	assert.ErrorIs(t, err, types.ErrTxTypeNotSupported)
+}
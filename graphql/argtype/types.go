@@ -179,7 +179,8 @@ func (b *Bytes) UnmarshalGraphQL(input interface{}) error {
 
 	switch input := input.(type) {
 	case string:
-		data, err := hex.DecodeString(input)
+		// the 0x prefix is optional
+		data, err := decodeToHex([]byte(input))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,193 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/dogechain-lab/dogechain/graphql/argtype"
+	rpc "github.com/dogechain-lab/dogechain/jsonrpc"
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	errContractCreationWithoutData = errors.New("contract creation without data provided")
+)
+
+// CallData represents the data associated with a local contract call.
+// All fields are optional.
+type CallData struct {
+	From                 *types.Address // The Dogechain address the call is from
+	To                   *types.Address // The Dogechain address the call is to
+	Gas                  *argtype.Long  // The amount of gas provided for the call
+	GasPrice             *argtype.Big   // The price of each unit of gas, in wei
+	MaxFeePerGas         *argtype.Big   // The max price of each unit of gas, in wei (1559)
+	MaxPriorityFeePerGas *argtype.Big   // The max tip of each unit of gas, in wei (1559)
+	Value                *argtype.Big   // The value sent along with the call
+	Data                 *argtype.Bytes // Any data sent with the call
+}
+
+// CallResult is the result of a local call operation.
+type CallResult struct {
+	data    argtype.Bytes
+	gasUsed argtype.Long
+	status  argtype.Long
+}
+
+func (c *CallResult) Data() argtype.Bytes {
+	return c.data
+}
+
+func (c *CallResult) GasUsed() argtype.Long {
+	return c.gasUsed
+}
+
+func (c *CallResult) Status() argtype.Long {
+	return c.status
+}
+
+// toTransaction converts the call data to a transaction on top of the state of the
+// header. The nonce of the sender is read from the state, and the gas falls back to
+// the block gas limit.
+func (c *CallData) toTransaction(
+	backend GraphQLStore,
+	chainID uint64,
+	header *types.Header,
+) (*types.Transaction, error) {
+	txn := &types.Transaction{
+		From:     types.ZeroAddress,
+		To:       c.To,
+		Gas:      header.GasLimit,
+		GasPrice: new(big.Int),
+		Value:    new(big.Int),
+		Input:    []byte{},
+	}
+
+	if c.Data != nil {
+		txn.Input = *c.Data
+	} else if c.To == nil {
+		return nil, errContractCreationWithoutData
+	}
+
+	if c.From != nil {
+		txn.From = *c.From
+
+		acc, err := backend.GetAccount(header.StateRoot, txn.From)
+		if err != nil && !errors.Is(err, rpc.ErrStateNotFound) {
+			return nil, err
+		} else if err == nil {
+			txn.Nonce = acc.Nonce
+		}
+	}
+
+	if c.Gas != nil {
+		txn.Gas = uint64(*c.Gas)
+	}
+
+	if c.GasPrice != nil {
+		txn.GasPrice = new(big.Int).Set((*big.Int)(c.GasPrice))
+	}
+
+	if c.Value != nil {
+		txn.Value = new(big.Int).Set((*big.Int)(c.Value))
+	}
+
+	if c.MaxFeePerGas != nil || c.MaxPriorityFeePerGas != nil {
+		txn.Type = types.DynamicFeeTx
+		txn.ChainID = new(big.Int).SetUint64(chainID)
+
+		txn.GasTipCap = new(big.Int)
+
+		if c.MaxPriorityFeePerGas != nil {
+			txn.GasTipCap.Set((*big.Int)(c.MaxPriorityFeePerGas))
+		}
+
+		// the fee cap falls back to the gas price, or the base fee plus the tip,
+		// and the gas price mirrors the fee cap
+		if c.MaxFeePerGas != nil {
+			txn.GasPrice = new(big.Int).Set((*big.Int)(c.MaxFeePerGas))
+		} else if c.GasPrice == nil {
+			txn.GasPrice = new(big.Int).Set(txn.GasTipCap)
+
+			if header.BaseFee != nil {
+				txn.GasPrice.Add(txn.GasPrice, header.BaseFee)
+			}
+		}
+
+		txn.GasFeeCap = new(big.Int).Set(txn.GasPrice)
+	}
+
+	txn.Hash()
+
+	return txn, nil
+}
+
+// doCall executes the call on top of the state of the header. The reverted and the
+// failed calls are reported by the status instead of an error.
+func doCall(
+	ctx context.Context,
+	backend GraphQLStore,
+	chainID uint64,
+	header *types.Header,
+	data *CallData,
+) (*CallResult, error) {
+	txn, err := data.toTransaction(backend, chainID, header)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := backend.ApplyTxnContext(ctx, header, txn)
+	if err != nil {
+		return nil, err
+	}
+
+	status := argtype.Long(types.ReceiptSuccess)
+	if result.Failed() {
+		status = argtype.Long(types.ReceiptFailed)
+	}
+
+	return &CallResult{
+		data:    argtype.Bytes(result.ReturnValue),
+		gasUsed: argtype.Long(result.GasUsed),
+		status:  status,
+	}, nil
+}
+
+// doEstimateGas binary searches the lowest gas limit which executes the call
+// successfully on top of the state of the header, the same way as eth_estimateGas.
+// The search is aborted once the context is done.
+func doEstimateGas(
+	ctx context.Context,
+	backend GraphQLStore,
+	chainID uint64,
+	header *types.Header,
+	data *CallData,
+) (argtype.Long, error) {
+	txn, err := data.toTransaction(backend, chainID, header)
+	if err != nil {
+		return 0, err
+	}
+
+	getBalance := func(addr types.Address) (*big.Int, error) {
+		acc, err := backend.GetAccount(header.StateRoot, addr)
+		if err != nil && !errors.Is(err, rpc.ErrStateNotFound) {
+			return nil, err
+		} else if err == nil {
+			return acc.Balance, nil
+		}
+
+		return big.NewInt(0), nil
+	}
+
+	applyTxn := func(txn *types.Transaction) (*runtime.ExecutionResult, error) {
+		return backend.ApplyTxnContext(ctx, header, txn)
+	}
+
+	gas, err := rpc.EstimateTxnGas(header, backend.GetForksInTime(header.Number), txn, getBalance, applyTxn)
+	if err != nil {
+		return 0, err
+	}
+
+	return argtype.Long(gas), nil
+}
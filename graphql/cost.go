@@ -0,0 +1,264 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dogechain-lab/dogechain/types"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/introspection"
+	"github.com/graph-gophers/graphql-go/trace/tracer"
+)
+
+// The reasons of the rejected queries
+const (
+	rejectReasonSyntax  = "syntax"
+	rejectReasonCost    = "cost"
+	rejectReasonDepth   = "depth"
+	rejectReasonBlocks  = "blocks"
+	rejectReasonTimeout = "timeout"
+)
+
+// The error codes of the rejected queries
+const (
+	errCodeParseFailed    = "GRAPHQL_PARSE_FAILED"
+	errCodeCostExceeded   = "QUERY_COST_EXCEEDED"
+	errCodeDepthExceeded  = "QUERY_DEPTH_EXCEEDED"
+	errCodeBlocksExceeded = "QUERY_BLOCKS_EXCEEDED"
+	errCodeTimeout        = "QUERY_TIMEOUT"
+)
+
+// The validation rule and the error prefix of the graphql library
+const (
+	ruleMaxDepthExceeded = "MaxDepthExceeded"
+	syntaxErrorPrefix    = "syntax error"
+)
+
+// headerStore returns the latest header, which is the default end of the ranges
+type headerStore interface {
+	Header() *types.Header
+}
+
+// queryLimiter limits the cost of the queries. The depth is limited by the
+// validation of the graphql library, and the cost is accounted by tracing the
+// fields resolved by the library, which cancels the query exceeding the limits
+// before the field is resolved. The zero limits are unlimited.
+type queryLimiter struct {
+	backend headerStore
+
+	maxCost   uint64
+	maxBlocks uint64
+}
+
+// budgetKey is the context key of the query budget
+type budgetKey struct{}
+
+// queryBudget accounts the cost of a query
+type queryBudget struct {
+	limiter *queryLimiter
+	cancel  context.CancelFunc
+
+	lock sync.Mutex
+	// the number of the resolved fields, and the blocks scanned for the logs
+	cost uint64
+	// the number of the blocks returned by the block ranges
+	blocks uint64
+	// the rejection of the query, nil if within the limits
+	reason string
+	err    *gqlerrors.QueryError
+}
+
+// begin returns the context accounting the cost of a query, which is cancelled
+// once the limits are exceeded
+func (l *queryLimiter) begin(ctx context.Context) (context.Context, *queryBudget) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	b := &queryBudget{
+		limiter: l,
+		cancel:  cancel,
+	}
+
+	return context.WithValue(ctx, budgetKey{}, b), b
+}
+
+// TraceQuery implements the tracer of the graphql library
+func (l *queryLimiter) TraceQuery(
+	ctx context.Context,
+	queryString string,
+	operationName string,
+	variables map[string]interface{},
+	varTypes map[string]*introspection.Type,
+) (context.Context, tracer.QueryFinishFunc) {
+	return ctx, func([]*gqlerrors.QueryError) {}
+}
+
+// TraceField implements the tracer of the graphql library, it adds the cost of
+// the field to the budget of the query
+func (l *queryLimiter) TraceField(
+	ctx context.Context,
+	label, typeName, fieldName string,
+	trivial bool,
+	args map[string]interface{},
+) (context.Context, tracer.FieldFinishFunc) {
+	if b, ok := ctx.Value(budgetKey{}).(*queryBudget); ok && b.limiter == l {
+		b.add(typeName, fieldName, args)
+	}
+
+	return ctx, func(*gqlerrors.QueryError) {}
+}
+
+// add adds the cost of the field, the blocks of a block range, and the blocks
+// scanned for the logs
+func (b *queryBudget) add(typeName, fieldName string, args map[string]interface{}) {
+	// the introspection is cheap, and deeply nested by the clients
+	if strings.HasPrefix(typeName, "__") || strings.HasPrefix(fieldName, "__") {
+		return
+	}
+
+	var (
+		cost   uint64 = 1
+		blocks uint64
+	)
+
+	head := b.limiter.backend.Header().Number
+
+	switch typeName + "." + fieldName {
+	case "Query.blocks":
+		blocks = rangeSize(blockNumber(args["from"], 0), blockNumber(args["to"], head))
+	case "Query.logs":
+		filter, _ := args["filter"].(map[string]interface{})
+
+		cost = addCost(cost, rangeSize(blockNumber(filter["fromBlock"], head), blockNumber(filter["toBlock"], head)))
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.cost = addCost(b.cost, cost)
+	b.blocks = addCost(b.blocks, blocks)
+
+	if b.err != nil {
+		return
+	}
+
+	l := b.limiter
+
+	switch {
+	case l.maxBlocks > 0 && b.blocks > l.maxBlocks:
+		b.reason = rejectReasonBlocks
+		b.err = withLimit(rejectQuery(errCodeBlocksExceeded,
+			"query returns %d blocks exceeding the limit %d", b.blocks, l.maxBlocks), b.blocks, l.maxBlocks)
+	case l.maxCost > 0 && b.cost > l.maxCost:
+		b.reason = rejectReasonCost
+		b.err = withLimit(rejectQuery(errCodeCostExceeded,
+			"query cost %d exceeds the limit %d", b.cost, l.maxCost), b.cost, l.maxCost)
+	default:
+		return
+	}
+
+	// no more fields are resolved
+	b.cancel()
+}
+
+// rejected returns the rejection of the query, the error is nil if the query
+// is within the limits
+func (b *queryBudget) rejected() (string, *gqlerrors.QueryError) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.reason, b.err
+}
+
+// reset clears the cost, which is accounted for every event of a subscription
+func (b *queryBudget) reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.cost, b.blocks = 0, 0
+}
+
+// release releases the context of the query
+func (b *queryBudget) release() {
+	b.cancel()
+}
+
+// rejectReason returns the reason of the query rejected by the validation of the
+// graphql library, the errors get the codes of the rejection. It is empty if the
+// query is not rejected by the limits.
+func rejectReason(errs []*gqlerrors.QueryError) string {
+	reason := ""
+
+	for _, err := range errs {
+		switch {
+		case err.Rule == ruleMaxDepthExceeded:
+			reason = rejectReasonDepth
+			err.Extensions = map[string]interface{}{"code": errCodeDepthExceeded}
+		case strings.HasPrefix(err.Message, syntaxErrorPrefix):
+			reason = rejectReasonSyntax
+			err.Extensions = map[string]interface{}{"code": errCodeParseFailed}
+		}
+	}
+
+	return reason
+}
+
+// rangeSize returns the number of the blocks in the range, zero if empty
+func rangeSize(from, to uint64) uint64 {
+	if to < from {
+		return 0
+	}
+
+	return addCost(to-from, 1)
+}
+
+// blockNumber converts the argument value, the missing or invalid ones fall back
+// to the default
+func blockNumber(v interface{}, def uint64) uint64 {
+	switch value := v.(type) {
+	case int32:
+		if value >= 0 {
+			return uint64(value)
+		}
+	case int64:
+		if value >= 0 {
+			return uint64(value)
+		}
+	case float64:
+		if value >= 0 && value < math.MaxUint64 {
+			return uint64(value)
+		}
+	case string:
+		if n, err := strconv.ParseUint(value, 0, 64); err == nil {
+			return n
+		}
+	}
+
+	return def
+}
+
+func rejectQuery(code string, format string, a ...interface{}) *gqlerrors.QueryError {
+	return &gqlerrors.QueryError{
+		Message:    fmt.Sprintf(format, a...),
+		Extensions: map[string]interface{}{"code": code},
+	}
+}
+
+func withLimit(err *gqlerrors.QueryError, value, limit uint64) *gqlerrors.QueryError {
+	err.Extensions["value"] = value
+	err.Extensions["limit"] = limit
+
+	return err
+}
+
+// addCost adds the costs, saturating on overflow
+func addCost(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+
+	return a + b
+}
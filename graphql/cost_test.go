@@ -0,0 +1,202 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowStore returns every header in the range, with a delay
+type slowStore struct {
+	*mockStore
+
+	delay time.Duration
+}
+
+func (s *slowStore) GetHeaderByNumber(num uint64) (*types.Header, bool) {
+	time.Sleep(s.delay)
+
+	return &types.Header{Number: num}, true
+}
+
+// newTestSchema returns the schema limited by the limiter, and the depth
+func newTestSchema(t *testing.T, store GraphQLStore, limiter *queryLimiter, maxDepth int) *graphql.Schema {
+	t.Helper()
+
+	s, err := graphql.ParseSchema(schema, &Resolver{backend: store, chainID: 100},
+		graphql.MaxDepth(maxDepth),
+		graphql.Tracer(limiter),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestQueryLimiter_Cost(t *testing.T) {
+	store := &slowStore{mockStore: newMockStore()}
+	limiter := &queryLimiter{backend: store}
+	s := newTestSchema(t, store, limiter, 0)
+
+	cases := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		cost      uint64
+		blocks    uint64
+	}{
+		{
+			"every resolved field is counted",
+			`{ blocks(from: 0, to: 9) { number hash } }`,
+			nil,
+			1 + 10*2,
+			10,
+		},
+		{
+			"the ranges end at the head by default",
+			`{ blocks(from: 5) { number } }`,
+			nil,
+			1 + 6,
+			6,
+		},
+		{
+			"the variables and the fragments are resolved",
+			`query ($to: Long) { blocks(from: 0, to: $to) { ...F } } fragment F on Block { number }`,
+			map[string]interface{}{"to": float64(99)},
+			1 + 100,
+			100,
+		},
+		{
+			"the empty ranges are free",
+			`{ blocks(from: 10, to: 1) { number } }`,
+			nil,
+			1,
+			0,
+		},
+		{
+			"the introspection is free",
+			`{ __schema { types { name } } }`,
+			nil,
+			0,
+			0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, budget := limiter.begin(context.Background())
+			defer budget.release()
+
+			response := s.Exec(ctx, c.query, "", c.variables)
+			assert.Empty(t, response.Errors)
+
+			assert.Equal(t, c.cost, budget.cost)
+			assert.Equal(t, c.blocks, budget.blocks)
+		})
+	}
+}
+
+func TestQueryLimiter_Rejected(t *testing.T) {
+	store := &slowStore{mockStore: newMockStore()}
+	limiter := &queryLimiter{backend: store, maxCost: 20, maxBlocks: 50}
+	s := newTestSchema(t, store, limiter, 0)
+
+	exec := func(query string) (string, map[string]interface{}) {
+		ctx, budget := limiter.begin(context.Background())
+		defer budget.release()
+
+		s.Exec(ctx, query, "", nil)
+
+		reason, err := budget.rejected()
+		if err == nil {
+			return reason, nil
+		}
+
+		return reason, err.Extensions
+	}
+
+	reason, extensions := exec(`{ blocks(from: 0, to: 9) { number hash } }`)
+	assert.Equal(t, rejectReasonCost, reason)
+	assert.Equal(t, map[string]interface{}{
+		"code":  errCodeCostExceeded,
+		"value": uint64(21),
+		"limit": uint64(20),
+	}, extensions)
+
+	// the logs are rejected by the scanned blocks before the resolution
+	reason, _ = exec(`{ logs(filter: {fromBlock: "0x1", toBlock: "0x20"}) { data } }`)
+	assert.Equal(t, rejectReasonCost, reason)
+
+	reason, _ = exec(`{ blocks(from: 0, to: 50) { number } }`)
+	assert.Equal(t, rejectReasonBlocks, reason)
+
+	reason, extensions = exec(`{ blocks(from: 0, to: 9) { number } }`)
+	assert.Equal(t, "", reason)
+	assert.Nil(t, extensions)
+}
+
+func TestHandler_Limits(t *testing.T) {
+	store := &slowStore{mockStore: newMockStore(), delay: 20 * time.Millisecond}
+	limiter := &queryLimiter{backend: store, maxBlocks: 100}
+
+	h := handler{
+		Schema:  newTestSchema(t, store, limiter, 3),
+		logger:  hclog.NewNullLogger(),
+		limiter: limiter,
+		timeout: 50 * time.Millisecond,
+		metrics: NilMetrics(),
+	}
+
+	serve := func(query string) (int, string) {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		w := httptest.NewRecorder()
+
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+
+		var res struct {
+			Errors []struct {
+				Extensions map[string]interface{}
+			}
+		}
+
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+
+		if len(res.Errors) == 0 {
+			return w.Code, ""
+		}
+
+		code, _ := res.Errors[0].Extensions["code"].(string)
+
+		return w.Code, code
+	}
+
+	status, code := serve(`{ blocks(from: 0, to: 200) { number } }`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, errCodeBlocksExceeded, code)
+
+	status, code = serve(`{ blocks(from: 0, to: 20) { number } }`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, errCodeTimeout, code)
+
+	status, code = serve(`{ block { parent { parent { number } } } }`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, errCodeDepthExceeded, code)
+
+	status, code = serve(`{ block {`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, errCodeParseFailed, code)
+
+	status, code = serve(`{ blocks(from: 0, to: 0) { number } }`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "", code)
+}
@@ -0,0 +1,16 @@
+package graphql
+
+import "time"
+
+const (
+	// DefaultMaxQueryCost maximum cost of a graphql query, which is the number
+	// of the resolved fields and the blocks scanned for the logs
+	DefaultMaxQueryCost uint64 = 100000
+	// DefaultMaxQueryDepth maximum depth of the nested fields of a graphql query
+	DefaultMaxQueryDepth uint64 = 10
+	// DefaultMaxQueryBlocks maximum blocks returned by the block ranges of a
+	// graphql query
+	DefaultMaxQueryBlocks uint64 = 1000
+	// DefaultQueryTimeout execution deadline of a graphql query
+	DefaultQueryTimeout = 10 * time.Second
+)
@@ -1,13 +1,16 @@
 package graphql
 
 import (
+	"context"
 	"math/big"
 
 	"github.com/dogechain-lab/dogechain/blockchain"
+	"github.com/dogechain-lab/dogechain/blockchain/addrindex"
 	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/helper/progress"
 	"github.com/dogechain-lab/dogechain/state"
 	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/txpool/proto"
 	"github.com/dogechain-lab/dogechain/types"
 )
 
@@ -33,6 +36,9 @@ type ethBlockchainStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
+	// FinalizedHeader returns the header of the last block with a verified commit quorum
+	FinalizedHeader() *types.Header
+
 	// GetHeaderByNumber returns the header by number
 	GetHeaderByNumber(block uint64) (*types.Header, bool)
 
@@ -54,8 +60,9 @@ type ethBlockchainStore interface {
 	// GetAvgGasPrice returns the average gas price
 	GetAvgGasPrice() *big.Int
 
-	// ApplyTxn applies a transaction object to the blockchain
-	ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error)
+	// ApplyTxnContext applies a transaction object to the blockchain, the execution
+	// is aborted once the context is done
+	ApplyTxnContext(ctx context.Context, header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error)
 
 	// GetSyncProgression retrieves the current sync progression, if any
 	GetSyncProgression() *progress.Progression
@@ -82,6 +89,9 @@ type filterManagerStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
+	// FinalizedHeader returns the header of the last block with a verified commit quorum
+	FinalizedHeader() *types.Header
+
 	// SubscribeEvents subscribes for chain head events
 	SubscribeEvents() blockchain.Subscription
 
@@ -93,4 +103,23 @@ type filterManagerStore interface {
 
 	// GetBlockByNumber returns a block using the provided number
 	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
+
+	// SubscribeTxPoolEvents subscribes for the given types of txpool events
+	SubscribeTxPoolEvents(eventTypes ...proto.EventType) (<-chan *proto.TxPoolEvent, func())
+
+	// FilterBlocksByBloom returns the blocks in the range which might match the filters
+	// by the bloom bits index, and the first block not covered by the index
+	FilterBlocksByBloom(from, to uint64, filters [][][]byte) ([]uint64, uint64, error)
+}
+
+// addressIndexStore provides the methods of the address index
+type addressIndexStore interface {
+	// SearchAddressTransactions returns a page of the transactions touching the address
+	// from the given block on, and whether there are more transactions after the page
+	SearchAddressTransactions(
+		addr types.Address,
+		from uint64,
+		backward bool,
+		pageSize int,
+	) ([]addrindex.TxRef, bool, error)
 }
@@ -2,20 +2,25 @@
 package graphql
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 
+	"github.com/dogechain-lab/dogechain/blockchain/addrindex"
 	"github.com/dogechain-lab/dogechain/crypto"
 	"github.com/dogechain-lab/dogechain/graphql/argtype"
+	"github.com/dogechain-lab/dogechain/helper/progress"
 	rpc "github.com/dogechain-lab/dogechain/jsonrpc"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/dogechain-lab/fastrlp"
 )
 
 var (
-	latestBlockNum, _ = rpc.CreateBlockNumberPointer(rpc.LatestBlockFlag)
+	latestBlockNum, _    = rpc.CreateBlockNumberPointer(rpc.LatestBlockFlag)
+	finalizedBlockNum, _ = rpc.CreateBlockNumberPointer(rpc.FinalizedBlockFlag)
 )
 
 var (
@@ -26,13 +31,26 @@ var (
 	errPendingNotSupport     = errors.New("fetching the pending header is not supported")
 	errBlockNumber           = errors.New("invalid argument 0: block number larger than int64")
 	errFetchingHeader        = errors.New("failed to get header from block hash or block number")
+	errPageBoundsConflict    = errors.New("only one of before and after could be provided")
+	errInvalidPageSize       = errors.New("invalid page size")
+)
+
+const (
+	// defaultTransactionsPageSize is the number of the transactions of an account page
+	// if not provided
+	defaultTransactionsPageSize = 25
+
+	// maxTransactionsPageSize is the max number of the transactions of an account page
+	maxTransactionsPageSize = 1000
 )
 
 // Account represents an Dogechain account at a particular block.
 type Account struct {
 	backend       GraphQLStore
+	resolver      *Resolver
 	address       types.Address
 	blockNrOrHash rpc.BlockNumberOrHash
+	pending       bool // the nonce counts the pending transactions of the pool
 }
 
 // getState fetches the StateDB object for a account.
@@ -78,6 +96,9 @@ func (a *Account) getBlockHeader(number rpc.BlockNumber) (*types.Header, error)
 	case rpc.LatestBlockNumber:
 		return a.backend.Header(), nil
 
+	case rpc.FinalizedBlockNumber, rpc.SafeBlockNumber:
+		return a.backend.FinalizedHeader(), nil
+
 	case rpc.EarliestBlockNumber:
 		header, ok := a.backend.GetHeaderByNumber(uint64(0))
 		if !ok {
@@ -128,6 +149,10 @@ func (a *Account) Balance(ctx context.Context) (argtype.Big, error) {
 }
 
 func (a *Account) TransactionCount(ctx context.Context) (argtype.Uint64, error) {
+	if a.pending {
+		return argtype.Uint64(a.backend.GetNonce(a.address)), nil
+	}
+
 	root, err := a.getStateRoot(ctx)
 	if err != nil {
 		return 0, err
@@ -194,6 +219,109 @@ func (a *Account) Storage(ctx context.Context, args struct{ Slot types.Hash }) (
 	return types.BytesToHash(data), nil
 }
 
+// TransactionsPage is a page of the transactions touching an account, in the
+// descending order
+type TransactionsPage struct {
+	transactions []*Transaction
+	firstPage    bool
+	lastPage     bool
+}
+
+func (p *TransactionsPage) Transactions(ctx context.Context) []*Transaction {
+	return p.transactions
+}
+
+func (p *TransactionsPage) FirstPage(ctx context.Context) bool {
+	return p.firstPage
+}
+
+func (p *TransactionsPage) LastPage(ctx context.Context) bool {
+	return p.lastPage
+}
+
+// Transactions returns a page of the transactions touching the account by the
+// address index, before the given block (the latest by default) or after it.
+func (a *Account) Transactions(ctx context.Context, args struct {
+	Before   *argtype.Long
+	After    *argtype.Long
+	PageSize *int32
+}) (*TransactionsPage, error) {
+	if args.Before != nil && args.After != nil {
+		return nil, errPageBoundsConflict
+	}
+
+	pageSize := defaultTransactionsPageSize
+	if args.PageSize != nil {
+		pageSize = int(*args.PageSize)
+	}
+
+	if pageSize <= 0 || pageSize > maxTransactionsPageSize {
+		return nil, errInvalidPageSize
+	}
+
+	page := &TransactionsPage{}
+
+	var (
+		refs []addrindex.TxRef
+		more bool
+		err  error
+	)
+
+	if args.After != nil {
+		after := uint64(*args.After)
+
+		refs, more, err = a.backend.SearchAddressTransactions(a.address, after+1, false, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		// the pages are always in the descending order
+		for i, j := 0, len(refs)-1; i < j; i, j = i+1, j-1 {
+			refs[i], refs[j] = refs[j], refs[i]
+		}
+
+		page.firstPage = !more
+		page.lastPage = after == 0
+	} else {
+		from := a.backend.Header().Number
+		if args.Before != nil && *args.Before > 0 {
+			from = uint64(*args.Before) - 1
+		}
+
+		refs, more, err = a.backend.SearchAddressTransactions(a.address, from, true, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		page.firstPage = args.Before == nil || *args.Before == 0
+		page.lastPage = !more
+	}
+
+	page.transactions = make([]*Transaction, 0, len(refs))
+
+	for _, ref := range refs {
+		block, ok := a.backend.GetBlockByNumber(ref.BlockNumber, true)
+		if !ok || ref.TxIndex >= uint64(len(block.Transactions)) {
+			return nil, errBlockNotExists
+		}
+
+		tx := &Transaction{
+			backend:  a.backend,
+			resolver: a.resolver,
+			hash:     block.Transactions[ref.TxIndex].Hash(),
+		}
+
+		// resolve the block of the transaction as well
+		if _, err := tx.resolve(ctx); err != nil {
+			return nil, err
+		}
+
+		page.transactions = append(page.transactions, tx)
+	}
+
+	return page, nil
+}
+
 // Log represents an individual log message. All arguments are mandatory.
 type Log struct {
 	backend     GraphQLStore
@@ -208,6 +336,7 @@ func (l *Log) Transaction(ctx context.Context) *Transaction {
 func (l *Log) Account(ctx context.Context, args BlockNumberArgs) *Account {
 	return &Account{
 		backend:       l.backend,
+		resolver:      l.transaction.resolver,
 		address:       l.log.Address,
 		blockNrOrHash: args.NumberOrLatest(),
 	}
@@ -225,6 +354,20 @@ func (l *Log) Data(ctx context.Context) argtype.Bytes {
 	return l.log.Data
 }
 
+// AccessTuple represents an EIP-2930 access list tuple.
+type AccessTuple struct {
+	address     types.Address
+	storageKeys []types.Hash
+}
+
+func (at *AccessTuple) Address(ctx context.Context) types.Address {
+	return at.address
+}
+
+func (at *AccessTuple) StorageKeys(ctx context.Context) []types.Hash {
+	return at.storageKeys
+}
+
 // Transaction represents an Dogechain transaction.
 // backend and hash are mandatory; all others will be fetched when required.
 type Transaction struct {
@@ -241,6 +384,11 @@ type Transaction struct {
 // resolve returns the internal transaction object, fetching it if needed.
 func (t *Transaction) resolve(ctx context.Context) (*types.Transaction, error) {
 	if t.tx == nil {
+		// the fetching stops on the query deadline
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		// 1. Check the chain state for the txn
 		if t.findSealedTx(t.hash) {
 			return t.tx, nil
@@ -372,6 +520,24 @@ func (t *Transaction) GasPrice(ctx context.Context) (argtype.Big, error) {
 	return argtype.Big(*tx.GasPrice), nil
 }
 
+func (t *Transaction) MaxFeePerGas(ctx context.Context) (*argtype.Big, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil || tx.Type != types.DynamicFeeTx {
+		return nil, err
+	}
+
+	return (*argtype.Big)(tx.GetGasFeeCap()), nil
+}
+
+func (t *Transaction) MaxPriorityFeePerGas(ctx context.Context) (*argtype.Big, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil || tx.Type != types.DynamicFeeTx {
+		return nil, err
+	}
+
+	return (*argtype.Big)(tx.GetGasTipCap()), nil
+}
+
 func (t *Transaction) Value(ctx context.Context) (argtype.Big, error) {
 	tx, err := t.resolve(ctx)
 	if err != nil || tx == nil || tx.Value == nil {
@@ -403,6 +569,7 @@ func (t *Transaction) To(ctx context.Context, args BlockNumberArgs) (*Account, e
 
 	return &Account{
 		backend:       t.backend,
+		resolver:      t.resolver,
 		address:       *to,
 		blockNrOrHash: args.NumberOrLatest(),
 	}, nil
@@ -422,6 +589,7 @@ func (t *Transaction) From(ctx context.Context, args BlockNumberArgs) (*Account,
 
 	return &Account{
 		backend:       t.backend,
+		resolver:      t.resolver,
 		address:       from,
 		blockNrOrHash: args.NumberOrLatest(),
 	}, nil
@@ -513,6 +681,7 @@ func (t *Transaction) CreatedContract(ctx context.Context, args BlockNumberArgs)
 
 	return &Account{
 		backend:       t.backend,
+		resolver:      t.resolver,
 		address:       *receipt.ContractAddress,
 		blockNrOrHash: args.NumberOrLatest(),
 	}, nil
@@ -591,6 +760,34 @@ func (t *Transaction) RawReceipt(ctx context.Context) (argtype.Bytes, error) {
 	return receipt.MarshalRLP(), nil
 }
 
+func (t *Transaction) Type(ctx context.Context) (*argtype.Long, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+
+	txType := argtype.Long(tx.Type)
+
+	return &txType, nil
+}
+
+func (t *Transaction) AccessList(ctx context.Context) (*[]*AccessTuple, error) {
+	tx, err := t.resolve(ctx)
+	if err != nil || tx == nil || !tx.Type.IsTyped() {
+		return nil, err
+	}
+
+	ret := make([]*AccessTuple, 0, len(tx.AccessList))
+	for _, tuple := range tx.AccessList {
+		ret = append(ret, &AccessTuple{
+			address:     tuple.Address,
+			storageKeys: tuple.StorageKeys,
+		})
+	}
+
+	return &ret, nil
+}
+
 // Block represents an Dogechain block.
 // backend, and numberOrHash are mandatory. All other fields are lazily fetched
 // when required.
@@ -612,6 +809,11 @@ func (b *Block) resolve(ctx context.Context) (*types.Block, error) {
 		return b.block, nil
 	}
 
+	// the fetching stops on the query deadline
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if b.numberOrHash == nil {
 		b.numberOrHash = &rpc.BlockNumberOrHash{
 			BlockNumber: latestBlockNum,
@@ -658,6 +860,11 @@ func (b *Block) resolveHeader(ctx context.Context) (*types.Header, error) {
 	}
 
 	if b.header == nil {
+		// the fetching stops on the query deadline
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		var exists bool
 
 		if b.hash != types.ZeroHash {
@@ -688,6 +895,9 @@ func (b *Block) getNumericBlockNumber(number rpc.BlockNumber) (uint64, error) {
 	case rpc.LatestBlockNumber:
 		return b.backend.Header().Number, nil
 
+	case rpc.FinalizedBlockNumber, rpc.SafeBlockNumber:
+		return b.backend.FinalizedHeader().Number, nil
+
 	case rpc.EarliestBlockNumber:
 		return 0, nil
 
@@ -706,6 +916,11 @@ func (b *Block) getNumericBlockNumber(number rpc.BlockNumber) (uint64, error) {
 // resolveReceipts returns the list of receipts for this block, fetching them if necessary.
 func (b *Block) resolveReceipts(ctx context.Context) ([]*types.Receipt, error) {
 	if b.receipts == nil {
+		// the fetching stops on the query deadline
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		hash := b.hash
 
 		if hash == types.ZeroHash {
@@ -760,6 +975,18 @@ func (b *Block) GasUsed(ctx context.Context) (argtype.Long, error) {
 	return argtype.Long(b.header.GasUsed), nil
 }
 
+func (b *Block) BaseFeePerGas(ctx context.Context) (*argtype.Big, error) {
+	if _, err := b.resolveHeader(ctx); err != nil {
+		return nil, err
+	}
+
+	if b.header.BaseFee == nil {
+		return nil, nil
+	}
+
+	return (*argtype.Big)(b.header.BaseFee), nil
+}
+
 func (b *Block) Parent(ctx context.Context) (*Block, error) {
 	if _, err := b.resolveHeader(ctx); err != nil {
 		return nil, err
@@ -907,6 +1134,7 @@ func (b *Block) Miner(ctx context.Context, args BlockNumberArgs) (*Account, erro
 
 	return &Account{
 		backend:       b.backend,
+		resolver:      b.resolver,
 		address:       b.header.Miner,
 		blockNrOrHash: args.NumberOrLatest(),
 	}, nil
@@ -992,14 +1220,23 @@ func runFilter(
 	filter *rpc.FilterManager,
 	query *rpc.LogQuery,
 ) ([]*Log, error) {
-	logs, err := filter.GetLogs(query)
+	logs, err := filter.GetLogsContext(ctx, query)
 	if err != nil || logs == nil {
 		return nil, err
 	}
 
+	return toLogs(ctx, backend, resolver, logs)
+}
+
+// toLogs wraps the logs with their transactions
+func toLogs(ctx context.Context, backend GraphQLStore, resolver *Resolver, logs []*rpc.Log) ([]*Log, error) {
 	ret := make([]*Log, 0, len(logs))
 
 	for _, log := range logs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		t := &Transaction{backend: backend, resolver: resolver, hash: log.TxHash}
 		if _, err := t.resolve(ctx); err != nil {
 			return nil, err
@@ -1050,11 +1287,132 @@ func (b *Block) Account(ctx context.Context, args struct {
 
 	return &Account{
 		backend:       b.backend,
+		resolver:      b.resolver,
 		address:       args.Address,
 		blockNrOrHash: *b.numberOrHash,
 	}, nil
 }
 
+func (b *Block) Call(ctx context.Context, args struct {
+	Data CallData
+}) (*CallResult, error) {
+	if _, err := b.resolveHeader(ctx); err != nil {
+		return nil, err
+	}
+
+	return doCall(ctx, b.backend, b.resolver.chainID, b.header, &args.Data)
+}
+
+func (b *Block) EstimateGas(ctx context.Context, args struct {
+	Data CallData
+}) (argtype.Long, error) {
+	if _, err := b.resolveHeader(ctx); err != nil {
+		return 0, err
+	}
+
+	return doEstimateGas(ctx, b.backend, b.resolver.chainID, b.header, &args.Data)
+}
+
+// Pending represents the pending state of the chain. The node never builds a pending
+// block, so that the pending state is the latest state with the transactions of the
+// pool on top.
+type Pending struct {
+	backend  GraphQLStore
+	resolver *Resolver
+}
+
+// transactions returns the pending transactions of the pool, ordered by the sender
+// and the nonce
+func (p *Pending) transactions() []*types.Transaction {
+	pending, _ := p.backend.GetTxs(false)
+
+	senders := make([]types.Address, 0, len(pending))
+
+	for addr := range pending {
+		senders = append(senders, addr)
+	}
+
+	sort.Slice(senders, func(i, j int) bool {
+		return bytes.Compare(senders[i].Bytes(), senders[j].Bytes()) < 0
+	})
+
+	txs := make([]*types.Transaction, 0)
+
+	for _, addr := range senders {
+		txs = append(txs, pending[addr]...)
+	}
+
+	return txs
+}
+
+func (p *Pending) TransactionCount(ctx context.Context) int32 {
+	return int32(len(p.transactions()))
+}
+
+func (p *Pending) Transactions(ctx context.Context) (*[]*Transaction, error) {
+	txs := p.transactions()
+	ret := make([]*Transaction, 0, len(txs))
+
+	for _, tx := range txs {
+		t := &Transaction{
+			backend:  p.backend,
+			resolver: p.resolver,
+			hash:     tx.Hash(),
+			tx:       tx,
+		}
+
+		// resolve the signer of the transaction
+		if _, err := t.resolve(ctx); err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, t)
+	}
+
+	return &ret, nil
+}
+
+func (p *Pending) Account(ctx context.Context, args struct {
+	Address types.Address
+}) *Account {
+	return &Account{
+		backend:       p.backend,
+		resolver:      p.resolver,
+		address:       args.Address,
+		blockNrOrHash: rpc.BlockNumberOrHash{BlockNumber: latestBlockNum},
+		pending:       true,
+	}
+}
+
+func (p *Pending) Call(ctx context.Context, args struct {
+	Data CallData
+}) (*CallResult, error) {
+	return doCall(ctx, p.backend, p.resolver.chainID, p.backend.Header(), &args.Data)
+}
+
+func (p *Pending) EstimateGas(ctx context.Context, args struct {
+	Data CallData
+}) (argtype.Long, error) {
+	return doEstimateGas(ctx, p.backend, p.resolver.chainID, p.backend.Header(), &args.Data)
+}
+
+// SyncState represents the progress of the bulk sync of the node
+type SyncState struct {
+	progression *progress.Progression
+}
+
+func (s *SyncState) StartingBlock() argtype.Long {
+	return argtype.Long(s.progression.StartingBlock)
+}
+
+func (s *SyncState) CurrentBlock() argtype.Long {
+	return argtype.Long(s.progression.CurrentBlock)
+}
+
+func (s *SyncState) HighestBlock() argtype.Long {
+	return argtype.Long(s.progression.HighestBlock.Load())
+}
+
 // Resolver is the top-level object in the GraphQL hierarchy.
 type Resolver struct {
 	backend       GraphQLStore
@@ -1111,6 +1469,21 @@ func (r *Resolver) Block(ctx context.Context, args struct {
 	return block, nil
 }
 
+// Finalized returns the last block with a verified commit quorum
+func (r *Resolver) Finalized(ctx context.Context) (*Block, error) {
+	block := &Block{
+		backend:      r.backend,
+		resolver:     r,
+		numberOrHash: &rpc.BlockNumberOrHash{BlockNumber: finalizedBlockNum},
+	}
+
+	if _, err := block.resolveHeader(ctx); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
 func (r *Resolver) Blocks(ctx context.Context, args struct {
 	From *argtype.Long
 	To   *argtype.Long
@@ -1133,6 +1506,10 @@ func (r *Resolver) Blocks(ctx context.Context, args struct {
 	ret := make([]*Block, 0, to-from+1)
 
 	for i := from; i <= to; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		idx := i // variable copy
 
 		numberOrHash := rpc.BlockNumberOrHash{BlockNumber: &idx}
@@ -1260,3 +1637,35 @@ func (r *Resolver) GasPrice(ctx context.Context) (argtype.Big, error) {
 func (r *Resolver) ChainID(ctx context.Context) (argtype.Big, error) {
 	return argtype.Big(*new(big.Int).SetUint64(r.chainID)), nil
 }
+
+// Pending returns the pending state of the chain
+func (r *Resolver) Pending(ctx context.Context) *Pending {
+	return &Pending{
+		backend:  r.backend,
+		resolver: r,
+	}
+}
+
+// Syncing returns the progress of the bulk sync, or null if the node is not syncing
+func (r *Resolver) Syncing(ctx context.Context) *SyncState {
+	progression := r.backend.GetSyncProgression()
+	if progression == nil {
+		return nil
+	}
+
+	return &SyncState{progression: progression}
+}
+
+// SendRawTransaction adds the RLP encoded transaction to the pool, and returns its hash
+func (r *Resolver) SendRawTransaction(ctx context.Context, args struct{ Data argtype.Bytes }) (types.Hash, error) {
+	tx := &types.Transaction{}
+	if err := tx.UnmarshalRLP(args.Data); err != nil {
+		return types.ZeroHash, err
+	}
+
+	if err := r.backend.AddTx(tx); err != nil {
+		return types.ZeroHash, err
+	}
+
+	return tx.Hash(), nil
+}
@@ -0,0 +1,349 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/graphql/argtype"
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/helper/progress"
+	rpc "github.com/dogechain-lab/dogechain/jsonrpc"
+	"github.com/dogechain-lab/dogechain/state"
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+)
+
+const (
+	// mockCallGas is the gas used by every successful call of the mock store
+	mockCallGas = 30000
+)
+
+var (
+	mockSender   = types.StringToAddress("0x1")
+	mockReceiver = types.StringToAddress("0x2")
+)
+
+// mockStore executes every call with the fixed gas, and keeps the pool in memory
+type mockStore struct {
+	GraphQLStore
+
+	header      *types.Header
+	pending     map[types.Address][]*types.Transaction
+	progression *progress.Progression
+	applied     int    // the number of the applied calls
+	onApply     func() // called on every applied call if set
+}
+
+func newMockStore() *mockStore {
+	header := &types.Header{Number: 10, GasLimit: 100000}
+	header.ComputeHash()
+
+	return &mockStore{
+		header:  header,
+		pending: make(map[types.Address][]*types.Transaction),
+	}
+}
+
+func (m *mockStore) Header() *types.Header {
+	return m.header
+}
+
+func (m *mockStore) FinalizedHeader() *types.Header {
+	return m.header
+}
+
+func (m *mockStore) GetHeaderByNumber(num uint64) (*types.Header, bool) {
+	if num != m.header.Number {
+		return nil, false
+	}
+
+	return m.header, true
+}
+
+func (m *mockStore) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
+	if hash != m.header.Hash {
+		return nil, false
+	}
+
+	return m.header, true
+}
+
+func (m *mockStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return chain.AllForksEnabled.At(blockNumber)
+}
+
+func (m *mockStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	if addr != mockSender {
+		return nil, rpc.ErrStateNotFound
+	}
+
+	return &state.Account{Nonce: 3, Balance: big.NewInt(1000000)}, nil
+}
+
+func (m *mockStore) ApplyTxnContext(
+	ctx context.Context,
+	header *types.Header,
+	txn *types.Transaction,
+) (*runtime.ExecutionResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.applied++
+
+	if m.onApply != nil {
+		m.onApply()
+	}
+
+	switch {
+	case txn.Gas < state.TxGas:
+		return nil, state.ErrNotEnoughIntrinsicGas
+	case txn.Gas < mockCallGas:
+		return &runtime.ExecutionResult{GasUsed: txn.Gas, Err: runtime.ErrOutOfGas}, nil
+	case txn.To != nil && *txn.To == mockSender:
+		return &runtime.ExecutionResult{GasUsed: mockCallGas, Err: runtime.ErrExecutionReverted}, nil
+	}
+
+	return &runtime.ExecutionResult{GasUsed: mockCallGas, ReturnValue: []byte{0x2a}}, nil
+}
+
+func (m *mockStore) GetNonce(addr types.Address) uint64 {
+	return uint64(len(m.pending[addr]))
+}
+
+func (m *mockStore) AddTx(tx *types.Transaction) error {
+	m.pending[tx.From] = append(m.pending[tx.From], tx)
+
+	return nil
+}
+
+func (m *mockStore) GetTxs(inclQueued bool) (
+	map[types.Address][]*types.Transaction,
+	map[types.Address][]*types.Transaction,
+) {
+	return m.pending, nil
+}
+
+func (m *mockStore) GetSyncProgression() *progress.Progression {
+	return m.progression
+}
+
+func execQuery(t *testing.T, store *mockStore, query string) (map[string]interface{}, error) {
+	t.Helper()
+
+	schema, err := graphql.ParseSchema(schema, &Resolver{backend: store, chainID: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := schema.Exec(context.Background(), query, "", nil)
+	if len(response.Errors) > 0 {
+		return nil, response.Errors[0]
+	}
+
+	var data map[string]interface{}
+
+	assert.NoError(t, json.Unmarshal(response.Data, &data))
+
+	return data, nil
+}
+
+func TestGraphQL_CallAndEstimateGas(t *testing.T) {
+	store := newMockStore()
+
+	data, err := execQuery(t, store, `{
+		block {
+			call(data: {from: "`+mockSender.String()+`", to: "`+mockReceiver.String()+`"}) {
+				data
+				gasUsed
+				status
+			}
+			estimateGas(data: {to: "`+mockReceiver.String()+`"})
+		}
+		pending {
+			call(data: {to: "`+mockSender.String()+`"}) {
+				status
+			}
+			estimateGas(data: {from: "`+mockSender.String()+`", to: "`+mockReceiver.String()+`", gas: 50000})
+		}
+	}`)
+	assert.NoError(t, err)
+
+	block, _ := data["block"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"data":    "0x2a",
+		"gasUsed": float64(mockCallGas),
+		"status":  float64(1),
+	}, block["call"])
+	assert.Equal(t, float64(mockCallGas), block["estimateGas"])
+
+	// the reverted calls are reported by the status
+	pending, _ := data["pending"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"status": float64(0)}, pending["call"])
+	assert.Equal(t, float64(mockCallGas), pending["estimateGas"])
+
+	// never succeeds under the given ceiling
+	_, err = execQuery(t, store, `{ pending { estimateGas(data: {to: "`+mockReceiver.String()+`", gas: 25000}) } }`)
+	assert.Error(t, err)
+
+	// the contract creations need the data
+	_, err = execQuery(t, store, `{ pending { call(data: {}) { status } } }`)
+	assert.Error(t, err)
+}
+
+func TestGraphQL_CallAborted(t *testing.T) {
+	store := newMockStore()
+	data := &CallData{From: &mockSender, To: &mockReceiver}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the deadline is reached in the middle of the binary search
+	store.onApply = cancel
+
+	_, err := doEstimateGas(ctx, store, 100, store.header, data)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, store.applied)
+
+	_, err = doCall(ctx, store, 100, store.header, data)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, store.applied)
+}
+
+func TestCallData_ToTransaction(t *testing.T) {
+	store := newMockStore()
+	header := &types.Header{GasLimit: 100000, BaseFee: big.NewInt(100)}
+
+	bigArg := func(v int64) *argtype.Big {
+		return (*argtype.Big)(big.NewInt(v))
+	}
+
+	cases := []struct {
+		name   string
+		data   *CallData
+		feeCap int64
+		tipCap int64
+	}{
+		{
+			"the fee cap is given",
+			&CallData{To: &mockReceiver, MaxFeePerGas: bigArg(500), MaxPriorityFeePerGas: bigArg(10)},
+			500,
+			10,
+		},
+		{
+			"the fee cap falls back to the gas price",
+			&CallData{To: &mockReceiver, GasPrice: bigArg(300), MaxPriorityFeePerGas: bigArg(10)},
+			300,
+			10,
+		},
+		{
+			"the fee cap falls back to the base fee plus the tip",
+			&CallData{To: &mockReceiver, MaxPriorityFeePerGas: bigArg(10)},
+			110,
+			10,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			txn, err := c.data.toTransaction(store, 100, header)
+			assert.NoError(t, err)
+
+			assert.Equal(t, types.DynamicFeeTx, txn.Type)
+			assert.Equal(t, big.NewInt(c.feeCap), txn.GasFeeCap)
+			assert.Equal(t, big.NewInt(c.tipCap), txn.GasTipCap)
+			assert.Equal(t, txn.GasFeeCap, txn.GasPrice)
+		})
+	}
+}
+
+func TestGraphQL_PendingState(t *testing.T) {
+	store := newMockStore()
+
+	for i := uint64(0); i < 2; i++ {
+		assert.NoError(t, store.AddTx(&types.Transaction{
+			Nonce:    i,
+			From:     mockSender,
+			To:       &mockReceiver,
+			GasPrice: big.NewInt(0),
+			Value:    big.NewInt(0),
+		}))
+	}
+
+	data, err := execQuery(t, store, `{
+		pending {
+			transactionCount
+			transactions { nonce index }
+			account(address: "`+mockSender.String()+`") { transactionCount }
+		}
+	}`)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"transactionCount": float64(2),
+		"transactions": []interface{}{
+			map[string]interface{}{"nonce": "0x0", "index": nil},
+			map[string]interface{}{"nonce": "0x1", "index": nil},
+		},
+		"account": map[string]interface{}{"transactionCount": "0x2"},
+	}, data["pending"])
+}
+
+func TestGraphQL_Syncing(t *testing.T) {
+	store := newMockStore()
+
+	data, err := execQuery(t, store, `{ syncing { currentBlock } }`)
+	assert.NoError(t, err)
+	assert.Nil(t, data["syncing"])
+
+	store.progression = &progress.Progression{
+		StartingBlock: 1,
+		CurrentBlock:  5,
+		HighestBlock:  atomic.NewUint64(20),
+	}
+
+	data, err = execQuery(t, store, `{ syncing { startingBlock currentBlock highestBlock } }`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"startingBlock": float64(1),
+		"currentBlock":  float64(5),
+		"highestBlock":  float64(20),
+	}, data["syncing"])
+}
+
+func TestGraphQL_Finalized(t *testing.T) {
+	store := newMockStore()
+
+	data, err := execQuery(t, store, `{ finalized { hash } }`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"hash": store.header.Hash.String()}, data["finalized"])
+}
+
+func TestGraphQL_SendRawTransaction(t *testing.T) {
+	store := newMockStore()
+	tx := &types.Transaction{
+		Nonce:    1,
+		To:       &mockReceiver,
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(1),
+		V:        big.NewInt(27),
+		R:        big.NewInt(1),
+		S:        big.NewInt(1),
+	}
+
+	data, err := execQuery(t, store, `mutation { sendRawTransaction(data: "`+
+		hex.EncodeToHex(tx.MarshalRLP())+`") }`)
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Hash().String(), data["sendRawTransaction"])
+	assert.Len(t, store.pending[types.ZeroAddress], 1)
+
+	_, err = execQuery(t, store, `mutation { sendRawTransaction(data: "0x01") }`)
+	assert.Error(t, err)
+}
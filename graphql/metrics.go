@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"github.com/dogechain-lab/dogechain/helper/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics represents the graphql metrics
+type Metrics struct {
+	// Requests number
+	requests prometheus.Counter
+	// Rejected requests number
+	rejects *prometheus.CounterVec
+}
+
+func (m *Metrics) RequestsCounterInc() {
+	metrics.CounterInc(m.requests)
+}
+
+func (m *Metrics) RejectsCounterInc(reason string) {
+	if m.rejects != nil {
+		m.rejects.With(prometheus.Labels{"reason": reason}).Inc()
+	}
+}
+
+// GetPrometheusMetrics return the graphql metrics instance
+func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
+	constLabels := metrics.ParseLables(labelsWithValues...)
+
+	m := &Metrics{
+		requests: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "graphql",
+			Name:        "requests",
+			Help:        "Requests number",
+			ConstLabels: constLabels,
+		}),
+		rejects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "graphql",
+			Name:        "rejected_requests",
+			Help:        "Rejected requests number, by the exceeded limit",
+			ConstLabels: constLabels,
+		}, []string{"reason"}),
+	}
+
+	prometheus.MustRegister(
+		m.requests,
+		m.rejects,
+	)
+
+	return m
+}
+
+// NilMetrics will return the non operational graphql metrics
+func NilMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// NewDummyMetrics will return the no nil graphql metrics
+func NewDummyMetrics(metrics *Metrics) *Metrics {
+	if metrics != nil {
+		return metrics
+	}
+
+	return NilMetrics()
+}
@@ -17,6 +17,8 @@ const schema string = `
 
     schema {
         query: Query
+        mutation: Mutation
+        subscription: Subscription
     }
 
     # Account is an Dogechain account at a particular block.
@@ -35,6 +37,22 @@ const schema string = `
         # Storage provides access to the storage of a contract account, indexed
         # by its 32 byte slot identifier.
         storage(slot: Bytes32!): Bytes32!
+        # Transactions is a page of the transactions sent from, sent to, creating
+        # or emitting logs of this account, in the descending order. The page
+        # starts before the given block (the latest by default), or ends after
+        # it. At most one of before and after could be provided. The address
+        # index must be enabled.
+        transactions(before: Long, after: Long, pageSize: Int): TransactionsPage!
+    }
+
+    # TransactionsPage is a page of the transactions touching an account.
+    type TransactionsPage {
+        # Transactions are the transactions of this page, in the descending order.
+        transactions: [Transaction!]!
+        # FirstPage is true if this is the newest page.
+        firstPage: Boolean!
+        # LastPage is true if this is the oldest page.
+        lastPage: Boolean!
     }
 
     # Log is an Dogechain event log.
@@ -71,6 +89,12 @@ const schema string = `
         value: BigInt!
         # GasPrice is the price offered to miners for gas, in wei per unit.
         gasPrice: BigInt!
+        # MaxFeePerGas is the maximum fee per gas offered to include a transaction, in wei.
+        # This is null for non-dynamic fee transactions.
+        maxFeePerGas: BigInt
+        # MaxPriorityFeePerGas is the maximum miner tip per gas offered to include a
+        # transaction, in wei. This is null for non-dynamic fee transactions.
+        maxPriorityFeePerGas: BigInt
         # Gas is the maximum amount of gas this transaction can consume.
         gas: Long!
         # InputData is the data supplied to the target of the transaction.
@@ -105,6 +129,18 @@ const schema string = `
         raw: Bytes!
         # RawReceipt is the canonical encoding of the receipt.
         rawReceipt: Bytes!
+        # Type is the EIP-2718 envelope type of the transaction, 0 for legacy ones.
+        type: Long
+        # AccessList is the EIP-2930 access list of the transaction. This is null
+        # for legacy transactions.
+        accessList: [AccessTuple!]
+    }
+
+    # AccessTuple is an address and the storage keys it declares in an EIP-2930
+    # access list.
+    type AccessTuple {
+        address: Address!
+        storageKeys: [Bytes32!]!
     }
 
     # BlockFilterCriteria encapsulates log filter criteria for a filter applied
@@ -154,6 +190,9 @@ const schema string = `
         gasLimit: Long!
         # GasUsed is the amount of gas that was used executing transactions in this block.
         gasUsed: Long!
+        # BaseFeePerGas is the fee per unit of gas burned by the protocol in this block.
+        # This is null for blocks before the london hardfork.
+        baseFeePerGas: BigInt
         # Timestamp is the unix timestamp at which this block was mined.
         timestamp: Long!
         # LogsBloom is a bloom filter that can be used to check if a block may
@@ -178,6 +217,70 @@ const schema string = `
         rawHeader: Bytes!
         # Raw is the RLP encoding of the block.
         raw: Bytes!
+        # Call executes a local call operation at the current block's state.
+        call(data: CallData!): CallResult
+        # EstimateGas estimates the amount of gas that will be required for
+        # successful execution of a transaction at the current block's state.
+        estimateGas(data: CallData!): Long!
+    }
+
+    # CallData represents the data associated with a local contract call.
+    # All fields are optional.
+    input CallData {
+        # From is the address making the call.
+        from: Address
+        # To is the address the call is sent to.
+        to: Address
+        # Gas is the amount of gas sent with the call.
+        gas: Long
+        # GasPrice is the price, in wei, offered for each unit of gas.
+        gasPrice: BigInt
+        # MaxFeePerGas is the maximum fee per gas offered, in wei.
+        maxFeePerGas: BigInt
+        # MaxPriorityFeePerGas is the maximum miner tip per gas offered, in wei.
+        maxPriorityFeePerGas: BigInt
+        # Value is the value, in wei, sent along with the call.
+        value: BigInt
+        # Data is the data sent to the callee.
+        data: Bytes
+    }
+
+    # CallResult is the result of a local call operation.
+    type CallResult {
+        # Data is the return data of the called contract.
+        data: Bytes!
+        # GasUsed is the amount of gas used by the call, after any refunds.
+        gasUsed: Long!
+        # Status is the result of the call - 1 for success or 0 for failure.
+        status: Long!
+    }
+
+    # Pending represents the current pending state. The node never builds a
+    # pending block, so that the pending state is the latest state with the
+    # transactions of the pool on top.
+    type Pending {
+        # TransactionCount is the number of transactions in the pending state.
+        transactionCount: Int!
+        # Transactions is a list of transactions in the current pending state.
+        transactions: [Transaction!]
+        # Account fetches an Dogechain account for the pending state. Its
+        # transaction count includes the pending transactions of the pool.
+        account(address: Address!): Account!
+        # Call executes a local call operation for the pending state.
+        call(data: CallData!): CallResult
+        # EstimateGas estimates the amount of gas that will be required for
+        # successful execution of a transaction for the pending state.
+        estimateGas(data: CallData!): Long!
+    }
+
+    # SyncState contains the current synchronisation state of the node.
+    type SyncState {
+        # StartingBlock is the block number the current sync batch started from.
+        startingBlock: Long!
+        # CurrentBlock is the last block written by the current sync batch.
+        currentBlock: Long!
+        # HighestBlock is the target block of the current sync batch.
+        highestBlock: Long!
     }
 
     # FilterCriteria encapsulates log filter criteria for searching log entries.
@@ -212,6 +315,9 @@ const schema string = `
         # Blocks returns all the blocks between two numbers, inclusive. If
         # to is not supplied, it defaults to the most recent known block.
         blocks(from: Long, to: Long): [Block!]!
+        # Finalized returns the last block with a verified commit quorum, which
+        # is also the safe block since the committed blocks are final.
+        finalized: Block!
         # Transaction returns a transaction specified by its hash.
         transaction(hash: Bytes32!): Transaction
         # Logs returns log entries matching the provided filter.
@@ -221,5 +327,26 @@ const schema string = `
         gasPrice: BigInt!
         # ChainID returns the current chain ID for transaction replay protection.
         chainID: BigInt!
+        # Pending returns the current pending state.
+        pending: Pending!
+        # Syncing returns the bulk sync status of the node, or null if the node
+        # is not syncing.
+        syncing: SyncState
+    }
+
+    type Mutation {
+        # SendRawTransaction sends an RLP-encoded transaction to the network.
+        sendRawTransaction(data: Bytes!): Bytes32!
+    }
+
+    # Subscription streams the chain events over the graphql-transport-ws
+    # websocket protocol.
+    type Subscription {
+        # NewBlocks emits the blocks becoming canonical, including the ones
+        # of a reorg.
+        newBlocks: Block!
+        # NewLogs emits the logs of the blocks becoming canonical which match
+        # the filter.
+        newLogs(filter: BlockFilterCriteria!): Log!
     }
 `
@@ -1,7 +1,9 @@
 package graphql
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -9,7 +11,9 @@ import (
 
 	"github.com/dogechain-lab/dogechain/chain"
 	rpc "github.com/dogechain-lab/dogechain/jsonrpc"
+	"github.com/gorilla/websocket"
 	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
 	"github.com/hashicorp/go-hclog"
 )
 
@@ -29,6 +33,11 @@ type Config struct {
 	BlockRangeLimit          uint64
 	EnablePProf              bool
 	PriceLimit               uint64
+	MaxQueryCost             uint64
+	MaxQueryDepth            uint64
+	MaxQueryBlocks           uint64
+	QueryTimeout             time.Duration
+	Metrics                  *Metrics
 }
 
 // GraphQLStore defines all the methods required
@@ -37,6 +46,7 @@ type GraphQLStore interface {
 	ethStore
 	txPoolStore
 	filterManagerStore
+	addressIndexStore
 }
 
 // NewJSONRPC returns the JSONRPC http server
@@ -48,16 +58,32 @@ func NewGraphQLService(logger hclog.Logger, config *Config) (*GraphQLService, er
 		filterManager: rpc.NewFilterManager(hclog.NewNullLogger(), config.Store, config.BlockRangeLimit),
 	}
 
-	s, err := graphql.ParseSchema(schema, &q)
+	limiter := &queryLimiter{
+		backend:   config.Store,
+		maxCost:   config.MaxQueryCost,
+		maxBlocks: config.MaxQueryBlocks,
+	}
+
+	s, err := graphql.ParseSchema(schema, &q,
+		graphql.MaxDepth(int(config.MaxQueryDepth)),
+		graphql.Tracer(limiter),
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	srv := &GraphQLService{
-		logger:  logger.Named("graphql"),
-		config:  config,
-		ui:      &GraphiQL{},
-		handler: &handler{Schema: s},
+		logger: logger.Named("graphql"),
+		config: config,
+		ui:     &GraphiQL{},
+		handler: &handler{
+			Schema:         s,
+			logger:         logger.Named("graphql"),
+			allowedOrigins: config.AccessControlAllowOrigin,
+			limiter:        limiter,
+			timeout:        config.QueryTimeout,
+			metrics:        NewDummyMetrics(config.Metrics),
+		},
 	}
 
 	// start http server
@@ -109,9 +135,22 @@ func (svc *GraphQLService) setupHTTP() error {
 
 type handler struct {
 	Schema *graphql.Schema
+
+	logger         hclog.Logger
+	allowedOrigins []string
+	limiter        *queryLimiter
+	timeout        time.Duration
+	metrics        *Metrics
 }
 
 func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the subscriptions are served over the websocket
+	if websocket.IsWebSocketUpgrade(r) {
+		h.serveWS(w, r)
+
+		return
+	}
+
 	var params struct {
 		Query         string                 `json:"query"`
 		OperationName string                 `json:"operationName"`
@@ -124,8 +163,10 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.metrics.RequestsCounterInc()
+
 	// exec schema query
-	response := h.Schema.Exec(r.Context(), params.Query, params.OperationName, params.Variables)
+	response := h.exec(r.Context(), params.Query, params.OperationName, params.Variables)
 
 	// marshal response
 	responseJSON, err := json.Marshal(response)
@@ -147,6 +188,49 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// exec executes the query within the deadline, unless the query is rejected by
+// the limits
+func (h handler) exec(
+	ctx context.Context,
+	query string,
+	operationName string,
+	variables map[string]interface{},
+) *graphql.Response {
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	ctx, budget := h.limiter.begin(ctx)
+	defer budget.release()
+
+	response := h.Schema.Exec(ctx, query, operationName, variables)
+
+	if reason, err := budget.rejected(); err != nil {
+		h.metrics.RejectsCounterInc(reason)
+
+		return &graphql.Response{Errors: []*gqlerrors.QueryError{err}}
+	}
+
+	if reason := rejectReason(response.Errors); reason != "" {
+		h.metrics.RejectsCounterInc(reason)
+
+		return response
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		h.metrics.RejectsCounterInc(rejectReasonTimeout)
+
+		err := rejectQuery(errCodeTimeout, "query execution exceeds the deadline %s", h.timeout)
+
+		return &graphql.Response{Errors: []*gqlerrors.QueryError{err}}
+	}
+
+	return response
+}
+
 // The middlewareFactory builds a middleware which enables CORS using the provided config.
 func middlewareFactory(config *Config) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
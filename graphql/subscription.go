@@ -0,0 +1,139 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dogechain-lab/dogechain/blockchain"
+	rpc "github.com/dogechain-lab/dogechain/jsonrpc"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	errSubscriptionClosed = errors.New("chain event subscription closed")
+)
+
+// NewBlocks streams the blocks becoming canonical, the side blocks of the forks
+// are skipped
+func (r *Resolver) NewBlocks(ctx context.Context) (<-chan *Block, error) {
+	sub := r.backend.SubscribeEvents()
+	if sub == nil {
+		return nil, errSubscriptionClosed
+	}
+
+	ch := make(chan *Block)
+
+	go func() {
+		defer close(ch)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evnt, ok := <-sub.GetEvent():
+				if !ok {
+					return
+				}
+
+				if evnt.Type == blockchain.EventFork {
+					continue
+				}
+
+				for _, header := range evnt.NewChain {
+					hash := header.Hash
+
+					block := &Block{
+						backend:      r.backend,
+						resolver:     r,
+						numberOrHash: &rpc.BlockNumberOrHash{BlockHash: &hash},
+						hash:         hash,
+						header:       header,
+					}
+
+					select {
+					case ch <- block:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// NewLogs streams the logs of the blocks becoming canonical which match the filter.
+// The logs are matched the same way as the log filters of the jsonrpc.
+func (r *Resolver) NewLogs(ctx context.Context, args struct{ Filter BlockFilterCriteria }) (<-chan *Log, error) {
+	query := &rpc.LogQuery{}
+
+	if args.Filter.Addresses != nil {
+		query.Addresses = *args.Filter.Addresses
+	}
+
+	if args.Filter.Topics != nil {
+		query.Topics = *args.Filter.Topics
+	}
+
+	sub := r.backend.SubscribeEvents()
+	if sub == nil {
+		return nil, errSubscriptionClosed
+	}
+
+	ch := make(chan *Log)
+
+	go func() {
+		defer close(ch)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evnt, ok := <-sub.GetEvent():
+				if !ok {
+					return
+				}
+
+				if evnt.Type == blockchain.EventFork {
+					continue
+				}
+
+				for _, header := range evnt.NewChain {
+					logs, err := r.blockLogs(ctx, query, header)
+					if err != nil {
+						// skip the block, the subscription keeps alive
+						continue
+					}
+
+					for _, log := range logs {
+						select {
+						case ch <- log:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// blockLogs returns the logs of the block matching the query
+func (r *Resolver) blockLogs(ctx context.Context, query *rpc.LogQuery, header *types.Header) ([]*Log, error) {
+	block, ok := r.backend.GetBlockByHash(header.Hash, true)
+	if !ok {
+		return nil, errBlockNotExists
+	}
+
+	logs, err := r.filterManager.GetLogsFromBlock(query, block)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLogs(ctx, r.backend, r, logs)
+}
@@ -0,0 +1,374 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// graphqlTransportWSProtocol is the websocket sub-protocol of the subscriptions,
+	// see https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+	graphqlTransportWSProtocol = "graphql-transport-ws"
+
+	// wsConnectionInitTimeout is the time for the client to initialise the connection
+	wsConnectionInitTimeout = 10 * time.Second
+
+	// wsWriteTimeout is the time for writing a message to the client
+	wsWriteTimeout = 10 * time.Second
+)
+
+// message types of the graphql-transport-ws protocol
+const (
+	wsMsgConnectionInit = "connection_init"
+	wsMsgConnectionAck  = "connection_ack"
+	wsMsgPing           = "ping"
+	wsMsgPong           = "pong"
+	wsMsgSubscribe      = "subscribe"
+	wsMsgNext           = "next"
+	wsMsgError          = "error"
+	wsMsgComplete       = "complete"
+)
+
+// close codes of the graphql-transport-ws protocol
+const (
+	wsCloseBadRequest             = 4400
+	wsCloseUnauthorized           = 4401
+	wsCloseSubprotocolNotAccepted = 4406
+	wsCloseInitTimeout            = 4408
+	wsCloseSubscriberExists       = 4409
+	wsCloseTooManyInitRequests    = 4429
+)
+
+// wsMessage is a message of the graphql-transport-ws protocol
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsSubscribePayload is the payload of the subscribe message
+type wsSubscribePayload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// wsConn serves the operations of a graphql-transport-ws connection. Every operation
+// runs in its own goroutine until completed by either side.
+type wsConn struct {
+	schema  *graphql.Schema
+	conn    *websocket.Conn
+	logger  hclog.Logger
+	limiter *queryLimiter
+	metrics *Metrics
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	writeLock sync.Mutex
+
+	lock          sync.Mutex
+	initReceived  bool
+	acknowledged  bool
+	subscriptions map[string]context.CancelFunc
+
+	wg sync.WaitGroup
+}
+
+// serveWS upgrades the request and serves the graphql-transport-ws protocol
+func (h handler) serveWS(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{graphqlTransportWSProtocol},
+		CheckOrigin:  h.checkWsOrigin,
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Error("unable to upgrade to a websocket connection", "err", err)
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := &wsConn{
+		schema:        h.Schema,
+		conn:          conn,
+		logger:        h.logger,
+		limiter:       h.limiter,
+		metrics:       h.metrics,
+		ctx:           ctx,
+		cancel:        cancel,
+		subscriptions: make(map[string]context.CancelFunc),
+	}
+
+	c.run()
+}
+
+// checkWsOrigin checks the origin of the websocket request against the allowed
+// origins. The request without origin is not from a browser, which is allowed.
+func (h handler) checkWsOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowedOrigin := range h.allowedOrigins {
+		if allowedOrigin == "*" || allowedOrigin == origin {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *wsConn) run() {
+	defer func() {
+		c.cancel()
+		c.wg.Wait()
+		c.conn.Close()
+	}()
+
+	if c.conn.Subprotocol() != graphqlTransportWSProtocol {
+		c.close(wsCloseSubprotocolNotAccepted, "Subprotocol not acceptable")
+
+		return
+	}
+
+	initTimer := time.AfterFunc(wsConnectionInitTimeout, func() {
+		c.lock.Lock()
+		acknowledged := c.acknowledged
+		c.lock.Unlock()
+
+		if !acknowledged {
+			c.close(wsCloseInitTimeout, "Connection initialisation timeout")
+		}
+	})
+	defer initTimer.Stop()
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type == "" {
+			c.close(wsCloseBadRequest, "Invalid message received")
+
+			return
+		}
+
+		if !c.handleMessage(&msg) {
+			return
+		}
+	}
+}
+
+// handleMessage handles a message of the client, it returns false if the connection
+// is closed
+func (c *wsConn) handleMessage(msg *wsMessage) bool {
+	switch msg.Type {
+	case wsMsgConnectionInit:
+		c.lock.Lock()
+		initReceived := c.initReceived
+		c.initReceived = true
+		c.acknowledged = true
+		c.lock.Unlock()
+
+		if initReceived {
+			c.close(wsCloseTooManyInitRequests, "Too many initialisation requests")
+
+			return false
+		}
+
+		c.write(&wsMessage{Type: wsMsgConnectionAck})
+	case wsMsgPing:
+		c.write(&wsMessage{Type: wsMsgPong})
+	case wsMsgPong:
+		// nothing to do
+	case wsMsgSubscribe:
+		return c.subscribe(msg)
+	case wsMsgComplete:
+		c.lock.Lock()
+		cancel, ok := c.subscriptions[msg.ID]
+		delete(c.subscriptions, msg.ID)
+		c.lock.Unlock()
+
+		if ok {
+			cancel()
+		}
+	default:
+		c.close(wsCloseBadRequest, fmt.Sprintf("Unexpected message of type %s received", msg.Type))
+
+		return false
+	}
+
+	return true
+}
+
+// subscribe starts the operation of the message, it returns false if the connection
+// is closed
+func (c *wsConn) subscribe(msg *wsMessage) bool {
+	var payload wsSubscribePayload
+	if msg.ID == "" || json.Unmarshal(msg.Payload, &payload) != nil {
+		c.close(wsCloseBadRequest, "Invalid message received")
+
+		return false
+	}
+
+	c.lock.Lock()
+
+	if !c.acknowledged {
+		c.lock.Unlock()
+		c.close(wsCloseUnauthorized, "Unauthorized")
+
+		return false
+	}
+
+	if _, ok := c.subscriptions[msg.ID]; ok {
+		c.lock.Unlock()
+		c.close(wsCloseSubscriberExists, fmt.Sprintf("Subscriber for %s already exists", msg.ID))
+
+		return false
+	}
+
+	c.lock.Unlock()
+
+	c.metrics.RequestsCounterInc()
+
+	// the subscriptions are long-lived, the cost is limited for every event
+	ctx, budget := c.limiter.begin(c.ctx)
+
+	c.lock.Lock()
+	c.subscriptions[msg.ID] = budget.release
+	c.lock.Unlock()
+
+	responses, err := c.schema.Subscribe(ctx, payload.Query, payload.OperationName, payload.Variables)
+	if err != nil {
+		c.finish(msg.ID)
+		c.writeError(msg.ID, err)
+
+		return true
+	}
+
+	c.wg.Add(1)
+
+	go func() {
+		defer c.wg.Done()
+
+		c.forward(ctx, msg.ID, budget, responses)
+	}()
+
+	return true
+}
+
+// forward writes the responses of the operation to the client, until the operation
+// ends or is completed by the client. The cost is limited for every response.
+func (c *wsConn) forward(ctx context.Context, id string, budget *queryBudget, responses <-chan interface{}) {
+	first := true
+
+	for res := range responses {
+		response, ok := res.(*graphql.Response)
+
+		// the rejected operation is cancelled, the left responses are drained
+		if reason, err := budget.rejected(); err != nil {
+			if c.finish(id) {
+				c.metrics.RejectsCounterInc(reason)
+				c.write(&wsMessage{ID: id, Type: wsMsgError, Payload: mustMarshal([]*gqlerrors.QueryError{err})})
+			}
+
+			continue
+		}
+
+		budget.reset()
+
+		if !ok || ctx.Err() != nil {
+			continue
+		}
+
+		if reason := rejectReason(response.Errors); reason != "" {
+			c.metrics.RejectsCounterInc(reason)
+		}
+
+		// the operation is rejected before the execution
+		if first && len(response.Errors) > 0 && response.Data == nil {
+			if c.finish(id) {
+				c.write(&wsMessage{ID: id, Type: wsMsgError, Payload: mustMarshal(response.Errors)})
+			}
+
+			return
+		}
+
+		first = false
+
+		c.write(&wsMessage{ID: id, Type: wsMsgNext, Payload: mustMarshal(response)})
+	}
+
+	// the operation completed by the client needs no complete message
+	if c.finish(id) {
+		c.write(&wsMessage{ID: id, Type: wsMsgComplete})
+	}
+}
+
+// finish removes the operation, it returns false if the operation is already
+// completed by the client
+func (c *wsConn) finish(id string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	cancel, ok := c.subscriptions[id]
+	if ok {
+		cancel()
+		delete(c.subscriptions, id)
+	}
+
+	return ok
+}
+
+func (c *wsConn) writeError(id string, err error) {
+	c.write(&wsMessage{
+		ID:      id,
+		Type:    wsMsgError,
+		Payload: mustMarshal([]map[string]string{{"message": err.Error()}}),
+	})
+}
+
+func (c *wsConn) write(msg *wsMessage) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+
+	if err := c.conn.WriteJSON(msg); err != nil {
+		c.logger.Debug("unable to write websocket message", "err", err)
+	}
+}
+
+// close closes the connection with the code, the reading loop ends on closing
+func (c *wsConn) close(code int, reason string) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	_ = c.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(wsWriteTimeout),
+	)
+	_ = c.conn.Close()
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	data, _ := json.Marshal(v)
+
+	return data
+}
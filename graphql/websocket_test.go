@@ -0,0 +1,259 @@
+package graphql
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/blockchain"
+	rpc "github.com/dogechain-lab/dogechain/jsonrpc"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockChainStore serves the blocks and the chain events of the subscriptions
+type mockChainStore struct {
+	*mockStore
+
+	lock     sync.Mutex
+	subs     []*blockchain.MockSubscription
+	blocks   map[types.Hash]*types.Block
+	receipts map[types.Hash][]*types.Receipt
+}
+
+func newMockChainStore() *mockChainStore {
+	return &mockChainStore{
+		mockStore: newMockStore(),
+		blocks:    make(map[types.Hash]*types.Block),
+		receipts:  make(map[types.Hash][]*types.Receipt),
+	}
+}
+
+func (m *mockChainStore) SubscribeEvents() blockchain.Subscription {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	sub := blockchain.NewMockSubscription()
+	m.subs = append(m.subs, sub)
+
+	return sub
+}
+
+func (m *mockChainStore) subscriptions() []*blockchain.MockSubscription {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.subs
+}
+
+func (m *mockChainStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	block, ok := m.blocks[hash]
+
+	return block, ok
+}
+
+func (m *mockChainStore) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	return m.receipts[hash], nil
+}
+
+func (m *mockChainStore) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+	for blockHash, block := range m.blocks {
+		for _, tx := range block.Transactions {
+			if tx.Hash() == hash {
+				return blockHash, true
+			}
+		}
+	}
+
+	return types.ZeroHash, false
+}
+
+func newTestWSServer(t *testing.T, store GraphQLStore) string {
+	t.Helper()
+
+	resolver := &Resolver{
+		backend:       store,
+		chainID:       100,
+		filterManager: rpc.NewFilterManager(hclog.NewNullLogger(), store, 0),
+	}
+
+	s, err := graphql.ParseSchema(schema, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(handler{Schema: s, logger: hclog.NewNullLogger(), metrics: NilMetrics()})
+	t.Cleanup(srv.Close)
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func dialTestWS(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	dialer := websocket.Dialer{Subprotocols: []string{graphqlTransportWSProtocol}}
+
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return conn
+}
+
+func writeTestWS(t *testing.T, conn *websocket.Conn, msg string) {
+	t.Helper()
+
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+}
+
+func readTestWS(t *testing.T, conn *websocket.Conn) *wsMessage {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+
+	return &msg
+}
+
+func readTestWSClose(t *testing.T, conn *websocket.Conn) int {
+	t.Helper()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	_, _, err := conn.ReadMessage()
+
+	closeErr, ok := err.(*websocket.CloseError) //nolint:errorlint
+	if !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	return closeErr.Code
+}
+
+func TestWebsocket_Subscriptions(t *testing.T) {
+	store := newMockChainStore()
+	url := newTestWSServer(t, store)
+	conn := dialTestWS(t, url)
+
+	emitter := types.StringToAddress("0x10")
+	tx := &types.Transaction{Nonce: 1, To: &emitter}
+	block := &types.Block{
+		Header:       &types.Header{Number: 11},
+		Transactions: []*types.Transaction{tx},
+	}
+	block.Header.ComputeHash()
+
+	store.blocks[block.Hash()] = block
+	store.receipts[block.Hash()] = []*types.Receipt{
+		{
+			Logs: []*types.Log{
+				{Address: types.StringToAddress("0x11"), Data: []byte{0x1}},
+				{Address: emitter, Data: []byte{0x2}},
+			},
+		},
+	}
+
+	writeTestWS(t, conn, `{"type": "connection_init"}`)
+	assert.Equal(t, wsMsgConnectionAck, readTestWS(t, conn).Type)
+
+	writeTestWS(t, conn, `{"type": "ping"}`)
+	assert.Equal(t, wsMsgPong, readTestWS(t, conn).Type)
+
+	writeTestWS(t, conn, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { newBlocks { number } }"}}`)
+	writeTestWS(t, conn, `{"id": "2", "type": "subscribe", "payload": {
+		"query": "subscription ($addr: Address!) { newLogs(filter: {addresses: [$addr]}) { data transaction { hash } } }",
+		"variables": {"addr": "`+emitter.String()+`"}
+	}}`)
+
+	assert.Eventually(t, func() bool {
+		return len(store.subscriptions()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for _, sub := range store.subscriptions() {
+		// the forks are skipped
+		sub.Push(&blockchain.Event{Type: blockchain.EventFork, NewChain: []*types.Header{{Number: 10}}})
+		sub.Push(&blockchain.Event{Type: blockchain.EventHead, NewChain: []*types.Header{block.Header}})
+	}
+
+	payloads := make(map[string]string)
+
+	for i := 0; i < 2; i++ {
+		msg := readTestWS(t, conn)
+		assert.Equal(t, wsMsgNext, msg.Type)
+
+		payloads[msg.ID] = string(msg.Payload)
+	}
+
+	assert.JSONEq(t, `{"data": {"newBlocks": {"number": 11}}}`, payloads["1"])
+	assert.JSONEq(t, `{"data": {"newLogs": {"data": "0x02", "transaction": {"hash": "`+
+		tx.Hash().String()+`"}}}}`, payloads["2"])
+
+	// completed by the client
+	writeTestWS(t, conn, `{"id": "1", "type": "complete"}`)
+
+	// the queries are completed after the result
+	writeTestWS(t, conn, `{"id": "3", "type": "subscribe", "payload": {"query": "{ chainID }"}}`)
+
+	msg := readTestWS(t, conn)
+	assert.Equal(t, wsMsgNext, msg.Type)
+	assert.Equal(t, "3", msg.ID)
+	assert.JSONEq(t, `{"data": {"chainID": "0x64"}}`, string(msg.Payload))
+
+	msg = readTestWS(t, conn)
+	assert.Equal(t, wsMsgComplete, msg.Type)
+	assert.Equal(t, "3", msg.ID)
+
+	// the invalid operations are rejected
+	writeTestWS(t, conn, `{"id": "4", "type": "subscribe", "payload": {"query": "subscription { unknown }"}}`)
+
+	msg = readTestWS(t, conn)
+	assert.Equal(t, wsMsgError, msg.Type)
+	assert.Equal(t, "4", msg.ID)
+
+	// the ids are unique
+	writeTestWS(t, conn, `{"id": "2", "type": "subscribe", "payload": {"query": "{ chainID }"}}`)
+	assert.Equal(t, wsCloseSubscriberExists, readTestWSClose(t, conn))
+}
+
+func TestWebsocket_ConnectionInit(t *testing.T) {
+	url := newTestWSServer(t, newMockChainStore())
+
+	// subscribe before the initialisation
+	conn := dialTestWS(t, url)
+	writeTestWS(t, conn, `{"id": "1", "type": "subscribe", "payload": {"query": "{ chainID }"}}`)
+	assert.Equal(t, wsCloseUnauthorized, readTestWSClose(t, conn))
+
+	// initialise twice
+	conn = dialTestWS(t, url)
+	writeTestWS(t, conn, `{"type": "connection_init"}`)
+	assert.Equal(t, wsMsgConnectionAck, readTestWS(t, conn).Type)
+	writeTestWS(t, conn, `{"type": "connection_init"}`)
+	assert.Equal(t, wsCloseTooManyInitRequests, readTestWSClose(t, conn))
+
+	// invalid message
+	conn = dialTestWS(t, url)
+	writeTestWS(t, conn, `{"id": "1"}`)
+	assert.Equal(t, wsCloseBadRequest, readTestWSClose(t, conn))
+
+	// unknown sub-protocol
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+
+	defer conn.Close()
+
+	assert.Equal(t, wsCloseSubprotocolNotAccepted, readTestWSClose(t, conn))
+}
@@ -0,0 +1,162 @@
+package gasprice
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	ErrInvalidPercentile = errors.New("invalid reward percentile")
+	ErrBlockNotFound     = errors.New("block not found")
+)
+
+// FeeHistory is the fee market history of a range of blocks (eip-1559)
+type FeeHistory struct {
+	// OldestBlock is the number of the first block of the range
+	OldestBlock uint64
+	// Reward is the requested percentiles of the effective tips of each block
+	Reward [][]*big.Int
+	// BaseFee is the base fee of each block, including the next block of the range
+	BaseFee []*big.Int
+	// GasUsedRatio is the gas used ratio of each block
+	GasUsedRatio []float64
+}
+
+// txGasAndReward is the sort item of the rewards of a block
+type txGasAndReward struct {
+	gasUsed uint64
+	reward  *big.Int
+}
+
+// FeeHistory returns the fee market history of the given number of blocks up to
+// the last block. The rewards are the given percentiles of the effective tips of
+// the transactions in each block, weighted by the gas used.
+func (oracle *Oracle) FeeHistory(blocks, lastBlock uint64, rewardPercentiles []float64) (*FeeHistory, error) {
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("%w: %f", ErrInvalidPercentile, p)
+		}
+
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return nil, fmt.Errorf("%w: #%d:%f > #%d:%f", ErrInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+
+	// the range could not exceed the genesis
+	if blocks > lastBlock+1 {
+		blocks = lastBlock + 1
+	}
+
+	history := &FeeHistory{
+		OldestBlock: lastBlock + 1 - blocks,
+	}
+
+	if blocks == 0 {
+		return history, nil
+	}
+
+	history.BaseFee = make([]*big.Int, blocks+1)
+	history.GasUsedRatio = make([]float64, blocks)
+
+	if len(rewardPercentiles) > 0 {
+		history.Reward = make([][]*big.Int, blocks)
+	}
+
+	var header *types.Header
+
+	for i := uint64(0); i < blocks; i++ {
+		block, ok := oracle.backend.GetBlockByNumber(history.OldestBlock+i, true)
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrBlockNotFound, history.OldestBlock+i)
+		}
+
+		header = block.Header
+
+		history.BaseFee[i] = baseFeeOrZero(header.BaseFee)
+
+		if header.GasLimit > 0 {
+			history.GasUsedRatio[i] = float64(header.GasUsed) / float64(header.GasLimit)
+		}
+
+		if len(rewardPercentiles) == 0 {
+			continue
+		}
+
+		reward, err := oracle.blockRewards(block, rewardPercentiles)
+		if err != nil {
+			return nil, err
+		}
+
+		history.Reward[i] = reward
+	}
+
+	// the base fee of the next block is derived from the last one
+	history.BaseFee[blocks] = baseFeeOrZero(oracle.backend.CalculateBaseFee(header))
+
+	return history, nil
+}
+
+// blockRewards returns the percentiles of the effective tips of the block
+func (oracle *Oracle) blockRewards(block *types.Block, percentiles []float64) ([]*big.Int, error) {
+	reward := make([]*big.Int, len(percentiles))
+
+	if len(block.Transactions) == 0 {
+		// return an all zero row if there are no transactions to gather data from
+		for i := range reward {
+			reward[i] = new(big.Int)
+		}
+
+		return reward, nil
+	}
+
+	receipts, err := oracle.backend.GetReceiptsByHash(block.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(receipts) != len(block.Transactions) {
+		return nil, fmt.Errorf("receipts of block %d not found", block.Number())
+	}
+
+	sorter := make([]txGasAndReward, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		sorter[i] = txGasAndReward{
+			gasUsed: receipts[i].GasUsed,
+			reward:  tx.EffectiveGasTip(block.Header.BaseFee),
+		}
+	}
+
+	sort.SliceStable(sorter, func(i, j int) bool {
+		return sorter[i].reward.Cmp(sorter[j].reward) < 0
+	})
+
+	var (
+		txIndex     int
+		sumGasUsed  = sorter[0].gasUsed
+		blockGasUse = block.Header.GasUsed
+	)
+
+	for i, p := range percentiles {
+		thresholdGasUsed := uint64(float64(blockGasUse) * p / 100)
+		for sumGasUsed < thresholdGasUsed && txIndex < len(sorter)-1 {
+			txIndex++
+			sumGasUsed += sorter[txIndex].gasUsed
+		}
+
+		reward[i] = new(big.Int).Set(sorter[txIndex].reward)
+	}
+
+	return reward, nil
+}
+
+func baseFeeOrZero(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(baseFee)
+}
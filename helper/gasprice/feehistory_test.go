@@ -0,0 +1,124 @@
+package gasprice
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/blockchain"
+	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockBackend struct {
+	OracleBackend
+	blocks   []*types.Block
+	receipts map[types.Hash][]*types.Receipt
+}
+
+func (m *mockBackend) Header() *types.Header {
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+func (m *mockBackend) GetBlockByNumber(n uint64, full bool) (*types.Block, bool) {
+	if n >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+
+	return m.blocks[n], true
+}
+
+func (m *mockBackend) GetReceiptsByHash(hash types.Hash) ([]*types.Receipt, error) {
+	receipts, ok := m.receipts[hash]
+	if !ok {
+		return nil, errors.New("receipts not found")
+	}
+
+	return receipts, nil
+}
+
+func (m *mockBackend) ForksInTime(number uint64) chain.ForksInTime {
+	return chain.AllForksEnabled.At(number)
+}
+
+func (m *mockBackend) SubscribeEvents() blockchain.Subscription {
+	return nil
+}
+
+func (m *mockBackend) CalculateBaseFee(parent *types.Header) *big.Int {
+	return new(big.Int).Add(parent.BaseFee, big.NewInt(1))
+}
+
+// newMockBackend returns a chain of 3 blocks, the last one contains
+// transactions with the tips of 1, 2 and 3 wei, each using 21000 gas
+func newMockBackend() *mockBackend {
+	m := &mockBackend{
+		receipts: make(map[types.Hash][]*types.Receipt),
+	}
+
+	for i := uint64(0); i < 3; i++ {
+		m.blocks = append(m.blocks, &types.Block{
+			Header: &types.Header{
+				Number:   i,
+				Hash:     types.BytesToHash([]byte{byte(i + 1)}),
+				GasLimit: 126000,
+				BaseFee:  big.NewInt(10),
+			},
+		})
+	}
+
+	last := m.blocks[2]
+	for i := int64(3); i > 0; i-- {
+		last.Transactions = append(last.Transactions, &types.Transaction{
+			Type:      types.DynamicFeeTx,
+			GasFeeCap: big.NewInt(100),
+			GasTipCap: big.NewInt(i),
+		})
+		m.receipts[last.Hash()] = append(m.receipts[last.Hash()], &types.Receipt{GasUsed: 21000})
+		last.Header.GasUsed += 21000
+	}
+
+	return m
+}
+
+func TestOracle_FeeHistory(t *testing.T) {
+	oracle, err := NewOracle(newMockBackend(), Defaults)
+	assert.NoError(t, err)
+
+	t.Run("Rewards", func(t *testing.T) {
+		history, err := oracle.FeeHistory(2, 2, []float64{0, 50, 100})
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(1), history.OldestBlock)
+		assert.Equal(t, []*big.Int{big.NewInt(10), big.NewInt(10), big.NewInt(11)}, history.BaseFee)
+		assert.Equal(t, []float64{0, 0.5}, history.GasUsedRatio)
+		assert.Equal(t, [][]*big.Int{
+			{big.NewInt(0), big.NewInt(0), big.NewInt(0)},
+			{big.NewInt(1), big.NewInt(2), big.NewInt(3)},
+		}, history.Reward)
+	})
+
+	t.Run("Range exceeds the genesis", func(t *testing.T) {
+		history, err := oracle.FeeHistory(10, 1, nil)
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(0), history.OldestBlock)
+		assert.Len(t, history.BaseFee, 3)
+		assert.Len(t, history.GasUsedRatio, 2)
+		assert.Nil(t, history.Reward)
+	})
+
+	t.Run("Block not found", func(t *testing.T) {
+		_, err := oracle.FeeHistory(2, 5, nil)
+		assert.ErrorIs(t, err, ErrBlockNotFound)
+	})
+
+	t.Run("Invalid percentiles", func(t *testing.T) {
+		_, err := oracle.FeeHistory(1, 2, []float64{101})
+		assert.ErrorIs(t, err, ErrInvalidPercentile)
+
+		_, err = oracle.FeeHistory(1, 2, []float64{50, 10})
+		assert.ErrorIs(t, err, ErrInvalidPercentile)
+	})
+}
@@ -49,6 +49,7 @@ type OracleBackend interface {
 	ChainID() uint64
 	ForksInTime(number uint64) chain.ForksInTime
 	SubscribeEvents() blockchain.Subscription
+	CalculateBaseFee(parent *types.Header) *big.Int
 }
 
 // Oracle recommends gas prices based on the content of recent
@@ -229,12 +230,14 @@ type results struct {
 }
 
 type txSorter struct {
-	txs []*types.Transaction
+	txs     []*types.Transaction
+	baseFee *big.Int
 }
 
-func newSorter(txs []*types.Transaction) *txSorter {
+func newSorter(txs []*types.Transaction, baseFee *big.Int) *txSorter {
 	return &txSorter{
-		txs: txs,
+		txs:     txs,
+		baseFee: baseFee,
 	}
 }
 
@@ -243,7 +246,11 @@ func (s *txSorter) Swap(i, j int) {
 	s.txs[i], s.txs[j] = s.txs[j], s.txs[i]
 }
 func (s *txSorter) Less(i, j int) bool {
-	return s.txs[i].GasPrice.Cmp(s.txs[j].GasPrice) < 0
+	// the effective tip would never be negative for transactions in a block
+	tip1 := s.txs[i].EffectiveGasTip(s.baseFee)
+	tip2 := s.txs[j].EffectiveGasTip(s.baseFee)
+
+	return tip1.Cmp(tip2) < 0
 }
 
 // getBlockPrices calculates the lowest transaction gas price in a given block
@@ -268,17 +275,17 @@ func (oracle *Oracle) getBlockValues(
 		return
 	}
 
-	// Sort the transaction by gas price in ascending sort.
+	// Sort the transaction by effective tip in ascending sort.
 	txs := make([]*types.Transaction, len(block.Transactions))
 	copy(txs, block.Transactions)
 
-	sorter := newSorter(txs)
+	sorter := newSorter(txs, block.Header.BaseFee)
 	sort.Sort(sorter)
 
 	var prices = make([]*big.Int, 0, limit)
 
 	for _, tx := range sorter.txs {
-		tip := tx.GasPrice
+		tip := tx.EffectiveGasTip(block.Header.BaseFee)
 		if ignoreUnder != nil && tip.Cmp(ignoreUnder) == -1 {
 			continue
 		}
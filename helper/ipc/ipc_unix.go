@@ -26,7 +26,8 @@ func Listen(path string) (net.Listener, error) {
 		return nil, err
 	}
 
-	if removeErr := os.Remove(path); removeErr != nil {
+	// remove the stale endpoint left by the last run
+	if removeErr := os.Remove(path); removeErr != nil && !os.IsNotExist(removeErr) {
 		return nil, removeErr
 	}
 
@@ -41,3 +42,12 @@ func Listen(path string) (net.Listener, error) {
 
 	return lis, nil
 }
+
+// Remove removes the endpoint of an IPC path, which is left by a closed listener
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
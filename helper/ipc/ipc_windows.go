@@ -24,3 +24,9 @@ func DialTimeout(path string, timeout time.Duration) (net.Conn, error) {
 func Listen(path string) (net.Listener, error) {
 	return npipe.Listen(path)
 }
+
+// Remove removes the endpoint of an IPC path, the named pipes are gone with the
+// listener
+func Remove(path string) error {
+	return nil
+}
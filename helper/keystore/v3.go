@@ -0,0 +1,271 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/helper/keccak"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// StandardScryptN is the scrypt N parameter of the standard key files
+	StandardScryptN = 1 << 18
+	// StandardScryptP is the scrypt P parameter of the standard key files
+	StandardScryptP = 1
+	// LightScryptN is the scrypt N parameter of the light key files, which
+	// take less memory and cpu to decrypt
+	LightScryptN = 1 << 12
+	// LightScryptP is the scrypt P parameter of the light key files
+	LightScryptP = 6
+
+	keyFileVersion = 3
+	scryptR        = 8
+	scryptDKLen    = 32
+	cipherName     = "aes-128-ctr"
+	kdfScrypt      = "scrypt"
+	kdfPBKDF2      = "pbkdf2"
+	pbkdf2PRF      = "hmac-sha256"
+
+	// the scrypt params of the key files are bounded, so that a crafted key
+	// file never takes unbounded memory or cpu to decrypt
+	maxScryptMemory = 1 << 30 // 128 * n * r bytes
+	maxScryptP      = 16
+)
+
+var (
+	ErrDecrypt             = errors.New("could not decrypt key with given password")
+	ErrKeyFileVersion      = errors.New("unsupported key file version")
+	ErrKeyFileCipher       = errors.New("unsupported key file cipher")
+	ErrKeyFileKDF          = errors.New("unsupported key file kdf")
+	ErrKeyFileAddress      = errors.New("invalid key file address")
+	ErrKeyFileKDFParams    = errors.New("invalid key file kdf params")
+	ErrKeyFileMissingField = errors.New("key file field missing")
+)
+
+// keyFileV3 is the web3 secret storage definition of the key file, version 3
+type keyFileV3 struct {
+	Address string       `json:"address"`
+	Crypto  cryptoFields `json:"crypto"`
+	ID      string       `json:"id"`
+	Version int          `json:"version"`
+}
+
+type cryptoFields struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParams           `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// EncryptKey encrypts the private key of the address by the password, into the
+// web3 secret storage key file of version 3 (scrypt and aes-128-ctr)
+func EncryptKey(key []byte, address types.Address, password string, scryptN, scryptP int) ([]byte, error) {
+	salt, err := randomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, err := aesCTRXOR(derivedKey[:16], key, iv)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := keccak.Keccak256(nil, append(append([]byte{}, derivedKey[16:32]...), cipherText...))
+
+	return json.Marshal(&keyFileV3{
+		Address: hex.EncodeToString(address.Bytes()),
+		Crypto: cryptoFields{
+			Cipher:     cipherName,
+			CipherText: hex.EncodeToString(cipherText),
+			CipherParams: cipherParams{
+				IV: hex.EncodeToString(iv),
+			},
+			KDF: kdfScrypt,
+			KDFParams: map[string]interface{}{
+				"n":     scryptN,
+				"r":     scryptR,
+				"p":     scryptP,
+				"dklen": scryptDKLen,
+				"salt":  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(mac),
+		},
+		ID:      uuid.New().String(),
+		Version: keyFileVersion,
+	})
+}
+
+// DecryptKey decrypts the private key of the key file by the password
+func DecryptKey(keyJSON []byte, password string) ([]byte, error) {
+	var keyFile keyFileV3
+	if err := json.Unmarshal(keyJSON, &keyFile); err != nil {
+		return nil, err
+	}
+
+	if keyFile.Version != keyFileVersion {
+		return nil, fmt.Errorf("%w: %d", ErrKeyFileVersion, keyFile.Version)
+	}
+
+	if keyFile.Crypto.Cipher != cipherName {
+		return nil, fmt.Errorf("%w: %s", ErrKeyFileCipher, keyFile.Crypto.Cipher)
+	}
+
+	mac, err := hex.DecodeString(keyFile.Crypto.MAC)
+	if err != nil {
+		return nil, err
+	}
+
+	iv, err := hex.DecodeString(keyFile.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherText, err := hex.DecodeString(keyFile.Crypto.CipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKey, err := deriveKey(&keyFile.Crypto, password)
+	if err != nil {
+		return nil, err
+	}
+
+	calculatedMAC := keccak.Keccak256(nil, append(append([]byte{}, derivedKey[16:32]...), cipherText...))
+	if !bytes.Equal(calculatedMAC, mac) {
+		return nil, ErrDecrypt
+	}
+
+	return aesCTRXOR(derivedKey[:16], cipherText, iv)
+}
+
+// ReadAddress returns the address of the key file, without decrypting it
+func ReadAddress(keyJSON []byte) (types.Address, error) {
+	var keyFile struct {
+		Address string `json:"address"`
+	}
+
+	if err := json.Unmarshal(keyJSON, &keyFile); err != nil {
+		return types.ZeroAddress, err
+	}
+
+	buf, err := hex.DecodeString(keyFile.Address)
+	if err != nil || len(buf) != types.AddressLength {
+		return types.ZeroAddress, ErrKeyFileAddress
+	}
+
+	return types.BytesToAddress(buf), nil
+}
+
+// deriveKey derives the decryption key by the kdf of the key file
+func deriveKey(fields *cryptoFields, password string) ([]byte, error) {
+	salt, err := hex.DecodeString(stringParam(fields.KDFParams, "salt"))
+	if err != nil {
+		return nil, err
+	}
+
+	dkLen := intParam(fields.KDFParams, "dklen")
+	if dkLen < 32 {
+		return nil, ErrKeyFileKDFParams
+	}
+
+	switch fields.KDF {
+	case kdfScrypt:
+		n := intParam(fields.KDFParams, "n")
+		r := intParam(fields.KDFParams, "r")
+		p := intParam(fields.KDFParams, "p")
+
+		if !validScryptParams(n, r, p) {
+			return nil, fmt.Errorf("%w: scrypt n %d, r %d, p %d", ErrKeyFileKDFParams, n, r, p)
+		}
+
+		return scrypt.Key([]byte(password), salt, n, r, p, dkLen)
+	case kdfPBKDF2:
+		if prf := stringParam(fields.KDFParams, "prf"); prf != pbkdf2PRF {
+			return nil, fmt.Errorf("%w: prf %s", ErrKeyFileKDF, prf)
+		}
+
+		c := intParam(fields.KDFParams, "c")
+		if c <= 0 {
+			return nil, ErrKeyFileKDFParams
+		}
+
+		return pbkdf2.Key([]byte(password), salt, c, dkLen, sha256.New), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrKeyFileKDF, fields.KDF)
+}
+
+// validScryptParams returns whether n is a power of two greater than 1, and
+// the memory and parallelism of the params are in range
+func validScryptParams(n, r, p int) bool {
+	if n <= 1 || n&(n-1) != 0 || r <= 0 || p <= 0 || p > maxScryptP {
+		return false
+	}
+
+	return n <= maxScryptMemory/128/r
+}
+
+func aesCTRXOR(key, in, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != aes.BlockSize {
+		return nil, ErrKeyFileMissingField
+	}
+
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+
+	return out, nil
+}
+
+func randomBytes(n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// intParam returns the integer of the kdf params, which are decoded as float64
+func intParam(params map[string]interface{}, name string) int {
+	v, _ := params[name].(float64)
+
+	return int(v)
+}
+
+func stringParam(params map[string]interface{}, name string) string {
+	v, _ := params[name].(string)
+
+	return v
+}
@@ -0,0 +1,123 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// the test vectors of the web3 secret storage definition
+const (
+	testVectorPassword = "testpassword"
+	testVectorKey      = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+
+	testVectorScrypt = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "83dbcc02d8ccb40e466191a123791e0e"},
+			"ciphertext": "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+			"kdf": "scrypt",
+			"kdfparams": {
+				"dklen": 32,
+				"n": 262144,
+				"r": 1,
+				"p": 8,
+				"salt": "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"
+			},
+			"mac": "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+
+	testVectorPBKDF2 = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf": "pbkdf2",
+			"kdfparams": {
+				"c": 262144,
+				"dklen": 32,
+				"prf": "hmac-sha256",
+				"salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+			},
+			"mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+)
+
+func TestDecryptKey_TestVectors(t *testing.T) {
+	for name, keyJSON := range map[string]string{
+		"scrypt": testVectorScrypt,
+		"pbkdf2": testVectorPBKDF2,
+	} {
+		t.Run(name, func(t *testing.T) {
+			key, err := DecryptKey([]byte(keyJSON), testVectorPassword)
+			assert.NoError(t, err)
+			assert.Equal(t, testVectorKey, hex.EncodeToString(key))
+
+			_, err = DecryptKey([]byte(keyJSON), "wrong")
+			assert.ErrorIs(t, err, ErrDecrypt)
+		})
+	}
+}
+
+func TestEncryptKey(t *testing.T) {
+	key, _ := hex.DecodeString(testVectorKey)
+	address := types.StringToAddress("0x008aeeda4d805471df9b2a5b0f38a0c3bcba786b")
+
+	keyJSON, err := EncryptKey(key, address, "foo", LightScryptN, LightScryptP)
+	assert.NoError(t, err)
+
+	decrypted, err := DecryptKey(keyJSON, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, key, decrypted)
+
+	_, err = DecryptKey(keyJSON, "bar")
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	readAddress, err := ReadAddress(keyJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, address, readAddress)
+}
+
+func TestDecryptKey_Unsupported(t *testing.T) {
+	_, err := DecryptKey([]byte(`{"version": 1}`), "")
+	assert.ErrorIs(t, err, ErrKeyFileVersion)
+
+	_, err = DecryptKey([]byte(`{"version": 3, "crypto": {"cipher": "aes-128-cbc"}}`), "")
+	assert.ErrorIs(t, err, ErrKeyFileCipher)
+
+	_, err = DecryptKey([]byte(`{"version": 3, "crypto": {
+		"cipher": "aes-128-ctr", "kdf": "argon2", "kdfparams": {"dklen": 32}
+	}}`), "")
+	assert.ErrorIs(t, err, ErrKeyFileKDF)
+}
+
+func TestDecryptKey_ScryptParams(t *testing.T) {
+	keyFile := func(n, r, p int) []byte {
+		return []byte(fmt.Sprintf(`{"version": 3, "crypto": {
+			"cipher": "aes-128-ctr", "kdf": "scrypt",
+			"kdfparams": {"dklen": 32, "n": %d, "r": %d, "p": %d, "salt": "00"}
+		}}`, n, r, p))
+	}
+
+	for _, params := range [][3]int{
+		{0, 8, 1},       // zero n
+		{1000, 8, 1},    // not power of two
+		{1 << 18, 0, 1}, // zero r
+		{1 << 18, 8, 0}, // zero p
+		{1 << 18, 8, 17},
+		{1 << 21, 8, 1}, // out of memory
+		{1 << 18, -8, 1},
+	} {
+		_, err := DecryptKey(keyFile(params[0], params[1], params[2]), "")
+		assert.ErrorIs(t, err, ErrKeyFileKDFParams, "params %v", params)
+	}
+}
@@ -2,6 +2,7 @@ package kvdb
 
 type KVBatch interface {
 	Set(k, v []byte)
+	Delete(k []byte)
 	Write() error
 }
 
@@ -16,6 +16,10 @@ func (b *levelBatch) Set(k, v []byte) {
 	b.batch.Put(k, v)
 }
 
+func (b *levelBatch) Delete(k []byte) {
+	b.batch.Delete(k)
+}
+
 func (b *levelBatch) Write() error {
 	return b.db.Write(b.batch, nil)
 }
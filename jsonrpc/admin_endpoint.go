@@ -0,0 +1,124 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/versioning"
+)
+
+var (
+	ErrNotificationsUnsupported = errors.New("notifications not supported")
+)
+
+// The types of the peer events
+const (
+	PeerEventAdd    = "add"    // the peer is connected
+	PeerEventDrop   = "drop"   // the peer is disconnected
+	PeerEventFailed = "failed" // the peer failed to connect
+)
+
+// NodeInfo is the information of the local node
+type NodeInfo struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	P2PAddr     string   `json:"p2pAddr"`
+	ListenAddrs []string `json:"listenAddrs"`
+}
+
+// PeerInfo is the information of a connected peer
+type PeerInfo struct {
+	ID        string   `json:"id"`
+	Addrs     []string `json:"addrs"`
+	Protocols []string `json:"protocols"`
+	Static    bool     `json:"static"`
+}
+
+// PeerEvent is a change of the peer connections
+type PeerEvent struct {
+	Type string `json:"type"`
+	Peer string `json:"peer"`
+}
+
+// adminStore provides methods needed for Admin endpoint
+type adminStore interface {
+	// GetNodeInfo returns the id and the addresses of the node
+	GetNodeInfo() *NodeInfo
+
+	// GetPeers returns the connected peers
+	GetPeers() []*PeerInfo
+
+	// AddPeer dials the peer of the multiaddr, a static peer is kept connected
+	AddPeer(rawMultiaddr string, static bool) error
+
+	// RemovePeer disconnects the peer of the id or the multiaddr
+	RemovePeer(peer string) error
+
+	// SubscribePeerEvents subscribes for the peer events
+	SubscribePeerEvents() (<-chan *PeerEvent, func())
+}
+
+// Admin is the admin jsonrpc endpoint, which manages the peers of the node
+type Admin struct {
+	store   adminStore
+	chainID uint64
+
+	metrics *Metrics
+}
+
+// NodeInfo returns the information of the node
+func (a *Admin) NodeInfo() (interface{}, error) {
+	a.metrics.AdminAPICounterInc(AdminNodeInfoLabel)
+
+	info := a.store.GetNodeInfo()
+	info.Name = fmt.Sprintf(_clientVersionTemplate, a.chainID, versioning.Version)
+
+	return info, nil
+}
+
+// Peers returns the connected peers
+func (a *Admin) Peers() (interface{}, error) {
+	a.metrics.AdminAPICounterInc(AdminPeersLabel)
+
+	return a.store.GetPeers(), nil
+}
+
+// AddPeer requests the node to connect the peer of the multiaddr
+func (a *Admin) AddPeer(url string) (interface{}, error) {
+	a.metrics.AdminAPICounterInc(AdminAddPeerLabel)
+
+	if err := a.store.AddPeer(url, false); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RemovePeer disconnects the peer of the id or the multiaddr, the static peers
+// can not be removed
+func (a *Admin) RemovePeer(url string) (interface{}, error) {
+	a.metrics.AdminAPICounterInc(AdminRemovePeerLabel)
+
+	if err := a.store.RemovePeer(url); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// AddTrustedPeer requests the node to connect the peer of the multiaddr, and
+// keeps it connected as a static peer
+func (a *Admin) AddTrustedPeer(url string) (interface{}, error) {
+	a.metrics.AdminAPICounterInc(AdminAddTrustedPeerLabel)
+
+	if err := a.store.AddPeer(url, true); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// PeerEvents is a subscription of the websocket connections only
+func (a *Admin) PeerEvents() (interface{}, error) {
+	return nil, ErrNotificationsUnsupported
+}
@@ -0,0 +1,192 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/versioning"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var errMockUnknownPeer = errors.New("unknown peer")
+
+// mockAdminStore records the added and removed peers
+type mockAdminStore struct {
+	*mockStore
+
+	peers       map[string]*PeerInfo
+	peerEventCh chan *PeerEvent
+}
+
+func newMockAdminStore() *mockAdminStore {
+	return &mockAdminStore{
+		mockStore:   newMockStore(),
+		peers:       map[string]*PeerInfo{},
+		peerEventCh: make(chan *PeerEvent, 10),
+	}
+}
+
+func (m *mockAdminStore) GetNodeInfo() *NodeInfo {
+	return &NodeInfo{
+		ID:          "node",
+		P2PAddr:     "/ip4/127.0.0.1/tcp/1478/p2p/node",
+		ListenAddrs: []string{"/ip4/127.0.0.1/tcp/1478"},
+	}
+}
+
+func (m *mockAdminStore) GetPeers() []*PeerInfo {
+	peers := make([]*PeerInfo, 0, len(m.peers))
+	for _, peer := range m.peers {
+		peers = append(peers, peer)
+	}
+
+	return peers
+}
+
+func (m *mockAdminStore) AddPeer(rawMultiaddr string, static bool) error {
+	m.peers[rawMultiaddr] = &PeerInfo{ID: rawMultiaddr, Static: static}
+
+	return nil
+}
+
+func (m *mockAdminStore) RemovePeer(peer string) error {
+	if _, ok := m.peers[peer]; !ok {
+		return errMockUnknownPeer
+	}
+
+	delete(m.peers, peer)
+
+	return nil
+}
+
+func (m *mockAdminStore) SubscribePeerEvents() (<-chan *PeerEvent, func()) {
+	return m.peerEventCh, func() {}
+}
+
+func TestAdminEndpoint(t *testing.T) {
+	store := newMockAdminStore()
+	admin := &Admin{store, 100, NilMetrics()}
+
+	res, err := admin.NodeInfo()
+	assert.NoError(t, err)
+
+	info, ok := res.(*NodeInfo)
+	assert.True(t, ok)
+	assert.Equal(t, "node", info.ID)
+	assert.Equal(t, fmt.Sprintf(_clientVersionTemplate, 100, versioning.Version), info.Name)
+
+	res, err = admin.AddPeer("a")
+	assert.NoError(t, err)
+	assert.Equal(t, true, res)
+
+	res, err = admin.AddTrustedPeer("b")
+	assert.NoError(t, err)
+	assert.Equal(t, true, res)
+
+	assert.False(t, store.peers["a"].Static)
+	assert.True(t, store.peers["b"].Static)
+
+	res, err = admin.Peers()
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+
+	res, err = admin.RemovePeer("a")
+	assert.NoError(t, err)
+	assert.Equal(t, true, res)
+
+	res, err = admin.RemovePeer("a")
+	assert.ErrorIs(t, err, errMockUnknownPeer)
+	assert.Equal(t, false, res)
+
+	_, err = admin.PeerEvents()
+	assert.ErrorIs(t, err, ErrNotificationsUnsupported)
+}
+
+func TestAdminNamespace(t *testing.T) {
+	store := newMockAdminStore()
+	req := []byte(`{"method": "admin_nodeInfo", "params": [], "id": 1}`)
+
+	cases := []struct {
+		name    string
+		ns      []Namespace
+		enabled bool
+	}{
+		{"all namespaces exclude the admin one", []Namespace{NamespaceAll}, false},
+		{"admin namespace listed", []Namespace{NamespaceAdmin}, true},
+		{"all namespaces with the admin one", []Namespace{NamespaceAll, NamespaceAdmin}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dispatcher := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 0, 0, 0, 0, c.ns)
+
+			data, err := dispatcher.Handle(req)
+			assert.NoError(t, err)
+
+			resp := new(SuccessResponse)
+			assert.NoError(t, json.Unmarshal(data, resp))
+
+			if c.enabled {
+				assert.Nil(t, resp.Error)
+			} else {
+				assert.NotNil(t, resp.Error)
+			}
+		})
+	}
+}
+
+func TestAdminPeerEvents(t *testing.T) {
+	req := []byte(`{"method": "admin_peerEvents", "params": [], "id": 1}`)
+
+	t.Run("peer events are not subscribed without the admin namespace", func(t *testing.T) {
+		store := newMockAdminStore()
+		dispatcher := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 0, 0, 0, 0, []Namespace{
+			NamespaceAll,
+		})
+
+		_, err := dispatcher.HandleWs(req, &mockWsConn{msgCh: make(chan []byte, 1)})
+		assert.Equal(t, NewMethodNotFoundError("admin_peerEvents"), err)
+	})
+
+	t.Run("peer events are pushed thru admin_peerEvents", func(t *testing.T) {
+		store := newMockAdminStore()
+		dispatcher := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 0, 0, 0, 0, []Namespace{
+			NamespaceAdmin,
+		})
+
+		mockConnection := &mockWsConn{
+			msgCh: make(chan []byte, 1),
+		}
+
+		data, err := dispatcher.HandleWs(req, mockConnection)
+		assert.NoError(t, err)
+
+		resp := new(SuccessResponse)
+		assert.NoError(t, json.Unmarshal(data, resp))
+		assert.Nil(t, resp.Error)
+
+		store.peerEventCh <- &PeerEvent{Type: PeerEventAdd, Peer: "a"}
+
+		select {
+		case msg := <-mockConnection.msgCh:
+			var notification struct {
+				Method string `json:"method"`
+				Params struct {
+					Subscription string     `json:"subscription"`
+					Result       *PeerEvent `json:"result"`
+				} `json:"params"`
+			}
+
+			assert.NoError(t, json.Unmarshal(msg, &notification))
+			assert.Equal(t, adminSubscriptionMethod, notification.Method)
+			assert.Equal(t, mockConnection.GetFilterID(), notification.Params.Subscription)
+			assert.Equal(t, &PeerEvent{Type: PeerEventAdd, Peer: "a"}, notification.Params.Result)
+		case <-time.After(2 * time.Second):
+			t.Fatal("peer event not received in 2 seconds")
+		}
+	})
+}
@@ -0,0 +1,395 @@
+package jsonrpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrAPIKeyRequired = errors.New("api key required")
+	ErrAPIKeyInvalid  = errors.New("invalid api key")
+	ErrJWTMalformed   = errors.New("malformed jwt token")
+	ErrJWTAlgorithm   = errors.New("unsupported jwt algorithm")
+	ErrJWTSignature   = errors.New("invalid jwt signature")
+	ErrJWTExpired     = errors.New("jwt token expired")
+	ErrJWTNotValidYet = errors.New("jwt token not valid yet")
+	ErrJWTIatMissing  = errors.New("jwt token missing iat")
+	ErrJWTIatStale    = errors.New("jwt token iat out of range")
+)
+
+const (
+	// apiKeyHeader is the http header carrying the api key
+	apiKeyHeader = "X-API-Key"
+
+	// bearerPrefix is the prefix of the jwt token in the authorization header
+	bearerPrefix = "Bearer "
+
+	// jwtIatTolerance is the max difference between the iat claim and the local
+	// time, which limits the replay of a captured token
+	jwtIatTolerance = 60 * time.Second
+)
+
+// privilegedNamespaces are the namespaces requiring the jwt auth once it is enabled
+var privilegedNamespaces = map[Namespace]struct{}{
+	NamespaceDebug:    {},
+	NamespaceTxpool:   {},
+	NamespaceTrace:    {},
+	NamespaceAdmin:    {},
+	NamespacePersonal: {},
+}
+
+// privilegedMethods are the methods of the other namespaces signing by the unlocked
+// keystore accounts, which require the jwt auth as the personal namespace, otherwise
+// any caller could send from the account unlocked by the operator
+var privilegedMethods = map[string]struct{}{
+	"eth_sendTransaction":  {},
+	"eth_signTransaction":  {},
+	"eth_sign":             {},
+	"eth_signTypedData_v4": {},
+}
+
+// unlockMethods are the methods unlocking the keystore accounts or signing by the
+// unlocked ones, which are refused over http and websocket without the jwt auth,
+// unless the insecure unlock is allowed. Otherwise any remote caller could send
+// from the account unlocked by the operator.
+var unlockMethods = map[string]struct{}{
+	"personal_unlockAccount": {},
+	"eth_sendTransaction":    {},
+	"eth_signTransaction":    {},
+	"eth_sign":               {},
+	"eth_signTypedData_v4":   {},
+}
+
+// APIKey is the credential of a client, and its quotas. The zero quotas are
+// unlimited, while the non-zero ones only tighten the global limits.
+type APIKey struct {
+	Name              string      // the name reported in the metrics, never the key itself
+	Key               string      // the secret key
+	RequestsPerSecond float64     // max requests per second, batch items are counted one by one
+	BatchLengthLimit  uint64      // max length of a batch request
+	BlockRangeLimit   uint64      // max block range of eth_getLogs
+	Namespaces        []Namespace // allowed namespaces, all enabled namespaces if empty
+}
+
+// apiKeyState is the api key with its runtime states
+type apiKeyState struct {
+	*APIKey
+
+	limiter    *rate.Limiter          // nil if unlimited
+	namespaces map[Namespace]struct{} // nil if all namespaces allowed
+}
+
+func newAPIKeyState(key *APIKey) *apiKeyState {
+	state := &apiKeyState{
+		APIKey: key,
+	}
+
+	if key.RequestsPerSecond > 0 {
+		burst := int(key.RequestsPerSecond)
+		if burst < 1 {
+			burst = 1
+		}
+
+		state.limiter = rate.NewLimiter(rate.Limit(key.RequestsPerSecond), burst)
+	}
+
+	if len(key.Namespaces) > 0 {
+		state.namespaces = make(map[Namespace]struct{}, len(key.Namespaces))
+
+		for _, ns := range key.Namespaces {
+			state.namespaces[ns] = struct{}{}
+		}
+	}
+
+	return state
+}
+
+// allowNamespace checks whether the namespace is allowed for the key
+func (k *apiKeyState) allowNamespace(ns Namespace) bool {
+	if k.namespaces == nil {
+		return true
+	}
+
+	if _, ok := k.namespaces[NamespaceAll]; ok {
+		return true
+	}
+
+	_, ok := k.namespaces[ns]
+
+	return ok
+}
+
+// caller is the identity of a request. The nil caller is trusted, such as the
+// ipc client, which is not limited at all.
+type caller struct {
+	key        *apiKeyState // nil if the api key is not required
+	privileged bool         // whether the privileged namespaces are allowed
+	noUnlock   bool         // whether the unlock methods are refused
+}
+
+// authenticator identifies the callers of the http and websocket requests
+type authenticator struct {
+	keys                map[string]*apiKeyState
+	jwtSecret           []byte
+	allowInsecureUnlock bool
+}
+
+func newAuthenticator(keys []*APIKey, jwtSecret []byte, allowInsecureUnlock bool) *authenticator {
+	a := &authenticator{
+		keys:                make(map[string]*apiKeyState, len(keys)),
+		jwtSecret:           jwtSecret,
+		allowInsecureUnlock: allowInsecureUnlock,
+	}
+
+	for i, key := range keys {
+		// copy it, do not modify the config
+		key := *key
+		if key.Name == "" {
+			key.Name = fmt.Sprintf("key%d", i)
+		}
+
+		a.keys[key.Key] = newAPIKeyState(&key)
+	}
+
+	return a
+}
+
+// enabled returns whether any of the auth is enabled
+func (a *authenticator) enabled() bool {
+	return len(a.keys) > 0 || len(a.jwtSecret) > 0
+}
+
+// authenticate identifies the caller of the http request. The api key is read from
+// the header, or the first path segment after the prefix. The jwt token is read from
+// the authorization header. The unlock methods are refused without the jwt auth,
+// unless the insecure unlock is allowed.
+func (a *authenticator) authenticate(r *http.Request, prefix string) (*caller, error) {
+	noUnlock := len(a.jwtSecret) == 0 && !a.allowInsecureUnlock
+
+	if !a.enabled() {
+		if !noUnlock {
+			return nil, nil
+		}
+
+		return &caller{privileged: true, noUnlock: true}, nil
+	}
+
+	c := &caller{
+		privileged: true,
+		noUnlock:   noUnlock,
+	}
+
+	if len(a.keys) > 0 {
+		key := r.Header.Get(apiKeyHeader)
+		if key == "" {
+			key = strings.SplitN(strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/"), "/", 2)[0]
+		}
+
+		if key == "" {
+			return nil, ErrAPIKeyRequired
+		}
+
+		state, ok := a.keys[key]
+		if !ok {
+			return nil, ErrAPIKeyInvalid
+		}
+
+		c.key = state
+	}
+
+	if len(a.jwtSecret) > 0 {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, bearerPrefix) {
+			// not a privileged caller, but still able to call the others
+			c.privileged = false
+
+			return c, nil
+		}
+
+		if err := verifyJWT(strings.TrimPrefix(auth, bearerPrefix), a.jwtSecret, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// verifyJWT verifies the HS256 signed jwt token. The iat claim is required within
+// the tolerance of the local time, the exp and nbf claims are checked if present.
+func verifyJWT(token string, secret []byte, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrJWTMalformed
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ErrJWTMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return ErrJWTMalformed
+	}
+
+	if header.Alg != "HS256" {
+		return ErrJWTAlgorithm
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrJWTMalformed
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return ErrJWTSignature
+	}
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrJWTMalformed
+	}
+
+	var claims struct {
+		Iat *float64 `json:"iat"`
+		Exp *float64 `json:"exp"`
+		Nbf *float64 `json:"nbf"`
+	}
+
+	if err := json.Unmarshal(rawClaims, &claims); err != nil {
+		return ErrJWTMalformed
+	}
+
+	if claims.Exp != nil && float64(now.Unix()) >= *claims.Exp {
+		return ErrJWTExpired
+	}
+
+	if claims.Nbf != nil && float64(now.Unix()) < *claims.Nbf {
+		return ErrJWTNotValidYet
+	}
+
+	if claims.Iat == nil {
+		return ErrJWTIatMissing
+	}
+
+	if math.Abs(float64(now.Unix())-*claims.Iat) > jwtIatTolerance.Seconds() {
+		return ErrJWTIatStale
+	}
+
+	return nil
+}
+
+// keyName returns the name of the caller key for the metrics
+func (c *caller) keyName() string {
+	if c == nil || c.key == nil {
+		return ""
+	}
+
+	return c.key.Name
+}
+
+// batchLengthLimit returns the batch length limit of the caller, which is the
+// tighter one of the global limit and the key quota
+func (c *caller) batchLengthLimit(limit uint64) uint64 {
+	if c == nil || c.key == nil || c.key.BatchLengthLimit == 0 {
+		return limit
+	}
+
+	if limit == 0 || c.key.BatchLengthLimit < limit {
+		return c.key.BatchLengthLimit
+	}
+
+	return limit
+}
+
+// IsPrivilegedMethod returns whether the method is of the privileged namespaces,
+// or a privileged method, which requires the jwt auth once it is enabled
+func IsPrivilegedMethod(method string) bool {
+	if _, ok := privilegedNamespaces[Namespace(strings.SplitN(method, "_", 2)[0])]; ok {
+		return true
+	}
+
+	_, ok := privilegedMethods[method]
+
+	return ok
+}
+
+// checkCaller checks whether the caller is allowed to send the request, the rate
+// limit is consumed on every request, including the rejected ones
+func (d *Dispatcher) checkCaller(c *caller, req Request) Error {
+	if c == nil {
+		return nil
+	}
+
+	name := c.keyName()
+	ns := Namespace(strings.SplitN(req.Method, "_", 2)[0])
+
+	if c.key != nil {
+		d.metrics.APIKeyRequestsInc(name)
+
+		if c.key.limiter != nil && !c.key.limiter.Allow() {
+			d.metrics.APIKeyRejectsInc(name, "rate_limit")
+
+			return NewLimitExceededError("request rate limit exceeded")
+		}
+
+		if !c.key.allowNamespace(ns) {
+			d.metrics.APIKeyRejectsInc(name, "namespace")
+
+			return NewUnauthorizedError(fmt.Sprintf("namespace %s is not allowed", ns))
+		}
+	}
+
+	if _, ok := privilegedNamespaces[ns]; ok && !c.privileged {
+		d.metrics.APIKeyRejectsInc(name, "jwt")
+
+		return NewUnauthorizedError(fmt.Sprintf("namespace %s requires jwt auth", ns))
+	}
+
+	if _, ok := privilegedMethods[req.Method]; ok && !c.privileged {
+		d.metrics.APIKeyRejectsInc(name, "jwt")
+
+		return NewUnauthorizedError(fmt.Sprintf("method %s requires jwt auth", req.Method))
+	}
+
+	if _, ok := unlockMethods[req.Method]; ok && c.noUnlock {
+		d.metrics.APIKeyRejectsInc(name, "insecure_unlock")
+
+		return NewUnauthorizedError(fmt.Sprintf("method %s is forbidden over http and websocket without jwt auth",
+			req.Method))
+	}
+
+	if req.Method == "eth_getLogs" && c.key != nil && c.key.BlockRangeLimit > 0 && d.filterManager != nil {
+		var params []*LogQuery
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params) == 0 || params[0] == nil {
+			// let the handler report the invalid params
+			return nil
+		}
+
+		// the other errors are reported by the handler
+		err := d.filterManager.checkBlockRange(params[0], c.key.BlockRangeLimit)
+		if errors.Is(err, ErrBlockRangeTooHigh) {
+			d.metrics.APIKeyRejectsInc(name, "block_range")
+
+			return NewLimitExceededError(err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+package jsonrpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var testJWTSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// signTestJWT returns the HS256 signed token of the claims
+func signTestJWT(t *testing.T, alg string, claims map[string]interface{}, secret []byte) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	assert.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	assert.NoError(t, err)
+
+	signing := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signing))
+
+	return signing + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyJWT(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{
+			"valid token",
+			signTestJWT(t, "HS256", map[string]interface{}{"iat": now.Unix()}, testJWTSecret),
+			nil,
+		},
+		{
+			"malformed token",
+			"abc.def",
+			ErrJWTMalformed,
+		},
+		{
+			"wrong algorithm",
+			signTestJWT(t, "none", map[string]interface{}{}, testJWTSecret),
+			ErrJWTAlgorithm,
+		},
+		{
+			"wrong secret",
+			signTestJWT(t, "HS256", map[string]interface{}{}, []byte("another secret")),
+			ErrJWTSignature,
+		},
+		{
+			"expired token",
+			signTestJWT(t, "HS256", map[string]interface{}{
+				"iat": now.Unix(),
+				"exp": now.Add(-time.Minute).Unix(),
+			}, testJWTSecret),
+			ErrJWTExpired,
+		},
+		{
+			"token not valid yet",
+			signTestJWT(t, "HS256", map[string]interface{}{
+				"iat": now.Unix(),
+				"nbf": now.Add(time.Minute).Unix(),
+			}, testJWTSecret),
+			ErrJWTNotValidYet,
+		},
+		{
+			"missing iat",
+			signTestJWT(t, "HS256", map[string]interface{}{}, testJWTSecret),
+			ErrJWTIatMissing,
+		},
+		{
+			"stale iat",
+			signTestJWT(t, "HS256", map[string]interface{}{"iat": now.Add(-2 * time.Minute).Unix()}, testJWTSecret),
+			ErrJWTIatStale,
+		},
+		{
+			"future iat",
+			signTestJWT(t, "HS256", map[string]interface{}{"iat": now.Add(2 * time.Minute).Unix()}, testJWTSecret),
+			ErrJWTIatStale,
+		},
+		{
+			"iat within the tolerance",
+			signTestJWT(t, "HS256", map[string]interface{}{"iat": now.Add(-30 * time.Second).Unix()}, testJWTSecret),
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.ErrorIs(t, verifyJWT(c.token, testJWTSecret, now), c.err)
+		})
+	}
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	auth := newAuthenticator([]*APIKey{
+		{Name: "partner", Key: "secret-key"},
+	}, testJWTSecret, false)
+
+	token := signTestJWT(t, "HS256", map[string]interface{}{"iat": time.Now().Unix()}, testJWTSecret)
+
+	t.Run("api key from header", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", nil)
+		req.Header.Set(apiKeyHeader, "secret-key")
+
+		c, err := auth.authenticate(req, "/")
+		assert.NoError(t, err)
+		assert.Equal(t, "partner", c.keyName())
+		assert.False(t, c.privileged)
+	})
+
+	t.Run("api key from path", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/ws/secret-key", nil)
+		req.Header.Set("Authorization", bearerPrefix+token)
+
+		c, err := auth.authenticate(req, "/ws")
+		assert.NoError(t, err)
+		assert.Equal(t, "partner", c.keyName())
+		assert.True(t, c.privileged)
+	})
+
+	t.Run("missing api key", func(t *testing.T) {
+		_, err := auth.authenticate(httptest.NewRequest("POST", "/", nil), "/")
+		assert.ErrorIs(t, err, ErrAPIKeyRequired)
+	})
+
+	t.Run("invalid api key", func(t *testing.T) {
+		_, err := auth.authenticate(httptest.NewRequest("POST", "/unknown", nil), "/")
+		assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+	})
+
+	t.Run("invalid jwt token", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/secret-key", nil)
+		req.Header.Set("Authorization", bearerPrefix+"abc")
+
+		_, err := auth.authenticate(req, "/")
+		assert.ErrorIs(t, err, ErrJWTMalformed)
+	})
+
+	t.Run("auth disabled", func(t *testing.T) {
+		c, err := newAuthenticator(nil, nil, true).authenticate(httptest.NewRequest("POST", "/", nil), "/")
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+	})
+
+	t.Run("insecure unlock refused", func(t *testing.T) {
+		c, err := newAuthenticator(nil, nil, false).authenticate(httptest.NewRequest("POST", "/", nil), "/")
+		assert.NoError(t, err)
+		assert.True(t, c.privileged)
+		assert.True(t, c.noUnlock)
+	})
+
+	t.Run("unlock allowed with jwt", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/secret-key", nil)
+
+		c, err := auth.authenticate(req, "/")
+		assert.NoError(t, err)
+		assert.False(t, c.noUnlock)
+	})
+}
+
+func TestDispatcher_CallerQuotas(t *testing.T) {
+	store := newMockStore()
+	store.header = &types.Header{Number: 1000}
+
+	dispatcher := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 0, 0, 0, 0, []Namespace{
+		NamespaceAll,
+	})
+
+	newCaller := func(key *APIKey, privileged bool) *caller {
+		return &caller{
+			key:        newAPIKeyState(key),
+			privileged: privileged,
+		}
+	}
+
+	expectResult := func(t *testing.T, resp []byte) {
+		t.Helper()
+
+		var res interface{}
+		assert.NoError(t, expectJSONResult(resp, &res))
+	}
+
+	expectError := func(t *testing.T, resp []byte, code int) {
+		t.Helper()
+
+		var res ErrorResponse
+		assert.NoError(t, json.Unmarshal(resp, &res))
+
+		if assert.NotNil(t, res.Error) {
+			assert.Equal(t, code, res.Error.Code)
+		}
+	}
+
+	t.Run("namespaces", func(t *testing.T) {
+		c := newCaller(&APIKey{Namespaces: []Namespace{NamespaceWeb3}}, true)
+
+		resp, err := dispatcher.handle(c, []byte(`{"method": "web3_clientVersion"}`))
+		assert.NoError(t, err)
+		expectResult(t, resp)
+
+		resp, err = dispatcher.handle(c, []byte(`{"method": "net_version"}`))
+		assert.NoError(t, err)
+		expectError(t, resp, -32001)
+	})
+
+	t.Run("privileged namespaces", func(t *testing.T) {
+		resp, err := dispatcher.handle(newCaller(&APIKey{}, false), []byte(`{"method": "txpool_status"}`))
+		assert.NoError(t, err)
+		expectError(t, resp, -32001)
+
+		resp, err = dispatcher.handle(newCaller(&APIKey{}, true), []byte(`{"method": "txpool_status"}`))
+		assert.NoError(t, err)
+		expectResult(t, resp)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		c := newCaller(&APIKey{RequestsPerSecond: 2}, true)
+
+		for i := 0; i < 2; i++ {
+			resp, err := dispatcher.handle(c, []byte(`{"method": "web3_clientVersion"}`))
+			assert.NoError(t, err)
+			expectResult(t, resp)
+		}
+
+		resp, err := dispatcher.handle(c, []byte(`{"method": "web3_clientVersion"}`))
+		assert.NoError(t, err)
+		expectError(t, resp, -32005)
+	})
+
+	t.Run("batch length", func(t *testing.T) {
+		c := newCaller(&APIKey{BatchLengthLimit: 1}, true)
+
+		resp, err := dispatcher.handle(c, []byte(`[
+			{"id": 1, "method": "web3_clientVersion"},
+			{"id": 2, "method": "web3_clientVersion"}
+		]`))
+		assert.NoError(t, err)
+		expectError(t, resp, -32600)
+	})
+
+	t.Run("block range", func(t *testing.T) {
+		c := newCaller(&APIKey{BlockRangeLimit: 10}, true)
+
+		resp, err := dispatcher.handle(c, []byte(`{
+			"method": "eth_getLogs",
+			"params": [{"fromBlock": "0x1", "toBlock": "0x64"}]
+		}`))
+		assert.NoError(t, err)
+		expectError(t, resp, -32005)
+
+		resp, err = dispatcher.handle(c, []byte(`{
+			"method": "eth_getLogs",
+			"params": [{"fromBlock": "0x1", "toBlock": "0x5"}]
+		}`))
+		assert.NoError(t, err)
+		expectResult(t, resp)
+	})
+
+	t.Run("trusted caller", func(t *testing.T) {
+		resp, err := dispatcher.handle(nil, []byte(`{"method": "txpool_status"}`))
+		assert.NoError(t, err)
+		expectResult(t, resp)
+	})
+}
@@ -113,15 +113,21 @@ func (e *ObjectError) MarshalJSON() ([]byte, error) {
 }
 
 const (
-	PendingBlockFlag  = "pending"
-	LatestBlockFlag   = "latest"
-	EarliestBlockFlag = "earliest"
+	PendingBlockFlag   = "pending"
+	LatestBlockFlag    = "latest"
+	EarliestBlockFlag  = "earliest"
+	FinalizedBlockFlag = "finalized"
+	SafeBlockFlag      = "safe"
 )
 
+// The finalized and safe blocks are the same one, which is the last block with
+// a verified commit quorum, since the ibft blocks are final once committed.
 const (
-	PendingBlockNumber  = BlockNumber(-3)
-	LatestBlockNumber   = BlockNumber(-2)
-	EarliestBlockNumber = BlockNumber(-1)
+	SafeBlockNumber      = BlockNumber(-5)
+	FinalizedBlockNumber = BlockNumber(-4)
+	PendingBlockNumber   = BlockNumber(-3)
+	LatestBlockNumber    = BlockNumber(-2)
+	EarliestBlockNumber  = BlockNumber(-1)
 )
 
 type BlockNumber int64
@@ -134,7 +140,7 @@ type BlockNumberOrHash struct {
 // UnmarshalJSON will try to extract the filter's data.
 // Here are the possible input formats :
 //
-// 1 - "latest", "pending" or "earliest"	- self-explaining keywords
+// 1 - "latest", "pending", "earliest", "finalized" or "safe"	- self-explaining keywords
 // 2 - "0x2"								- block number #2 (EIP-1898 backward compatible)
 // 3 - {blockNumber:	"0x2"}				- EIP-1898 compliant block number #2
 // 4 - {blockHash:		"0xe0e..."}			- EIP-1898 compliant block hash 0xe0e...
@@ -179,6 +185,10 @@ func StringToBlockNumber(str string) (BlockNumber, error) {
 		return LatestBlockNumber, nil
 	case EarliestBlockFlag:
 		return EarliestBlockNumber, nil
+	case FinalizedBlockFlag:
+		return FinalizedBlockNumber, nil
+	case SafeBlockFlag:
+		return SafeBlockNumber, nil
 	}
 
 	n, err := types.ParseUint64orHex(&str)
@@ -223,6 +233,19 @@ func NewRPCErrorResponse(id interface{}, errCode int, err string, data []byte, j
 	return response
 }
 
+// handleErrorResponse returns the json-rpc error of a request failed to be
+// handled, the id is unknown. The errors without a code are internal errors.
+func handleErrorResponse(err error) []byte {
+	rpcErr, ok := err.(Error) //nolint:errorlint
+	if !ok {
+		rpcErr = NewInternalError(err.Error())
+	}
+
+	resp, _ := NewRPCResponse(nil, "2.0", nil, rpcErr).Bytes()
+
+	return resp
+}
+
 // NewRPCResponse returns Success/Error response object
 func NewRPCResponse(id interface{}, jsonrpcver string, reply []byte, err Error) Response {
 	var response Response
@@ -14,6 +14,8 @@ func TestBlockNumberOrHash_UnmarshalJSON(t *testing.T) {
 
 	blockNumberZero := BlockNumber(0x0)
 	blockNumberLatest := LatestBlockNumber
+	blockNumberFinalized := FinalizedBlockNumber
+	blockNumberSafe := SafeBlockNumber
 
 	tests := []struct {
 		name        string
@@ -56,6 +58,22 @@ func TestBlockNumberOrHash_UnmarshalJSON(t *testing.T) {
 				BlockNumber: &blockNumberLatest,
 			},
 		},
+		{
+			"should unmarshal finalized block number properly",
+			`"finalized"`,
+			false,
+			BlockNumberOrHash{
+				BlockNumber: &blockNumberFinalized,
+			},
+		},
+		{
+			"should unmarshal safe block number properly",
+			`{"blockNumber": "safe"}`,
+			false,
+			BlockNumberOrHash{
+				BlockNumber: &blockNumberSafe,
+			},
+		},
 		{
 			"should unmarshal block number 0 properly #1",
 			`{"blockNumber": "0x0"}`,
@@ -1,29 +1,65 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dogechain-lab/dogechain/helper/hex"
 	"github.com/dogechain-lab/dogechain/state"
 	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/tracer"
 	"github.com/dogechain-lab/dogechain/state/tracer/structlogger"
 	"github.com/dogechain-lab/dogechain/types"
+
+	// register the js and native tracers
+	_ "github.com/dogechain-lab/dogechain/state/tracer/js"
+	_ "github.com/dogechain-lab/dogechain/state/tracer/native"
 )
 
 var (
 	ErrTransactionNotSeal         = errors.New("transaction not sealed")
 	ErrGenesisNotTracable         = errors.New("genesis is not traceable")
 	ErrTransactionNotFoundInBlock = errors.New("transaction not found in block")
+	ErrExecutionTimeout           = errors.New("execution timeout")
+)
+
+const (
+	// defaultTraceTimeout is the amount of time a single transaction can execute
+	// by default before being forcefully aborted.
+	defaultTraceTimeout = 5 * time.Second
 )
 
 type Debug struct {
 	store ethStore
+	eth   *Eth
 
 	metrics *Metrics
 }
 
-func (d *Debug) TraceTransaction(hash types.Hash) (interface{}, error) {
+// TraceConfig holds extra parameters to trace functions,
+// compatible with the geth tracing options
+type TraceConfig struct {
+	EnableMemory     bool    `json:"enableMemory"`
+	DisableStack     bool    `json:"disableStack"`
+	DisableStorage   bool    `json:"disableStorage"`
+	EnableReturnData bool    `json:"enableReturnData"`
+	Timeout          *string `json:"timeout"`
+	// Tracer is the name of the tracer, the struct logger is used if not set
+	Tracer *string `json:"tracer"`
+	// TracerConfig is the config of the named tracer
+	TracerConfig json.RawMessage `json:"tracerConfig"`
+}
+
+// txTraceResult is the result of a single transaction trace within a block
+type txTraceResult struct {
+	TxHash types.Hash  `json:"txHash"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+func (d *Debug) TraceTransaction(hash types.Hash, config *TraceConfig) (interface{}, error) {
 	d.metrics.DebugAPICounterInc(DebugTraceTransactionLabel)
 
 	// Check the chain state for the transaction
@@ -68,21 +104,210 @@ func (d *Debug) TraceTransaction(hash types.Hash) (interface{}, error) {
 		return nil, err
 	}
 
-	return d.traceTx(txn, tx)
+	txctx := &tracer.Context{
+		BlockHash: block.Hash(),
+		TxIndex:   txIdx,
+		TxHash:    hash,
+		Forks:     d.store.GetForksInTime(block.Number()),
+	}
+
+	return d.traceTx(txn, tx, txctx, config)
+}
+
+// TraceCall lets you trace a given eth_call. It collects the structured logs
+// created during the execution of EVM on top of the state of the given block,
+// and returns them as a JSON object.
+func (d *Debug) TraceCall(arg *txnArgs, filter BlockNumberOrHash, config *TraceConfig) (interface{}, error) {
+	d.metrics.DebugAPICounterInc(DebugTraceCallLabel)
+
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = CreateBlockNumberPointer(LatestBlockFlag)
+	}
+
+	header, err := d.eth.getHeaderFromBlockNumberOrHash(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := d.eth.decodeTxn(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
+	if tx.Gas == 0 {
+		tx.Gas = header.GasLimit
+	}
+
+	txn, err := d.store.BeginTxn(header)
+	if err != nil {
+		return nil, err
+	}
+
+	// zero priced calls are exempted from the base fee
+	txn.SetNoBaseFee(true)
+
+	txctx := &tracer.Context{
+		Forks: d.store.GetForksInTime(header.Number),
+	}
+
+	return d.traceTx(txn, tx, txctx, config)
 }
 
-func (d *Debug) traceTx(txn *state.Transition, tx *types.Transaction) (interface{}, error) {
-	var tracer runtime.EVMLogger = structlogger.NewStructLogger(txn.Txn())
+// TraceBlockByNumber returns the structured logs created during the execution
+// of EVM and returns them as a JSON object, one per transaction of the block.
+func (d *Debug) TraceBlockByNumber(number BlockNumber, config *TraceConfig) (interface{}, error) {
+	d.metrics.DebugAPICounterInc(DebugTraceBlockByNumberLabel)
 
-	txn.SetEVMLogger(tracer)
+	num, err := GetNumericBlockNumber(number, d.eth)
+	if err != nil {
+		return nil, err
+	}
+
+	block, ok := d.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	return d.traceBlock(block, config)
+}
+
+// TraceBlockByHash returns the structured logs created during the execution
+// of EVM and returns them as a JSON object, one per transaction of the block.
+func (d *Debug) TraceBlockByHash(hash types.Hash, config *TraceConfig) (interface{}, error) {
+	d.metrics.DebugAPICounterInc(DebugTraceBlockByHashLabel)
+
+	block, ok := d.store.GetBlockByHash(hash, true)
+	if !ok {
+		return nil, fmt.Errorf("block %s not found", hash)
+	}
+
+	return d.traceBlock(block, config)
+}
+
+// traceBlock replays all the transactions of the block on top of its parent state,
+// and traces them one by one.
+func (d *Debug) traceBlock(block *types.Block, config *TraceConfig) (interface{}, error) {
+	if block.Number() == 0 {
+		return nil, ErrGenesisNotTracable
+	}
+
+	results := make([]*txTraceResult, len(block.Transactions))
+	if len(block.Transactions) == 0 {
+		return results, nil
+	}
+
+	txn, err := d.store.StateAtTransaction(block, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	forks := d.store.GetForksInTime(block.Number())
+
+	for idx, tx := range block.Transactions {
+		results[idx] = &txTraceResult{
+			TxHash: tx.Hash(),
+		}
+
+		// the following transactions are executed on top of the traced one
+		txctx := &tracer.Context{
+			BlockHash: block.Hash(),
+			TxIndex:   idx,
+			TxHash:    tx.Hash(),
+			Forks:     forks,
+		}
+
+		result, err := d.traceTx(txn, tx, txctx, config)
+		if err != nil {
+			results[idx].Error = err.Error()
+		} else {
+			results[idx].Result = result
+		}
+	}
+
+	return results, nil
+}
+
+func (d *Debug) traceTx(
+	txn *state.Transition,
+	tx *types.Transaction,
+	txctx *tracer.Context,
+	config *TraceConfig,
+) (interface{}, error) {
+	var (
+		timeout   = defaultTraceTimeout
+		loggerCfg = &structlogger.Config{}
+		logger    runtime.EVMLogger
+	)
+
+	if config != nil {
+		loggerCfg.EnableMemory = config.EnableMemory
+		loggerCfg.DisableStack = config.DisableStack
+		loggerCfg.DisableStorage = config.DisableStorage
+		loggerCfg.EnableReturnData = config.EnableReturnData
+
+		if config.Timeout != nil {
+			var err error
+			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// the failing tracers abort the execution as well
+	txctx.Cancel = txn.Cancel
+
+	// use the named tracer if given, otherwise the struct logger
+	if config != nil && config.Tracer != nil {
+		namedTracer, err := tracer.New(*config.Tracer, txctx, config.TracerConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		logger = namedTracer
+	} else {
+		logger = structlogger.NewStructLogger(txn.Txn(), loggerCfg)
+	}
+
+	txn.SetEVMLogger(logger)
+	// reset logger after tracing, the transition might be reused
+	defer txn.SetEVMLogger(runtime.NewDummyLogger())
+
+	// stop capturing and abort the execution when the timeout is reached
+	timeoutCh := make(chan struct{})
+	deadlineTimer := time.AfterFunc(timeout, func() {
+		defer close(timeoutCh)
+
+		switch logger := logger.(type) {
+		case *structlogger.StructLogger:
+			logger.Stop(ErrExecutionTimeout)
+		case tracer.Tracer:
+			logger.Stop(ErrExecutionTimeout)
+		}
+
+		txn.Cancel()
+	})
 
 	result, err := txn.Apply(tx)
+
+	// the transition might be reused by the next transaction
+	if !deadlineTimer.Stop() {
+		<-timeoutCh
+	}
+
+	txn.ResetCancel()
+
 	if err != nil {
 		return nil, fmt.Errorf("tracing failed: %w", err)
 	}
 
-	switch tracer := tracer.(type) {
+	switch logger := logger.(type) {
 	case *structlogger.StructLogger:
+		if err := logger.Reason(); err != nil {
+			return nil, err
+		}
+
 		returnVal := fmt.Sprintf("%x", result.Return())
 		// If the result contains a revert reason, return it.
 		if result.Reverted() {
@@ -93,10 +318,12 @@ func (d *Debug) traceTx(txn *state.Transition, tx *types.Transaction) (interface
 			Gas:         result.GasUsed,
 			Failed:      result.Failed(),
 			ReturnValue: returnVal,
-			StructLogs:  formatLogs(tracer.StructLogs()),
+			StructLogs:  formatLogs(logger.StructLogs()),
 		}, nil
+	case tracer.Tracer:
+		return logger.GetResult()
 	default:
-		panic(fmt.Sprintf("bad tracer type %T", tracer))
+		panic(fmt.Sprintf("bad tracer type %T", logger))
 	}
 }
 
@@ -113,15 +340,16 @@ type ExecutionResult struct {
 // StructLogRes stores a structured log emitted by the EVM while replaying a
 // transaction in debug mode
 type StructLogRes struct {
-	Pc      uint64             `json:"pc"`
-	Op      string             `json:"op"`
-	Gas     uint64             `json:"gas"`
-	GasCost uint64             `json:"gasCost"`
-	Depth   int                `json:"depth"`
-	Error   string             `json:"error,omitempty"`
-	Stack   *[]string          `json:"stack,omitempty"`
-	Memory  *[]string          `json:"memory,omitempty"`
-	Storage *map[string]string `json:"storage,omitempty"`
+	Pc         uint64             `json:"pc"`
+	Op         string             `json:"op"`
+	Gas        uint64             `json:"gas"`
+	GasCost    uint64             `json:"gasCost"`
+	Depth      int                `json:"depth"`
+	Error      string             `json:"error,omitempty"`
+	Stack      *[]string          `json:"stack,omitempty"`
+	Memory     *[]string          `json:"memory,omitempty"`
+	Storage    *map[string]string `json:"storage,omitempty"`
+	ReturnData string             `json:"returnData,omitempty"`
 }
 
 // formatLogs formats EVM returned structured logs for json output
@@ -167,6 +395,10 @@ func formatLogs(logs []*structlogger.StructLog) []StructLogRes {
 
 			formatted[index].Storage = &storage
 		}
+
+		if len(trace.ReturnData) > 0 {
+			formatted[index].ReturnData = hex.EncodeToHex(trace.ReturnData)
+		}
 	}
 
 	return formatted
@@ -1,16 +1,382 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/state"
+	itrie "github.com/dogechain-lab/dogechain/state/immutable-trie"
+	"github.com/dogechain-lab/dogechain/state/runtime"
 	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/state/runtime/precompiled"
 	"github.com/dogechain-lab/dogechain/state/tracer/structlogger"
 	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 )
 
+var (
+	traceSender   = types.StringToAddress("0x1000")
+	traceReceiver = types.StringToAddress("0x2000")
+	traceContract = types.StringToAddress("0x3000")
+	traceCoinbase = types.StringToAddress("0x4000")
+)
+
+// mockTraceStore executes transactions on top of a real in-memory state
+type mockTraceStore struct {
+	ethStore
+
+	forks    *chain.Forks
+	executor *state.Executor
+	root     types.Hash
+	blocks   []*types.Block
+}
+
+func newMockTraceStore(t *testing.T) *mockTraceStore {
+	t.Helper()
+
+	// the executor and the endpoints share the forks
+	forks := chain.AllForksEnabled
+
+	executor := state.NewExecutor(
+		&chain.Params{Forks: forks, ChainID: 100},
+		itrie.NewStateDB(itrie.NewMemoryStorage(), hclog.NewNullLogger(), nil),
+		hclog.NewNullLogger(),
+	)
+	executor.SetRuntime(precompiled.NewPrecompiled())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) func(i uint64) types.Hash {
+		return func(i uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	// SSTORE(0, 0x2a), MSTORE(0, 0x2a), then RETURN(0, 0x20)
+	code, err := hex.DecodeHex("602a600055602a60005260206000f3")
+	assert.NoError(t, err)
+
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		traceSender: {
+			Balance: big.NewInt(1000000000000000000),
+		},
+		traceContract: {
+			Code: code,
+		},
+	})
+	assert.NoError(t, err)
+
+	genesis := &types.Block{
+		Header: &types.Header{
+			Number:    0,
+			GasLimit:  10000000,
+			StateRoot: root,
+		},
+	}
+	genesis.Header.ComputeHash()
+
+	transfer := func(nonce uint64) *types.Transaction {
+		return &types.Transaction{
+			Nonce:    nonce,
+			From:     traceSender,
+			To:       &traceReceiver,
+			Gas:      21000,
+			GasPrice: big.NewInt(0),
+			Value:    big.NewInt(1),
+		}
+	}
+
+	block := &types.Block{
+		Header: &types.Header{
+			Number:     1,
+			ParentHash: genesis.Hash(),
+			GasLimit:   10000000,
+			Miner:      traceCoinbase,
+		},
+		Transactions: []*types.Transaction{
+			transfer(0),
+			transfer(1),
+			transfer(5), // nonce too high
+		},
+	}
+	block.Header.ComputeHash()
+
+	return &mockTraceStore{
+		forks:    forks,
+		executor: executor,
+		root:     root,
+		blocks:   []*types.Block{genesis, block},
+	}
+}
+
+func (m *mockTraceStore) Header() *types.Header {
+	return m.blocks[0].Header
+}
+
+func (m *mockTraceStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool) {
+	if num >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+
+	return m.blocks[num], true
+}
+
+func (m *mockTraceStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	for _, b := range m.blocks {
+		if b.Hash() == hash {
+			return b, true
+		}
+	}
+
+	return nil, false
+}
+
+func (m *mockTraceStore) StateAtTransaction(block *types.Block, txIndex int) (*state.Transition, error) {
+	txn, err := m.executor.BeginTxn(m.root, block.Header, traceCoinbase)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range block.Transactions[:txIndex] {
+		if _, err := txn.Apply(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	return txn, nil
+}
+
+func (m *mockTraceStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return m.forks.At(blockNumber)
+}
+
+func (m *mockTraceStore) BeginTxn(header *types.Header) (*state.Transition, error) {
+	return m.executor.BeginTxn(header.StateRoot, header, traceCoinbase)
+}
+
+func (m *mockTraceStore) BeginPendingTxn(
+	parent, pending *types.Header,
+	coinbase *types.Address,
+) (*state.Transition, error) {
+	receiver := traceCoinbase
+	if coinbase != nil {
+		receiver = *coinbase
+	}
+
+	return m.executor.BeginTxn(parent.StateRoot, pending, receiver)
+}
+
+func (m *mockTraceStore) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
+	transition, err := m.BeginTxn(header)
+	if err != nil {
+		return nil, err
+	}
+
+	transition.SetNoBaseFee(true)
+
+	return transition.Apply(txn)
+}
+
+func newTestDebugEndpoint(store ethStore) *Debug {
+	eth := &Eth{
+		store:   store,
+		chainID: 100,
+		metrics: NilMetrics(),
+	}
+
+	return &Debug{store, eth, NilMetrics()}
+}
+
+func TestDebug_TraceBlock(t *testing.T) {
+	store := newMockTraceStore(t)
+	debug := newTestDebugEndpoint(store)
+
+	// genesis is not traceable
+	_, err := debug.TraceBlockByNumber(EarliestBlockNumber, nil)
+	assert.ErrorIs(t, err, ErrGenesisNotTracable)
+
+	block := store.blocks[1]
+
+	byNumber, err := debug.TraceBlockByNumber(BlockNumber(1), nil)
+	assert.NoError(t, err)
+
+	byHash, err := debug.TraceBlockByHash(block.Hash(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, byNumber, byHash)
+
+	results, ok := byNumber.([]*txTraceResult)
+	assert.True(t, ok)
+	assert.Len(t, results, len(block.Transactions))
+
+	// the transactions are executed on top of the previous ones
+	for i, res := range results[:2] {
+		assert.Equal(t, block.Transactions[i].Hash(), res.TxHash)
+		assert.Empty(t, res.Error)
+
+		execRes, ok := res.Result.(*ExecutionResult)
+		assert.True(t, ok)
+		assert.Equal(t, uint64(21000), execRes.Gas)
+		assert.False(t, execRes.Failed)
+	}
+
+	// the failing transaction reports its own error
+	assert.Equal(t, block.Transactions[2].Hash(), results[2].TxHash)
+	assert.Nil(t, results[2].Result)
+	assert.NotEmpty(t, results[2].Error)
+
+	// unknown block
+	_, err = debug.TraceBlockByNumber(BlockNumber(2), nil)
+	assert.Error(t, err)
+}
+
+func TestDebug_TraceCall(t *testing.T) {
+	store := newMockTraceStore(t)
+	debug := newTestDebugEndpoint(store)
+
+	invalidTimeout := "forever"
+
+	tests := []struct {
+		name   string
+		config *TraceConfig
+		err    bool
+		check  func(t *testing.T, logs []StructLogRes)
+	}{
+		{
+			name:   "Default config captures stack and storage",
+			config: nil,
+			check: func(t *testing.T, logs []StructLogRes) {
+				t.Helper()
+
+				for _, log := range logs {
+					assert.NotNil(t, log.Stack)
+					assert.Nil(t, log.Memory)
+					assert.Empty(t, log.ReturnData)
+
+					if log.Op == evm.OpCode(evm.SSTORE).String() {
+						assert.NotNil(t, log.Storage)
+					}
+				}
+			},
+		},
+		{
+			name: "Options disable stack and storage, enable memory",
+			config: &TraceConfig{
+				EnableMemory:   true,
+				DisableStack:   true,
+				DisableStorage: true,
+			},
+			check: func(t *testing.T, logs []StructLogRes) {
+				t.Helper()
+
+				for _, log := range logs {
+					assert.Nil(t, log.Stack)
+					assert.Nil(t, log.Storage)
+				}
+
+				// memory is expanded by MSTORE
+				last := logs[len(logs)-1]
+				assert.Equal(t, evm.OpCode(evm.RETURN).String(), last.Op)
+				assert.NotNil(t, last.Memory)
+			},
+		},
+		{
+			name: "Invalid timeout",
+			config: &TraceConfig{
+				Timeout: &invalidTimeout,
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := traceSender
+			to := traceContract
+
+			res, err := debug.TraceCall(&txnArgs{
+				From:  &from,
+				To:    &to,
+				Nonce: argUintPtr(0),
+			}, BlockNumberOrHash{}, tt.config)
+
+			if tt.err {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			execRes, ok := res.(*ExecutionResult)
+			assert.True(t, ok)
+			assert.False(t, execRes.Failed)
+			assert.Equal(t, "000000000000000000000000000000000000000000000000000000000000002a", execRes.ReturnValue)
+			assert.Len(t, execRes.StructLogs, 9)
+
+			tt.check(t, execRes.StructLogs)
+		})
+	}
+}
+
+func TestDebug_TraceCallTimeout(t *testing.T) {
+	store := newMockTraceStore(t)
+	debug := newTestDebugEndpoint(store)
+
+	// the endless loop runs out of the gas much later than the timeout
+	store.blocks[0].Header.GasLimit = 1 << 40
+
+	from := traceSender
+	// JUMPDEST, PUSH1 0x00, JUMP
+	loop := argBytes(hex.MustDecodeHex("0x5b600056"))
+
+	traceLoop := func(config *TraceConfig) error {
+		start := time.Now()
+
+		_, err := debug.TraceCall(&txnArgs{
+			From:  &from,
+			Data:  &loop,
+			Nonce: argUintPtr(0),
+		}, BlockNumberOrHash{}, config)
+
+		// the execution is aborted near the deadline
+		assert.Less(t, time.Since(start), 2*time.Second)
+
+		return err
+	}
+
+	timeout := "100ms"
+
+	t.Run("struct logger", func(t *testing.T) {
+		err := traceLoop(&TraceConfig{DisableStack: true, Timeout: &timeout})
+		assert.ErrorIs(t, err, ErrExecutionTimeout)
+	})
+
+	t.Run("js tracer", func(t *testing.T) {
+		code := `{
+			fault: function(log, db) {},
+			result: function(ctx, db) { return ctx.error }
+		}`
+
+		err := traceLoop(&TraceConfig{Tracer: &code, Timeout: &timeout})
+		assert.ErrorIs(t, err, ErrExecutionTimeout)
+	})
+
+	t.Run("failing js tracer", func(t *testing.T) {
+		code := `{
+			step: function(log, db) { throw "tracer failure" },
+			fault: function(log, db) {},
+			result: function(ctx, db) { return 0 }
+		}`
+
+		// no timeout at all, the failure aborts the execution
+		err := traceLoop(&TraceConfig{Tracer: &code})
+		assert.ErrorContains(t, err, "tracer failure")
+	})
+}
+
 func TestDebug_FormatLogs(t *testing.T) {
 	var (
 		stackPc121 = []string{
@@ -196,3 +562,77 @@ func TestDebug_FormatLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestDebug_TraceCallWithTracer(t *testing.T) {
+	store := newMockTraceStore(t)
+	debug := newTestDebugEndpoint(store)
+
+	from := traceSender
+	to := traceContract
+
+	callTracer := "callTracer"
+	res, err := debug.TraceCall(&txnArgs{
+		From:  &from,
+		To:    &to,
+		Nonce: argUintPtr(0),
+	}, BlockNumberOrHash{}, &TraceConfig{Tracer: &callTracer})
+	assert.NoError(t, err)
+
+	raw, ok := res.(json.RawMessage)
+	assert.True(t, ok)
+
+	var frame map[string]interface{}
+
+	assert.NoError(t, json.Unmarshal(raw, &frame))
+	assert.Equal(t, "CALL", frame["type"])
+	assert.Equal(t, hex.EncodeToHex(traceContract.Bytes()), frame["to"])
+
+	unknownTracer := "unknownTracer"
+	_, err = debug.TraceCall(&txnArgs{
+		From:  &from,
+		To:    &to,
+		Nonce: argUintPtr(0),
+	}, BlockNumberOrHash{}, &TraceConfig{Tracer: &unknownTracer})
+	assert.Error(t, err)
+}
+
+func TestDebug_TraceCallWithJsTracer(t *testing.T) {
+	store := newMockTraceStore(t)
+	debug := newTestDebugEndpoint(store)
+
+	from := traceSender
+	to := traceContract
+
+	t.Run("Counting opcodes", func(t *testing.T) {
+		code := `{
+			count: 0,
+			step: function(log, db) { this.count++ },
+			fault: function(log, db) {},
+			result: function(ctx, db) { return this.count }
+		}`
+
+		res, err := debug.TraceCall(&txnArgs{
+			From:  &from,
+			To:    &to,
+			Nonce: argUintPtr(0),
+		}, BlockNumberOrHash{}, &TraceConfig{Tracer: &code})
+		assert.NoError(t, err)
+		assert.Equal(t, json.RawMessage("9"), res)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		code := `{
+			step: function(log, db) { while (true) {} },
+			fault: function(log, db) {},
+			result: function(ctx, db) { return 0 }
+		}`
+		timeout := "50ms"
+
+		_, err := debug.TraceCall(&txnArgs{
+			From:  &from,
+			To:    &to,
+			Nonce: argUintPtr(0),
+		}, BlockNumberOrHash{}, &TraceConfig{Tracer: &code, Timeout: &timeout})
+		assert.ErrorIs(t, err, ErrExecutionTimeout)
+	})
+}
@@ -1,9 +1,34 @@
 package jsonrpc
 
+import "time"
+
 const (
 	// DefaultJSONRPCBatchRequestLimit maximum length allowed for json_rpc batch requests
 	DefaultJSONRPCBatchRequestLimit uint64 = 1
 	// DefaultJSONRPCBlockRangeLimit maximum block range allowed for json_rpc
 	// requests with fromBlock/toBlock values (e.g. eth_getLogs)
 	DefaultJSONRPCBlockRangeLimit uint64 = 100
+	// DefaultJSONRPCCacheEntries maximum number of the cached json_rpc responses
+	DefaultJSONRPCCacheEntries uint64 = 10000
+	// DefaultJSONRPCCacheSize maximum total size (MB) of the cached json_rpc responses
+	DefaultJSONRPCCacheSize uint64 = 64
+	// DefaultJSONRPCRecordSampleRate ratio of the recorded json_rpc requests
+	DefaultJSONRPCRecordSampleRate float64 = 1
+	// DefaultJSONRPCRecordFileSize size (MB) of a capture file before it is rotated
+	DefaultJSONRPCRecordFileSize uint64 = 64
+	// DefaultJSONRPCRecordMaxFiles maximum number of the kept capture files
+	DefaultJSONRPCRecordMaxFiles uint64 = 16
+	// DefaultWSConnLimitPerIP maximum websocket connections of an ip
+	DefaultWSConnLimitPerIP uint64 = 64
+	// DefaultWSSubscriptionLimit maximum subscriptions of a websocket connection
+	DefaultWSSubscriptionLimit uint64 = 128
+	// DefaultWSSendQueueSize maximum outbound messages queued for a websocket connection
+	DefaultWSSendQueueSize uint64 = 256
+	// DefaultWSSlowConsumerPolicy drops the notifications not fitting the outbound queue
+	DefaultWSSlowConsumerPolicy = WSSlowConsumerDrop
+	// DefaultWSPingInterval interval of the websocket keepalive pings
+	DefaultWSPingInterval = 30 * time.Second
+	// DefaultWSIdleTimeout the websocket connection is closed once nothing is read
+	// in time, including the pongs
+	DefaultWSIdleTimeout = 90 * time.Second
 )
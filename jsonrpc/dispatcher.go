@@ -8,6 +8,7 @@ import (
 	"math"
 	"reflect"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/hashicorp/go-hclog"
@@ -16,12 +17,16 @@ import (
 type Namespace string
 
 const (
-	NamespaceEth    Namespace = "eth"
-	NamespaceNet    Namespace = "net"
-	NamespaceWeb3   Namespace = "web3"
-	NamespaceTxpool Namespace = "txpool"
-	NamespaceDebug  Namespace = "debug"
-	NamespaceAll    Namespace = "*"
+	NamespaceEth      Namespace = "eth"
+	NamespaceNet      Namespace = "net"
+	NamespaceWeb3     Namespace = "web3"
+	NamespaceTxpool   Namespace = "txpool"
+	NamespaceDebug    Namespace = "debug"
+	NamespaceTrace    Namespace = "trace"
+	NamespaceOts      Namespace = "ots"
+	NamespaceAdmin    Namespace = "admin"
+	NamespacePersonal Namespace = "personal"
+	NamespaceAll      Namespace = "*"
 )
 
 type serviceData struct {
@@ -41,11 +46,15 @@ func (f *funcData) numParams() int {
 }
 
 type endpoints struct {
-	Eth    *Eth
-	Web3   *Web3
-	Net    *Net
-	TxPool *TxPool
-	Debug  *Debug
+	Eth      *Eth
+	Web3     *Web3
+	Net      *Net
+	TxPool   *TxPool
+	Debug    *Debug
+	Trace    *Trace
+	Ots      *Ots
+	Admin    *Admin
+	Personal *Personal
 }
 
 // Dispatcher handles all json rpc requests by delegating
@@ -59,6 +68,9 @@ type Dispatcher struct {
 	jsonRPCBatchLengthLimit uint64
 	priceLimit              uint64
 	namespaces              map[Namespace]struct{}
+	metrics                 *Metrics
+	responseCache           *responseCache // nil if the responses are not cached
+	recorder                *recorder      // nil if the calls are not recorded
 }
 
 func newDispatcher(
@@ -77,6 +89,7 @@ func newDispatcher(
 		jsonRPCBatchLengthLimit: jsonRPCBatchLengthLimit,
 		priceLimit:              priceLimit,
 		namespaces:              make(map[Namespace]struct{}),
+		metrics:                 metrics,
 	}
 
 	// map namespaces
@@ -87,40 +100,52 @@ func newDispatcher(
 	// enable filter
 	if store != nil {
 		d.filterManager = NewFilterManager(logger, store, blockRangeLimit)
+
+		// the peer events are only subscribed for the admin namespace
+		if _, ok := d.namespaces[NamespaceAdmin]; ok {
+			d.filterManager.EnablePeerEvents(store)
+		}
+
 		go d.filterManager.Run()
 	}
 
-	d.initEndpoints(store, metrics)
+	d.initEndpoints(store, metrics, blockRangeLimit)
 	d.registerEndpoints()
 
 	return d
 }
 
-func (d *Dispatcher) initEndpoints(store JSONRPCStore, metrics *Metrics) {
+func (d *Dispatcher) initEndpoints(store JSONRPCStore, metrics *Metrics, blockRangeLimit uint64) {
 	d.endpoints.Eth = &Eth{
-		logger:        d.logger,
-		store:         store,
-		chainID:       d.chainID,
-		filterManager: d.filterManager,
-		priceLimit:    d.priceLimit,
-		metrics:       metrics,
+		logger:          d.logger,
+		store:           store,
+		chainID:         d.chainID,
+		filterManager:   d.filterManager,
+		priceLimit:      d.priceLimit,
+		blockRangeLimit: blockRangeLimit,
+		metrics:         metrics,
 	}
 	d.endpoints.Net = &Net{store, d.chainID, metrics}
 	d.endpoints.Web3 = &Web3{d.chainID, metrics}
 	d.endpoints.TxPool = &TxPool{store, metrics}
-	d.endpoints.Debug = &Debug{store, metrics}
+	d.endpoints.Debug = &Debug{store, d.endpoints.Eth, metrics}
+	d.endpoints.Trace = &Trace{store, d.endpoints.Eth, blockRangeLimit, metrics}
+	d.endpoints.Ots = &Ots{store, metrics}
+	d.endpoints.Admin = &Admin{store, d.chainID, metrics}
+	d.endpoints.Personal = &Personal{store, metrics}
 }
 
 func (d *Dispatcher) registerEndpoints() {
-	// enable all endpoints
+	// enable all endpoints, except the admin and the personal ones which manage
+	// the node and its accounts
 	if _, ok := d.namespaces[NamespaceAll]; ok {
 		d.registerService(string(NamespaceEth), d.endpoints.Eth)
 		d.registerService(string(NamespaceNet), d.endpoints.Net)
 		d.registerService(string(NamespaceWeb3), d.endpoints.Web3)
 		d.registerService(string(NamespaceTxpool), d.endpoints.TxPool)
 		d.registerService(string(NamespaceDebug), d.endpoints.Debug)
-
-		return
+		d.registerService(string(NamespaceTrace), d.endpoints.Trace)
+		d.registerService(string(NamespaceOts), d.endpoints.Ots)
 	}
 
 	for ns := range d.namespaces {
@@ -135,6 +160,14 @@ func (d *Dispatcher) registerEndpoints() {
 			d.registerService(string(ns), d.endpoints.TxPool)
 		case NamespaceDebug:
 			d.registerService(string(ns), d.endpoints.Debug)
+		case NamespaceTrace:
+			d.registerService(string(ns), d.endpoints.Trace)
+		case NamespaceOts:
+			d.registerService(string(ns), d.endpoints.Ots)
+		case NamespaceAdmin:
+			d.registerService(string(ns), d.endpoints.Admin)
+		case NamespacePersonal:
+			d.registerService(string(ns), d.endpoints.Personal)
 		}
 	}
 }
@@ -201,22 +234,52 @@ func (d *Dispatcher) handleSubscribe(req Request, conn wsConn) (string, Error) {
 		return "", NewSubscriptionNotFoundError(subscribeMethod)
 	}
 
-	var filterID string
+	var (
+		filterID string
+		err      error
+	)
+
 	if subscribeMethod == "newHeads" {
-		filterID = d.filterManager.NewBlockFilter(conn)
+		filterID, err = d.filterManager.NewBlockFilter(conn)
 	} else if subscribeMethod == "logs" {
-		logQuery, err := decodeLogQueryFromInterface(params[1])
-		if err != nil {
-			return "", NewInternalError(err.Error())
+		logQuery, decodeErr := decodeLogQueryFromInterface(params[1])
+		if decodeErr != nil {
+			return "", NewInternalError(decodeErr.Error())
+		}
+		filterID, err = d.filterManager.NewLogFilter(logQuery, conn)
+	} else if subscribeMethod == "newPendingTransactions" {
+		// the full transaction objects are returned instead of the hashes if set
+		fullTx := false
+		if len(params) > 1 {
+			if fullTx, ok = params[1].(bool); !ok {
+				return "", NewInvalidParamsError("Invalid params")
+			}
 		}
-		filterID = d.filterManager.NewLogFilter(logQuery, conn)
+		filterID, err = d.filterManager.NewPendingTxFilter(fullTx, conn)
+	} else if subscribeMethod == "syncing" {
+		filterID, err = d.filterManager.NewSyncingFilter(conn)
 	} else {
 		return "", NewSubscriptionNotFoundError(subscribeMethod)
 	}
 
+	if err != nil {
+		return "", d.subscriptionError(err)
+	}
+
 	return filterID, nil
 }
 
+// subscriptionError converts the error of adding the websocket filter
+func (d *Dispatcher) subscriptionError(err error) Error {
+	if errors.Is(err, ErrSubscriptionLimit) {
+		d.metrics.WSEventInc(wsEventSubscriptionRejected)
+
+		return NewLimitExceededError(err.Error())
+	}
+
+	return NewInternalError(err.Error())
+}
+
 func (d *Dispatcher) handleUnsubscribe(req Request) (bool, Error) {
 	var params []interface{}
 	if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -235,11 +298,25 @@ func (d *Dispatcher) handleUnsubscribe(req Request) (bool, Error) {
 	return d.filterManager.Uninstall(filterID), nil
 }
 
+// setSubscriptionLimit limits the number of the subscriptions of a connection,
+// it is unlimited if zero
+func (d *Dispatcher) setSubscriptionLimit(limit uint64) {
+	if d.filterManager != nil {
+		d.filterManager.SetWsFilterLimit(limit)
+	}
+}
+
 func (d *Dispatcher) RemoveFilterByWs(conn wsConn) {
 	d.filterManager.RemoveFilterByWs(conn)
 }
 
+// HandleWs handles the request of the trusted websocket or ipc connection
 func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
+	return d.handleWs(nil, reqBody, conn)
+}
+
+// handleWs handles the websocket request of the caller
+func (d *Dispatcher) handleWs(c *caller, reqBody []byte, conn wsConn) ([]byte, error) {
 	var req Request
 	if err := json.Unmarshal(reqBody, &req); err != nil {
 		return NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
@@ -248,6 +325,10 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 	// if the request method is eth_subscribe we need to create a
 	// new filter with ws connection
 	if req.Method == "eth_subscribe" {
+		if err := d.checkCaller(c, req); err != nil {
+			return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
+		}
+
 		filterID, err := d.handleSubscribe(req, conn)
 		if err != nil {
 			return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
@@ -262,6 +343,28 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 		return []byte(resp), nil
 	}
 
+	// the peer events are only subscribed once the admin namespace is enabled,
+	// otherwise the request falls through to the method not found error
+	if _, ok := d.serviceMap[string(NamespaceAdmin)]; ok && req.Method == "admin_peerEvents" {
+		if err := d.checkCaller(c, req); err != nil {
+			return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
+		}
+
+		d.metrics.AdminAPICounterInc(AdminPeerEventsLabel)
+
+		filterID, subErr := d.filterManager.NewPeerEventFilter(conn)
+		if subErr != nil {
+			return NewRPCResponse(req.ID, "2.0", nil, d.subscriptionError(subErr)).Bytes()
+		}
+
+		resp, err := formatFilterResponse(req.ID, filterID)
+		if err != nil {
+			return NewRPCResponse(req.ID, "2.0", nil, err).Bytes()
+		}
+
+		return []byte(resp), nil
+	}
+
 	if req.Method == "eth_unsubscribe" {
 		ok, err := d.handleUnsubscribe(req)
 		if err != nil {
@@ -282,7 +385,7 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 	}
 
 	// its a normal query that we handle with the dispatcher
-	resp, err := d.handleReq(req)
+	resp, err := d.handleCallerReq(c, req)
 	if err != nil {
 		return nil, err
 	}
@@ -290,7 +393,13 @@ func (d *Dispatcher) HandleWs(reqBody []byte, conn wsConn) ([]byte, error) {
 	return NewRPCResponse(req.ID, "2.0", resp, err).Bytes()
 }
 
+// Handle handles the trusted request
 func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
+	return d.handle(nil, reqBody)
+}
+
+// handle handles the request of the caller, which is limited by its quotas
+func (d *Dispatcher) handle(c *caller, reqBody []byte) ([]byte, error) {
 	x := bytes.TrimLeft(reqBody, " \t\r\n")
 	if len(x) == 0 {
 		return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
@@ -306,7 +415,7 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 			return NewRPCResponse(req.ID, "2.0", nil, NewInvalidRequestError("Invalid json request")).Bytes()
 		}
 
-		resp, err := d.handleReq(req)
+		resp, err := d.handleCallerReq(c, req)
 
 		return NewRPCResponse(req.ID, "2.0", resp, err).Bytes()
 	}
@@ -318,15 +427,15 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	}
 
 	// if not disabled, avoid handling long batch requests
-	if d.jsonRPCBatchLengthLimit > 0 &&
-		len(requests) > int(d.jsonRPCBatchLengthLimit) {
+	if batchLengthLimit := c.batchLengthLimit(d.jsonRPCBatchLengthLimit); batchLengthLimit > 0 &&
+		len(requests) > int(batchLengthLimit) {
 		return NewRPCResponse(nil, "2.0", nil, NewInvalidRequestError("Batch request length too long")).Bytes()
 	}
 
 	responses := make([]Response, 0)
 
 	for _, req := range requests {
-		var response, err = d.handleReq(req)
+		var response, err = d.handleCallerReq(c, req)
 		if err != nil {
 			errorResponse := NewRPCResponse(req.ID, "2.0", response, err)
 			responses = append(responses, errorResponse)
@@ -346,6 +455,24 @@ func (d *Dispatcher) Handle(reqBody []byte) ([]byte, error) {
 	return respBytes, nil
 }
 
+// handleCallerReq handles the request once the caller is allowed to send it
+func (d *Dispatcher) handleCallerReq(c *caller, req Request) ([]byte, Error) {
+	if err := d.checkCaller(c, req); err != nil {
+		return nil, err
+	}
+
+	if d.recorder == nil || !d.recorder.sampled(req.Method) {
+		return d.handleReq(req)
+	}
+
+	start := time.Now()
+	resp, err := d.handleReq(req)
+
+	d.recorder.record(req, NewRPCResponse(req.ID, "2.0", resp, err), start)
+
+	return resp, err
+}
+
 func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 	d.logger.Debug("request", "method", req.Method, "id", req.ID)
 
@@ -371,6 +498,17 @@ func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 		}
 	}
 
+	cacheKey, headRelative, cacheable := d.responseCacheKey(req.Method, inputs)
+	if cacheable {
+		if data, ok := d.responseCache.get(cacheKey); ok {
+			d.metrics.ResponseCacheHitInc(req.Method)
+
+			return data, nil
+		}
+
+		d.metrics.ResponseCacheMissInc(req.Method)
+	}
+
 	var (
 		data []byte
 		err  error
@@ -399,6 +537,10 @@ func (d *Dispatcher) handleReq(req Request) ([]byte, Error) {
 
 			return nil, NewInternalError("Internal error")
 		}
+
+		if cacheable && isCacheableResponse(res, data) {
+			d.responseCache.add(cacheKey, data, headRelative)
+		}
 	}
 
 	return data, nil
@@ -93,6 +93,63 @@ func TestDispatcher_HandleWebsocketConnection_EthSubscribe(t *testing.T) {
 			t.Fatal("\"newHeads\" event not received in 2 seconds")
 		}
 	})
+
+	t.Run("clients should be able to receive \"newPendingTransactions\" event thru eth_subscribe", func(t *testing.T) {
+		store := newMockStore()
+		dispatcher := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 0, 0, 0, 0, []Namespace{
+			NamespaceEth,
+		})
+
+		mockConnection := &mockWsConn{
+			msgCh: make(chan []byte, 1),
+		}
+
+		req := []byte(`{
+		"method": "eth_subscribe",
+		"params": ["newPendingTransactions", true]
+	}`)
+		if _, err := dispatcher.HandleWs(req, mockConnection); err != nil {
+			t.Fatal(err)
+		}
+
+		tx := newTestTransaction(1, addr0)
+		store.emitPendingTx(tx)
+
+		delayTimer := time.NewTimer(2 * time.Second)
+		defer delayTimer.Stop()
+
+		select {
+		case msg := <-mockConnection.msgCh:
+			var notification struct {
+				Params struct {
+					Result transaction `json:"result"`
+				} `json:"params"`
+			}
+
+			assert.NoError(t, json.Unmarshal(msg, &notification))
+			assert.Equal(t, tx.Hash(), notification.Params.Result.Hash)
+		case <-delayTimer.C:
+			t.Fatal("\"newPendingTransactions\" event not received in 2 seconds")
+		}
+	})
+
+	t.Run("invalid \"newPendingTransactions\" option should be rejected", func(t *testing.T) {
+		store := newMockStore()
+		dispatcher := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 0, 0, 0, 0, []Namespace{
+			NamespaceEth,
+		})
+
+		req := []byte(`{
+		"method": "eth_subscribe",
+		"params": ["newPendingTransactions", "full"]
+	}`)
+		resp, err := dispatcher.HandleWs(req, &mockWsConn{})
+		assert.NoError(t, err)
+
+		var res ErrorResponse
+		assert.NoError(t, json.Unmarshal(resp, &res))
+		assert.NotNil(t, res.Error)
+	})
 }
 
 func TestDispatcher_WebsocketConnection_RequestFormats(t *testing.T) {
@@ -64,6 +64,30 @@ func (e *subscriptionNotFoundError) ErrorCode() int {
 	return -32601
 }
 
+type unauthorizedError struct {
+	err string
+}
+
+func (e *unauthorizedError) Error() string {
+	return e.err
+}
+
+func (e *unauthorizedError) ErrorCode() int {
+	return -32001
+}
+
+type limitExceededError struct {
+	err string
+}
+
+func (e *limitExceededError) Error() string {
+	return e.err
+}
+
+func (e *limitExceededError) ErrorCode() int {
+	return -32005
+}
+
 type methodNotFoundError struct {
 	err string
 }
@@ -94,6 +118,14 @@ func NewSubscriptionNotFoundError(method string) *subscriptionNotFoundError {
 	return &subscriptionNotFoundError{fmt.Sprintf("subscribe method %s not found", method)}
 }
 
+func NewUnauthorizedError(msg string) *unauthorizedError {
+	return &unauthorizedError{msg}
+}
+
+func NewLimitExceededError(msg string) *limitExceededError {
+	return &limitExceededError{msg}
+}
+
 func constructErrorFromRevert(result *runtime.ExecutionResult) error {
 	revertErrMsg, unpackErr := abi.UnpackRevertError(result.ReturnValue)
 	if unpackErr != nil {
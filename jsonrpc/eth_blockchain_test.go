@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -8,9 +9,11 @@ import (
 	"testing"
 
 	"github.com/dogechain-lab/dogechain/blockchain"
+	"github.com/dogechain-lab/dogechain/helper/gasprice"
 	"github.com/dogechain-lab/dogechain/helper/hex"
 	"github.com/dogechain-lab/dogechain/helper/progress"
 	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/txpool/proto"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/atomic"
@@ -32,6 +35,8 @@ func TestEth_Block_GetBlockByNumber(t *testing.T) {
 	}{
 		{"should be able to get the latest block number", LatestBlockNumber, true, false},
 		{"should be able to get the earliest block number", EarliestBlockNumber, true, false},
+		{"should be able to get the finalized block number", FinalizedBlockNumber, true, false},
+		{"should be able to get the safe block number", SafeBlockNumber, true, false},
 		{"should not be able to get block with negative number", BlockNumber(-50), false, true},
 		{"should be able to get block with number 0", BlockNumber(0), true, false},
 		{"should be able to get block with number 2", BlockNumber(2), true, false},
@@ -205,6 +210,74 @@ func TestEth_GetTransactionReceipt(t *testing.T) {
 	})
 }
 
+func TestEth_GetBlockReceipts(t *testing.T) {
+	store := newMockBlockStore()
+	eth := newTestEthEndpoint(store)
+	block := newTestBlock(1, hash4)
+	store.add(newTestBlock(0, hash1), block)
+
+	for i := 0; i < 3; i++ {
+		block.Transactions = append(block.Transactions, newTestTransaction(uint64(i), addr0))
+
+		rec := &types.Receipt{
+			GasUsed: 21000,
+			Logs: []*types.Log{
+				{
+					Topics: []types.Hash{
+						hash4,
+					},
+				},
+			},
+		}
+		rec.SetStatus(types.ReceiptSuccess)
+		store.receipts[hash4] = append(store.receipts[hash4], rec)
+	}
+
+	t.Run("returns the receipts of the block by number", func(t *testing.T) {
+		num := BlockNumber(1)
+
+		res, err := eth.GetBlockReceipts(BlockNumberOrHash{BlockNumber: &num})
+		assert.NoError(t, err)
+
+		//nolint:forcetypeassert
+		receipts := res.([]*receipt)
+		assert.Len(t, receipts, 3)
+
+		for i, r := range receipts {
+			assert.Equal(t, block.Transactions[i].Hash(), r.TxHash)
+			assert.Equal(t, argUint64(i), r.TxIndex)
+			assert.Equal(t, hash4, r.BlockHash)
+			assert.Equal(t, argUint64(1), r.BlockNumber)
+			assert.Equal(t, argUint64(types.ReceiptSuccess), r.Status)
+			assert.Len(t, r.Logs, 1)
+		}
+	})
+
+	t.Run("returns the receipts of the block by hash", func(t *testing.T) {
+		res, err := eth.GetBlockReceipts(BlockNumberOrHash{BlockHash: &hash4})
+		assert.NoError(t, err)
+
+		//nolint:forcetypeassert
+		assert.Len(t, res.([]*receipt), 3)
+	})
+
+	t.Run("returns empty receipts of an empty block", func(t *testing.T) {
+		num := BlockNumber(0)
+
+		res, err := eth.GetBlockReceipts(BlockNumberOrHash{BlockNumber: &num})
+		assert.NoError(t, err)
+
+		//nolint:forcetypeassert
+		assert.Empty(t, res.([]*receipt))
+	})
+
+	t.Run("returns nil if block not found", func(t *testing.T) {
+		res, err := eth.GetBlockReceipts(BlockNumberOrHash{BlockHash: &hash2})
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
+
 func TestEth_Syncing(t *testing.T) {
 	store := newMockBlockStore()
 	eth := newTestEthEndpoint(store)
@@ -250,6 +323,82 @@ func TestEth_GasPrice(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("0x%x", store.averageGasPrice), response)
 }
 
+func TestEth_MaxPriorityFeePerGas(t *testing.T) {
+	store := newMockBlockStore()
+	store.averageGasPrice, _ = strconv.ParseInt(defaultMinGasPrice, 0, 64)
+	store.averageGasPrice *= 2
+	store.add(&types.Block{
+		Header: &types.Header{
+			Number:  1,
+			BaseFee: big.NewInt(1000),
+		},
+	})
+
+	eth := newTestEthEndpoint(store)
+
+	res, err := eth.MaxPriorityFeePerGas()
+	assert.NoError(t, err)
+	// the base fee is not included
+	assert.Equal(t, fmt.Sprintf("0x%x", store.averageGasPrice), res)
+
+	res, err = eth.GasPrice()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("0x%x", store.averageGasPrice+1000), res)
+}
+
+func TestEth_FeeHistory(t *testing.T) {
+	store := newMockBlockStore()
+	store.add(newTestBlock(9, hash1))
+	store.feeHistory = &gasprice.FeeHistory{
+		OldestBlock:  8,
+		Reward:       [][]*big.Int{{big.NewInt(1)}, {big.NewInt(2)}},
+		BaseFee:      []*big.Int{big.NewInt(10), big.NewInt(11), big.NewInt(12)},
+		GasUsedRatio: []float64{0.5, 1},
+	}
+
+	eth := newTestEthEndpoint(store)
+	eth.blockRangeLimit = 10
+
+	t.Run("returns the fee history of the latest blocks", func(t *testing.T) {
+		res, err := eth.FeeHistory(2, LatestBlockNumber, &[]float64{50})
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(2), store.feeHistoryArgs.blocks)
+		assert.Equal(t, uint64(9), store.feeHistoryArgs.lastBlock)
+		assert.Equal(t, []float64{50}, store.feeHistoryArgs.percentiles)
+
+		raw, err := json.Marshal(res)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"oldestBlock": "0x8",
+			"reward": [["0x1"], ["0x2"]],
+			"baseFeePerGas": ["0xa", "0xb", "0xc"],
+			"gasUsedRatio": [0.5, 1]
+		}`, string(raw))
+	})
+
+	t.Run("returns the fee history of the given block", func(t *testing.T) {
+		_, err := eth.FeeHistory(2, BlockNumber(5), nil)
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(5), store.feeHistoryArgs.lastBlock)
+		assert.Nil(t, store.feeHistoryArgs.percentiles)
+	})
+
+	t.Run("clamps the newest block to the head", func(t *testing.T) {
+		_, err := eth.FeeHistory(2, BlockNumber(100), nil)
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(2), store.feeHistoryArgs.blocks)
+		assert.Equal(t, uint64(9), store.feeHistoryArgs.lastBlock)
+	})
+
+	t.Run("returns error if the range is too high", func(t *testing.T) {
+		_, err := eth.FeeHistory(11, LatestBlockNumber, nil)
+		assert.ErrorIs(t, err, ErrBlockRangeTooHigh)
+	})
+}
+
 func TestEth_Call(t *testing.T) {
 	t.Run("returns error if transaction execution fails", func(t *testing.T) {
 		store := newMockBlockStore()
@@ -266,7 +415,7 @@ func TestEth_Call(t *testing.T) {
 			Nonce:    argUintPtr(0),
 		}
 
-		res, err := eth.Call(contractCall, BlockNumberOrHash{})
+		res, err := eth.Call(contractCall, BlockNumberOrHash{}, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), store.ethCallError.Error())
@@ -288,7 +437,7 @@ func TestEth_Call(t *testing.T) {
 			Nonce:    argUintPtr(0),
 		}
 
-		res, err := eth.Call(contractCall, BlockNumberOrHash{})
+		res, err := eth.Call(contractCall, BlockNumberOrHash{}, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, res)
@@ -312,7 +461,7 @@ func TestEth_Call(t *testing.T) {
 			Nonce:    argUintPtr(0),
 		}
 
-		res, err := eth.Call(contractCall, BlockNumberOrHash{})
+		res, err := eth.Call(contractCall, BlockNumberOrHash{}, nil)
 		assert.Error(t, err)
 		assert.NotNil(t, res)
 		bres := res.([]byte) //nolint:forcetypeassert
@@ -330,6 +479,13 @@ type mockBlockStore struct {
 	averageGasPrice int64
 	ethCallError    error
 	returnValue     []byte
+	feeHistory      *gasprice.FeeHistory
+	feeHistoryArgs  struct {
+		blocks, lastBlock uint64
+		percentiles       []float64
+	}
+	bloomIndexed    uint64   // blocks before it are indexed
+	bloomCandidates []uint64 // indexed blocks matching the bloom filters
 }
 
 func newMockBlockStore() *mockBlockStore {
@@ -466,9 +622,17 @@ func (m *mockBlockStore) GetBlockByHash(hash types.Hash, full bool) (*types.Bloc
 }
 
 func (m *mockBlockStore) Header() *types.Header {
+	if len(m.blocks) == 0 {
+		return nil
+	}
+
 	return m.blocks[len(m.blocks)-1].Header
 }
 
+func (m *mockBlockStore) FinalizedHeader() *types.Header {
+	return m.Header()
+}
+
 func (m *mockBlockStore) ReadTxLookup(txnHash types.Hash) (types.Hash, bool) {
 	for _, block := range m.blocks {
 		for _, txn := range block.Transactions {
@@ -508,6 +672,17 @@ func (m *mockBlockStore) GetAvgGasPrice() *big.Int {
 	return big.NewInt(m.averageGasPrice)
 }
 
+func (m *mockBlockStore) FeeHistory(
+	blocks, lastBlock uint64,
+	rewardPercentiles []float64,
+) (*gasprice.FeeHistory, error) {
+	m.feeHistoryArgs.blocks = blocks
+	m.feeHistoryArgs.lastBlock = lastBlock
+	m.feeHistoryArgs.percentiles = rewardPercentiles
+
+	return m.feeHistory, nil
+}
+
 func (m *mockBlockStore) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
 	return &runtime.ExecutionResult{
 		Err:         m.ethCallError,
@@ -519,6 +694,28 @@ func (m *mockBlockStore) SubscribeEvents() blockchain.Subscription {
 	return nil
 }
 
+func (m *mockBlockStore) SubscribeTxPoolEvents(
+	eventTypes ...proto.EventType,
+) (<-chan *proto.TxPoolEvent, func()) {
+	return nil, func() {}
+}
+
+func (m *mockBlockStore) FilterBlocksByBloom(from, to uint64, filters [][][]byte) ([]uint64, uint64, error) {
+	if m.bloomIndexed <= from {
+		return nil, from, nil
+	}
+
+	blocks := make([]uint64, 0)
+
+	for _, num := range m.bloomCandidates {
+		if num >= from && num <= to && num < m.bloomIndexed {
+			blocks = append(blocks, num)
+		}
+	}
+
+	return blocks, m.bloomIndexed, nil
+}
+
 func newTestBlock(number uint64, hash types.Hash) *types.Block {
 	return &types.Block{
 		Header: &types.Header{
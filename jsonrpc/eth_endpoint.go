@@ -1,15 +1,22 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
+	"github.com/dogechain-lab/dogechain/accounts"
 	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/helper/gasprice"
 	"github.com/dogechain-lab/dogechain/helper/hex"
 	"github.com/dogechain-lab/dogechain/helper/progress"
 	"github.com/dogechain-lab/dogechain/state"
 	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/runtime/precompiled"
+	"github.com/dogechain-lab/dogechain/state/tracer/accesslist"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/hashicorp/go-hclog"
 )
@@ -34,12 +41,17 @@ type ethStateStore interface {
 	GetStorage(root types.Hash, addr types.Address, slot types.Hash) ([]byte, error)
 	GetForksInTime(blockNumber uint64) chain.ForksInTime
 	GetCode(stateRoot types.Hash, accoun types.Address) ([]byte, error)
+	// GetProof returns the merkle proofs of the account and its storage slots (eip-1186)
+	GetProof(root types.Hash, addr types.Address, slots []types.Hash) (*state.AccountProof, error)
 }
 
 type ethBlockchainStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
+	// FinalizedHeader returns the header of the last block with a verified commit quorum
+	FinalizedHeader() *types.Header
+
 	// GetHeaderByNumber returns the header by number
 	GetHeaderByNumber(block uint64) (*types.Header, bool)
 
@@ -61,6 +73,9 @@ type ethBlockchainStore interface {
 	// GetAvgGasPrice returns the average gas price
 	GetAvgGasPrice() *big.Int
 
+	// FeeHistory returns the fee market history of the blocks up to the last block
+	FeeHistory(blocks, lastBlock uint64, rewardPercentiles []float64) (*gasprice.FeeHistory, error)
+
 	// ApplyTxn applies a transaction object to the blockchain
 	ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error)
 
@@ -70,6 +85,15 @@ type ethBlockchainStore interface {
 	// StateAtTransaction returns the execution environment of a certain transaction.
 	// The transition should not commit, it shall be collected by GC.
 	StateAtTransaction(block *types.Block, txIndex int) (*state.Transition, error)
+
+	// BeginTxn returns the execution environment on top of the state of the given header.
+	// The transition should not commit, it shall be collected by GC.
+	BeginTxn(header *types.Header) (*state.Transition, error)
+
+	// BeginPendingTxn returns the execution environment of a pending block on top of the
+	// state of its parent, the fees go to the coinbase, or the creator of the parent if nil.
+	// The transition should not commit, it shall be collected by GC.
+	BeginPendingTxn(parent, pending *types.Header, coinbase *types.Address) (*state.Transition, error)
 }
 
 // ethStore provides access to the methods needed by eth endpoint
@@ -77,6 +101,7 @@ type ethStore interface {
 	ethTxPoolStore
 	ethStateStore
 	ethBlockchainStore
+	accountStore
 }
 
 // Eth is the eth jsonrpc endpoint
@@ -86,6 +111,8 @@ type Eth struct {
 	chainID       uint64
 	filterManager *FilterManager
 	priceLimit    uint64
+	// blockRangeLimit is the max number of blocks a request could query, 0 means no limit
+	blockRangeLimit uint64
 
 	metrics *Metrics
 }
@@ -93,6 +120,10 @@ type Eth struct {
 var (
 	ErrInsufficientFunds = errors.New("insufficient funds for execution")
 	ErrGasCapOverflow    = errors.New("unable to apply transaction for the highest gas limit")
+	ErrEmptyBundle       = errors.New("bundle has no transactions")
+	ErrBundleGasLimit    = errors.New("bundle exceeds block gas limit")
+	ErrMissingSender     = errors.New("missing the sender of the transaction")
+	ErrBerlinNotEnabled  = errors.New("access lists are not supported before the berlin hardfork")
 )
 
 // ChainId returns the chain id of the client
@@ -130,15 +161,9 @@ func (e *Eth) getHeaderFromBlockNumberOrHash(bnh *BlockNumberOrHash) (*types.Hea
 func (e *Eth) Syncing() (interface{}, error) {
 	e.metrics.EthAPICounterInc(EthSyncingLabel)
 
-	if syncProgression := e.store.GetSyncProgression(); syncProgression != nil {
+	if syncProgression := toProgression(e.store.GetSyncProgression()); syncProgression != nil {
 		// Node is bulk syncing, return the status
-		return progression{
-			Type:          string(syncProgression.SyncType),
-			SyncingPeer:   syncProgression.SyncingPeer,
-			StartingBlock: hex.EncodeUint64(syncProgression.StartingBlock),
-			CurrentBlock:  hex.EncodeUint64(syncProgression.CurrentBlock),
-			HighestBlock:  hex.EncodeUint64(syncProgression.HighestBlock.Load()),
-		}, nil
+		return *syncProgression, nil
 	}
 
 	// Node is not bulk syncing
@@ -150,6 +175,9 @@ func GetNumericBlockNumber(number BlockNumber, e *Eth) (uint64, error) {
 	case LatestBlockNumber:
 		return e.store.Header().Number, nil
 
+	case FinalizedBlockNumber, SafeBlockNumber:
+		return e.store.FinalizedHeader().Number, nil
+
 	case EarliestBlockNumber:
 		return 0, nil
 
@@ -245,10 +273,137 @@ func (e *Eth) SendRawTransaction(input string) (interface{}, error) {
 	return tx.Hash().String(), nil
 }
 
-// Reject eth_sendTransaction json-rpc call as we don't support wallet management
+// SendTransaction signs the transaction by the unlocked keystore account of
+// the sender, and sends it to the pool
 func (e *Eth) SendTransaction(arg *txnArgs) (interface{}, error) {
-	return nil, fmt.Errorf("request calls to eth_sendTransaction method are not supported," +
-		" use eth_sendRawTransaction insead")
+	e.metrics.EthAPICounterInc(EthSendTransactionLabel)
+
+	tx, err := e.signTransaction(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.store.AddTx(tx); err != nil {
+		return nil, err
+	}
+
+	return tx.Hash().String(), nil
+}
+
+// SignTransaction signs the transaction by the unlocked keystore account of
+// the sender, without sending it
+func (e *Eth) SignTransaction(arg *txnArgs) (interface{}, error) {
+	e.metrics.EthAPICounterInc(EthSignTransactionLabel)
+
+	tx, err := e.signTransaction(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signTransactionResult{
+		Raw: argBytes(tx.MarshalRLP()),
+		Tx:  toPendingTransaction(tx),
+	}, nil
+}
+
+// Sign signs the data with the ethereum message prefix by the unlocked keystore
+// account, V of the signature is 27 or 28
+func (e *Eth) Sign(address types.Address, data argBytes) (interface{}, error) {
+	e.metrics.EthAPICounterInc(EthSignLabel)
+
+	msg := append([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(data))), data...)
+
+	return e.signHash(address, crypto.Keccak256(msg))
+}
+
+// SignTypedData_v4 signs the eip-712 typed data by the unlocked keystore account,
+// V of the signature is 27 or 28
+//
+//nolint:stylecheck
+func (e *Eth) SignTypedData_v4(address types.Address, data json.RawMessage) (interface{}, error) {
+	e.metrics.EthAPICounterInc(EthSignTypedDataLabel)
+
+	typedData, err := accounts.ParseTypedData(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid typed data: %w", err)
+	}
+
+	hash, err := typedData.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return e.signHash(address, hash)
+}
+
+// signHash signs the hash by the unlocked keystore account in the format of
+// the ethereum clients
+func (e *Eth) signHash(address types.Address, hash []byte) (interface{}, error) {
+	sig, err := e.store.SignHash(address, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	sig[64] += 27
+
+	return argBytes(sig), nil
+}
+
+// signTransaction fills the missing fields of the transaction, and signs it by
+// the unlocked keystore account of the sender
+func (e *Eth) signTransaction(arg *txnArgs) (*types.Transaction, error) {
+	if arg.From == nil {
+		return nil, ErrMissingSender
+	}
+
+	if arg.Nonce == nil {
+		nonce, err := e.getNextNonce(*arg.From, PendingBlockNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		arg.Nonce = argUintPtr(nonce)
+	}
+
+	header := e.store.Header()
+
+	if arg.MaxFeePerGas != nil || arg.MaxPriorityFeePerGas != nil {
+		if arg.MaxPriorityFeePerGas == nil {
+			arg.MaxPriorityFeePerGas = (*argBig)(e.suggestTipCap())
+		}
+
+		// leave room for the base fee to double
+		if arg.MaxFeePerGas == nil {
+			feeCap := new(big.Int).Set((*big.Int)(arg.MaxPriorityFeePerGas))
+			if header.BaseFee != nil {
+				feeCap.Add(feeCap, new(big.Int).Mul(header.BaseFee, big.NewInt(2)))
+			}
+
+			arg.MaxFeePerGas = (*argBig)(feeCap)
+		}
+	} else if arg.GasPrice == nil {
+		arg.GasPrice = argBytesPtr(e.suggestGasPrice().Bytes())
+	}
+
+	if arg.Gas == nil {
+		estimateArg := *arg
+
+		gas, err := e.estimateGas(&estimateArg, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		arg.Gas = argUintPtr(gas)
+	}
+
+	tx, err := e.decodeTxn(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := crypto.NewSigner(e.store.GetForksInTime(header.Number), e.chainID)
+
+	return e.store.SignTx(*arg.From, tx, signer)
 }
 
 // GetTransactionByHash returns a transaction by its hash.
@@ -282,6 +437,7 @@ func (e *Eth) GetTransactionByHash(hash types.Hash) (interface{}, error) {
 					argUintPtr(block.Number()),
 					argHashPtr(block.Hash()),
 					&idx,
+					block.Header.BaseFee,
 				)
 			}
 		}
@@ -373,38 +529,47 @@ func (e *Eth) GetTransactionReceipt(hash types.Hash) (interface{}, error) {
 		return nil, nil
 	}
 
-	txn := block.Transactions[txIndex]
-	raw := receipts[txIndex]
+	return toReceipt(receipts[txIndex], block.Transactions[txIndex], txIndex, block.Header), nil
+}
 
-	logs := make([]*Log, len(raw.Logs))
-	for indx, elem := range raw.Logs {
-		logs[indx] = &Log{
-			Address:     elem.Address,
-			Topics:      elem.Topics,
-			Data:        argBytes(elem.Data),
-			BlockHash:   block.Hash(),
-			BlockNumber: argUint64(block.Number()),
-			TxHash:      txn.Hash(),
-			TxIndex:     argUint64(txIndex),
-			LogIndex:    argUint64(indx),
-			Removed:     false,
-		}
+// GetBlockReceipts returns all the transaction receipts of the given block
+func (e *Eth) GetBlockReceipts(filter BlockNumberOrHash) (interface{}, error) {
+	e.metrics.EthAPICounterInc(EthGetBlockReceiptsLabel)
+
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = CreateBlockNumberPointer(LatestBlockFlag)
+	}
+
+	header, err := e.getHeaderFromBlockNumberOrHash(&filter)
+	if err != nil {
+		// block not found
+		return nil, nil
+	}
+
+	block, ok := e.store.GetBlockByHash(header.Hash, true)
+	if !ok {
+		// block not found
+		return nil, nil
+	}
+
+	receipts, err := e.store.GetReceiptsByHash(block.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(receipts) != len(block.Transactions) {
+		// Receipts not written yet on the db
+		e.logger.Warn(
+			fmt.Sprintf("No receipts found for block with hash [%s]", block.Hash().String()),
+		)
+
+		return nil, nil
 	}
 
-	res := &receipt{
-		Root:              raw.Root,
-		CumulativeGasUsed: argUint64(raw.CumulativeGasUsed),
-		LogsBloom:         raw.LogsBloom,
-		Status:            argUint64(*raw.Status),
-		TxHash:            txn.Hash(),
-		TxIndex:           argUint64(txIndex),
-		BlockHash:         block.Hash(),
-		BlockNumber:       argUint64(block.Number()),
-		GasUsed:           argUint64(raw.GasUsed),
-		ContractAddress:   raw.ContractAddress,
-		FromAddr:          txn.From,
-		ToAddr:            txn.To,
-		Logs:              logs,
+	res := make([]*receipt, len(receipts))
+	for i, raw := range receipts {
+		res[i] = toReceipt(raw, block.Transactions[i], i, block.Header)
 	}
 
 	return res, nil
@@ -450,7 +615,80 @@ func (e *Eth) GetStorageAt(
 func (e *Eth) GasPrice() (interface{}, error) {
 	e.metrics.EthAPICounterInc(EthGasPriceLabel)
 
-	// Grab the average gas price and convert it to a hex value
+	return hex.EncodeBig(e.suggestGasPrice()), nil
+}
+
+// MaxPriorityFeePerGas returns the suggested tip cap of the dynamic fee transactions
+func (e *Eth) MaxPriorityFeePerGas() (interface{}, error) {
+	e.metrics.EthAPICounterInc(EthMaxPriorityFeePerGasLabel)
+
+	return hex.EncodeBig(e.suggestTipCap()), nil
+}
+
+// FeeHistory returns the base fees, gas used ratios and the reward percentiles
+// of the effective tips of a range of blocks
+func (e *Eth) FeeHistory(
+	blockCount argUint64,
+	newestBlock BlockNumber,
+	rewardPercentiles *[]float64,
+) (interface{}, error) {
+	e.metrics.EthAPICounterInc(EthFeeHistoryLabel)
+
+	if e.blockRangeLimit > 0 && uint64(blockCount) > e.blockRangeLimit {
+		return nil, ErrBlockRangeTooHigh
+	}
+
+	var (
+		lastBlock   uint64
+		percentiles []float64
+	)
+
+	switch newestBlock {
+	case LatestBlockNumber, PendingBlockNumber:
+		lastBlock = e.store.Header().Number
+	case FinalizedBlockNumber, SafeBlockNumber:
+		lastBlock = e.store.FinalizedHeader().Number
+	case EarliestBlockNumber:
+		lastBlock = 0
+	default:
+		if newestBlock < 0 {
+			return nil, fmt.Errorf("invalid argument 1: block number larger than int64")
+		}
+
+		lastBlock = uint64(newestBlock)
+	}
+
+	// the future blocks are not in the range
+	if head := e.store.Header().Number; lastBlock > head {
+		lastBlock = head
+	}
+
+	if rewardPercentiles != nil {
+		percentiles = *rewardPercentiles
+	}
+
+	history, err := e.store.FeeHistory(uint64(blockCount), lastBlock, percentiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return toFeeHistory(history), nil
+}
+
+// suggestGasPrice returns the suggested tip cap, plus the base fee which the
+// sender should pay too after the london hardfork
+func (e *Eth) suggestGasPrice() *big.Int {
+	v := e.suggestTipCap()
+
+	if header := e.store.Header(); header != nil && header.BaseFee != nil {
+		v = new(big.Int).Add(v, header.BaseFee)
+	}
+
+	return v
+}
+
+// suggestTipCap returns the average gas price, which is not lower than the price limit
+func (e *Eth) suggestTipCap() *big.Int {
 	priceLimit := new(big.Int).SetUint64(e.priceLimit)
 	minGasPrice, _ := new(big.Int).SetString(defaultMinGasPrice, 0)
 
@@ -464,11 +702,11 @@ func (e *Eth) GasPrice() (interface{}, error) {
 		v = priceLimit
 	}
 
-	return hex.EncodeBig(v), nil
+	return v
 }
 
 // Call executes a smart contract call using the transaction object data
-func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error) {
+func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash, override *stateOverride) (interface{}, error) {
 	e.metrics.EthAPICounterInc(EthCallLabel)
 
 	var (
@@ -486,7 +724,7 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 		return nil, fmt.Errorf("failed to get header from block hash or block number")
 	}
 
-	transaction, err := e.decodeTxn(arg)
+	transaction, err := e.decodeCallTxn(arg, override)
 
 	if err != nil {
 		return nil, err
@@ -497,7 +735,7 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 	}
 
 	// The return value of the execution is saved in the transition (returnValue field)
-	result, err := e.store.ApplyTxn(header, transaction)
+	result, err := e.applyTxn(header, transaction, override)
 	if err != nil {
 		return nil, err
 	}
@@ -515,12 +753,23 @@ func (e *Eth) Call(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error)
 }
 
 // EstimateGas estimates the gas needed to execute a transaction
-func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error) {
+func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber, override *stateOverride) (interface{}, error) {
 	e.metrics.EthAPICounterInc(EthEstimateGasLabel)
 
-	transaction, err := e.decodeTxn(arg)
+	gas, err := e.estimateGas(arg, rawNum, override)
 	if err != nil {
-		return nil, err
+		return 0, err
+	}
+
+	return hex.EncodeUint64(gas), nil
+}
+
+// estimateGas returns the lowest gas limit the transaction passes with, by the
+// binary search
+func (e *Eth) estimateGas(arg *txnArgs, rawNum *BlockNumber, override *stateOverride) (uint64, error) {
+	transaction, err := e.decodeCallTxn(arg, override)
+	if err != nil {
+		return 0, err
 	}
 
 	number := LatestBlockNumber
@@ -531,11 +780,47 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 	// Fetch the requested header
 	header, err := e.getBlockHeader(number)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
 	forksInTime := e.store.GetForksInTime(uint64(number))
 
+	// If the account is not initialized yet in state, assume it's an empty account
+	getBalance := func(addr types.Address) (*big.Int, error) {
+		if balance := override.balance(addr); balance != nil {
+			// The overridden balance takes precedence over the state
+			return balance, nil
+		}
+
+		acc, err := e.store.GetAccount(header.StateRoot, addr)
+		if err != nil && !errors.Is(err, ErrStateNotFound) {
+			// An unrelated error occurred, return it
+			return nil, err
+		} else if err == nil {
+			return acc.Balance, nil
+		}
+
+		return big.NewInt(0), nil
+	}
+
+	applyTxn := func(txn *types.Transaction) (*runtime.ExecutionResult, error) {
+		return e.applyTxn(header, txn, override)
+	}
+
+	return EstimateTxnGas(header, forksInTime, transaction, getBalance, applyTxn)
+}
+
+// EstimateTxnGas returns the lowest gas limit the transaction passes with on top of
+// the state of the header, by the binary search. The ceiling is the gas limit of
+// the transaction if given, otherwise the block gas limit, and it is capped by the
+// funds of the sender.
+func EstimateTxnGas(
+	header *types.Header,
+	forksInTime chain.ForksInTime,
+	transaction *types.Transaction,
+	getBalance func(addr types.Address) (*big.Int, error),
+	applyTxn func(txn *types.Transaction) (*runtime.ExecutionResult, error),
+) (uint64, error) {
 	var standardGas uint64
 	if transaction.IsContractCreation() && forksInTime.Homestead {
 		standardGas = state.TxGasContractCreation
@@ -564,19 +849,9 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 	// If the sender address is present, figure out how much available funds
 	// are we working with
 	if transaction.From != types.ZeroAddress {
-		// Get the account balance
-		// If the account is not initialized yet in state,
-		// assume it's an empty account
-		accountBalance := big.NewInt(0)
-		acc, err := e.store.GetAccount(header.StateRoot, transaction.From)
-
-		if err != nil && !errors.Is(err, ErrStateNotFound) {
-			// An unrelated error occurred, return it
-			return nil, err
-		} else if err == nil {
-			// No error when fetching the account,
-			// read the balance from state
-			accountBalance = acc.Balance
+		accountBalance, err := getBalance(transaction.From)
+		if err != nil {
+			return 0, err
 		}
 
 		availableBalance = new(big.Int).Set(accountBalance)
@@ -599,13 +874,6 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 
 		// Check the gas allowance for this account, make sure high end is capped to it
 		if gasAllowance.IsUint64() && highEnd > gasAllowance.Uint64() {
-			e.logger.Debug(
-				fmt.Sprintf(
-					"Gas estimation high-end capped by allowance [%d]",
-					gasAllowance.Uint64(),
-				),
-			)
-
 			highEnd = gasAllowance.Uint64()
 		}
 	}
@@ -633,7 +901,7 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 		txn := transaction.Copy()
 		txn.Gas = gas
 
-		result, applyErr := e.store.ApplyTxn(header, txn)
+		result, applyErr := applyTxn(txn)
 
 		if applyErr != nil {
 			// Check the application error.
@@ -701,7 +969,225 @@ func (e *Eth) EstimateGas(arg *txnArgs, rawNum *BlockNumber) (interface{}, error
 		)
 	}
 
-	return hex.EncodeUint64(highEnd), nil
+	return highEnd, nil
+}
+
+// CreateAccessList returns the access list of the addresses and storage slots the
+// transaction touches, along with the gas used when it is attached (eip-2930)
+func (e *Eth) CreateAccessList(arg *txnArgs, filter BlockNumberOrHash) (interface{}, error) {
+	e.metrics.EthAPICounterInc(EthCreateAccessListLabel)
+
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = CreateBlockNumberPointer(LatestBlockFlag)
+	}
+
+	header, err := e.getHeaderFromBlockNumberOrHash(&filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header from block hash or block number")
+	}
+
+	forks := e.store.GetForksInTime(header.Number)
+	if !forks.Berlin {
+		return nil, ErrBerlinNotEnabled
+	}
+
+	transaction, err := e.decodeTxn(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Gas == 0 {
+		transaction.Gas = header.GasLimit
+	}
+
+	// The sender, the receiver and the precompiled contracts are always warm
+	to := crypto.CreateAddress(transaction.From, transaction.Nonce)
+	if transaction.To != nil {
+		to = *transaction.To
+	}
+
+	excluded := append([]types.Address{transaction.From, to}, precompiled.ActiveAddresses(&forks)...)
+
+	// Apply the transaction with the access list collected by the last run, until
+	// the access list stops changing, since it changes the execution path
+	prevTracer := accesslist.NewTracer(transaction.AccessList, excluded)
+
+	for {
+		acl := prevTracer.AccessList()
+
+		txn := transaction.Copy()
+		txn.AccessList = acl
+
+		transition, err := e.store.BeginTxn(header)
+		if err != nil {
+			return nil, err
+		}
+
+		// zero priced calls are exempted from the base fee
+		transition.SetNoBaseFee(true)
+
+		tracer := accesslist.NewTracer(acl, excluded)
+		transition.SetEVMLogger(tracer)
+
+		result, err := transition.Apply(txn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply transaction: %w", err)
+		}
+
+		if tracer.Equal(prevTracer) {
+			res := &accessListResult{
+				AccessList: acl,
+				GasUsed:    argUint64(result.GasUsed),
+			}
+
+			if result.Reverted() {
+				res.Error = constructErrorFromRevert(result).Error()
+			} else if result.Failed() {
+				res.Error = result.Err.Error()
+			}
+
+			return res, nil
+		}
+
+		prevTracer = tracer
+	}
+}
+
+// CallBundle simulates an ordered list of transactions in a pending block on top of the
+// state of the given block, each transaction sees the state effects of the previous ones.
+// The number, timestamp and coinbase of the pending block could be overridden.
+func (e *Eth) CallBundle(args []*txnArgs, filter BlockNumberOrHash, override *blockOverride) (interface{}, error) {
+	e.metrics.EthAPICounterInc(EthCallBundleLabel)
+
+	if len(args) == 0 {
+		return nil, ErrEmptyBundle
+	}
+
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = CreateBlockNumberPointer(LatestBlockFlag)
+	}
+
+	parent, err := e.getHeaderFromBlockNumberOrHash(&filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header from block hash or block number")
+	}
+
+	// The pending block is the child of the given block by default
+	pending := &types.Header{
+		ParentHash: parent.Hash,
+		Number:     parent.Number + 1,
+		GasLimit:   parent.GasLimit,
+		Difficulty: parent.Difficulty,
+		Timestamp:  uint64(time.Now().Unix()),
+	}
+
+	if pending.Timestamp <= parent.Timestamp {
+		pending.Timestamp = parent.Timestamp + 1
+	}
+
+	var coinbase *types.Address
+
+	if override != nil {
+		if override.Number != nil {
+			pending.Number = uint64(*override.Number)
+		}
+
+		if override.Timestamp != nil {
+			pending.Timestamp = uint64(*override.Timestamp)
+		}
+
+		coinbase = override.Coinbase
+	}
+
+	transition, err := e.store.BeginPendingTxn(parent, pending, coinbase)
+	if err != nil {
+		return nil, err
+	}
+
+	// zero priced calls are exempted from the base fee
+	transition.SetNoBaseFee(true)
+
+	receiver := transition.GetTxContext().Coinbase
+	balanceBefore := transition.GetBalance(receiver)
+
+	res := &bundleResult{
+		Results:          make([]*bundleCallResult, len(args)),
+		StateBlockNumber: argUint64(parent.Number),
+		BlockNumber:      argUint64(pending.Number),
+	}
+
+	var (
+		gasUsed  uint64
+		logIndex uint64
+	)
+
+	for i, arg := range args {
+		// No gas is left in the pending block for the rest transactions
+		if gasUsed >= pending.GasLimit {
+			return nil, fmt.Errorf("%w at transaction %d", ErrBundleGasLimit, i)
+		}
+
+		// The nonce follows the previous transactions of the sender in the bundle
+		if arg.From != nil && arg.Nonce == nil {
+			arg.Nonce = argUintPtr(transition.GetNonce(*arg.From))
+		}
+
+		txn, err := e.decodeTxn(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode transaction %d: %w", i, err)
+		}
+
+		// Use up the rest gas of the block by default
+		if txn.Gas == 0 {
+			txn.Gas = pending.GasLimit - gasUsed
+		}
+
+		result, err := transition.Apply(txn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply transaction %d: %w", i, err)
+		}
+
+		gasUsed += result.GasUsed
+
+		callResult := &bundleCallResult{
+			TxHash:      txn.Hash(),
+			GasUsed:     argUint64(result.GasUsed),
+			ReturnValue: result.ReturnValue,
+			Logs:        []*Log{},
+		}
+
+		if result.Reverted() {
+			callResult.Error = constructErrorFromRevert(result).Error()
+		} else if result.Failed() {
+			callResult.Error = result.Err.Error()
+		}
+
+		for _, log := range transition.Txn().Logs() {
+			callResult.Logs = append(callResult.Logs, &Log{
+				Address:     log.Address,
+				Topics:      log.Topics,
+				Data:        argBytes(log.Data),
+				BlockNumber: argUint64(pending.Number),
+				TxHash:      callResult.TxHash,
+				TxIndex:     argUint64(i),
+				LogIndex:    argUint64(logIndex),
+			})
+
+			logIndex++
+		}
+
+		// The suicided accounts are set as deleted for the next transaction
+		transition.Txn().CleanDeleteObjects(true)
+
+		res.Results[i] = callResult
+	}
+
+	res.GasUsed = argUint64(gasUsed)
+	res.CoinbaseDiff = argBig(*new(big.Int).Sub(transition.GetBalance(receiver), balanceBefore))
+
+	return res, nil
 }
 
 // GetFilterLogs returns an array of logs for the specified filter
@@ -825,18 +1311,45 @@ func (e *Eth) GetCode(address types.Address, filter BlockNumberOrHash) (interfac
 	return argBytesPtr(code), nil
 }
 
+// GetProof returns the account and storage values of the specified account
+// including the merkle proofs (eip-1186)
+func (e *Eth) GetProof(
+	address types.Address,
+	storageKeys []types.Hash,
+	filter BlockNumberOrHash,
+) (interface{}, error) {
+	e.metrics.EthAPICounterInc(EthGetProofLabel)
+
+	// The filter is empty, use the latest block by default
+	if filter.BlockNumber == nil && filter.BlockHash == nil {
+		filter.BlockNumber, _ = CreateBlockNumberPointer(LatestBlockFlag)
+	}
+
+	header, err := e.getHeaderFromBlockNumberOrHash(&filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header from block hash or block number")
+	}
+
+	proof, err := e.store.GetProof(header.StateRoot, address, storageKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAccountProof(proof), nil
+}
+
 // NewFilter creates a filter object, based on filter options, to notify when the state changes (logs).
 func (e *Eth) NewFilter(filter *LogQuery) (interface{}, error) {
 	e.metrics.EthAPICounterInc(EthNewFilterLabel)
 
-	return e.filterManager.NewLogFilter(filter, nil), nil
+	return e.filterManager.NewLogFilter(filter, nil)
 }
 
 // NewBlockFilter creates a filter in the node, to notify when a new block arrives
 func (e *Eth) NewBlockFilter() (interface{}, error) {
 	e.metrics.EthAPICounterInc(EthNewBlockFilterLabel)
 
-	return e.filterManager.NewBlockFilter(nil), nil
+	return e.filterManager.NewBlockFilter(nil)
 }
 
 // GetFilterChanges is a polling method for a filter, which returns an array of logs
@@ -867,6 +1380,9 @@ func (e *Eth) getBlockHeader(number BlockNumber) (*types.Header, error) {
 	case LatestBlockNumber:
 		return e.store.Header(), nil
 
+	case FinalizedBlockNumber, SafeBlockNumber:
+		return e.store.FinalizedHeader(), nil
+
 	case EarliestBlockNumber:
 		header, ok := e.store.GetHeaderByNumber(uint64(0))
 		if !ok {
@@ -919,6 +1435,42 @@ func (e *Eth) getNextNonce(address types.Address, number BlockNumber) (uint64, e
 	return acc.Nonce, nil
 }
 
+// decodeCallTxn decodes the transaction of a call, the overridden nonce of the sender
+// is used if the nonce is not given
+func (e *Eth) decodeCallTxn(arg *txnArgs, override *stateOverride) (*types.Transaction, error) {
+	if arg.From != nil && arg.Nonce == nil {
+		arg.Nonce = override.nonce(*arg.From)
+	}
+
+	return e.decodeTxn(arg)
+}
+
+// applyTxn applies the transaction on top of the state of the header,
+// the overrides are put under the state if any
+func (e *Eth) applyTxn(
+	header *types.Header,
+	txn *types.Transaction,
+	override *stateOverride,
+) (*runtime.ExecutionResult, error) {
+	if override == nil {
+		return e.store.ApplyTxn(header, txn)
+	}
+
+	transition, err := e.store.BeginTxn(header)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := transition.ApplyStateOverride(override.toStateOverride()); err != nil {
+		return nil, err
+	}
+
+	// zero priced calls are exempted from the base fee
+	transition.SetNoBaseFee(true)
+
+	return transition.Apply(txn)
+}
+
 func (e *Eth) decodeTxn(arg *txnArgs) (*types.Transaction, error) {
 	// set default values
 	if arg.From == nil {
@@ -974,6 +1526,44 @@ func (e *Eth) decodeTxn(arg *txnArgs) (*types.Transaction, error) {
 		txn.To = arg.To
 	}
 
+	if arg.Type != nil {
+		txn.Type = types.TxType(*arg.Type)
+	} else if arg.MaxFeePerGas != nil || arg.MaxPriorityFeePerGas != nil {
+		txn.Type = types.DynamicFeeTx
+	} else if arg.AccessList != nil {
+		txn.Type = types.AccessListTx
+	}
+
+	if !txn.Type.IsSupported() {
+		return nil, types.ErrTxTypeNotSupported
+	}
+
+	if txn.Type.IsTyped() {
+		if arg.ChainID != nil {
+			txn.ChainID = new(big.Int).Set((*big.Int)(arg.ChainID))
+		} else {
+			txn.ChainID = new(big.Int).SetUint64(e.chainID)
+		}
+
+		if arg.AccessList != nil {
+			txn.AccessList = arg.AccessList.Copy()
+		}
+	}
+
+	if txn.Type == types.DynamicFeeTx {
+		// the fee cap falls back to the gas price, and the gas price mirrors the fee cap
+		if arg.MaxFeePerGas != nil {
+			txn.GasPrice = new(big.Int).Set((*big.Int)(arg.MaxFeePerGas))
+		}
+
+		txn.GasFeeCap = new(big.Int).Set(txn.GasPrice)
+		txn.GasTipCap = new(big.Int)
+
+		if arg.MaxPriorityFeePerGas != nil {
+			txn.GasTipCap.Set((*big.Int)(arg.MaxPriorityFeePerGas))
+		}
+	}
+
 	txn.Hash()
 
 	return txn, nil
@@ -7,10 +7,13 @@ import (
 	"testing"
 
 	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/crypto"
 	"github.com/dogechain-lab/dogechain/helper/hex"
 	"github.com/dogechain-lab/dogechain/state"
+	itrie "github.com/dogechain-lab/dogechain/state/immutable-trie"
 	"github.com/dogechain-lab/dogechain/state/runtime"
 	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -577,6 +580,104 @@ func TestEth_State_GetStorageAt(t *testing.T) {
 	}
 }
 
+type mockProofStore struct {
+	mockSpecialStore
+	state state.State
+}
+
+func (m *mockProofStore) GetProof(
+	root types.Hash,
+	addr types.Address,
+	slots []types.Hash,
+) (*state.AccountProof, error) {
+	//nolint:forcetypeassert
+	return m.state.(state.Prover).GetProof(root, addr, slots)
+}
+
+func TestEth_State_GetProof(t *testing.T) {
+	stateDB := itrie.NewStateDB(itrie.NewMemoryStorage(), hclog.NewNullLogger(), nil)
+	slot := types.StringToHash("0x1")
+
+	_, root, err := stateDB.NewSnapshot().Commit([]*state.Object{
+		{
+			Address:  addr0,
+			Balance:  big.NewInt(100),
+			Nonce:    1,
+			Root:     types.EmptyRootHash,
+			CodeHash: types.BytesToHash(crypto.Keccak256(code0)),
+			Storage: []*state.StorageObject{
+				{Key: slot.Bytes(), Val: types.StringToHash("0x2a").Bytes()},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	stateRoot := types.BytesToHash(root)
+	store := &mockProofStore{
+		mockSpecialStore: mockSpecialStore{
+			block: &types.Block{
+				Header: &types.Header{
+					Hash:      types.ZeroHash,
+					Number:    0,
+					StateRoot: stateRoot,
+				},
+			},
+		},
+		state: stateDB,
+	}
+	eth := newTestEthEndpoint(store)
+
+	t.Run("returns the proofs of an existing account", func(t *testing.T) {
+		res, err := eth.GetProof(addr0, []types.Hash{slot}, BlockNumberOrHash{})
+		assert.NoError(t, err)
+
+		//nolint:forcetypeassert
+		proof := res.(*accountProof)
+		assert.Equal(t, addr0, proof.Address)
+		assert.Equal(t, argUint64(1), proof.Nonce)
+		assert.Equal(t, *argBigPtr(big.NewInt(100)), proof.Balance)
+		assert.Equal(t, types.BytesToHash(crypto.Keccak256(code0)), proof.CodeHash)
+		assert.NotEqual(t, types.EmptyRootHash, proof.StorageHash)
+
+		nodes := make([][]byte, len(proof.AccountProof))
+		for i, node := range proof.AccountProof {
+			nodes[i] = node
+		}
+
+		data, err := itrie.VerifyProof(stateRoot, crypto.Keccak256(addr0.Bytes()), nodes)
+		assert.NoError(t, err)
+
+		var account state.Account
+		assert.NoError(t, account.UnmarshalRlp(data))
+		assert.Equal(t, proof.StorageHash, account.Root)
+
+		assert.Len(t, proof.StorageProof, 1)
+		assert.Equal(t, slot, proof.StorageProof[0].Key)
+		assert.Equal(t, *argBigPtr(big.NewInt(0x2a)), proof.StorageProof[0].Value)
+		assert.NotEmpty(t, proof.StorageProof[0].Proof)
+	})
+
+	t.Run("returns the proofs of a missing account", func(t *testing.T) {
+		res, err := eth.GetProof(uninitializedAddress, []types.Hash{slot}, BlockNumberOrHash{})
+		assert.NoError(t, err)
+
+		//nolint:forcetypeassert
+		proof := res.(*accountProof)
+		assert.Equal(t, argUint64(0), proof.Nonce)
+		assert.Equal(t, types.EmptyRootHash, proof.StorageHash)
+		assert.Equal(t, types.BytesToHash(crypto.Keccak256(nil)), proof.CodeHash)
+		assert.NotEmpty(t, proof.AccountProof)
+		assert.Empty(t, proof.StorageProof[0].Proof)
+	})
+
+	t.Run("returns error if the block is not found", func(t *testing.T) {
+		hash := types.StringToHash("0x1")
+
+		_, err := eth.GetProof(addr0, nil, BlockNumberOrHash{BlockHash: &hash})
+		assert.Error(t, err)
+	})
+}
+
 func constructMockTx(gasLimit *argUint64, data *argBytes) *txnArgs {
 	return &txnArgs{
 		From:     &addr0,
@@ -676,7 +777,7 @@ func TestEth_EstimateGas_GasLimit(t *testing.T) {
 			}
 
 			// Run the estimation
-			estimate, estimateErr := ethEndpoint.EstimateGas(testCase.transaction, nil)
+			estimate, estimateErr := ethEndpoint.EstimateGas(testCase.transaction, nil, nil)
 
 			if testCase.expectedError != nil {
 				if estimateErr == nil {
@@ -727,6 +828,7 @@ func TestEth_EstimateGas_Reverts(t *testing.T) {
 	estimate, estimateErr := ethEndpoint.EstimateGas(
 		constructMockTx(nil, nil),
 		nil,
+		nil,
 	)
 
 	assert.Equal(t, 0, estimate)
@@ -753,6 +855,7 @@ func TestEth_EstimateGas_Errors(t *testing.T) {
 	estimate, estimateErr := ethEndpoint.EstimateGas(
 		mockTx,
 		nil,
+		nil,
 	)
 
 	assert.Equal(t, 0, estimate)
@@ -761,6 +864,241 @@ func TestEth_EstimateGas_Errors(t *testing.T) {
 	assert.ErrorIs(t, estimateErr, ErrInsufficientFunds)
 }
 
+func TestEth_Call_StateOverride(t *testing.T) {
+	store := newMockTraceStore(t)
+	eth := newTestDebugEndpoint(store).eth
+
+	// SLOAD(0), MSTORE(0, value), then RETURN(0, 0x20)
+	code, err := hex.DecodeHex("60005460005260206000f3")
+	assert.NoError(t, err)
+
+	call := func() *txnArgs {
+		return &txnArgs{
+			From:  &traceSender,
+			To:    &traceReceiver,
+			Nonce: argUintPtr(0),
+		}
+	}
+
+	t.Run("without override", func(t *testing.T) {
+		res, err := eth.Call(call(), BlockNumberOrHash{}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, argBytesPtr(nil), res)
+	})
+
+	t.Run("with code and state override", func(t *testing.T) {
+		res, err := eth.Call(call(), BlockNumberOrHash{}, &stateOverride{
+			traceReceiver: {
+				Code:  argBytesPtr(code),
+				State: map[types.Hash]types.Hash{types.ZeroHash: types.StringToHash("0x2a")},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, argBytesPtr(types.StringToHash("0x2a").Bytes()), res)
+
+		// the override never leaks into the state
+		res, err = eth.Call(call(), BlockNumberOrHash{}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, argBytesPtr(nil), res)
+	})
+
+	t.Run("with both state and stateDiff", func(t *testing.T) {
+		_, err := eth.Call(call(), BlockNumberOrHash{}, &stateOverride{
+			traceReceiver: {
+				State:     map[types.Hash]types.Hash{},
+				StateDiff: map[types.Hash]types.Hash{},
+			},
+		})
+		assert.ErrorIs(t, err, state.ErrStateOverrideConflict)
+	})
+}
+
+func TestEth_EstimateGas_StateOverride(t *testing.T) {
+	store := newMockTraceStore(t)
+	eth := newTestDebugEndpoint(store).eth
+
+	from := types.StringToAddress("0x5000")
+
+	// the account has no balance or nonce in the state
+	estimate, err := eth.EstimateGas(&txnArgs{
+		From:  &from,
+		To:    &traceReceiver,
+		Value: argBytesPtr([]byte{0x1}),
+	}, nil, &stateOverride{
+		from: {
+			Nonce:   argUintPtr(3),
+			Balance: argBigPtr(big.NewInt(1)),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeUint64(state.TxGas), estimate)
+}
+
+func TestEth_CreateAccessList(t *testing.T) {
+	store := newMockTraceStore(t)
+	eth := newTestDebugEndpoint(store).eth
+
+	res, err := eth.CreateAccessList(&txnArgs{
+		From:  &traceSender,
+		To:    &traceContract,
+		Nonce: argUintPtr(0),
+	}, BlockNumberOrHash{})
+	assert.NoError(t, err)
+
+	result, ok := res.(*accessListResult)
+	assert.True(t, ok)
+
+	// the receiver is always warm, only its slots are listed
+	assert.Equal(t, types.AccessList{
+		{Address: traceContract, StorageKeys: []types.Hash{types.ZeroHash}},
+	}, result.AccessList)
+	assert.Empty(t, result.Error)
+
+	// the access list is paid upfront
+	applied, err := eth.Call(&txnArgs{
+		From:       &traceSender,
+		To:         &traceContract,
+		Nonce:      argUintPtr(0),
+		AccessList: &result.AccessList,
+	}, BlockNumberOrHash{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, argBytesPtr(types.StringToHash("0x2a").Bytes()), applied)
+	assert.Greater(t, uint64(result.GasUsed), state.TxGas+state.TxAccessListAddressGas+state.TxAccessListStorageKeyGas)
+}
+
+// mockPreBerlinStore is the trace store with the berlin hardfork disabled
+type mockPreBerlinStore struct {
+	*mockTraceStore
+}
+
+func (m *mockPreBerlinStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	forks := m.mockTraceStore.GetForksInTime(blockNumber)
+	forks.Berlin = false
+
+	return forks
+}
+
+func TestEth_CreateAccessList_PreBerlin(t *testing.T) {
+	store := &mockPreBerlinStore{newMockTraceStore(t)}
+	eth := newTestDebugEndpoint(store).eth
+
+	res, err := eth.CreateAccessList(&txnArgs{
+		From:  &traceSender,
+		To:    &traceContract,
+		Nonce: argUintPtr(0),
+	}, BlockNumberOrHash{})
+	assert.ErrorIs(t, err, ErrBerlinNotEnabled)
+	assert.Nil(t, res)
+}
+
+func TestEth_CallBundle(t *testing.T) {
+	store := newMockTraceStore(t)
+	eth := newTestDebugEndpoint(store).eth
+
+	transfer := func() *txnArgs {
+		return &txnArgs{
+			From:     &traceSender,
+			To:       &traceReceiver,
+			Gas:      argUintPtr(state.TxGas),
+			GasPrice: argBytesPtr([]byte{0x1}),
+			Value:    argBytesPtr([]byte{0x1}),
+		}
+	}
+
+	// LOG0(0, 0) in the init code
+	initCode, err := hex.DecodeHex("60006000a000")
+	assert.NoError(t, err)
+
+	t.Run("should apply the transactions in order", func(t *testing.T) {
+		res, err := eth.CallBundle([]*txnArgs{
+			transfer(),
+			transfer(),
+			{
+				From:  &traceSender,
+				Data:  argBytesPtr(initCode),
+				Nonce: argUintPtr(2),
+			},
+			{
+				From: &traceSender,
+				To:   &traceContract,
+			},
+		}, BlockNumberOrHash{}, nil)
+		assert.NoError(t, err)
+
+		result, ok := res.(*bundleResult)
+		assert.True(t, ok)
+
+		assert.Len(t, result.Results, 4)
+		assert.Equal(t, argUint64(0), result.StateBlockNumber)
+		assert.Equal(t, argUint64(1), result.BlockNumber)
+
+		// the nonces follow the previous transactions
+		for _, r := range result.Results {
+			assert.Empty(t, r.Error)
+		}
+
+		// the fees of the transfers go to the coinbase
+		assert.Equal(t, big.NewInt(int64(2*state.TxGas)), (*big.Int)(&result.CoinbaseDiff))
+
+		logs := result.Results[2].Logs
+		assert.Len(t, logs, 1)
+		assert.Equal(t, crypto.CreateAddress(traceSender, 2), logs[0].Address)
+		assert.Equal(t, argUint64(2), logs[0].TxIndex)
+
+		assert.Equal(t, argBytes(types.StringToHash("0x2a").Bytes()), result.Results[3].ReturnValue)
+
+		var gasUsed uint64
+		for _, r := range result.Results {
+			gasUsed += uint64(r.GasUsed)
+		}
+
+		assert.Equal(t, argUint64(gasUsed), result.GasUsed)
+	})
+
+	t.Run("should override the pending block", func(t *testing.T) {
+		coinbase := types.StringToAddress("0x5000")
+
+		res, err := eth.CallBundle([]*txnArgs{transfer()}, BlockNumberOrHash{}, &blockOverride{
+			Number:    argUintPtr(100),
+			Timestamp: argUintPtr(1000),
+			Coinbase:  &coinbase,
+		})
+		assert.NoError(t, err)
+
+		result, ok := res.(*bundleResult)
+		assert.True(t, ok)
+
+		assert.Equal(t, argUint64(100), result.BlockNumber)
+		assert.Equal(t, big.NewInt(int64(state.TxGas)), (*big.Int)(&result.CoinbaseDiff))
+	})
+
+	t.Run("should fail on invalid transaction", func(t *testing.T) {
+		invalid := transfer()
+		invalid.Nonce = argUintPtr(5)
+
+		_, err := eth.CallBundle([]*txnArgs{transfer(), invalid}, BlockNumberOrHash{}, nil)
+		assert.ErrorIs(t, err, state.ErrNonceIncorrect)
+	})
+
+	t.Run("should fail on empty bundle", func(t *testing.T) {
+		_, err := eth.CallBundle([]*txnArgs{}, BlockNumberOrHash{}, nil)
+		assert.ErrorIs(t, err, ErrEmptyBundle)
+	})
+
+	t.Run("should fail when the block gas is used up", func(t *testing.T) {
+		store := newMockTraceStore(t)
+		store.blocks[0].Header.GasLimit = state.TxGas
+
+		eth := newTestDebugEndpoint(store).eth
+
+		rest := transfer()
+		rest.Gas = nil
+
+		_, err := eth.CallBundle([]*txnArgs{transfer(), rest}, BlockNumberOrHash{}, nil)
+		assert.ErrorIs(t, err, ErrBundleGasLimit)
+	})
+}
+
 type mockSpecialStore struct {
 	ethStore
 	account *mockAccount
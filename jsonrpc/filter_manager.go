@@ -3,6 +3,7 @@ package jsonrpc
 import (
 	"bytes"
 	"container/heap"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,8 @@ import (
 	"time"
 
 	"github.com/dogechain-lab/dogechain/blockchain"
+	"github.com/dogechain-lab/dogechain/helper/progress"
+	"github.com/dogechain-lab/dogechain/txpool/proto"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -26,6 +29,7 @@ var (
 	ErrBlockRangeTooHigh                = errors.New("block range too high")
 	ErrPendingBlockNumber               = errors.New("pending block number is not supported")
 	ErrNoWSConnection                   = errors.New("no websocket connection")
+	ErrSubscriptionLimit                = errors.New("too many subscriptions of the connection")
 )
 
 // defaultTimeout is the timeout to remove the filters that don't have a web socket stream
@@ -36,6 +40,9 @@ const (
 	NoIndexInHeap = -1
 	// _checkDuration is for filter timeout check
 	_checkDuration = time.Second
+	// _syncCheckDuration is the interval of checking the sync status, which is not
+	// delayed by the other events
+	_syncCheckDuration = time.Second
 )
 
 // filter is an interface that BlockFilter and LogFilter implement
@@ -87,9 +94,14 @@ func (f *filterBase) hasWSConn() bool {
 	return f.ws != nil
 }
 
-const ethSubscriptionTemplate = `{
+const (
+	ethSubscriptionMethod   = "eth_subscription"
+	adminSubscriptionMethod = "admin_subscription"
+)
+
+const subscriptionTemplate = `{
 	"jsonrpc": "2.0",
-	"method": "eth_subscription",
+	"method": "%s",
 	"params": {
 		"subscription":"%s",
 		"result": %s
@@ -98,12 +110,18 @@ const ethSubscriptionTemplate = `{
 
 // writeMessageToWs sends given message to websocket stream
 func (f *filterBase) writeMessageToWs(msg string) error {
+	return f.writeNotificationToWs(ethSubscriptionMethod, msg)
+}
+
+// writeNotificationToWs sends given message to websocket stream as the
+// notification of the method
+func (f *filterBase) writeNotificationToWs(method string, msg string) error {
 	if !f.hasWSConn() {
 		return ErrNoWSConnection
 	}
 
 	var v bytes.Buffer
-	if _, err := v.WriteString(fmt.Sprintf(ethSubscriptionTemplate, f.id, msg)); err != nil {
+	if _, err := v.WriteString(fmt.Sprintf(subscriptionTemplate, method, f.id, msg)); err != nil {
 		return err
 	}
 
@@ -221,11 +239,246 @@ func (f *logFilter) sendUpdates() error {
 	return nil
 }
 
+// pendingTxFilter is a filter to store the new pending transactions of the pool
+type pendingTxFilter struct {
+	filterBase
+	sync.Mutex
+	fullTx bool
+	hashes []types.Hash
+	txs    []*types.Transaction
+}
+
+// appendTx appends new pending transaction, the full object is only kept when required
+func (f *pendingTxFilter) appendTx(hash types.Hash, tx *types.Transaction) {
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.fullTx {
+		f.hashes = append(f.hashes, hash)
+
+		return
+	}
+
+	// the transaction is gone before we reach it
+	if tx == nil {
+		return
+	}
+
+	f.txs = append(f.txs, tx)
+}
+
+// takeTxUpdates returns all saved transactions in filter and set new slices
+func (f *pendingTxFilter) takeTxUpdates() ([]types.Hash, []*types.Transaction) {
+	f.Lock()
+	defer f.Unlock()
+
+	hashes, txs := f.hashes, f.txs
+	f.hashes, f.txs = []types.Hash{}, []*types.Transaction{}
+
+	return hashes, txs
+}
+
+// getUpdates returns stored transactions in string
+func (f *pendingTxFilter) getUpdates() (string, error) {
+	hashes, txs := f.takeTxUpdates()
+
+	var (
+		res []byte
+		err error
+	)
+
+	if f.fullTx {
+		updates := make([]*transaction, len(txs))
+		for i, tx := range txs {
+			updates[i] = toPendingTransaction(tx)
+		}
+
+		res, err = json.Marshal(updates)
+	} else {
+		res, err = json.Marshal(hashes)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+// sendUpdates writes stored transactions to web socket stream
+func (f *pendingTxFilter) sendUpdates() error {
+	hashes, txs := f.takeTxUpdates()
+
+	for _, hash := range hashes {
+		if err := f.writeMessageToWs(fmt.Sprintf("\"%s\"", hash.String())); err != nil {
+			return err
+		}
+	}
+
+	for _, tx := range txs {
+		res, err := json.Marshal(toPendingTransaction(tx))
+		if err != nil {
+			return err
+		}
+
+		if err := f.writeMessageToWs(string(res)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncingFilter is a filter to store the changes of the sync status
+type syncingFilter struct {
+	filterBase
+	sync.Mutex
+	status  *progression   // the latest status, nil if not syncing
+	updates []*progression // the status changes, nil if not syncing
+}
+
+// pushStatus appends the status if it is changed, returns whether it is changed
+func (f *syncingFilter) pushStatus(status *progression) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	if status.equal(f.status) {
+		return false
+	}
+
+	f.status = status
+	f.updates = append(f.updates, status)
+
+	return true
+}
+
+// takeStatusUpdates returns all saved status changes in filter and set new slice
+func (f *syncingFilter) takeStatusUpdates() []*progression {
+	f.Lock()
+	defer f.Unlock()
+
+	updates := f.updates
+	f.updates = []*progression{}
+
+	return updates
+}
+
+// marshalSyncStatus marshals the sync status the same as eth_syncing does
+func marshalSyncStatus(status *progression) ([]byte, error) {
+	if status == nil {
+		return json.Marshal(false)
+	}
+
+	return json.Marshal(status)
+}
+
+// getUpdates returns stored status changes in string
+func (f *syncingFilter) getUpdates() (string, error) {
+	updates := f.takeStatusUpdates()
+
+	// alloc once and for all
+	res := make([]string, len(updates))
+
+	for i, status := range updates {
+		raw, err := marshalSyncStatus(status)
+		if err != nil {
+			return "", err
+		}
+
+		res[i] = string(raw)
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(res, ",")), nil
+}
+
+// sendUpdates writes stored status changes to web socket stream
+func (f *syncingFilter) sendUpdates() error {
+	updates := f.takeStatusUpdates()
+
+	for _, status := range updates {
+		raw, err := marshalSyncStatus(status)
+		if err != nil {
+			return err
+		}
+
+		if err := f.writeMessageToWs(string(raw)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// peerEventFilter is a filter to store the peer events
+type peerEventFilter struct {
+	filterBase
+	sync.Mutex
+	updates []*PeerEvent
+}
+
+// appendEvent appends the peer event to the updates
+func (f *peerEventFilter) appendEvent(evnt *PeerEvent) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.updates = append(f.updates, evnt)
+}
+
+// takeEventUpdates returns all saved peer events in filter and set new slice
+func (f *peerEventFilter) takeEventUpdates() []*PeerEvent {
+	f.Lock()
+	defer f.Unlock()
+
+	updates := f.updates
+	f.updates = []*PeerEvent{}
+
+	return updates
+}
+
+// getUpdates returns stored peer events in string
+func (f *peerEventFilter) getUpdates() (string, error) {
+	updates := f.takeEventUpdates()
+
+	raw, err := json.Marshal(updates)
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
+
+// sendUpdates writes stored peer events to web socket stream
+func (f *peerEventFilter) sendUpdates() error {
+	updates := f.takeEventUpdates()
+
+	for _, evnt := range updates {
+		raw, err := json.Marshal(evnt)
+		if err != nil {
+			return err
+		}
+
+		if err := f.writeNotificationToWs(adminSubscriptionMethod, string(raw)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// peerEventStore provides the peer events for peerEventFilters
+type peerEventStore interface {
+	// SubscribePeerEvents subscribes for the peer events
+	SubscribePeerEvents() (<-chan *PeerEvent, func())
+}
+
 // filterManagerStore provides methods required by FilterManager
 type filterManagerStore interface {
 	// Header returns the current header of the chain (genesis if empty)
 	Header() *types.Header
 
+	// FinalizedHeader returns the header of the last block with a verified commit quorum
+	FinalizedHeader() *types.Header
+
 	// SubscribeEvents subscribes for chain head events
 	SubscribeEvents() blockchain.Subscription
 
@@ -237,6 +490,19 @@ type filterManagerStore interface {
 
 	// GetBlockByNumber returns a block using the provided number
 	GetBlockByNumber(num uint64, full bool) (*types.Block, bool)
+
+	// SubscribeTxPoolEvents subscribes for the given types of txpool events
+	SubscribeTxPoolEvents(eventTypes ...proto.EventType) (<-chan *proto.TxPoolEvent, func())
+
+	// GetPendingTx gets the pending transaction from the transaction pool, if it's present
+	GetPendingTx(txHash types.Hash) (*types.Transaction, bool)
+
+	// GetSyncProgression retrieves the current sync progression, if any
+	GetSyncProgression() *progress.Progression
+
+	// FilterBlocksByBloom returns the blocks in the range which might match the filters
+	// by the bloom bits index, and the first block not covered by the index
+	FilterBlocksByBloom(from, to uint64, filters [][][]byte) ([]uint64, uint64, error)
 }
 
 // FilterManager manages all running filters
@@ -248,12 +514,17 @@ type FilterManager struct {
 	timeout time.Duration
 
 	store           filterManagerStore
+	peerEventStore  peerEventStore // nil if the peer events are disabled
 	blockStream     *blockStream
 	blockRangeLimit uint64
 
 	filters  map[string]filter
 	timeouts timeHeapImpl
 
+	// the filter IDs of each websocket connection, and the max number of them
+	wsFilters     map[wsConn]map[string]struct{}
+	wsFilterLimit uint64
+
 	updateCh chan struct{}
 	closeCh  chan struct{}
 }
@@ -267,6 +538,7 @@ func NewFilterManager(logger hclog.Logger, store filterManagerStore, blockRangeL
 		blockRangeLimit: blockRangeLimit,
 		filters:         make(map[string]filter),
 		timeouts:        timeHeapImpl{},
+		wsFilters:       make(map[wsConn]map[string]struct{}),
 		updateCh:        make(chan struct{}),
 		closeCh:         make(chan struct{}),
 	}
@@ -278,6 +550,21 @@ func NewFilterManager(logger hclog.Logger, store filterManagerStore, blockRangeL
 	return m
 }
 
+// EnablePeerEvents makes the filter manager subscribe for the peer events of
+// the store, it must be called before Run
+func (f *FilterManager) EnablePeerEvents(store peerEventStore) {
+	f.peerEventStore = store
+}
+
+// SetWsFilterLimit limits the number of the filters of a websocket connection,
+// it is unlimited if zero
+func (f *FilterManager) SetWsFilterLimit(limit uint64) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.wsFilterLimit = limit
+}
+
 // Run starts worker process to handle events
 func (f *FilterManager) Run() {
 	// subscribe for new blockchain events
@@ -302,6 +589,20 @@ func (f *FilterManager) Run() {
 		}
 	}()
 
+	// subscribe for new pending transactions of the txpool
+	txCh, cancelTxSub := f.store.SubscribeTxPoolEvents(proto.EventType_PROMOTED)
+	defer cancelTxSub()
+
+	// subscribe for the peer events if enabled
+	var peerCh <-chan *PeerEvent
+
+	if f.peerEventStore != nil {
+		var cancelPeerSub func()
+
+		peerCh, cancelPeerSub = f.peerEventStore.SubscribePeerEvents()
+		defer cancelPeerSub()
+	}
+
 	// Do not use 'for range + create long time after chan' any more,
 	// which would bring out some unpredictable result, especially when
 	// re-assgining the chan, the elder one would not be recycled by
@@ -310,6 +611,11 @@ func (f *FilterManager) Run() {
 	var checkTimer = time.NewTimer(_checkDuration)
 	defer checkTimer.Stop()
 
+	// the timer above is reset by every event, so that the sync status is checked
+	// by its own ticker, otherwise it is never checked while importing the blocks
+	syncTicker := time.NewTicker(_syncCheckDuration)
+	defer syncTicker.Stop()
+
 	for {
 		// check for the next filter to be removed
 		filterBase := f.nextTimeoutFilter()
@@ -331,8 +637,36 @@ func (f *FilterManager) Run() {
 			if err := f.dispatchEvent(ev); err != nil {
 				f.logger.Error("failed to dispatch event", "err", err)
 			}
+		case ev, ok := <-txCh:
+			if !ok {
+				// the txpool is closed, no more events
+				txCh = nil
+
+				continue
+			}
+
+			// new pending transaction
+			if err := f.dispatchTxEvent(ev); err != nil {
+				f.logger.Error("failed to dispatch txpool event", "err", err)
+			}
+		case ev, ok := <-peerCh:
+			if !ok {
+				// the network is closed, no more events
+				peerCh = nil
+
+				continue
+			}
+
+			// new peer event
+			if err := f.dispatchPeerEvent(ev); err != nil {
+				f.logger.Error("failed to dispatch peer event", "err", err)
+			}
 		case <-checkTimer.C:
-			// no need to do anything, checkout the timeout filter in the next loop
+			// checkout the timeout filter in the next loop
+		case <-syncTicker.C:
+			if err := f.dispatchSyncStatus(); err != nil {
+				f.logger.Error("failed to dispatch sync status", "err", err)
+			}
 		case <-f.updateCh:
 			// filters change, reset the loop to start the timeout timer
 		case <-f.closeCh:
@@ -348,7 +682,7 @@ func (f *FilterManager) Close() {
 }
 
 // NewBlockFilter adds new BlockFilter
-func (f *FilterManager) NewBlockFilter(ws wsConn) string {
+func (f *FilterManager) NewBlockFilter(ws wsConn) (string, error) {
 	filter := &blockFilter{
 		filterBase: newFilterBase(ws),
 		block:      f.blockStream.Head(),
@@ -362,7 +696,7 @@ func (f *FilterManager) NewBlockFilter(ws wsConn) string {
 }
 
 // NewLogFilter adds new LogFilter
-func (f *FilterManager) NewLogFilter(logQuery *LogQuery, ws wsConn) string {
+func (f *FilterManager) NewLogFilter(logQuery *LogQuery, ws wsConn) (string, error) {
 	filter := &logFilter{
 		filterBase: newFilterBase(ws),
 		query:      logQuery,
@@ -371,6 +705,50 @@ func (f *FilterManager) NewLogFilter(logQuery *LogQuery, ws wsConn) string {
 	return f.addFilter(filter)
 }
 
+// NewPendingTxFilter adds new pendingTxFilter, which stores the full transactions
+// instead of the hashes if fullTx is set
+func (f *FilterManager) NewPendingTxFilter(fullTx bool, ws wsConn) (string, error) {
+	filter := &pendingTxFilter{
+		filterBase: newFilterBase(ws),
+		fullTx:     fullTx,
+	}
+
+	if filter.hasWSConn() {
+		ws.SetFilterID(filter.id)
+	}
+
+	return f.addFilter(filter)
+}
+
+// NewSyncingFilter adds new syncingFilter, which stores the changes of the sync
+// status from now on
+func (f *FilterManager) NewSyncingFilter(ws wsConn) (string, error) {
+	filter := &syncingFilter{
+		filterBase: newFilterBase(ws),
+		status:     toProgression(f.store.GetSyncProgression()),
+	}
+
+	if filter.hasWSConn() {
+		ws.SetFilterID(filter.id)
+	}
+
+	return f.addFilter(filter)
+}
+
+// NewPeerEventFilter adds new peerEventFilter, which stores the peer events
+// from now on
+func (f *FilterManager) NewPeerEventFilter(ws wsConn) (string, error) {
+	filter := &peerEventFilter{
+		filterBase: newFilterBase(ws),
+	}
+
+	if filter.hasWSConn() {
+		ws.SetFilterID(filter.id)
+	}
+
+	return f.addFilter(filter)
+}
+
 // Exists checks the filter with given ID exists
 func (f *FilterManager) Exists(id string) bool {
 	f.RLock()
@@ -381,7 +759,8 @@ func (f *FilterManager) Exists(id string) bool {
 	return ok
 }
 
-func (f *FilterManager) getLogsFromBlock(query *LogQuery, block *types.Block) ([]*Log, error) {
+// GetLogsFromBlock returns the logs of the block matching the query
+func (f *FilterManager) GetLogsFromBlock(query *LogQuery, block *types.Block) ([]*Log, error) {
 	receipts, err := f.store.GetReceiptsByHash(block.Header.Hash)
 	if err != nil {
 		return nil, err
@@ -411,7 +790,8 @@ func (f *FilterManager) getLogsFromBlock(query *LogQuery, block *types.Block) ([
 	return logs, nil
 }
 
-func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
+// resolveBlockRange returns the block range of the query, the genesis is skipped
+func (f *FilterManager) resolveBlockRange(query *LogQuery) (uint64, uint64, error) {
 	latestBlockNumber := f.store.Header().Number
 
 	resolveNum := func(num BlockNumber) (uint64, error) {
@@ -422,6 +802,8 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 			num = 0
 		case LatestBlockNumber:
 			return latestBlockNumber, nil
+		case FinalizedBlockNumber, SafeBlockNumber:
+			return f.store.FinalizedHeader().Number, nil
 		}
 
 		return uint64(num), nil
@@ -429,12 +811,12 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 
 	from, err := resolveNum(query.FromBlock)
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
 
 	to, err := resolveNum(query.ToBlock)
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
 
 	// If from equals genesis block
@@ -444,7 +826,35 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 	}
 
 	if to < from {
-		return nil, ErrIncorrectBlockRange
+		return 0, 0, ErrIncorrectBlockRange
+	}
+
+	return from, to, nil
+}
+
+// checkBlockRange checks the block range of the query against the limit, which
+// is disabled if zero. The query of a single block hash is always allowed.
+func (f *FilterManager) checkBlockRange(query *LogQuery, limit uint64) error {
+	if query.BlockHash != nil || limit == 0 {
+		return nil
+	}
+
+	from, to, err := f.resolveBlockRange(query)
+	if err != nil {
+		return err
+	}
+
+	if to-from > limit {
+		return ErrBlockRangeTooHigh
+	}
+
+	return nil
+}
+
+func (f *FilterManager) getLogsFromBlocks(ctx context.Context, query *LogQuery) ([]*Log, error) {
+	from, to, err := f.resolveBlockRange(query)
+	if err != nil {
+		return nil, err
 	}
 
 	// if not disabled, avoid handling large block ranges
@@ -452,10 +862,25 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 		return nil, ErrBlockRangeTooHigh
 	}
 
+	// narrow the candidate blocks by the bloom bits index, the blocks not indexed
+	// yet are all candidates
+	candidates, next, err := f.store.FilterBlocksByBloom(from, to, query.bloomFilters())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := next; i <= to; i++ {
+		candidates = append(candidates, i)
+	}
+
 	logs := make([]*Log, 0)
 
-	for i := from; i <= to; i++ {
-		block, ok := f.store.GetBlockByNumber(i, true)
+	for _, num := range candidates {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		block, ok := f.store.GetBlockByNumber(num, true)
 		if !ok {
 			break
 		}
@@ -465,7 +890,7 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 			continue
 		}
 
-		blockLogs, err := f.getLogsFromBlock(query, block)
+		blockLogs, err := f.GetLogsFromBlock(query, block)
 		if err != nil {
 			return nil, err
 		}
@@ -478,6 +903,12 @@ func (f *FilterManager) getLogsFromBlocks(query *LogQuery) ([]*Log, error) {
 
 // GetLogs return array of logs for given query
 func (f *FilterManager) GetLogs(query *LogQuery) ([]*Log, error) {
+	return f.GetLogsContext(context.Background(), query)
+}
+
+// GetLogsContext return array of logs for given query, the range of blocks is
+// aborted once the context is done
+func (f *FilterManager) GetLogsContext(ctx context.Context, query *LogQuery) ([]*Log, error) {
 	if query.BlockHash != nil {
 		//	BlockHash is set -> fetch logs from this block only
 		block, ok := f.store.GetBlockByHash(*query.BlockHash, true)
@@ -490,11 +921,11 @@ func (f *FilterManager) GetLogs(query *LogQuery) ([]*Log, error) {
 			return []*Log{}, nil
 		}
 
-		return f.getLogsFromBlock(query, block)
+		return f.GetLogsFromBlock(query, block)
 	}
 
 	//	gets logs from a range of blocks
-	return f.getLogsFromBlocks(query)
+	return f.getLogsFromBlocks(ctx, query)
 }
 
 // getFilterByID fetches the filter by the ID
@@ -592,6 +1023,14 @@ func (f *FilterManager) removeFilterByID(id string) bool {
 
 	delete(f.filters, id)
 
+	if base := filter.getFilterBase(); base.hasWSConn() {
+		delete(f.wsFilters[base.ws], id)
+
+		if len(f.wsFilters[base.ws]) == 0 {
+			delete(f.wsFilters, base.ws)
+		}
+	}
+
 	if removed := f.timeouts.removeFilter(filter.getFilterBase()); removed {
 		f.logger.Debug("filter found in timeout heap", "id", id)
 		f.emitSignalToUpdateCh()
@@ -602,21 +1041,38 @@ func (f *FilterManager) removeFilterByID(id string) bool {
 	return true
 }
 
-// RemoveFilterByWs removes the filter with given WS [Thread safe]
+// RemoveFilterByWs removes all the filters of the given WS [Thread safe]
 func (f *FilterManager) RemoveFilterByWs(ws wsConn) {
 	f.Lock()
 	defer f.Unlock()
 
-	f.removeFilterByID(ws.GetFilterID())
+	for id := range f.wsFilters[ws] {
+		f.removeFilterByID(id)
+	}
 }
 
-// addFilter is an internal method to add given filter to list and heap
-func (f *FilterManager) addFilter(filter filter) string {
+// addFilter is an internal method to add given filter to list and heap, the
+// filter of the websocket connection reaching the limit is rejected
+func (f *FilterManager) addFilter(filter filter) (string, error) {
 	f.Lock()
 	defer f.Unlock()
 
 	base := filter.getFilterBase()
 
+	if filter.hasWSConn() {
+		ids := f.wsFilters[base.ws]
+		if f.wsFilterLimit > 0 && uint64(len(ids)) >= f.wsFilterLimit {
+			return "", ErrSubscriptionLimit
+		}
+
+		if ids == nil {
+			ids = make(map[string]struct{})
+			f.wsFilters[base.ws] = ids
+		}
+
+		ids[base.id] = struct{}{}
+	}
+
 	f.filters[base.id] = filter
 
 	// Set timeout and add to heap if filter doesn't have web socket connection
@@ -626,7 +1082,7 @@ func (f *FilterManager) addFilter(filter filter) string {
 
 	f.logger.Debug("filter added", "id", base.id, "timeout", base.expiresAt)
 
-	return base.id
+	return base.id, nil
 }
 
 func (f *FilterManager) emitSignalToUpdateCh() {
@@ -734,6 +1190,89 @@ func (f *FilterManager) appendLogsToFilters(header *types.Header) error {
 	return nil
 }
 
+// dispatchTxEvent is an event handler for new pending transaction event
+func (f *FilterManager) dispatchTxEvent(evnt *proto.TxPoolEvent) error {
+	// store new transaction in each filters
+	if !f.processTxEvent(evnt) {
+		return nil
+	}
+
+	// send data to web socket stream
+	return f.flushWsFilters()
+}
+
+// processTxEvent makes each pendingTxFilter append the new transaction, returns
+// whether any filter is interested
+func (f *FilterManager) processTxEvent(evnt *proto.TxPoolEvent) bool {
+	txFilters := f.getPendingTxFilters()
+	if len(txFilters) == 0 {
+		return false
+	}
+
+	var (
+		hash    = types.StringToHash(evnt.TxHash)
+		tx      *types.Transaction
+		fetched bool
+	)
+
+	for _, filter := range txFilters {
+		// only fetch the transaction once, and only when it is required
+		if filter.fullTx && !fetched {
+			tx, _ = f.store.GetPendingTx(hash)
+			fetched = true
+		}
+
+		filter.appendTx(hash, tx)
+	}
+
+	return true
+}
+
+// dispatchSyncStatus pushes the sync status to syncingFilters if it is changed
+func (f *FilterManager) dispatchSyncStatus() error {
+	if !f.processSyncStatus() {
+		return nil
+	}
+
+	// send data to web socket stream
+	return f.flushWsFilters()
+}
+
+// processSyncStatus makes each syncingFilter append the current sync status,
+// returns whether any filter gets a change
+func (f *FilterManager) processSyncStatus() bool {
+	syncingFilters := f.getSyncingFilters()
+	if len(syncingFilters) == 0 {
+		return false
+	}
+
+	status := toProgression(f.store.GetSyncProgression())
+	changed := false
+
+	for _, filter := range syncingFilters {
+		if filter.pushStatus(status) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// dispatchPeerEvent is an event handler for new peer event
+func (f *FilterManager) dispatchPeerEvent(evnt *PeerEvent) error {
+	peerFilters := f.getPeerEventFilters()
+	if len(peerFilters) == 0 {
+		return nil
+	}
+
+	for _, filter := range peerFilters {
+		filter.appendEvent(evnt)
+	}
+
+	// send data to web socket stream
+	return f.flushWsFilters()
+}
+
 // flushWsFilters make each filters with web socket connection write the updates to web socket stream
 // flushWsFilters also removes the filters if flushWsFilters notices the connection is closed
 func (f *FilterManager) flushWsFilters() error {
@@ -792,6 +1331,54 @@ func (f *FilterManager) getLogFilters() []*logFilter {
 	return logFilters
 }
 
+// getPendingTxFilters returns pendingTxFilters
+func (f *FilterManager) getPendingTxFilters() []*pendingTxFilter {
+	f.RLock()
+	defer f.RUnlock()
+
+	txFilters := make([]*pendingTxFilter, 0)
+
+	for _, f := range f.filters {
+		if txFilter, ok := f.(*pendingTxFilter); ok {
+			txFilters = append(txFilters, txFilter)
+		}
+	}
+
+	return txFilters
+}
+
+// getSyncingFilters returns syncingFilters
+func (f *FilterManager) getSyncingFilters() []*syncingFilter {
+	f.RLock()
+	defer f.RUnlock()
+
+	syncingFilters := make([]*syncingFilter, 0)
+
+	for _, f := range f.filters {
+		if syncingFilter, ok := f.(*syncingFilter); ok {
+			syncingFilters = append(syncingFilters, syncingFilter)
+		}
+	}
+
+	return syncingFilters
+}
+
+// getPeerEventFilters returns peerEventFilters
+func (f *FilterManager) getPeerEventFilters() []*peerEventFilter {
+	f.RLock()
+	defer f.RUnlock()
+
+	peerFilters := make([]*peerEventFilter, 0)
+
+	for _, f := range f.filters {
+		if peerFilter, ok := f.(*peerEventFilter); ok {
+			peerFilters = append(peerFilters, peerFilter)
+		}
+	}
+
+	return peerFilters
+}
+
 type timeHeapImpl []*filterBase
 
 func (t *timeHeapImpl) addFilter(filter *filterBase) {
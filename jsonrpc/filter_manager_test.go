@@ -1,16 +1,19 @@
 package jsonrpc
 
 import (
+	"encoding/json"
 	"math/big"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/dogechain-lab/dogechain/blockchain"
+	"github.com/dogechain-lab/dogechain/helper/progress"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
 )
 
 func TestFilterLog(t *testing.T) {
@@ -26,11 +29,12 @@ func TestFilterLog(t *testing.T) {
 
 	go m.Run()
 
-	id := m.NewLogFilter(&LogQuery{
+	id, err := m.NewLogFilter(&LogQuery{
 		Topics: [][]types.Hash{
 			{hash1},
 		},
 	}, nil)
+	assert.NoError(t, err)
 
 	store.emitEvent(&mockEvent{
 		NewChain: []*mockHeader{
@@ -94,7 +98,8 @@ func TestFilterBlock(t *testing.T) {
 	go m.Run()
 
 	// add block filter
-	id := m.NewBlockFilter(nil)
+	id, err := m.NewBlockFilter(nil)
+	assert.NoError(t, err)
 
 	// emit two events
 	store.emitEvent(&mockEvent{
@@ -148,6 +153,183 @@ func TestFilterBlock(t *testing.T) {
 	}
 }
 
+func TestFilterPendingTx(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	// filter manager should Close(), but mock one might crash on writing on a closed channel
+	//nolint:errcheck
+	defer recover()
+	defer m.Close()
+
+	go m.Run()
+
+	hashID, err := m.NewPendingTxFilter(false, nil)
+	assert.NoError(t, err)
+
+	fullID, err := m.NewPendingTxFilter(true, nil)
+	assert.NoError(t, err)
+
+	tx1 := newTestTransaction(1, addr0)
+	tx2 := newTestTransaction(2, addr0)
+
+	store.emitPendingTx(tx1)
+	store.emitPendingTx(tx2)
+
+	// we need to wait for the manager to process the data
+	time.Sleep(500 * time.Millisecond)
+
+	res, err := m.GetFilterChanges(hashID)
+	assert.NoError(t, err)
+
+	var hashes []types.Hash
+	assert.NoError(t, json.Unmarshal([]byte(res), &hashes))
+	assert.Equal(t, []types.Hash{tx1.Hash(), tx2.Hash()}, hashes)
+
+	res, err = m.GetFilterChanges(fullID)
+	assert.NoError(t, err)
+
+	var txs []*transaction
+	assert.NoError(t, json.Unmarshal([]byte(res), &txs))
+	assert.Len(t, txs, 2)
+	assert.Equal(t, tx1.Hash(), txs[0].Hash)
+	assert.Equal(t, tx2.Hash(), txs[1].Hash)
+
+	// the updates are taken already
+	res, err = m.GetFilterChanges(hashID)
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", res)
+}
+
+func TestFilterSyncing(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	mock := &mockWsConn{
+		msgCh: make(chan []byte, 1),
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	// filter manager should Close(), but mock one might crash on writing on a closed channel
+	//nolint:errcheck
+	defer recover()
+	defer m.Close()
+
+	go m.Run()
+
+	id, err := m.NewSyncingFilter(mock)
+	assert.NoError(t, err)
+	assert.Equal(t, id, mock.GetFilterID())
+
+	readStatus := func() json.RawMessage {
+		t.Helper()
+
+		select {
+		case msg := <-mock.msgCh:
+			var notification struct {
+				Params struct {
+					Subscription string          `json:"subscription"`
+					Result       json.RawMessage `json:"result"`
+				} `json:"params"`
+			}
+
+			assert.NoError(t, json.Unmarshal(msg, &notification))
+			assert.Equal(t, id, notification.Params.Subscription)
+
+			return notification.Params.Result
+		case <-time.After(3 * time.Second):
+			t.Fatal("sync status not received in 3 seconds")
+		}
+
+		return nil
+	}
+
+	// start syncing
+	store.setProgression(&progress.Progression{
+		SyncType:      progress.ChainSyncBulk,
+		StartingBlock: 1,
+		CurrentBlock:  10,
+		HighestBlock:  atomic.NewUint64(100),
+	})
+
+	var status progression
+	assert.NoError(t, json.Unmarshal(readStatus(), &status))
+	assert.Equal(t, "0xa", status.CurrentBlock)
+	assert.Equal(t, "0x64", status.HighestBlock)
+
+	// stop syncing
+	store.setProgression(nil)
+
+	assert.Equal(t, "false", string(readStatus()))
+
+	// nothing pushed if the status is not changed
+	select {
+	case <-mock.msgCh:
+		t.Fatal("unexpected sync status")
+	case <-time.After(2 * _syncCheckDuration):
+	}
+}
+
+func TestFilterSyncing_ChainEvents(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	mock := &mockWsConn{
+		msgCh: make(chan []byte, 1),
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	// filter manager should Close(), but mock one might crash on writing on a closed channel
+	//nolint:errcheck
+	defer recover()
+	defer m.Close()
+
+	go m.Run()
+
+	_, err := m.NewSyncingFilter(mock)
+	assert.NoError(t, err)
+
+	store.setProgression(&progress.Progression{
+		SyncType:      progress.ChainSyncBulk,
+		StartingBlock: 1,
+		CurrentBlock:  10,
+		HighestBlock:  atomic.NewUint64(100),
+	})
+
+	// the blocks are imported faster than the sync check
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(_syncCheckDuration / 10)
+		defer ticker.Stop()
+
+		for i := 1; ; i++ {
+			select {
+			case <-ticker.C:
+				store.emitEvent(&mockEvent{
+					NewChain: []*mockHeader{
+						{header: &types.Header{Number: uint64(i), Hash: types.Hash{byte(i)}}},
+					},
+				})
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	select {
+	case msg := <-mock.msgCh:
+		assert.Contains(t, string(msg), "0x64")
+	case <-time.After(3 * _syncCheckDuration):
+		t.Fatal("sync status not received while importing the blocks")
+	}
+}
+
 func Test_GetLogsForQuery(t *testing.T) {
 	t.Parallel()
 
@@ -283,6 +465,62 @@ func Test_GetLogsForQuery(t *testing.T) {
 	}
 }
 
+func Test_GetLogsForQuery_BloomIndex(t *testing.T) {
+	t.Parallel()
+
+	topics := []types.Hash{types.StringToHash("4"), types.StringToHash("5")}
+
+	store := &mockBlockStore{
+		topics:          topics,
+		bloomIndexed:    3,
+		bloomCandidates: []uint64{2},
+	}
+	store.setupLogs()
+
+	for i := 0; i < 5; i++ {
+		store.appendBlocksToStore([]*types.Block{
+			{
+				Header: &types.Header{
+					Number: uint64(i),
+					Hash:   types.StringToHash(strconv.Itoa(i)),
+				},
+				Transactions: []*types.Transaction{
+					{
+						Value: big.NewInt(10),
+					},
+					{
+						Value: big.NewInt(11),
+					},
+					{
+						Value: big.NewInt(12),
+					},
+				},
+			},
+		})
+	}
+
+	f := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+
+	t.Cleanup(func() {
+		f.Close() // prevent memory leak
+	})
+
+	// block 1 is skipped by the index, block 3 is not indexed yet
+	logs, err := f.GetLogs(&LogQuery{
+		FromBlock: 1,
+		ToBlock:   3,
+		Topics:    [][]types.Hash{{topics[0]}, {topics[1]}},
+	})
+	assert.NoError(t, err)
+
+	blockNumbers := make([]uint64, 0, len(logs))
+	for _, log := range logs {
+		blockNumbers = append(blockNumbers, uint64(log.BlockNumber))
+	}
+
+	assert.Equal(t, []uint64{2, 3}, blockNumbers)
+}
+
 func Test_GetLogFilterFromID(t *testing.T) {
 	t.Parallel() // speed it up
 
@@ -302,9 +540,10 @@ func Test_GetLogFilterFromID(t *testing.T) {
 		FromBlock: 0,
 	}
 
-	retrivedLogFilter, err := m.GetLogFilterFromID(
-		m.NewLogFilter(logFilter, &MockClosedWSConnection{}),
-	)
+	id, err := m.NewLogFilter(logFilter, &MockClosedWSConnection{})
+	assert.NoError(t, err)
+
+	retrivedLogFilter, err := m.GetLogFilterFromID(id)
 
 	assert.NoError(t, err)
 	assert.Equal(t, logFilter, retrivedLogFilter.query)
@@ -326,7 +565,8 @@ func TestFilterTimeout(t *testing.T) {
 	go m.Run()
 
 	// add block filter
-	id := m.NewBlockFilter(nil)
+	id, err := m.NewBlockFilter(nil)
+	assert.NoError(t, err)
 
 	assert.True(t, m.Exists(id))
 	time.Sleep(3 * time.Second)
@@ -350,7 +590,8 @@ func TestRemoveFilterByWebsocket(t *testing.T) {
 
 	go m.Run()
 
-	id := m.NewBlockFilter(mock)
+	id, err := m.NewBlockFilter(mock)
+	assert.NoError(t, err)
 
 	m.RemoveFilterByWs(mock)
 
@@ -358,6 +599,43 @@ func TestRemoveFilterByWebsocket(t *testing.T) {
 	assert.False(t, m.Exists(id))
 }
 
+func TestWebsocketFilterLimit(t *testing.T) {
+	t.Parallel()
+
+	store := newMockStore()
+
+	mock := &mockWsConn{
+		msgCh: make(chan []byte, 1),
+	}
+
+	m := NewFilterManager(hclog.NewNullLogger(), store, 1000)
+	m.SetWsFilterLimit(2)
+
+	defer m.Close()
+
+	first, err := m.NewBlockFilter(mock)
+	assert.NoError(t, err)
+
+	second, err := m.NewLogFilter(&LogQuery{}, mock)
+	assert.NoError(t, err)
+
+	_, err = m.NewPendingTxFilter(false, mock)
+	assert.ErrorIs(t, err, ErrSubscriptionLimit)
+
+	// the http filters are not limited
+	_, err = m.NewBlockFilter(nil)
+	assert.NoError(t, err)
+
+	// all the filters of the connection are removed once closed
+	m.RemoveFilterByWs(mock)
+
+	assert.False(t, m.Exists(first))
+	assert.False(t, m.Exists(second))
+
+	_, err = m.NewBlockFilter(mock)
+	assert.NoError(t, err)
+}
+
 func TestFilterWebsocket(t *testing.T) {
 	t.Parallel()
 
@@ -375,10 +653,11 @@ func TestFilterWebsocket(t *testing.T) {
 
 	go m.Run()
 
-	id := m.NewBlockFilter(mock)
+	id, err := m.NewBlockFilter(mock)
+	assert.NoError(t, err)
 
 	// we cannot call get filter changes for a websocket filter
-	_, err := m.GetFilterChanges(id)
+	_, err = m.GetFilterChanges(id)
 	assert.Equal(t, err, ErrWSFilterDoesNotSupportGetChanges)
 }
 
@@ -451,12 +730,13 @@ func TestClosedFilterDeletion(t *testing.T) {
 	go m.Run()
 
 	// add block filter
-	id := m.NewBlockFilter(&MockClosedWSConnection{})
+	id, err := m.NewBlockFilter(&MockClosedWSConnection{})
+	assert.NoError(t, err)
 
 	assert.True(t, m.Exists(id))
 
 	// event is sent to the filter but writing to connection should fail
-	err := m.dispatchEvent(&blockchain.Event{
+	err = m.dispatchEvent(&blockchain.Event{
 		NewChain: []*types.Header{
 			{
 				Hash: types.StringToHash("1"),
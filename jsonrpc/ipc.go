@@ -0,0 +1,183 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/dogechain-lab/dogechain/helper/ipc"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// maxIPCMessageSize is the max size of a single ipc message, the same as the
+	// request content limit of the other clients
+	maxIPCMessageSize = 5 * 1024 * 1024
+)
+
+// ipcConn is a wrapping object for the ipc connection, it implements the wsConn
+// interface so that the subscriptions are served the same way as the websocket.
+// Messages are framed by newlines in both directions.
+type ipcConn struct {
+	sync.Mutex // basic w lock
+
+	conn     net.Conn     // the actual ipc connection
+	logger   hclog.Logger // module logger
+	filterID string       // filter ID
+}
+
+func (c *ipcConn) SetFilterID(filterID string) {
+	c.filterID = filterID
+}
+
+func (c *ipcConn) GetFilterID() string {
+	return c.filterID
+}
+
+// WriteMessage writes out the message to the ipc peer, the message type is ignored.
+// The json message is compacted to a single line to keep the framing.
+func (c *ipcConn) WriteMessage(_ int, data []byte) error {
+	c.Lock()
+	defer c.Unlock()
+
+	var msg bytes.Buffer
+	if err := json.Compact(&msg, data); err != nil {
+		msg.Reset()
+		msg.Write(data)
+	}
+
+	msg.WriteByte('\n')
+
+	if _, err := c.conn.Write(msg.Bytes()); err != nil {
+		c.logger.Error(fmt.Sprintf("Unable to write IPC message, %s", err.Error()))
+
+		return err
+	}
+
+	return nil
+}
+
+func (j *JSONRPC) setupIPC() error {
+	lis, err := ipc.Listen(j.config.IPCPath)
+	if err != nil {
+		return err
+	}
+
+	j.ipcListener = lis
+
+	j.logger.Info("ipc server started", "path", j.config.IPCPath)
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					j.logger.Error("closed ipc listener", "err", err)
+				}
+
+				return
+			}
+
+			if !j.addIPCConn(conn) {
+				_ = conn.Close()
+
+				return
+			}
+
+			go j.handleIPC(conn)
+		}
+	}()
+
+	return nil
+}
+
+func (j *JSONRPC) handleIPC(conn net.Conn) {
+	// Defer IPC closure
+	defer func() {
+		j.removeIPCConn(conn)
+
+		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			j.logger.Error(fmt.Sprintf("Unable to gracefully close IPC connection, %s", err.Error()))
+		}
+	}()
+
+	wrapConn := &ipcConn{conn: conn, logger: j.logger}
+
+	j.logger.Debug("IPC connection established")
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxIPCMessageSize)
+
+	// Run the listen loop
+	for scanner.Scan() {
+		// The buffer is reused by the scanner
+		message := append([]byte{}, scanner.Bytes()...)
+		if len(message) == 0 {
+			continue
+		}
+
+		go func() {
+			resp, handleErr := j.dispatcher.HandleWs(message, wrapConn)
+			if handleErr != nil {
+				j.logger.Error(fmt.Sprintf("Unable to handle IPC request, %s", handleErr.Error()))
+
+				_ = wrapConn.WriteMessage(0, handleErrorResponse(handleErr))
+			} else {
+				_ = wrapConn.WriteMessage(0, resp)
+			}
+		}()
+	}
+
+	if err := scanner.Err(); err != nil {
+		j.logger.Error(fmt.Sprintf("Unable to read IPC message, %s", err.Error()))
+	}
+
+	j.logger.Debug("Closing IPC connection")
+
+	// remove subscriptions of the connection when closed
+	j.dispatcher.RemoveFilterByWs(wrapConn)
+}
+
+// addIPCConn tracks the ipc connection, so that it is closed with the server.
+// It returns false if the server is closed.
+func (j *JSONRPC) addIPCConn(conn net.Conn) bool {
+	j.ipcLock.Lock()
+	defer j.ipcLock.Unlock()
+
+	if j.ipcClosed {
+		return false
+	}
+
+	if j.ipcConns == nil {
+		j.ipcConns = make(map[net.Conn]struct{})
+	}
+
+	j.ipcConns[conn] = struct{}{}
+
+	return true
+}
+
+func (j *JSONRPC) removeIPCConn(conn net.Conn) {
+	j.ipcLock.Lock()
+	defer j.ipcLock.Unlock()
+
+	delete(j.ipcConns, conn)
+}
+
+// closeIPCConns closes the open ipc connections, and refuses the new ones
+func (j *JSONRPC) closeIPCConns() {
+	j.ipcLock.Lock()
+	defer j.ipcLock.Unlock()
+
+	j.ipcClosed = true
+
+	for conn := range j.ipcConns {
+		_ = conn.Close()
+	}
+
+	j.ipcConns = nil
+}
@@ -2,15 +2,14 @@ package jsonrpc
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/dogechain-lab/dogechain/helper/ipc"
 	"github.com/dogechain-lab/dogechain/versioning"
-	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-hclog"
 )
 
@@ -45,12 +44,22 @@ type JSONRPC struct {
 	config     *Config
 	dispatcher dispatcher
 	metrics    *Metrics
+	auth       *authenticator
+	wsLimiter  *wsConnLimiter
+
+	ipcListener net.Listener
+	ipcLock     sync.Mutex
+	ipcConns    map[net.Conn]struct{}
+	ipcClosed   bool
 }
 
 type dispatcher interface {
 	RemoveFilterByWs(conn wsConn)
 	HandleWs(reqBody []byte, conn wsConn) ([]byte, error)
 	Handle(reqBody []byte) ([]byte, error)
+	handleWs(c *caller, reqBody []byte, conn wsConn) ([]byte, error)
+	handle(c *caller, reqBody []byte) ([]byte, error)
+	closeRecorder() error
 }
 
 // JSONRPCStore defines all the methods required
@@ -60,6 +69,9 @@ type JSONRPCStore interface {
 	networkStore
 	txPoolStore
 	filterManagerStore
+	traceStore
+	otsStore
+	adminStore
 }
 
 type Config struct {
@@ -71,28 +83,63 @@ type Config struct {
 	BlockRangeLimit          uint64
 	JSONNamespaces           []Namespace
 	EnableWS                 bool
+	IPCPath                  string // the ipc endpoint path, ipc is disabled if empty
 	PriceLimit               uint64
 	EnablePProf              bool // whether pprof enable or not
 	EnableJaeger             bool // whether jaeger enable or not
 	Metrics                  *Metrics
+	APIKeys                  []*APIKey       // the api keys of the clients, not required if empty
+	JWTSecret                []byte          // the jwt secret for the privileged namespaces, checked once per websocket
+	AllowInsecureUnlock      bool            // whether to unlock and sign over http and websocket without jwt
+	CacheEntries             int             // the max number of the cached responses, the cache is disabled if zero
+	CacheBytes               int             // the max total bytes of the cached responses
+	Recorder                 *RecorderConfig // the recorder of the calls, disabled if nil
+
+	WSConnLimitPerIP     uint64               // the max websocket connections of an ip, unlimited if zero
+	WSTrustedProxies     []string             // the proxies whose X-Forwarded-For is trusted by the ip limit
+	WSSubscriptionLimit  uint64               // the max subscriptions of a websocket connection, unlimited if zero
+	WSSendQueueSize      uint64               // the max outbound messages queued for a websocket connection
+	WSSlowConsumerPolicy WSSlowConsumerPolicy // the action taken once the outbound queue is full
+	WSPingInterval       time.Duration        // the interval of the keepalive pings, disabled if zero
+	WSIdleTimeout        time.Duration        // the connection is closed once nothing read in time, disabled if zero
 }
 
 // NewJSONRPC returns the JSONRPC http server
 func NewJSONRPC(logger hclog.Logger, config *Config) (*JSONRPC, error) {
+	d := newDispatcher(
+		logger,
+		NewDummyMetrics(config.Metrics),
+		config.Store,
+		config.ChainID,
+		config.BatchLengthLimit,
+		config.BlockRangeLimit,
+		config.PriceLimit,
+		config.JSONNamespaces,
+	)
+
+	if config.CacheEntries > 0 && config.CacheBytes > 0 {
+		if err := d.enableResponseCache(config.CacheEntries, config.CacheBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Recorder != nil {
+		if err := d.enableRecorder(*config.Recorder); err != nil {
+			return nil, err
+		}
+	}
+
+	d.setSubscriptionLimit(config.WSSubscriptionLimit)
+
+	metrics := NewDummyMetrics(config.Metrics)
+
 	srv := &JSONRPC{
-		logger: logger.Named("jsonrpc"),
-		config: config,
-		dispatcher: newDispatcher(
-			logger,
-			NewDummyMetrics(config.Metrics),
-			config.Store,
-			config.ChainID,
-			config.BatchLengthLimit,
-			config.BlockRangeLimit,
-			config.PriceLimit,
-			config.JSONNamespaces,
-		),
-		metrics: NewDummyMetrics(config.Metrics),
+		logger:     logger.Named("jsonrpc"),
+		config:     config,
+		dispatcher: d,
+		metrics:    metrics,
+		auth:       newAuthenticator(config.APIKeys, config.JWTSecret, config.AllowInsecureUnlock),
+		wsLimiter:  newWsConnLimiter(config.WSConnLimitPerIP, config.WSTrustedProxies, metrics),
 	}
 
 	// start http server
@@ -100,9 +147,38 @@ func NewJSONRPC(logger hclog.Logger, config *Config) (*JSONRPC, error) {
 		return nil, err
 	}
 
+	// start ipc server
+	if config.IPCPath != "" {
+		if err := srv.setupIPC(); err != nil {
+			return nil, err
+		}
+	}
+
 	return srv, nil
 }
 
+// Close stops the ipc server, closes the ipc connections, removes the endpoint,
+// and closes the capture file
+func (j *JSONRPC) Close() error {
+	if err := j.dispatcher.closeRecorder(); err != nil {
+		j.logger.Error("failed to close the recorder", "err", err)
+	}
+
+	if j.ipcListener == nil {
+		return nil
+	}
+
+	err := j.ipcListener.Close()
+
+	j.closeIPCConns()
+
+	if removeErr := ipc.Remove(j.config.IPCPath); removeErr != nil && err == nil {
+		err = removeErr
+	}
+
+	return err
+}
+
 func (j *JSONRPC) setupHTTP() error {
 	j.logger.Info("http server started", "addr", j.config.Addr.String())
 
@@ -129,6 +205,8 @@ func (j *JSONRPC) setupHTTP() error {
 	// would only enable websocket when set
 	if j.config.EnableWS {
 		mux.HandleFunc("/ws", j.handleWs)
+		// the api key could be a path segment
+		mux.HandleFunc("/ws/", j.handleWs)
 	}
 
 	srv := http.Server{
@@ -172,120 +250,6 @@ func middlewareFactory(config *Config) func(http.Handler) http.Handler {
 	}
 }
 
-// wsUpgrader defines upgrade parameters for the WS connection
-var wsUpgrader = websocket.Upgrader{
-	// Uses the default HTTP buffer sizes for Read / Write buffers.
-	// Documentation specifies that they are 4096B in size.
-	// There is no need to have them be 4x in size when requests / responses
-	// shouldn't exceed 1024B
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-}
-
-// wsWrapper is a wrapping object for the web socket connection and logger
-type wsWrapper struct {
-	sync.Mutex // basic r/w lock
-
-	ws       *websocket.Conn // the actual WS connection
-	logger   hclog.Logger    // module logger
-	filterID string          // filter ID
-}
-
-func (w *wsWrapper) SetFilterID(filterID string) {
-	w.filterID = filterID
-}
-
-func (w *wsWrapper) GetFilterID() string {
-	return w.filterID
-}
-
-// WriteMessage writes out the message to the WS peer
-func (w *wsWrapper) WriteMessage(messageType int, data []byte) error {
-	w.Lock()
-	defer w.Unlock()
-	writeErr := w.ws.WriteMessage(messageType, data)
-
-	if writeErr != nil {
-		w.logger.Error(
-			fmt.Sprintf("Unable to write WS message, %s", writeErr.Error()),
-		)
-	}
-
-	return writeErr
-}
-
-// isSupportedWSType returns a status indicating if the message type is supported
-func isSupportedWSType(messageType int) bool {
-	return messageType == websocket.TextMessage ||
-		messageType == websocket.BinaryMessage
-}
-
-func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
-	// CORS rule - Allow requests from anywhere
-	wsUpgrader.CheckOrigin = func(r *http.Request) bool { return true }
-
-	// Upgrade the connection to a WS one
-	ws, err := wsUpgrader.Upgrade(w, req, nil)
-	if err != nil {
-		j.logger.Error(fmt.Sprintf("Unable to upgrade to a WS connection, %s", err.Error()))
-
-		return
-	}
-
-	// Defer WS closure
-	defer func(ws *websocket.Conn) {
-		err = ws.Close()
-		if err != nil {
-			j.logger.Error(
-				fmt.Sprintf("Unable to gracefully close WS connection, %s", err.Error()),
-			)
-		}
-	}(ws)
-
-	wrapConn := &wsWrapper{ws: ws, logger: j.logger}
-
-	j.logger.Info("Websocket connection established")
-	// Run the listen loop
-	for {
-		// Read the incoming message
-		msgType, message, err := ws.ReadMessage()
-		if err != nil {
-			if websocket.IsCloseError(err,
-				websocket.CloseGoingAway,
-				websocket.CloseNormalClosure,
-				websocket.CloseAbnormalClosure,
-			) {
-				// Accepted close codes
-				j.logger.Info("Closing WS connection gracefully")
-			} else {
-				j.logger.Error(fmt.Sprintf("Unable to read WS message, %s", err.Error()))
-				j.logger.Info("Closing WS connection with error")
-			}
-
-			// remove websocket connection when closed
-			j.dispatcher.RemoveFilterByWs(wrapConn)
-
-			break
-		}
-
-		if isSupportedWSType(msgType) {
-			go func() {
-				resp, handleErr := j.dispatcher.HandleWs(message, wrapConn)
-				if handleErr != nil {
-					j.logger.Error(fmt.Sprintf("Unable to handle WS request, %s", handleErr.Error()))
-
-					_ = wrapConn.WriteMessage(
-						msgType,
-						[]byte(fmt.Sprintf("WS Handle error: %s", handleErr.Error())),
-					)
-				} else {
-					_ = wrapConn.WriteMessage(msgType, resp)
-				}
-			}()
-		}
-	}
-}
-
 func (j *JSONRPC) handle(w http.ResponseWriter, req *http.Request) {
 	defer j.metrics.RequestsCounterInc()
 
@@ -293,7 +257,7 @@ func (j *JSONRPC) handle(w http.ResponseWriter, req *http.Request) {
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set(
 		"Access-Control-Allow-Headers",
-		"Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization",
+		"Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, "+apiKeyHeader,
 	)
 
 	switch req.Method {
@@ -310,6 +274,15 @@ func (j *JSONRPC) handle(w http.ResponseWriter, req *http.Request) {
 }
 
 func (j *JSONRPC) handleJSONRPCRequest(w http.ResponseWriter, req *http.Request) {
+	c, err := j.auth.authenticate(req, "/")
+	if err != nil {
+		j.metrics.ErrorsCounterInc()
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(err.Error()))
+
+		return
+	}
+
 	data, err := io.ReadAll(req.Body)
 	if err != nil {
 		j.metrics.ErrorsCounterInc()
@@ -324,7 +297,7 @@ func (j *JSONRPC) handleJSONRPCRequest(w http.ResponseWriter, req *http.Request)
 	startT := time.Now()
 
 	// handle request
-	resp, err := j.dispatcher.Handle(data)
+	resp, err := j.dispatcher.handle(c, data)
 
 	j.metrics.ResponseTimeObserve(time.Since(startT).Seconds())
 
@@ -1,12 +1,19 @@
 package jsonrpc
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"io"
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/dogechain-lab/dogechain/helper/ipc"
 	"github.com/dogechain-lab/dogechain/helper/tests"
+	"github.com/dogechain-lab/dogechain/types"
 	"github.com/dogechain-lab/dogechain/versioning"
 	"github.com/stretchr/testify/assert"
 
@@ -32,6 +39,100 @@ func TestHTTPServer(t *testing.T) {
 	}
 }
 
+func TestIPCServer(t *testing.T) {
+	store := newMockStore()
+	port, portErr := tests.GetFreePort()
+
+	if portErr != nil {
+		t.Fatalf("Unable to fetch free port, %v", portErr)
+	}
+
+	ipcPath := filepath.Join(t.TempDir(), "dogechain.ipc")
+
+	config := &Config{
+		Store:          store,
+		Addr:           &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port},
+		IPCPath:        ipcPath,
+		JSONNamespaces: []Namespace{NamespaceEth, NamespaceWeb3},
+	}
+	srv, err := NewJSONRPC(hclog.NewNullLogger(), config)
+	assert.NoError(t, err)
+
+	conn, err := ipc.Dial(ipcPath)
+	assert.NoError(t, err)
+
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	readResponse := func() *SuccessResponse {
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+		line, err := reader.ReadBytes('\n')
+		assert.NoError(t, err)
+
+		resp := &SuccessResponse{}
+		assert.NoError(t, json.Unmarshal(line, resp))
+
+		return resp
+	}
+
+	// newline delimited requests
+	_, err = conn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"web3_clientVersion"}` + "\n"))
+	assert.NoError(t, err)
+
+	resp := readResponse()
+	assert.Nil(t, resp.Error)
+	assert.Contains(t, string(resp.Result), "dogechain")
+
+	// subscriptions are served as the websocket
+	_, err = conn.Write([]byte(`{"jsonrpc":"2.0","id":2,"method":"eth_subscribe","params":["newHeads"]}` + "\n"))
+	assert.NoError(t, err)
+
+	resp = readResponse()
+	assert.Nil(t, resp.Error)
+
+	store.emitEvent(&mockEvent{
+		NewChain: []*mockHeader{
+			{
+				header: &types.Header{
+					Hash: types.StringToHash("1"),
+				},
+			},
+		},
+	})
+
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	line, err := reader.ReadBytes('\n')
+	assert.NoError(t, err)
+	assert.Contains(t, string(line), "eth_subscription")
+
+	// the failed requests are answered with the json-rpc errors
+	_, err = conn.Write([]byte(`{"jsonrpc":"2.0","id":3,"method":"eth_unsubscribe","params":[]}` + "\n"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	line, err = reader.ReadBytes('\n')
+	assert.NoError(t, err)
+
+	errResp := &ErrorResponse{}
+	assert.NoError(t, json.Unmarshal(line, errResp))
+	assert.Equal(t, -32602, errResp.Error.Code)
+
+	// the connections and the endpoint are removed when closed
+	assert.NoError(t, srv.Close())
+
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	_, err = reader.ReadBytes('\n')
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = os.Stat(ipcPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
 func Test_handleGetRequest(t *testing.T) {
 	var (
 		chainName = _authoritativeChainName
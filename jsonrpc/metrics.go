@@ -10,29 +10,40 @@ type EthAPILabels prometheus.Labels
 var (
 	EthBlockNumberLabel      = EthAPILabels{"method": "eth_blockNumber"}
 	EthCallLabel             = EthAPILabels{"method": "eth_call"}
+	EthCallBundleLabel       = EthAPILabels{"method": "eth_callBundle"}
 	EthChainIDLabel          = EthAPILabels{"method": "eth_chainId"}
+	EthCreateAccessListLabel = EthAPILabels{"method": "eth_createAccessList"}
 	EthEstimateGasLabel      = EthAPILabels{"method": "eth_estimateGas"}
+	EthFeeHistoryLabel       = EthAPILabels{"method": "eth_feeHistory"}
 	EthGasPriceLabel         = EthAPILabels{"method": "eth_gasPrice"}
 	EthGetBalanceLabel       = EthAPILabels{"method": "eth_getBalance"}
 	EthGetBlockByHashLabel   = EthAPILabels{"method": "eth_getBlockByHash"}
 	EthGetBlockByNumberLabel = EthAPILabels{"method": "eth_getBlockByNumber"}
 
+	EthGetBlockReceiptsLabel                 = EthAPILabels{"method": "eth_getBlockReceipts"}
 	EthGetBlockTransactionCountByNumberLabel = EthAPILabels{"method": "eth_getBlockTransactionCountByNumber"}
 
 	EthGetCodeLabel          = EthAPILabels{"method": "eth_getCode"}
 	EthGetFilterChangesLabel = EthAPILabels{"method": "eth_getFilterChanges"}
 	EthGetFilterLogsLabel    = EthAPILabels{"method": "eth_getFilterLogs"}
 	EthGetLogsLabel          = EthAPILabels{"method": "eth_getLogs"}
+	EthGetProofLabel         = EthAPILabels{"method": "eth_getProof"}
 	EthGetStorageAtLabel     = EthAPILabels{"method": "eth_getStorageAt"}
 
 	EthGetTransactionByHashLabel  = EthAPILabels{"method": "eth_getTransactionByHash"}
 	EthGetTransactionCountLabel   = EthAPILabels{"method": "eth_getTransactionCount"}
 	EthGetTransactionReceiptLabel = EthAPILabels{"method": "eth_getTransactionReceipt"}
 
+	EthMaxPriorityFeePerGasLabel = EthAPILabels{"method": "eth_maxPriorityFeePerGas"}
+
 	EthNewBlockFilterLabel = EthAPILabels{"method": "eth_newBlockFilter"}
 	EthNewFilterLabel      = EthAPILabels{"method": "eth_newFilter"}
 
 	EthSendRawTransactionLabel = EthAPILabels{"method": "eth_sendRawTransaction"}
+	EthSendTransactionLabel    = EthAPILabels{"method": "eth_sendTransaction"}
+	EthSignLabel               = EthAPILabels{"method": "eth_sign"}
+	EthSignTransactionLabel    = EthAPILabels{"method": "eth_signTransaction"}
+	EthSignTypedDataLabel      = EthAPILabels{"method": "eth_signTypedData_v4"}
 	EthSyncingLabel            = EthAPILabels{"method": "eth_syncing"}
 
 	EthUninstallFilterLabel = EthAPILabels{"method": "eth_uninstallFilter"}
@@ -65,7 +76,46 @@ var (
 type DebugAPILabels prometheus.Labels
 
 var (
-	DebugTraceTransactionLabel = DebugAPILabels{"method": "debug_traceTransaction"}
+	DebugTraceTransactionLabel   = DebugAPILabels{"method": "debug_traceTransaction"}
+	DebugTraceCallLabel          = DebugAPILabels{"method": "debug_traceCall"}
+	DebugTraceBlockByNumberLabel = DebugAPILabels{"method": "debug_traceBlockByNumber"}
+	DebugTraceBlockByHashLabel   = DebugAPILabels{"method": "debug_traceBlockByHash"}
+)
+
+type OtsAPILabels prometheus.Labels
+
+var (
+	OtsSearchTransactionsBeforeLabel = OtsAPILabels{"method": "ots_searchTransactionsBefore"}
+	OtsSearchTransactionsAfterLabel  = OtsAPILabels{"method": "ots_searchTransactionsAfter"}
+)
+
+type TraceAPILabels prometheus.Labels
+
+var (
+	TraceBlockLabel                   = TraceAPILabels{"method": "trace_block"}
+	TraceTransactionLabel             = TraceAPILabels{"method": "trace_transaction"}
+	TraceFilterLabel                  = TraceAPILabels{"method": "trace_filter"}
+	TraceReplayBlockTransactionsLabel = TraceAPILabels{"method": "trace_replayBlockTransactions"}
+)
+
+type AdminAPILabels prometheus.Labels
+
+var (
+	AdminNodeInfoLabel       = AdminAPILabels{"method": "admin_nodeInfo"}
+	AdminPeersLabel          = AdminAPILabels{"method": "admin_peers"}
+	AdminAddPeerLabel        = AdminAPILabels{"method": "admin_addPeer"}
+	AdminRemovePeerLabel     = AdminAPILabels{"method": "admin_removePeer"}
+	AdminAddTrustedPeerLabel = AdminAPILabels{"method": "admin_addTrustedPeer"}
+	AdminPeerEventsLabel     = AdminAPILabels{"method": "admin_peerEvents"}
+)
+
+type PersonalAPILabels prometheus.Labels
+
+var (
+	PersonalListAccountsLabel  = PersonalAPILabels{"method": "personal_listAccounts"}
+	PersonalNewAccountLabel    = PersonalAPILabels{"method": "personal_newAccount"}
+	PersonalUnlockAccountLabel = PersonalAPILabels{"method": "personal_unlockAccount"}
+	PersonalLockAccountLabel   = PersonalAPILabels{"method": "personal_lockAccount"}
 )
 
 // Metrics represents the jsonrpc metrics
@@ -93,6 +143,34 @@ type Metrics struct {
 
 	// Debug metrics
 	debugAPI *prometheus.CounterVec
+
+	// Trace metrics
+	traceAPI *prometheus.CounterVec
+
+	// Ots metrics
+	otsAPI *prometheus.CounterVec
+
+	// Admin metrics
+	adminAPI *prometheus.CounterVec
+
+	// Personal metrics
+	personalAPI *prometheus.CounterVec
+
+	// Response cache hits and misses
+	responseCacheHits   *prometheus.CounterVec
+	responseCacheMisses *prometheus.CounterVec
+
+	// API key requests
+	apiKeyRequests *prometheus.CounterVec
+
+	// API key rejected requests
+	apiKeyRejects *prometheus.CounterVec
+
+	// Open websocket connections
+	wsConnections prometheus.Gauge
+
+	// Websocket connection events, like the rejections and the evictions
+	wsEvents *prometheus.CounterVec
 }
 
 func (m *Metrics) RequestsCounterInc() {
@@ -137,6 +215,64 @@ func (m *Metrics) DebugAPICounterInc(label DebugAPILabels) {
 	}
 }
 
+func (m *Metrics) TraceAPICounterInc(label TraceAPILabels) {
+	if m.traceAPI != nil {
+		m.traceAPI.With((prometheus.Labels)(label)).Inc()
+	}
+}
+
+func (m *Metrics) OtsAPICounterInc(label OtsAPILabels) {
+	if m.otsAPI != nil {
+		m.otsAPI.With((prometheus.Labels)(label)).Inc()
+	}
+}
+
+func (m *Metrics) AdminAPICounterInc(label AdminAPILabels) {
+	if m.adminAPI != nil {
+		m.adminAPI.With((prometheus.Labels)(label)).Inc()
+	}
+}
+
+func (m *Metrics) PersonalAPICounterInc(label PersonalAPILabels) {
+	if m.personalAPI != nil {
+		m.personalAPI.With((prometheus.Labels)(label)).Inc()
+	}
+}
+
+func (m *Metrics) ResponseCacheHitInc(method string) {
+	if m.responseCacheHits != nil {
+		m.responseCacheHits.With(prometheus.Labels{"method": method}).Inc()
+	}
+}
+
+func (m *Metrics) ResponseCacheMissInc(method string) {
+	if m.responseCacheMisses != nil {
+		m.responseCacheMisses.With(prometheus.Labels{"method": method}).Inc()
+	}
+}
+
+func (m *Metrics) APIKeyRequestsInc(key string) {
+	if m.apiKeyRequests != nil {
+		m.apiKeyRequests.With(prometheus.Labels{"key": key}).Inc()
+	}
+}
+
+func (m *Metrics) APIKeyRejectsInc(key, reason string) {
+	if m.apiKeyRejects != nil {
+		m.apiKeyRejects.With(prometheus.Labels{"key": key, "reason": reason}).Inc()
+	}
+}
+
+func (m *Metrics) WSConnectionsSet(v float64) {
+	metrics.SetGauge(m.wsConnections, v)
+}
+
+func (m *Metrics) WSEventInc(event string) {
+	if m.wsEvents != nil {
+		m.wsEvents.With(prometheus.Labels{"event": event}).Inc()
+	}
+}
+
 // GetPrometheusMetrics return the blockchain metrics instance
 func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics {
 	constLabels := metrics.ParseLables(labelsWithValues...)
@@ -206,6 +342,76 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 			Help:        "debug api requests",
 			ConstLabels: constLabels,
 		}, []string{"method"}),
+		traceAPI: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "trace_api_requests",
+			Help:        "trace api requests",
+			ConstLabels: constLabels,
+		}, []string{"method"}),
+		otsAPI: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "ots_api_requests",
+			Help:        "ots api requests",
+			ConstLabels: constLabels,
+		}, []string{"method"}),
+		adminAPI: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "admin_api_requests",
+			Help:        "admin api requests",
+			ConstLabels: constLabels,
+		}, []string{"method"}),
+		personalAPI: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "personal_api_requests",
+			Help:        "personal api requests",
+			ConstLabels: constLabels,
+		}, []string{"method"}),
+		responseCacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "response_cache_hits",
+			Help:        "response cache hits",
+			ConstLabels: constLabels,
+		}, []string{"method"}),
+		responseCacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "response_cache_misses",
+			Help:        "response cache misses",
+			ConstLabels: constLabels,
+		}, []string{"method"}),
+		apiKeyRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "api_key_requests",
+			Help:        "api key requests",
+			ConstLabels: constLabels,
+		}, []string{"key"}),
+		apiKeyRejects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "api_key_rejected_requests",
+			Help:        "api key rejected requests",
+			ConstLabels: constLabels,
+		}, []string{"key", "reason"}),
+		wsConnections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "ws_connections",
+			Help:        "open websocket connections",
+			ConstLabels: constLabels,
+		}),
+		wsEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "jsonrpc",
+			Name:        "ws_events",
+			Help:        "websocket connection events",
+			ConstLabels: constLabels,
+		}, []string{"event"}),
 	}
 
 	prometheus.MustRegister(
@@ -217,6 +423,16 @@ func GetPrometheusMetrics(namespace string, labelsWithValues ...string) *Metrics
 		m.web3API,
 		m.txPoolAPI,
 		m.debugAPI,
+		m.traceAPI,
+		m.otsAPI,
+		m.adminAPI,
+		m.personalAPI,
+		m.responseCacheHits,
+		m.responseCacheMisses,
+		m.apiKeyRequests,
+		m.apiKeyRejects,
+		m.wsConnections,
+		m.wsEvents,
 	)
 
 	return m
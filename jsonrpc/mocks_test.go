@@ -5,7 +5,9 @@ import (
 	"sync"
 
 	"github.com/dogechain-lab/dogechain/blockchain"
+	"github.com/dogechain-lab/dogechain/helper/progress"
 	"github.com/dogechain-lab/dogechain/state"
+	"github.com/dogechain-lab/dogechain/txpool/proto"
 	"github.com/dogechain-lab/dogechain/types"
 )
 
@@ -52,6 +54,10 @@ type mockStore struct {
 	receiptsLock sync.Mutex
 	receipts     map[types.Hash][]*types.Receipt
 	accounts     map[types.Address]*state.Account
+	txEventCh    chan *proto.TxPoolEvent
+	pendingTxs   map[types.Hash]*types.Transaction
+	progression  *progress.Progression
+	poolLock     sync.Mutex // guards the pending txs and the progression
 }
 
 func newMockStore() *mockStore {
@@ -59,9 +65,30 @@ func newMockStore() *mockStore {
 		header:       &types.Header{Number: 0},
 		subscription: blockchain.NewMockSubscription(),
 		accounts:     map[types.Address]*state.Account{},
+		txEventCh:    make(chan *proto.TxPoolEvent, 10),
+		pendingTxs:   map[types.Hash]*types.Transaction{},
 	}
 }
 
+// emitPendingTx adds the transaction to the pool and emits the promoted event
+func (m *mockStore) emitPendingTx(tx *types.Transaction) {
+	m.poolLock.Lock()
+	m.pendingTxs[tx.Hash()] = tx
+	m.poolLock.Unlock()
+
+	m.txEventCh <- &proto.TxPoolEvent{
+		Type:   proto.EventType_PROMOTED,
+		TxHash: tx.Hash().String(),
+	}
+}
+
+func (m *mockStore) setProgression(p *progress.Progression) {
+	m.poolLock.Lock()
+	defer m.poolLock.Unlock()
+
+	m.progression = p
+}
+
 func (m *mockStore) emitEvent(evnt *mockEvent) {
 	if m.receipts == nil {
 		m.receipts = map[types.Hash][]*types.Receipt{}
@@ -117,6 +144,32 @@ func (m *mockStore) SubscribeEvents() blockchain.Subscription {
 	return m.subscription
 }
 
+func (m *mockStore) SubscribeTxPoolEvents(
+	eventTypes ...proto.EventType,
+) (<-chan *proto.TxPoolEvent, func()) {
+	return m.txEventCh, func() {}
+}
+
+func (m *mockStore) GetPendingTx(txHash types.Hash) (*types.Transaction, bool) {
+	m.poolLock.Lock()
+	defer m.poolLock.Unlock()
+
+	tx, ok := m.pendingTxs[txHash]
+
+	return tx, ok
+}
+
+func (m *mockStore) GetSyncProgression() *progress.Progression {
+	m.poolLock.Lock()
+	defer m.poolLock.Unlock()
+
+	return m.progression
+}
+
+func (m *mockStore) FilterBlocksByBloom(from, to uint64, filters [][][]byte) ([]uint64, uint64, error) {
+	return nil, from, nil
+}
+
 func (m *mockStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
 	return nil, false
 }
@@ -0,0 +1,163 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/blockchain/addrindex"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	ErrInvalidPageSize = errors.New("invalid page size")
+)
+
+const (
+	// otsMaxPageSize is the max number of the transactions of a page
+	otsMaxPageSize = 1000
+)
+
+type otsStore interface {
+	ethStore
+
+	// SearchAddressTransactions returns a page of the transactions touching the address
+	// from the given block on, and whether there are more transactions after the page
+	SearchAddressTransactions(
+		addr types.Address,
+		from uint64,
+		backward bool,
+		pageSize int,
+	) ([]addrindex.TxRef, bool, error)
+}
+
+// Ots is the otterscan compatible jsonrpc endpoint, backed by the address index
+type Ots struct {
+	store otsStore
+
+	metrics *Metrics
+}
+
+// otsReceipt is the receipt with the timestamp of its block
+type otsReceipt struct {
+	*receipt
+	Timestamp argUint64 `json:"timestamp"`
+}
+
+// otsTransactionsPage is a page of the transactions of an address, in the
+// descending order
+type otsTransactionsPage struct {
+	Txs       []*transaction `json:"txs"`
+	Receipts  []*otsReceipt  `json:"receipts"`
+	FirstPage bool           `json:"firstPage"`
+	LastPage  bool           `json:"lastPage"`
+}
+
+// SearchTransactionsBefore returns a page of the transactions touching the address
+// before the block, or from the latest block if zero
+func (o *Ots) SearchTransactionsBefore(address types.Address, blockNumber, pageSize uint64) (interface{}, error) {
+	o.metrics.OtsAPICounterInc(OtsSearchTransactionsBeforeLabel)
+
+	if pageSize == 0 || pageSize > otsMaxPageSize {
+		return nil, ErrInvalidPageSize
+	}
+
+	from := o.store.Header().Number
+	if blockNumber > 0 {
+		from = blockNumber - 1
+	}
+
+	refs, more, err := o.store.SearchAddressTransactions(address, from, true, int(pageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := o.toTransactionsPage(refs)
+	if err != nil {
+		return nil, err
+	}
+
+	page.FirstPage = blockNumber == 0
+	page.LastPage = !more
+
+	return page, nil
+}
+
+// SearchTransactionsAfter returns a page of the transactions touching the address
+// after the block, or from the genesis if zero
+func (o *Ots) SearchTransactionsAfter(address types.Address, blockNumber, pageSize uint64) (interface{}, error) {
+	o.metrics.OtsAPICounterInc(OtsSearchTransactionsAfterLabel)
+
+	if pageSize == 0 || pageSize > otsMaxPageSize {
+		return nil, ErrInvalidPageSize
+	}
+
+	refs, more, err := o.store.SearchAddressTransactions(address, blockNumber+1, false, int(pageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// the pages are always in the descending order
+	for i, j := 0, len(refs)-1; i < j; i, j = i+1, j-1 {
+		refs[i], refs[j] = refs[j], refs[i]
+	}
+
+	page, err := o.toTransactionsPage(refs)
+	if err != nil {
+		return nil, err
+	}
+
+	page.FirstPage = !more
+	page.LastPage = blockNumber == 0
+
+	return page, nil
+}
+
+// toTransactionsPage fetches the transactions and the receipts of the page
+func (o *Ots) toTransactionsPage(refs []addrindex.TxRef) (*otsTransactionsPage, error) {
+	page := &otsTransactionsPage{
+		Txs:      make([]*transaction, 0, len(refs)),
+		Receipts: make([]*otsReceipt, 0, len(refs)),
+	}
+
+	var (
+		block    *types.Block
+		receipts []*types.Receipt
+	)
+
+	for _, ref := range refs {
+		if block == nil || block.Number() != ref.BlockNumber {
+			var ok bool
+
+			if block, ok = o.store.GetBlockByNumber(ref.BlockNumber, true); !ok {
+				return nil, fmt.Errorf("block %d not found", ref.BlockNumber)
+			}
+
+			var err error
+
+			if receipts, err = o.store.GetReceiptsByHash(block.Hash()); err != nil {
+				return nil, err
+			}
+		}
+
+		idx := int(ref.TxIndex)
+		if idx >= len(block.Transactions) || idx >= len(receipts) {
+			return nil, fmt.Errorf("transaction %d of block %d not found", idx, ref.BlockNumber)
+		}
+
+		tx := block.Transactions[idx]
+
+		page.Txs = append(page.Txs, toTransaction(
+			tx,
+			argUintPtr(block.Number()),
+			argHashPtr(block.Hash()),
+			&idx,
+			block.Header.BaseFee,
+		))
+		page.Receipts = append(page.Receipts, &otsReceipt{
+			receipt:   toReceipt(receipts[idx], tx, idx, block.Header),
+			Timestamp: argUint64(block.Header.Timestamp),
+		})
+	}
+
+	return page, nil
+}
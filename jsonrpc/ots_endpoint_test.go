@@ -0,0 +1,157 @@
+package jsonrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/blockchain/addrindex"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockOtsStore returns the given page of the address index, and records the query
+type mockOtsStore struct {
+	*mockBlockStore
+
+	refs []addrindex.TxRef
+	more bool
+
+	from     uint64
+	backward bool
+	pageSize int
+}
+
+func (m *mockOtsStore) SearchAddressTransactions(
+	addr types.Address,
+	from uint64,
+	backward bool,
+	pageSize int,
+) ([]addrindex.TxRef, bool, error) {
+	m.from, m.backward, m.pageSize = from, backward, pageSize
+
+	refs := make([]addrindex.TxRef, len(m.refs))
+	copy(refs, m.refs)
+
+	return refs, m.more, nil
+}
+
+func newOtsTestTx(nonce uint64) *types.Transaction {
+	return &types.Transaction{
+		Nonce:    nonce,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+		V:        big.NewInt(0),
+		R:        big.NewInt(0),
+		S:        big.NewInt(0),
+	}
+}
+
+func newMockOtsStore() *mockOtsStore {
+	store := &mockOtsStore{mockBlockStore: newMockBlockStore()}
+
+	for i := 0; i < 3; i++ {
+		block := &types.Block{
+			Header: &types.Header{
+				Number:    uint64(i),
+				Timestamp: uint64(100 + i),
+			},
+			Transactions: []*types.Transaction{
+				newOtsTestTx(uint64(2 * i)),
+				newOtsTestTx(uint64(2*i + 1)),
+			},
+		}
+		block.Header.ComputeHash()
+
+		store.add(block)
+		store.receipts[block.Hash()] = []*types.Receipt{{GasUsed: 1}, {GasUsed: 2}}
+	}
+
+	return store
+}
+
+func TestOts_SearchTransactionsBefore(t *testing.T) {
+	store := newMockOtsStore()
+	endpoint := &Ots{store, NilMetrics()}
+
+	store.refs = []addrindex.TxRef{{BlockNumber: 2, TxIndex: 1}, {BlockNumber: 1, TxIndex: 0}}
+	store.more = true
+
+	// from the latest block
+	res, err := endpoint.SearchTransactionsBefore(types.StringToAddress("0x1"), 0, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), store.from)
+	assert.True(t, store.backward)
+	assert.Equal(t, 2, store.pageSize)
+
+	page, ok := res.(*otsTransactionsPage)
+	assert.True(t, ok)
+	assert.True(t, page.FirstPage)
+	assert.False(t, page.LastPage)
+
+	if assert.Len(t, page.Txs, 2) && assert.Len(t, page.Receipts, 2) {
+		assert.Equal(t, store.blocks[2].Transactions[1].Hash(), page.Txs[0].Hash)
+		assert.Equal(t, argUint64(2), page.Receipts[0].GasUsed)
+		assert.Equal(t, argUint64(102), page.Receipts[0].Timestamp)
+		assert.Equal(t, store.blocks[1].Transactions[0].Hash(), page.Txs[1].Hash)
+		assert.Equal(t, argUint64(101), page.Receipts[1].Timestamp)
+	}
+
+	// before the given block
+	store.more = false
+
+	res, err = endpoint.SearchTransactionsBefore(types.StringToAddress("0x1"), 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), store.from)
+
+	page, ok = res.(*otsTransactionsPage)
+	assert.True(t, ok)
+	assert.False(t, page.FirstPage)
+	assert.True(t, page.LastPage)
+
+	_, err = endpoint.SearchTransactionsBefore(types.StringToAddress("0x1"), 0, 0)
+	assert.ErrorIs(t, err, ErrInvalidPageSize)
+
+	_, err = endpoint.SearchTransactionsBefore(types.StringToAddress("0x1"), 0, otsMaxPageSize+1)
+	assert.ErrorIs(t, err, ErrInvalidPageSize)
+
+	// unknown block
+	store.refs = []addrindex.TxRef{{BlockNumber: 3, TxIndex: 0}}
+
+	_, err = endpoint.SearchTransactionsBefore(types.StringToAddress("0x1"), 0, 2)
+	assert.Error(t, err)
+}
+
+func TestOts_SearchTransactionsAfter(t *testing.T) {
+	store := newMockOtsStore()
+	endpoint := &Ots{store, NilMetrics()}
+
+	store.refs = []addrindex.TxRef{{BlockNumber: 1, TxIndex: 0}, {BlockNumber: 1, TxIndex: 1}}
+	store.more = true
+
+	res, err := endpoint.SearchTransactionsAfter(types.StringToAddress("0x1"), 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), store.from)
+	assert.False(t, store.backward)
+
+	page, ok := res.(*otsTransactionsPage)
+	assert.True(t, ok)
+	assert.False(t, page.FirstPage)
+	assert.True(t, page.LastPage)
+
+	// the pages are in the descending order
+	if assert.Len(t, page.Txs, 2) {
+		assert.Equal(t, store.blocks[1].Transactions[1].Hash(), page.Txs[0].Hash)
+		assert.Equal(t, store.blocks[1].Transactions[0].Hash(), page.Txs[1].Hash)
+	}
+
+	store.more = false
+
+	res, err = endpoint.SearchTransactionsAfter(types.StringToAddress("0x1"), 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), store.from)
+
+	page, ok = res.(*otsTransactionsPage)
+	assert.True(t, ok)
+	assert.True(t, page.FirstPage)
+	assert.False(t, page.LastPage)
+}
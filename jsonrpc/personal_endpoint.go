@@ -0,0 +1,94 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+const (
+	// defaultUnlockDuration is the duration of personal_unlockAccount if not given
+	defaultUnlockDuration = 300 * time.Second
+
+	// maxUnlockDuration is the max duration in seconds, which does not overflow
+	maxUnlockDuration = uint64(math.MaxInt64 / int64(time.Second))
+)
+
+// accountStore provides methods needed for the keystore accounts
+type accountStore interface {
+	// ListAccounts returns the addresses of the keystore accounts
+	ListAccounts() ([]types.Address, error)
+
+	// NewAccount creates a new account encrypted by the password
+	NewAccount(password string) (types.Address, error)
+
+	// UnlockAccount decrypts the account by the password for the duration,
+	// it is unlocked until locked explicitly if the duration is zero
+	UnlockAccount(addr types.Address, password string, duration time.Duration) error
+
+	// LockAccount removes the decrypted key of the account from the memory
+	LockAccount(addr types.Address) error
+
+	// SignHash signs the hash by the unlocked account, V of the signature is 0 or 1
+	SignHash(addr types.Address, hash []byte) ([]byte, error)
+
+	// SignTx signs the transaction by the unlocked account
+	SignTx(addr types.Address, tx *types.Transaction, signer crypto.TxSigner) (*types.Transaction, error)
+}
+
+// Personal is the personal jsonrpc endpoint, which manages the keystore accounts
+type Personal struct {
+	store accountStore
+
+	metrics *Metrics
+}
+
+// ListAccounts returns the addresses of the keystore accounts
+func (p *Personal) ListAccounts() (interface{}, error) {
+	p.metrics.PersonalAPICounterInc(PersonalListAccountsLabel)
+
+	return p.store.ListAccounts()
+}
+
+// NewAccount creates a new account encrypted by the password, and returns its address
+func (p *Personal) NewAccount(password string) (interface{}, error) {
+	p.metrics.PersonalAPICounterInc(PersonalNewAccountLabel)
+
+	return p.store.NewAccount(password)
+}
+
+// UnlockAccount unlocks the account for the duration in seconds, which is 300
+// seconds by default. The account is unlocked until locked explicitly if the
+// duration is zero.
+func (p *Personal) UnlockAccount(addr types.Address, password string, duration *uint64) (interface{}, error) {
+	p.metrics.PersonalAPICounterInc(PersonalUnlockAccountLabel)
+
+	timeout := defaultUnlockDuration
+	if duration != nil {
+		if *duration > maxUnlockDuration {
+			return false, fmt.Errorf("unlock duration too large, the max is %d seconds", maxUnlockDuration)
+		}
+
+		timeout = time.Duration(*duration) * time.Second
+	}
+
+	if err := p.store.UnlockAccount(addr, password, timeout); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// LockAccount locks the account
+func (p *Personal) LockAccount(addr types.Address) (interface{}, error) {
+	p.metrics.PersonalAPICounterInc(PersonalLockAccountLabel)
+
+	if err := p.store.LockAccount(addr); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,354 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/accounts"
+	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/helper/keystore"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAccountStore keeps the accounts in a temporary keystore
+type mockAccountStore struct {
+	*mockStore
+
+	manager *accounts.Manager
+	txn     *types.Transaction
+}
+
+func newMockAccountStore(t *testing.T) *mockAccountStore {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "keystore")
+
+	return &mockAccountStore{
+		mockStore: newMockStore(),
+		manager:   accounts.NewManager(dir, keystore.LightScryptN, keystore.LightScryptP),
+	}
+}
+
+func (m *mockAccountStore) ListAccounts() ([]types.Address, error) {
+	return m.manager.Accounts()
+}
+
+func (m *mockAccountStore) NewAccount(password string) (types.Address, error) {
+	return m.manager.NewAccount(password)
+}
+
+func (m *mockAccountStore) UnlockAccount(addr types.Address, password string, duration time.Duration) error {
+	return m.manager.Unlock(addr, password, duration)
+}
+
+func (m *mockAccountStore) LockAccount(addr types.Address) error {
+	m.manager.Lock(addr)
+
+	return nil
+}
+
+func (m *mockAccountStore) SignHash(addr types.Address, hash []byte) ([]byte, error) {
+	return m.manager.SignHash(addr, hash)
+}
+
+func (m *mockAccountStore) SignTx(
+	addr types.Address,
+	tx *types.Transaction,
+	signer crypto.TxSigner,
+) (*types.Transaction, error) {
+	return m.manager.SignTx(addr, tx, signer)
+}
+
+func (m *mockAccountStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
+	return chain.AllForksEnabled.At(blockNumber)
+}
+
+func (m *mockAccountStore) GetNonce(addr types.Address) uint64 {
+	return 0
+}
+
+func (m *mockAccountStore) AddTx(tx *types.Transaction) error {
+	m.txn = tx
+
+	return nil
+}
+
+func newTestEthEndpointWithAccounts(store *mockAccountStore) *Eth {
+	return &Eth{hclog.NewNullLogger(), store, 100, nil, 0, 0, NilMetrics()}
+}
+
+func TestPersonalEndpoint(t *testing.T) {
+	store := newMockAccountStore(t)
+	personal := &Personal{store, NilMetrics()}
+
+	res, err := personal.NewAccount("foo")
+	assert.NoError(t, err)
+
+	addr, ok := res.(types.Address)
+	assert.True(t, ok)
+
+	res, err = personal.ListAccounts()
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Address{addr}, res)
+
+	res, err = personal.UnlockAccount(addr, "bar", nil)
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+	assert.Equal(t, false, res)
+
+	tooLong := maxUnlockDuration + 1
+
+	_, err = personal.UnlockAccount(addr, "foo", &tooLong)
+	assert.Error(t, err)
+	assert.False(t, store.manager.IsUnlocked(addr))
+
+	res, err = personal.UnlockAccount(addr, "foo", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, true, res)
+	assert.True(t, store.manager.IsUnlocked(addr))
+
+	res, err = personal.LockAccount(addr)
+	assert.NoError(t, err)
+	assert.Equal(t, true, res)
+	assert.False(t, store.manager.IsUnlocked(addr))
+}
+
+func TestEth_Sign(t *testing.T) {
+	store := newMockAccountStore(t)
+	eth := newTestEthEndpointWithAccounts(store)
+
+	addr, err := store.NewAccount("foo")
+	assert.NoError(t, err)
+
+	data := argBytes("hello")
+
+	_, err = eth.Sign(addr, data)
+	assert.ErrorIs(t, err, accounts.ErrAccountLocked)
+
+	assert.NoError(t, store.UnlockAccount(addr, "foo", 0))
+
+	res, err := eth.Sign(addr, data)
+	assert.NoError(t, err)
+
+	sig, ok := res.(argBytes)
+	assert.True(t, ok)
+	assert.Len(t, sig, 65)
+	assert.Contains(t, []byte{27, 28}, sig[64])
+
+	// recover the signer from the prefixed message
+	hash := crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n5hello"))
+	recoverable := append(append([]byte{}, sig[:64]...), sig[64]-27)
+
+	pub, err := crypto.SigToPub(hash, recoverable)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, crypto.PubKeyToAddress(pub))
+}
+
+func TestEth_SignTypedData(t *testing.T) {
+	store := newMockAccountStore(t)
+	eth := newTestEthEndpointWithAccounts(store)
+
+	addr, err := store.NewAccount("foo")
+	assert.NoError(t, err)
+	assert.NoError(t, store.UnlockAccount(addr, "foo", 0))
+
+	typedData := json.RawMessage(`{
+		"types": {
+			"EIP712Domain": [{"name": "name", "type": "string"}, {"name": "chainId", "type": "uint256"}],
+			"Greeting": [{"name": "text", "type": "string"}]
+		},
+		"primaryType": "Greeting",
+		"domain": {"name": "test", "chainId": 100},
+		"message": {"text": "hello"}
+	}`)
+
+	res, err := eth.SignTypedData_v4(addr, typedData)
+	assert.NoError(t, err)
+
+	sig, ok := res.(argBytes)
+	assert.True(t, ok)
+
+	parsed, err := accounts.ParseTypedData(typedData)
+	assert.NoError(t, err)
+
+	hash, err := parsed.Hash()
+	assert.NoError(t, err)
+
+	pub, err := crypto.SigToPub(hash, append(append([]byte{}, sig[:64]...), sig[64]-27))
+	assert.NoError(t, err)
+	assert.Equal(t, addr, crypto.PubKeyToAddress(pub))
+
+	_, err = eth.SignTypedData_v4(addr, json.RawMessage(`{"types": {}}`))
+	assert.ErrorIs(t, err, accounts.ErrTypedDataType)
+}
+
+func TestEth_SignTransaction(t *testing.T) {
+	store := newMockAccountStore(t)
+	eth := newTestEthEndpointWithAccounts(store)
+
+	addr, err := store.NewAccount("foo")
+	assert.NoError(t, err)
+
+	to := types.StringToAddress("1")
+	newArgs := func() *txnArgs {
+		return &txnArgs{
+			From:     &addr,
+			To:       &to,
+			Gas:      argUintPtr(21000),
+			GasPrice: argBytesPtr(big.NewInt(1).Bytes()),
+			Value:    argBytesPtr(big.NewInt(10).Bytes()),
+		}
+	}
+
+	_, err = eth.SignTransaction(&txnArgs{To: &to})
+	assert.ErrorIs(t, err, ErrMissingSender)
+
+	_, err = eth.SignTransaction(newArgs())
+	assert.ErrorIs(t, err, accounts.ErrAccountLocked)
+
+	assert.NoError(t, store.UnlockAccount(addr, "foo", 0))
+
+	res, err := eth.SignTransaction(newArgs())
+	assert.NoError(t, err)
+
+	result, ok := res.(*signTransactionResult)
+	assert.True(t, ok)
+
+	tx := &types.Transaction{}
+	assert.NoError(t, tx.UnmarshalRLP(result.Raw))
+	assert.Equal(t, result.Tx.Hash, tx.Hash())
+
+	signer := crypto.NewSigner(chain.AllForksEnabled.At(0), 100)
+	sender, err := signer.Sender(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, addr, sender)
+
+	// the signed transaction is sent to the pool
+	res, err = eth.SendTransaction(newArgs())
+	assert.NoError(t, err)
+	assert.Equal(t, tx.Hash().String(), res)
+	assert.Equal(t, tx.Hash(), store.txn.Hash())
+}
+
+func TestPersonalNamespace(t *testing.T) {
+	store := newMockAccountStore(t)
+	req := []byte(`{"method": "personal_listAccounts", "params": [], "id": 1}`)
+
+	cases := []struct {
+		name    string
+		ns      []Namespace
+		enabled bool
+	}{
+		{"all namespaces exclude the personal one", []Namespace{NamespaceAll}, false},
+		{"personal namespace listed", []Namespace{NamespacePersonal}, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dispatcher := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 0, 0, 0, 0, c.ns)
+
+			data, err := dispatcher.Handle(req)
+			assert.NoError(t, err)
+
+			resp := new(SuccessResponse)
+			assert.NoError(t, json.Unmarshal(data, resp))
+
+			if c.enabled {
+				assert.Nil(t, resp.Error)
+			} else {
+				assert.NotNil(t, resp.Error)
+			}
+		})
+	}
+}
+
+func TestEth_SignRequiresPrivilegedCaller(t *testing.T) {
+	store := newMockAccountStore(t)
+	dispatcher := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 100, 0, 0, 0, []Namespace{NamespaceAll})
+
+	addr, err := store.NewAccount("foo")
+	assert.NoError(t, err)
+
+	// the account is unlocked by the operator
+	assert.NoError(t, store.UnlockAccount(addr, "foo", 0))
+
+	req := []byte(fmt.Sprintf(`{
+		"method": "eth_sendTransaction",
+		"params": [{"from": "%s", "to": "%s", "gas": "0x5208", "gasPrice": "0x1", "value": "0x1"}],
+		"id": 1
+	}`, addr, types.StringToAddress("1")))
+
+	// the caller without the jwt token
+	data, err := dispatcher.handle(&caller{privileged: false}, req)
+	assert.NoError(t, err)
+
+	resp := new(SuccessResponse)
+	assert.NoError(t, json.Unmarshal(data, resp))
+
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, -32001, resp.Error.Code)
+	}
+
+	assert.Nil(t, store.txn)
+
+	// the jwt authenticated caller
+	data, err = dispatcher.handle(&caller{privileged: true}, req)
+	assert.NoError(t, err)
+
+	resp = new(SuccessResponse)
+	assert.NoError(t, json.Unmarshal(data, resp))
+	assert.Nil(t, resp.Error)
+	assert.NotNil(t, store.txn)
+}
+
+func TestPersonal_InsecureUnlock(t *testing.T) {
+	store := newMockAccountStore(t)
+	dispatcher := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 100, 0, 0, 0,
+		[]Namespace{NamespaceAll, NamespacePersonal})
+
+	addr, err := store.NewAccount("foo")
+	assert.NoError(t, err)
+
+	unlockReq := []byte(fmt.Sprintf(`{"method": "personal_unlockAccount", "params": ["%s", "foo", 0], "id": 1}`, addr))
+	sendReq := []byte(fmt.Sprintf(`{
+		"method": "eth_sendTransaction",
+		"params": [{"from": "%s", "to": "%s", "gas": "0x5208", "gasPrice": "0x1", "value": "0x1"}],
+		"id": 1
+	}`, addr, types.StringToAddress("1")))
+
+	handle := func(c *caller, req []byte) *ObjectError {
+		data, err := dispatcher.handle(c, req)
+		assert.NoError(t, err)
+
+		resp := new(SuccessResponse)
+		assert.NoError(t, json.Unmarshal(data, resp))
+
+		return resp.Error
+	}
+
+	// the remote caller without the jwt auth
+	remote := &caller{privileged: true, noUnlock: true}
+
+	if err := handle(remote, unlockReq); assert.NotNil(t, err) {
+		assert.Equal(t, -32001, err.Code)
+	}
+
+	// the account is unlocked over ipc
+	assert.Nil(t, handle(nil, unlockReq))
+
+	if err := handle(remote, sendReq); assert.NotNil(t, err) {
+		assert.Equal(t, -32001, err.Code)
+	}
+
+	assert.Nil(t, store.txn)
+
+	// the insecure unlock is allowed
+	assert.Nil(t, handle(&caller{privileged: true}, sendReq))
+	assert.NotNil(t, store.txn)
+}
@@ -213,3 +213,27 @@ func (q *LogQuery) Match(log *types.Log) bool {
 
 	return true
 }
+
+// bloomFilters returns the addresses and the topics of the query as the bloom
+// filters, the first one is of the addresses, the empty ones are the wildcards
+func (q *LogQuery) bloomFilters() [][][]byte {
+	filters := make([][][]byte, 0, len(q.Topics)+1)
+
+	addresses := make([][]byte, 0, len(q.Addresses))
+	for _, addr := range q.Addresses {
+		addresses = append(addresses, addr.Bytes())
+	}
+
+	filters = append(filters, addresses)
+
+	for _, sub := range q.Topics {
+		topics := make([][]byte, 0, len(sub))
+		for _, topic := range sub {
+			topics = append(topics, topic.Bytes())
+		}
+
+		filters = append(filters, topics)
+	}
+
+	return filters
+}
@@ -0,0 +1,399 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	recordFilePrefix = "rpc-"
+	recordFileSuffix = ".jsonl"
+
+	// recordFileTimeFormat keeps the capture files sorted by their creation
+	recordFileTimeFormat = "20060102T150405.000000000"
+
+	// recordQueueSize is the max records waiting for the writer, the records are
+	// dropped once it is full, so that the calls are never blocked by the disk
+	recordQueueSize = 4096
+)
+
+var (
+	ErrInvalidRecordSampleRate = errors.New("the record sample rate must be in (0, 1]")
+	ErrNoRecordFiles           = errors.New("no capture files found")
+)
+
+// stateChangingMethods are the unprivileged methods changing the node, such as the
+// pool and the filters, which are not idempotent
+var stateChangingMethods = map[string]struct{}{
+	"eth_sendRawTransaction": {},
+	"eth_sendTransaction":    {},
+	"eth_newFilter":          {},
+	"eth_newBlockFilter":     {},
+	"eth_getFilterChanges":   {},
+	"eth_uninstallFilter":    {},
+}
+
+// IsStateChangingMethod returns whether the call of the method changes the node,
+// so that it is not replayed by default
+func IsStateChangingMethod(method string) bool {
+	_, ok := stateChangingMethods[method]
+
+	return ok
+}
+
+// RecorderConfig is the config of the json-rpc traffic recorder
+type RecorderConfig struct {
+	Dir         string   // the directory of the capture files
+	SampleRate  float64  // the ratio of the recorded requests, in (0, 1]
+	Methods     []string // the recorded methods, all of the unprivileged ones if empty
+	MaxFileSize int64    // the capture file is rotated once larger than it
+	MaxFiles    int      // the oldest capture files are removed once more than it, kept if zero
+}
+
+// RecordEntry is a recorded json-rpc call, which is a line of the capture file
+type RecordEntry struct {
+	Time     time.Time       `json:"time"`
+	Method   string          `json:"method"`
+	Duration int64           `json:"durationUs"` // the handling time in microseconds
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response"`
+}
+
+// recorder writes the sampled calls to the rotating capture files. The records are
+// queued to a single writer, the file is only accessed by the writer.
+type recorder struct {
+	logger  hclog.Logger
+	config  RecorderConfig
+	methods map[string]struct{} // all of them if empty
+
+	queue     chan []byte
+	dropped   uint64 // the records dropped since the last write, accessed atomically
+	closeCh   chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+
+	file *os.File
+	size int64
+}
+
+func newRecorder(logger hclog.Logger, config RecorderConfig) (*recorder, error) {
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		return nil, ErrInvalidRecordSampleRate
+	}
+
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	r := &recorder{
+		logger:  logger.Named("recorder"),
+		config:  config,
+		methods: make(map[string]struct{}, len(config.Methods)),
+		queue:   make(chan []byte, recordQueueSize),
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+
+	for _, method := range config.Methods {
+		if method != "" {
+			r.methods[method] = struct{}{}
+		}
+	}
+
+	if err := r.rotate(); err != nil {
+		return nil, err
+	}
+
+	go r.run()
+
+	return r, nil
+}
+
+// sampled returns whether the call of the method should be recorded. The privileged
+// calls are never recorded, since their params might be the keystore passwords.
+func (r *recorder) sampled(method string) bool {
+	if IsPrivilegedMethod(method) {
+		return false
+	}
+
+	if len(r.methods) > 0 {
+		if _, ok := r.methods[method]; !ok {
+			return false
+		}
+	}
+
+	//nolint:gosec
+	return r.config.SampleRate >= 1 || rand.Float64() < r.config.SampleRate
+}
+
+// record queues the call to the writer, the failure is only logged, since the
+// recording never fails or blocks the call
+func (r *recorder) record(req Request, resp Response, start time.Time) {
+	entry := &RecordEntry{
+		Time:     start,
+		Method:   req.Method,
+		Duration: time.Since(start).Microseconds(),
+	}
+
+	var err error
+
+	if entry.Request, err = json.Marshal(req); err != nil {
+		r.logger.Error("failed to marshal request", "method", req.Method, "err", err)
+
+		return
+	}
+
+	if entry.Response, err = json.Marshal(resp); err != nil {
+		r.logger.Error("failed to marshal response", "method", req.Method, "err", err)
+
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		r.logger.Error("failed to marshal record", "method", req.Method, "err", err)
+
+		return
+	}
+
+	r.enqueue(append(data, '\n'))
+}
+
+// enqueue queues the record to the writer, it is dropped if the queue is full
+// or the recorder is closed
+func (r *recorder) enqueue(data []byte) {
+	select {
+	case <-r.closeCh:
+		return
+	default:
+	}
+
+	select {
+	case r.queue <- data:
+	default:
+		atomic.AddUint64(&r.dropped, 1)
+	}
+}
+
+// run writes the queued records until closed, the queue is drained before exit
+func (r *recorder) run() {
+	defer close(r.doneCh)
+
+	for {
+		select {
+		case data := <-r.queue:
+			r.write(data)
+		case <-r.closeCh:
+			for {
+				select {
+				case data := <-r.queue:
+					r.write(data)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write writes the record to the capture file, which is rotated once full
+func (r *recorder) write(data []byte) {
+	if dropped := atomic.SwapUint64(&r.dropped, 0); dropped > 0 {
+		r.logger.Warn("dropped records of the full queue", "count", dropped)
+	}
+
+	if r.file == nil {
+		return
+	}
+
+	if r.size > 0 && r.config.MaxFileSize > 0 && r.size+int64(len(data)) > r.config.MaxFileSize {
+		if err := r.rotate(); err != nil {
+			r.logger.Error("failed to rotate capture file", "err", err)
+
+			return
+		}
+	}
+
+	n, err := r.file.Write(data)
+	r.size += int64(n)
+
+	if err != nil {
+		r.logger.Error("failed to write record", "err", err)
+	}
+}
+
+// rotate closes the current capture file, opens a new one, and removes the
+// oldest files out of the limit. It is called by the writer.
+func (r *recorder) rotate() error {
+	if r.file != nil {
+		if err := r.file.Close(); err != nil {
+			r.logger.Error("failed to close capture file", "err", err)
+		}
+
+		r.file = nil
+	}
+
+	name := recordFilePrefix + time.Now().UTC().Format(recordFileTimeFormat) + recordFileSuffix
+
+	file, err := os.OpenFile(filepath.Join(r.config.Dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+
+	r.file, r.size = file, 0
+
+	if r.config.MaxFiles <= 0 {
+		return nil
+	}
+
+	files, err := RecordFiles(r.config.Dir)
+	if err != nil {
+		return err
+	}
+
+	for len(files) > r.config.MaxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+
+		files = files[1:]
+	}
+
+	return nil
+}
+
+// close writes the queued records and closes the current capture file, nothing
+// is recorded afterwards
+func (r *recorder) close() error {
+	var err error
+
+	r.closeOnce.Do(func() {
+		close(r.closeCh)
+		<-r.doneCh
+
+		if r.file != nil {
+			err = r.file.Close()
+			r.file = nil
+		}
+	})
+
+	return err
+}
+
+// enableRecorder records the sampled calls to the capture files
+func (d *Dispatcher) enableRecorder(config RecorderConfig) error {
+	r, err := newRecorder(d.logger, config)
+	if err != nil {
+		return err
+	}
+
+	d.recorder = r
+
+	return nil
+}
+
+// closeRecorder stops the recording, if enabled
+func (d *Dispatcher) closeRecorder() error {
+	if d.recorder == nil {
+		return nil
+	}
+
+	return d.recorder.close()
+}
+
+// RecordFiles returns the capture files of the directory, from the oldest one
+func RecordFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, recordFilePrefix+"*"+recordFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// ReadRecords reads the records of the capture file or directory in order, until
+// the callback returns an error
+func ReadRecords(path string, fn func(*RecordEntry) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	files := []string{path}
+
+	if info.IsDir() {
+		if files, err = RecordFiles(path); err != nil {
+			return err
+		}
+
+		if len(files) == 0 {
+			return ErrNoRecordFiles
+		}
+	}
+
+	for _, file := range files {
+		if err := readRecordFile(file, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readRecordFile reads the records of the capture file. Only the records written
+// before it is opened are read, so that the capture being recorded is replayed
+// against the recording node without a loop.
+func readRecordFile(path string, fn func(*RecordEntry) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// the responses might be too large for a scanner
+	reader := bufio.NewReader(io.LimitReader(file, info.Size()))
+
+	for line := 1; ; line++ {
+		data, readErr := reader.ReadBytes('\n')
+		if errors.Is(readErr, io.EOF) {
+			// the last record without a line break is still being written
+			return nil
+		} else if readErr != nil {
+			return readErr
+		}
+
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		entry := &RecordEntry{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return fmt.Errorf("invalid record at %s:%d, %w", path, line, err)
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
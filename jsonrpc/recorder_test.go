@@ -0,0 +1,213 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRecordedDispatcher(t *testing.T, config RecorderConfig) *Dispatcher {
+	t.Helper()
+
+	d := newDispatcher(hclog.NewNullLogger(), NilMetrics(), newMockStore(), 0, 0, 0, 0, []Namespace{NamespaceWeb3})
+	assert.NoError(t, d.enableRecorder(config))
+
+	t.Cleanup(func() { assert.NoError(t, d.closeRecorder()) })
+
+	return d
+}
+
+func readAllRecords(t *testing.T, path string) []*RecordEntry {
+	t.Helper()
+
+	records := make([]*RecordEntry, 0)
+
+	assert.NoError(t, ReadRecords(path, func(entry *RecordEntry) error {
+		records = append(records, entry)
+
+		return nil
+	}))
+
+	return records
+}
+
+func TestRecorder_Record(t *testing.T) {
+	dir := t.TempDir()
+	d := newRecordedDispatcher(t, RecorderConfig{Dir: dir, SampleRate: 1})
+
+	resp, err := d.Handle([]byte(`{"id": 1, "method": "web3_clientVersion"}`))
+	assert.NoError(t, err)
+
+	// the batch requests are recorded one by one
+	_, err = d.Handle([]byte(`[{"id": 2, "method": "web3_sha3", "params": ["0x00"]}, {"id": 3, "method": "foo_bar"}]`))
+	assert.NoError(t, err)
+
+	// the queued records are written once closed
+	assert.NoError(t, d.closeRecorder())
+
+	records := readAllRecords(t, dir)
+	assert.Len(t, records, 3)
+
+	assert.Equal(t, "web3_clientVersion", records[0].Method)
+	assert.JSONEq(t, string(resp), string(records[0].Response))
+	assert.False(t, records[0].Time.IsZero())
+
+	req := &Request{}
+	assert.NoError(t, json.Unmarshal(records[1].Request, req))
+	assert.Equal(t, "web3_sha3", req.Method)
+	assert.JSONEq(t, `["0x00"]`, string(req.Params))
+
+	// the errors are recorded too
+	errResp := &ErrorResponse{}
+	assert.NoError(t, json.Unmarshal(records[2].Response, errResp))
+	assert.NotNil(t, errResp.Error)
+}
+
+func TestRecorder_Filter(t *testing.T) {
+	dir := t.TempDir()
+	d := newRecordedDispatcher(t, RecorderConfig{Dir: dir, SampleRate: 1, Methods: []string{"web3_sha3"}})
+
+	_, err := d.Handle([]byte(`{"id": 1, "method": "web3_clientVersion"}`))
+	assert.NoError(t, err)
+
+	_, err = d.Handle([]byte(`{"id": 2, "method": "web3_sha3", "params": ["0x00"]}`))
+	assert.NoError(t, err)
+
+	assert.NoError(t, d.closeRecorder())
+
+	records := readAllRecords(t, dir)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "web3_sha3", records[0].Method)
+	}
+
+	// the sample rate is validated
+	_, err = newRecorder(hclog.NewNullLogger(), RecorderConfig{Dir: dir, SampleRate: 0})
+	assert.ErrorIs(t, err, ErrInvalidRecordSampleRate)
+
+	_, err = newRecorder(hclog.NewNullLogger(), RecorderConfig{Dir: dir, SampleRate: 1.5})
+	assert.ErrorIs(t, err, ErrInvalidRecordSampleRate)
+}
+
+func TestRecorder_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	// a file holds a record at most
+	d := newRecordedDispatcher(t, RecorderConfig{Dir: dir, SampleRate: 1, MaxFileSize: 1, MaxFiles: 2})
+
+	for i := 0; i < 4; i++ {
+		_, err := d.Handle([]byte(`{"id": 1, "method": "web3_clientVersion"}`))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, d.closeRecorder())
+
+	// the oldest files are removed
+	files, err := RecordFiles(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	assert.Len(t, readAllRecords(t, dir), 2)
+	assert.Len(t, readAllRecords(t, files[0]), 1)
+
+	// the reading is stopped by the callback
+	stopErr := errors.New("stop")
+	count := 0
+
+	assert.ErrorIs(t, ReadRecords(dir, func(*RecordEntry) error {
+		count++
+
+		return stopErr
+	}), stopErr)
+	assert.Equal(t, 1, count)
+
+	assert.ErrorIs(t, ReadRecords(t.TempDir(), func(*RecordEntry) error { return nil }), ErrNoRecordFiles)
+}
+
+func TestRecorder_Privileged(t *testing.T) {
+	dir := t.TempDir()
+	store := newMockAccountStore(t)
+
+	d := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 0, 0, 0, 0, []Namespace{
+		NamespaceAll,
+		NamespacePersonal,
+	})
+	// the privileged methods are not recorded even if listed
+	assert.NoError(t, d.enableRecorder(RecorderConfig{
+		Dir:        dir,
+		SampleRate: 1,
+		Methods:    []string{"personal_newAccount", "eth_sign", "web3_clientVersion"},
+	}))
+
+	t.Cleanup(func() { assert.NoError(t, d.closeRecorder()) })
+
+	for _, req := range []string{
+		`{"id": 1, "method": "personal_newAccount", "params": ["secret-password"]}`,
+		`{"id": 2, "method": "eth_sign", "params": ["0x0000000000000000000000000000000000000001", "0x00"]}`,
+		`{"id": 3, "method": "web3_clientVersion"}`,
+	} {
+		_, err := d.Handle([]byte(req))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, d.closeRecorder())
+
+	records := readAllRecords(t, dir)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "web3_clientVersion", records[0].Method)
+	}
+
+	files, err := RecordFiles(dir)
+	assert.NoError(t, err)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(data), "secret-password")
+	}
+}
+
+func TestRecorder_Overflow(t *testing.T) {
+	// the writer is not started, so that the queue is never drained
+	r := &recorder{
+		queue:   make(chan []byte, 1),
+		closeCh: make(chan struct{}),
+	}
+
+	r.enqueue([]byte("a"))
+	r.enqueue([]byte("b"))
+
+	assert.Len(t, r.queue, 1)
+	assert.Equal(t, uint64(1), r.dropped)
+
+	// nothing is queued once closed
+	close(r.closeCh)
+	<-r.queue
+
+	r.enqueue([]byte("c"))
+	assert.Len(t, r.queue, 0)
+
+	// the calls are still served after the recorder is closed
+	dir := t.TempDir()
+	d := newRecordedDispatcher(t, RecorderConfig{Dir: dir, SampleRate: 1})
+
+	assert.NoError(t, d.closeRecorder())
+
+	resp, err := d.Handle([]byte(`{"id": 1, "method": "web3_clientVersion"}`))
+	assert.NoError(t, err)
+	assert.Contains(t, string(resp), "result")
+
+	assert.Len(t, readAllRecords(t, dir), 0)
+}
+
+func TestIsStateChangingMethod(t *testing.T) {
+	assert.True(t, IsStateChangingMethod("eth_sendRawTransaction"))
+	assert.True(t, IsStateChangingMethod("eth_newFilter"))
+	assert.False(t, IsStateChangingMethod("eth_getBlockByNumber"))
+
+	assert.True(t, IsPrivilegedMethod("personal_newAccount"))
+	assert.True(t, IsPrivilegedMethod("eth_sendTransaction"))
+	assert.False(t, IsPrivilegedMethod("eth_call"))
+}
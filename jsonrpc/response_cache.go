@@ -0,0 +1,237 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+// cacheableMethods are the methods whose responses never change once the block
+// is sealed, since the blocks of ibft are final instantly. The value is the index
+// of the block number (or hash) param to be resolved, -1 if there is none. The
+// missing block param is the latest block.
+var cacheableMethods = map[string]int{
+	"eth_getBlockByHash":        -1,
+	"eth_getTransactionByHash":  -1,
+	"eth_getTransactionReceipt": -1,
+	"eth_getBlockByNumber":      0,
+	"eth_getBlockReceipts":      0,
+	"eth_call":                  1,
+	"eth_getBalance":            1,
+}
+
+// responseCache is the lru cache of the serialized responses, which is bounded
+// by both the number of the entries and the total bytes of the responses. The
+// responses of the queries relative to the latest block are dropped once the
+// head changes.
+type responseCache struct {
+	lock sync.Mutex
+
+	entries  *simplelru.LRU
+	maxBytes int
+	size     int
+
+	head         types.Hash
+	headRelative map[string]struct{}
+}
+
+func newResponseCache(maxEntries, maxBytes int) (*responseCache, error) {
+	c := &responseCache{
+		maxBytes:     maxBytes,
+		headRelative: make(map[string]struct{}),
+	}
+
+	entries, err := simplelru.NewLRU(maxEntries, c.onEvict)
+	if err != nil {
+		return nil, err
+	}
+
+	c.entries = entries
+
+	return c, nil
+}
+
+// get returns the cached response of the key
+func (c *responseCache) get(key string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	data, ok := c.entries.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	//nolint:forcetypeassert
+	return data.([]byte), true
+}
+
+// add caches the response of the key, the response larger than the budget is skipped
+func (c *responseCache) add(key string, data []byte, headRelative bool) {
+	if len(key)+len(data) > c.maxBytes {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.entries.Contains(key) {
+		return
+	}
+
+	c.entries.Add(key, data)
+	c.size += len(key) + len(data)
+
+	if headRelative {
+		c.headRelative[key] = struct{}{}
+	}
+
+	for c.size > c.maxBytes {
+		c.entries.RemoveOldest()
+	}
+}
+
+// setHead drops the responses relative to the previous head once the head changes
+func (c *responseCache) setHead(head types.Hash) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.head == head {
+		return
+	}
+
+	c.head = head
+
+	for key := range c.headRelative {
+		c.entries.Remove(key)
+	}
+}
+
+// onEvict keeps the size and the head relative keys up to date, the lock is held
+// by the caller
+func (c *responseCache) onEvict(key interface{}, value interface{}) {
+	//nolint:forcetypeassert
+	k, data := key.(string), value.([]byte)
+
+	c.size -= len(k) + len(data)
+	delete(c.headRelative, k)
+}
+
+// enableResponseCache caches the responses of the immutable queries, bounded by
+// the number of the entries and the total bytes
+func (d *Dispatcher) enableResponseCache(maxEntries, maxBytes int) error {
+	cache, err := newResponseCache(maxEntries, maxBytes)
+	if err != nil {
+		return err
+	}
+
+	d.responseCache = cache
+
+	return nil
+}
+
+// responseCacheKey returns the cache key of the request, which is the method, the
+// normalized params and the resolved block. The block param relative to the latest
+// block is replaced by the resolved number, so that the response matches the key.
+func (d *Dispatcher) responseCacheKey(method string, inputs []interface{}) (string, bool, bool) {
+	blockParam, ok := cacheableMethods[method]
+	if !ok || d.responseCache == nil {
+		return "", false, false
+	}
+
+	head := d.endpoints.Eth.store.Header()
+
+	d.responseCache.setHead(head.Hash)
+
+	var (
+		block        types.Hash
+		headRelative bool
+	)
+
+	if blockParam >= len(inputs) {
+		// the params are shrunk to the sent ones, the missing block param is
+		// the latest block by default
+		block, headRelative = head.Hash, true
+	} else if blockParam >= 0 {
+		header, relative, ok := d.resolveCacheBlock(inputs[blockParam])
+		if !ok {
+			return "", false, false
+		}
+
+		block, headRelative = header.Hash, relative
+	}
+
+	params, err := json.Marshal(inputs)
+	if err != nil {
+		return "", false, false
+	}
+
+	return method + "@" + block.String() + string(params), headRelative, true
+}
+
+// resolveCacheBlock resolves the header of the block param, and whether it is relative
+// to the latest block. The pending block is never cached.
+func (d *Dispatcher) resolveCacheBlock(param interface{}) (*types.Header, bool, bool) {
+	eth := d.endpoints.Eth
+
+	switch v := param.(type) {
+	case *BlockNumber:
+		if *v == PendingBlockNumber {
+			return nil, false, false
+		}
+
+		header, err := eth.getBlockHeader(*v)
+		if err != nil {
+			return nil, false, false
+		}
+
+		relative := *v < 0 && *v != EarliestBlockNumber
+		*v = BlockNumber(header.Number)
+
+		return header, relative, true
+	case *BlockNumberOrHash:
+		if v.BlockHash != nil {
+			header, ok := eth.store.GetHeaderByHash(*v.BlockHash)
+
+			return header, false, ok
+		}
+
+		// the latest block by default
+		number := LatestBlockNumber
+		if v.BlockNumber != nil {
+			number = *v.BlockNumber
+		}
+
+		if number == PendingBlockNumber {
+			return nil, false, false
+		}
+
+		header, err := eth.getBlockHeader(number)
+		if err != nil {
+			return nil, false, false
+		}
+
+		resolved := BlockNumber(header.Number)
+		v.BlockNumber = &resolved
+
+		return header, number < 0 && number != EarliestBlockNumber, true
+	}
+
+	return nil, false, false
+}
+
+// isCacheableResponse returns whether the response is final, the missing objects
+// and the pending transactions might change later
+func isCacheableResponse(res interface{}, data []byte) bool {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return false
+	}
+
+	if tx, ok := res.(*transaction); ok && tx.BlockHash == nil {
+		return false
+	}
+
+	return true
+}
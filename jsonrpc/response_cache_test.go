@@ -0,0 +1,252 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/state"
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCacheStore counts the block queries hitting the store
+type mockCacheStore struct {
+	*mockStore
+
+	blocks  []*types.Block
+	queries int
+}
+
+func newMockCacheStore(blocks int) *mockCacheStore {
+	m := &mockCacheStore{mockStore: newMockStore()}
+
+	for i := 0; i < blocks; i++ {
+		m.addBlock()
+	}
+
+	return m
+}
+
+func (m *mockCacheStore) addBlock() {
+	header := &types.Header{
+		Number:    uint64(len(m.blocks)),
+		ExtraData: []byte{},
+		StateRoot: types.Hash{byte(len(m.blocks))},
+	}
+	header.ComputeHash()
+
+	m.blocks = append(m.blocks, &types.Block{Header: header})
+}
+
+func (m *mockCacheStore) Header() *types.Header {
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+func (m *mockCacheStore) GetHeaderByNumber(num uint64) (*types.Header, bool) {
+	if num >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+
+	return m.blocks[num].Header, true
+}
+
+func (m *mockCacheStore) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
+	for _, block := range m.blocks {
+		if block.Hash() == hash {
+			return block.Header, true
+		}
+	}
+
+	return nil, false
+}
+
+func (m *mockCacheStore) GetBlockByNumber(num uint64, full bool) (*types.Block, bool) {
+	m.queries++
+
+	if num >= uint64(len(m.blocks)) {
+		return nil, false
+	}
+
+	return m.blocks[num], true
+}
+
+func (m *mockCacheStore) GetBlockByHash(hash types.Hash, full bool) (*types.Block, bool) {
+	m.queries++
+
+	header, ok := m.GetHeaderByHash(hash)
+	if !ok {
+		return nil, false
+	}
+
+	return m.blocks[header.Number], true
+}
+
+// GetAccount returns the balance of the number of the block by its state root
+func (m *mockCacheStore) GetAccount(root types.Hash, addr types.Address) (*state.Account, error) {
+	m.queries++
+
+	return &state.Account{Balance: big.NewInt(int64(root[0]))}, nil
+}
+
+// ApplyTxn returns the number of the block
+func (m *mockCacheStore) ApplyTxn(header *types.Header, txn *types.Transaction) (*runtime.ExecutionResult, error) {
+	m.queries++
+
+	return &runtime.ExecutionResult{ReturnValue: []byte{byte(header.Number)}}, nil
+}
+
+func newCachedDispatcher(t *testing.T, store *mockCacheStore, maxEntries, maxBytes int) *Dispatcher {
+	t.Helper()
+
+	d := newDispatcher(hclog.NewNullLogger(), NilMetrics(), store, 0, 0, 0, 0, []Namespace{NamespaceEth})
+	assert.NoError(t, d.enableResponseCache(maxEntries, maxBytes))
+
+	return d
+}
+
+func handleCachedRequest(t *testing.T, d *Dispatcher, method string, params ...interface{}) *block {
+	t.Helper()
+
+	var res *block
+
+	assert.NoError(t, json.Unmarshal(handleCachedResult(t, d, method, params...), &res))
+
+	return res
+}
+
+func handleCachedResult(t *testing.T, d *Dispatcher, method string, params ...interface{}) json.RawMessage {
+	t.Helper()
+
+	rawParams, err := json.Marshal(params)
+	assert.NoError(t, err)
+
+	data, err := d.Handle([]byte(fmt.Sprintf(`{"method": "%s", "params": %s, "id": 1}`, method, rawParams)))
+	assert.NoError(t, err)
+
+	resp := &SuccessResponse{}
+	assert.NoError(t, json.Unmarshal(data, resp))
+	assert.Nil(t, resp.Error)
+
+	return resp.Result
+}
+
+func TestResponseCache_Immutable(t *testing.T) {
+	store := newMockCacheStore(3)
+	d := newCachedDispatcher(t, store, 100, 1<<20)
+
+	first := handleCachedRequest(t, d, "eth_getBlockByNumber", "0x1", false)
+	second := handleCachedRequest(t, d, "eth_getBlockByNumber", "0x01", false)
+
+	assert.Equal(t, store.blocks[1].Hash(), first.Hash)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, store.queries)
+
+	// the other params are not cached together
+	handleCachedRequest(t, d, "eth_getBlockByNumber", "0x1", true)
+	assert.Equal(t, 2, store.queries)
+
+	handleCachedRequest(t, d, "eth_getBlockByHash", store.blocks[2].Hash(), false)
+	handleCachedRequest(t, d, "eth_getBlockByHash", store.blocks[2].Hash(), false)
+	assert.Equal(t, 3, store.queries)
+
+	// the missing blocks might be found later
+	assert.Nil(t, handleCachedRequest(t, d, "eth_getBlockByNumber", "0x5", false))
+	assert.Nil(t, handleCachedRequest(t, d, "eth_getBlockByNumber", "0x5", false))
+	assert.Equal(t, 5, store.queries)
+}
+
+func TestResponseCache_Latest(t *testing.T) {
+	store := newMockCacheStore(2)
+	d := newCachedDispatcher(t, store, 100, 1<<20)
+
+	res := handleCachedRequest(t, d, "eth_getBlockByNumber", "latest", false)
+	assert.Equal(t, store.blocks[1].Hash(), res.Hash)
+
+	res = handleCachedRequest(t, d, "eth_getBlockByNumber", "latest", false)
+	assert.Equal(t, store.blocks[1].Hash(), res.Hash)
+	assert.Equal(t, 1, store.queries)
+	assert.Len(t, d.responseCache.headRelative, 1)
+
+	// the latest responses are dropped on the new head
+	store.addBlock()
+
+	res = handleCachedRequest(t, d, "eth_getBlockByNumber", "latest", false)
+	assert.Equal(t, store.blocks[2].Hash(), res.Hash)
+	assert.Equal(t, 2, store.queries)
+	assert.Len(t, d.responseCache.headRelative, 1)
+	assert.Equal(t, 1, d.responseCache.entries.Len())
+
+	// the pending block is never cached
+	d.handleReq(Request{Method: "eth_getBlockByNumber", Params: []byte(`["pending", false]`)})
+	d.handleReq(Request{Method: "eth_getBlockByNumber", Params: []byte(`["pending", false]`)})
+	assert.Equal(t, 1, d.responseCache.entries.Len())
+}
+
+func TestResponseCache_Budget(t *testing.T) {
+	store := newMockCacheStore(3)
+
+	// only a response fits the budget
+	data, err := json.Marshal(toBlock(store.blocks[0], false))
+	assert.NoError(t, err)
+
+	d := newCachedDispatcher(t, store, 100, len(data)+200)
+
+	handleCachedRequest(t, d, "eth_getBlockByNumber", "0x0", false)
+	handleCachedRequest(t, d, "eth_getBlockByNumber", "0x1", false)
+	assert.Equal(t, 1, d.responseCache.entries.Len())
+	assert.LessOrEqual(t, d.responseCache.size, d.responseCache.maxBytes)
+
+	// the oldest one is evicted
+	handleCachedRequest(t, d, "eth_getBlockByNumber", "0x1", false)
+	assert.Equal(t, 2, store.queries)
+
+	handleCachedRequest(t, d, "eth_getBlockByNumber", "0x0", false)
+	assert.Equal(t, 3, store.queries)
+
+	// bounded by the number of the entries too
+	d = newCachedDispatcher(t, store, 2, 1<<20)
+
+	for i := 0; i < 3; i++ {
+		handleCachedRequest(t, d, "eth_getBlockByNumber", fmt.Sprintf("0x%x", i), false)
+	}
+
+	assert.Equal(t, 2, d.responseCache.entries.Len())
+}
+
+func TestResponseCache_MissingBlockParam(t *testing.T) {
+	store := newMockCacheStore(2)
+	d := newCachedDispatcher(t, store, 100, 1<<20)
+
+	call := map[string]interface{}{"to": types.StringToAddress("0x1")}
+	account := types.StringToAddress("0x2")
+
+	// the missing block param is the latest block
+	assert.JSONEq(t, `"0x01"`, string(handleCachedResult(t, d, "eth_call", call)))
+	assert.JSONEq(t, `"0x01"`, string(handleCachedResult(t, d, "eth_call", call)))
+	assert.JSONEq(t, `"0x1"`, string(handleCachedResult(t, d, "eth_getBalance", account)))
+	assert.JSONEq(t, `"0x1"`, string(handleCachedResult(t, d, "eth_getBalance", account)))
+	assert.Equal(t, 2, store.queries)
+	assert.Len(t, d.responseCache.headRelative, 2)
+
+	// the responses are dropped on the new head
+	store.addBlock()
+
+	assert.JSONEq(t, `"0x02"`, string(handleCachedResult(t, d, "eth_call", call)))
+	assert.JSONEq(t, `"0x2"`, string(handleCachedResult(t, d, "eth_getBalance", account)))
+	assert.Equal(t, 4, store.queries)
+
+	// the explicit block is never dropped
+	assert.JSONEq(t, `"0x01"`, string(handleCachedResult(t, d, "eth_call", call, "0x1")))
+	assert.JSONEq(t, `"0x1"`, string(handleCachedResult(t, d, "eth_getBalance", account, "0x1")))
+	assert.Equal(t, 6, store.queries)
+
+	store.addBlock()
+
+	assert.JSONEq(t, `"0x01"`, string(handleCachedResult(t, d, "eth_call", call, "0x1")))
+	assert.JSONEq(t, `"0x1"`, string(handleCachedResult(t, d, "eth_getBalance", account, "0x1")))
+	assert.Equal(t, 6, store.queries)
+}
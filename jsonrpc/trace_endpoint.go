@@ -0,0 +1,441 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/tracer/flat"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	ErrUnsupportedTraceType = errors.New("unsupported trace type")
+)
+
+const (
+	// traceTypeTrace is the flat call traces type of trace_replayBlockTransactions,
+	// the vmTrace and stateDiff types are not supported
+	traceTypeTrace = "trace"
+)
+
+type traceStore interface {
+	ethStore
+
+	// ReadBlockTraces returns the flat call traces recorded when the block was written
+	ReadBlockTraces(hash types.Hash) ([]*flat.TxTraces, bool)
+}
+
+// Trace is the parity style trace jsonrpc endpoint. The traces are read from the
+// trace store if recorded, otherwise the block is replayed.
+type Trace struct {
+	store traceStore
+	eth   *Eth
+	// blockRangeLimit is the max number of blocks a filter could query, 0 means no limit
+	blockRangeLimit uint64
+
+	metrics *Metrics
+}
+
+// traceFilter is the filter of trace_filter, the traces matching any of the from
+// addresses and any of the to addresses are returned
+type traceFilter struct {
+	FromBlock   *BlockNumber    `json:"fromBlock"`
+	ToBlock     *BlockNumber    `json:"toBlock"`
+	FromAddress []types.Address `json:"fromAddress"`
+	ToAddress   []types.Address `json:"toAddress"`
+	After       *uint64         `json:"after"`
+	Count       *uint64         `json:"count"`
+}
+
+type traceAction struct {
+	CallType string         `json:"callType,omitempty"`
+	From     types.Address  `json:"from"`
+	To       *types.Address `json:"to,omitempty"`
+	Gas      argUint64      `json:"gas"`
+	Input    *argBytes      `json:"input,omitempty"`
+	Init     *argBytes      `json:"init,omitempty"`
+	Value    argBig         `json:"value"`
+}
+
+type traceResult struct {
+	GasUsed argUint64      `json:"gasUsed"`
+	Output  *argBytes      `json:"output,omitempty"`
+	Address *types.Address `json:"address,omitempty"`
+	Code    *argBytes      `json:"code,omitempty"`
+}
+
+// localizedTrace is a parity style trace, the block and transaction fields are
+// omitted in the results of trace_replayBlockTransactions
+type localizedTrace struct {
+	Action              traceAction  `json:"action"`
+	BlockHash           *types.Hash  `json:"blockHash,omitempty"`
+	BlockNumber         *uint64      `json:"blockNumber,omitempty"`
+	Result              *traceResult `json:"result"`
+	Error               string       `json:"error,omitempty"`
+	Subtraces           int          `json:"subtraces"`
+	TraceAddress        []int        `json:"traceAddress"`
+	TransactionHash     *types.Hash  `json:"transactionHash,omitempty"`
+	TransactionPosition *uint64      `json:"transactionPosition,omitempty"`
+	Type                string       `json:"type"`
+}
+
+// replayResult is the result of a transaction of trace_replayBlockTransactions
+type replayResult struct {
+	Output          argBytes          `json:"output"`
+	StateDiff       interface{}       `json:"stateDiff"`
+	Trace           []*localizedTrace `json:"trace"`
+	VMTrace         interface{}       `json:"vmTrace"`
+	TransactionHash types.Hash        `json:"transactionHash"`
+}
+
+// Block returns the traces of all the transactions of the block
+func (t *Trace) Block(number BlockNumber) (interface{}, error) {
+	t.metrics.TraceAPICounterInc(TraceBlockLabel)
+
+	num, err := GetNumericBlockNumber(number, t.eth)
+	if err != nil {
+		return nil, err
+	}
+
+	block, ok := t.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	txTraces, err := t.blockTraces(block)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*localizedTrace, 0)
+
+	for _, txTrace := range txTraces {
+		for _, trace := range txTrace.Traces {
+			results = append(results, newLocalizedTrace(trace, block, txTrace))
+		}
+	}
+
+	return results, nil
+}
+
+// Transaction returns the traces of the transaction
+func (t *Trace) Transaction(hash types.Hash) (interface{}, error) {
+	t.metrics.TraceAPICounterInc(TraceTransactionLabel)
+
+	blockHash, ok := t.store.ReadTxLookup(hash)
+	if !ok {
+		return nil, nil
+	}
+
+	block, ok := t.store.GetBlockByHash(blockHash, true)
+	if !ok {
+		return nil, ErrTransactionNotSeal
+	}
+
+	txTraces, err := t.blockTraces(block)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*localizedTrace, 0)
+
+	for _, txTrace := range txTraces {
+		if txTrace.TxHash != hash {
+			continue
+		}
+
+		for _, trace := range txTrace.Traces {
+			results = append(results, newLocalizedTrace(trace, block, txTrace))
+		}
+	}
+
+	return results, nil
+}
+
+// Filter returns the traces in the block range matching the addresses
+func (t *Trace) Filter(filter *traceFilter) (interface{}, error) {
+	t.metrics.TraceAPICounterInc(TraceFilterLabel)
+
+	if filter == nil {
+		filter = &traceFilter{}
+	}
+
+	// the latest block by default
+	from := t.store.Header().Number
+	to := from
+
+	var err error
+
+	if filter.FromBlock != nil {
+		if from, err = GetNumericBlockNumber(*filter.FromBlock, t.eth); err != nil {
+			return nil, err
+		}
+	}
+
+	if filter.ToBlock != nil {
+		if to, err = GetNumericBlockNumber(*filter.ToBlock, t.eth); err != nil {
+			return nil, err
+		}
+	}
+
+	if to < from {
+		return nil, ErrIncorrectBlockRange
+	}
+
+	// if not disabled, avoid handling large block ranges
+	if t.blockRangeLimit > 0 && to-from > t.blockRangeLimit {
+		return nil, ErrBlockRangeTooHigh
+	}
+
+	var (
+		after   uint64
+		results = make([]*localizedTrace, 0)
+	)
+
+	if filter.After != nil {
+		after = *filter.After
+	}
+
+	for num := from; num <= to; num++ {
+		block, ok := t.store.GetBlockByNumber(num, true)
+		if !ok {
+			return nil, fmt.Errorf("block %d not found", num)
+		}
+
+		txTraces, err := t.blockTraces(block)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, txTrace := range txTraces {
+			for _, trace := range txTrace.Traces {
+				if !filter.match(trace) {
+					continue
+				}
+
+				if after > 0 {
+					after--
+
+					continue
+				}
+
+				if filter.Count != nil && uint64(len(results)) >= *filter.Count {
+					return results, nil
+				}
+
+				results = append(results, newLocalizedTrace(trace, block, txTrace))
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// ReplayBlockTransactions replays all the transactions of the block, and returns
+// the traces of the given types
+func (t *Trace) ReplayBlockTransactions(number BlockNumber, traceTypes []string) (interface{}, error) {
+	t.metrics.TraceAPICounterInc(TraceReplayBlockTransactionsLabel)
+
+	withTrace := false
+
+	for _, traceType := range traceTypes {
+		if traceType != traceTypeTrace {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedTraceType, traceType)
+		}
+
+		withTrace = true
+	}
+
+	num, err := GetNumericBlockNumber(number, t.eth)
+	if err != nil {
+		return nil, err
+	}
+
+	block, ok := t.store.GetBlockByNumber(num, true)
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", num)
+	}
+
+	txTraces, outputs, err := t.replayBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*replayResult, len(txTraces))
+
+	for i, txTrace := range txTraces {
+		results[i] = &replayResult{
+			Output:          outputs[i],
+			Trace:           make([]*localizedTrace, 0),
+			TransactionHash: txTrace.TxHash,
+		}
+
+		if !withTrace {
+			continue
+		}
+
+		for _, trace := range txTrace.Traces {
+			results[i].Trace = append(results[i].Trace, newLocalizedTrace(trace, nil, nil))
+		}
+	}
+
+	return results, nil
+}
+
+// blockTraces returns the recorded traces of the block, or replays it if not
+// recorded
+func (t *Trace) blockTraces(block *types.Block) ([]*flat.TxTraces, error) {
+	// nothing executed in the genesis
+	if block.Number() == 0 {
+		return nil, nil
+	}
+
+	if txTraces, ok := t.store.ReadBlockTraces(block.Hash()); ok {
+		return txTraces, nil
+	}
+
+	txTraces, _, err := t.replayBlock(block)
+
+	return txTraces, err
+}
+
+// replayBlock replays all the transactions of the block on top of its parent state,
+// and returns their traces and outputs. The transactions exceeding the block gas
+// limit are never executed, so that they are skipped.
+func (t *Trace) replayBlock(block *types.Block) ([]*flat.TxTraces, [][]byte, error) {
+	if block.Number() == 0 {
+		return nil, nil, ErrGenesisNotTracable
+	}
+
+	txTraces := make([]*flat.TxTraces, 0, len(block.Transactions))
+	outputs := make([][]byte, 0, len(block.Transactions))
+
+	if len(block.Transactions) == 0 {
+		return txTraces, outputs, nil
+	}
+
+	txn, err := t.store.StateAtTransaction(block, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tracer := flat.NewTracer()
+
+	txn.SetEVMLogger(tracer)
+	defer txn.SetEVMLogger(runtime.NewDummyLogger())
+
+	for idx, tx := range block.Transactions {
+		if tx.ExceedsBlockGasLimit(block.Header.GasLimit) {
+			continue
+		}
+
+		result, err := txn.Apply(tx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tracing failed: %w", err)
+		}
+
+		traces := tracer.Traces()
+
+		txTraces = append(txTraces, &flat.TxTraces{
+			TxHash:     tx.Hash(),
+			TxPosition: uint64(idx),
+			Traces:     traces[len(traces)-1],
+		})
+		outputs = append(outputs, result.ReturnValue)
+	}
+
+	return txTraces, outputs, nil
+}
+
+// match checks whether the trace matches the addresses of the filter
+func (f *traceFilter) match(trace *flat.Trace) bool {
+	if len(f.FromAddress) > 0 && !containsAddress(f.FromAddress, trace.Action.From) {
+		return false
+	}
+
+	if len(f.ToAddress) == 0 {
+		return true
+	}
+
+	// the created contract is the callee of a creation
+	to := trace.Action.To
+	if to == nil && trace.Result != nil {
+		to = trace.Result.Address
+	}
+
+	return to != nil && containsAddress(f.ToAddress, *to)
+}
+
+func containsAddress(addrs []types.Address, addr types.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newLocalizedTrace formats the trace in the parity style, the block and the
+// transaction fields are omitted if not given
+func newLocalizedTrace(trace *flat.Trace, block *types.Block, txTrace *flat.TxTraces) *localizedTrace {
+	value := trace.Action.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	res := &localizedTrace{
+		Action: traceAction{
+			CallType: trace.Action.CallType,
+			From:     trace.Action.From,
+			To:       trace.Action.To,
+			Gas:      argUint64(trace.Action.Gas),
+			Value:    argBig(*value),
+		},
+		Error:        trace.Error,
+		Subtraces:    trace.Subtraces,
+		TraceAddress: trace.TraceAddress,
+		Type:         trace.Type,
+	}
+
+	if res.TraceAddress == nil {
+		res.TraceAddress = []int{}
+	}
+
+	if trace.Type == flat.TraceTypeCreate {
+		res.Action.Init = argBytesPtr(trace.Action.Input)
+	} else {
+		res.Action.Input = argBytesPtr(trace.Action.Input)
+	}
+
+	if trace.Result != nil {
+		res.Result = &traceResult{
+			GasUsed: argUint64(trace.Result.GasUsed),
+		}
+
+		if trace.Type == flat.TraceTypeCreate {
+			res.Result.Address = trace.Result.Address
+			res.Result.Code = argBytesPtr(trace.Result.Output)
+		} else {
+			res.Result.Output = argBytesPtr(trace.Result.Output)
+		}
+	}
+
+	if block != nil {
+		hash, number := block.Hash(), block.Number()
+
+		res.BlockHash = &hash
+		res.BlockNumber = &number
+	}
+
+	if txTrace != nil {
+		hash, position := txTrace.TxHash, txTrace.TxPosition
+
+		res.TransactionHash = &hash
+		res.TransactionPosition = &position
+	}
+
+	return res
+}
@@ -0,0 +1,302 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/state/tracer/flat"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockFlatTraceStore replays the blocks on top of the mock trace store, unless the
+// traces of the block are recorded
+type mockFlatTraceStore struct {
+	*mockTraceStore
+
+	traces map[types.Hash][]*flat.TxTraces
+}
+
+func newMockFlatTraceStore(t *testing.T) *mockFlatTraceStore {
+	t.Helper()
+
+	store := newMockTraceStore(t)
+	genesis := store.blocks[0]
+
+	block := &types.Block{
+		Header: &types.Header{
+			Number:     1,
+			ParentHash: genesis.Hash(),
+			GasLimit:   10000000,
+			Miner:      traceCoinbase,
+		},
+		Transactions: []*types.Transaction{
+			{
+				Nonce:    0,
+				From:     traceSender,
+				To:       &traceReceiver,
+				Gas:      21000,
+				GasPrice: big.NewInt(0),
+				Value:    big.NewInt(1),
+			},
+			{
+				Nonce:    1,
+				From:     traceSender,
+				To:       &traceContract,
+				Gas:      100000,
+				GasPrice: big.NewInt(0),
+				Value:    big.NewInt(0),
+			},
+		},
+	}
+	block.Header.ComputeHash()
+
+	store.blocks = []*types.Block{genesis, block}
+
+	return &mockFlatTraceStore{
+		mockTraceStore: store,
+		traces:         make(map[types.Hash][]*flat.TxTraces),
+	}
+}
+
+func (m *mockFlatTraceStore) Header() *types.Header {
+	return m.blocks[len(m.blocks)-1].Header
+}
+
+func (m *mockFlatTraceStore) ReadTxLookup(hash types.Hash) (types.Hash, bool) {
+	for _, b := range m.blocks {
+		for _, tx := range b.Transactions {
+			if tx.Hash() == hash {
+				return b.Hash(), true
+			}
+		}
+	}
+
+	return types.ZeroHash, false
+}
+
+func (m *mockFlatTraceStore) ReadBlockTraces(hash types.Hash) ([]*flat.TxTraces, bool) {
+	traces, ok := m.traces[hash]
+
+	return traces, ok
+}
+
+func newTestTraceEndpoint(store traceStore, blockRangeLimit uint64) *Trace {
+	eth := &Eth{
+		store:   store,
+		chainID: 100,
+		metrics: NilMetrics(),
+	}
+
+	return &Trace{store, eth, blockRangeLimit, NilMetrics()}
+}
+
+func TestTrace_Block(t *testing.T) {
+	store := newMockFlatTraceStore(t)
+	endpoint := newTestTraceEndpoint(store, 0)
+	block := store.blocks[1]
+
+	// nothing in the genesis
+	res, err := endpoint.Block(EarliestBlockNumber)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+
+	res, err = endpoint.Block(LatestBlockNumber)
+	assert.NoError(t, err)
+
+	traces, ok := res.([]*localizedTrace)
+	assert.True(t, ok)
+	assert.Len(t, traces, 2)
+
+	for i, trace := range traces {
+		assert.Equal(t, flat.TraceTypeCall, trace.Type)
+		assert.Equal(t, "call", trace.Action.CallType)
+		assert.Equal(t, traceSender, trace.Action.From)
+		assert.Equal(t, block.Hash(), *trace.BlockHash)
+		assert.Equal(t, uint64(1), *trace.BlockNumber)
+		assert.Equal(t, block.Transactions[i].Hash(), *trace.TransactionHash)
+		assert.Equal(t, uint64(i), *trace.TransactionPosition)
+		assert.Empty(t, trace.TraceAddress)
+		assert.NotNil(t, trace.Result)
+	}
+
+	assert.Equal(t, traceReceiver, *traces[0].Action.To)
+	assert.Equal(t, traceContract, *traces[1].Action.To)
+	assert.Equal(t, types.BytesToHash([]byte{0x2a}).Bytes(), []byte(*traces[1].Result.Output))
+
+	// the recorded traces are never replayed
+	store.traces[block.Hash()] = []*flat.TxTraces{
+		{
+			TxHash:     block.Transactions[1].Hash(),
+			TxPosition: 1,
+			Traces: []*flat.Trace{
+				{
+					Type:   flat.TraceTypeCall,
+					Action: flat.Action{CallType: "call", From: traceSender, To: &traceContract},
+					Error:  "Reverted",
+				},
+			},
+		},
+	}
+
+	res, err = endpoint.Block(BlockNumber(1))
+	assert.NoError(t, err)
+
+	traces, ok = res.([]*localizedTrace)
+	assert.True(t, ok)
+
+	if assert.Len(t, traces, 1) {
+		assert.Equal(t, "Reverted", traces[0].Error)
+		assert.Nil(t, traces[0].Result)
+		assert.Equal(t, uint64(1), *traces[0].TransactionPosition)
+	}
+
+	// unknown block
+	_, err = endpoint.Block(BlockNumber(2))
+	assert.Error(t, err)
+}
+
+func TestTrace_Transaction(t *testing.T) {
+	store := newMockFlatTraceStore(t)
+	endpoint := newTestTraceEndpoint(store, 0)
+	tx := store.blocks[1].Transactions[1]
+
+	res, err := endpoint.Transaction(tx.Hash())
+	assert.NoError(t, err)
+
+	traces, ok := res.([]*localizedTrace)
+	assert.True(t, ok)
+
+	if assert.Len(t, traces, 1) {
+		assert.Equal(t, tx.Hash(), *traces[0].TransactionHash)
+		assert.Equal(t, traceContract, *traces[0].Action.To)
+	}
+
+	// unknown transaction
+	res, err = endpoint.Transaction(types.StringToHash("0x1"))
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+}
+
+func TestTrace_Filter(t *testing.T) {
+	store := newMockFlatTraceStore(t)
+	endpoint := newTestTraceEndpoint(store, 0)
+	block := store.blocks[1]
+
+	filter := func(t *testing.T, input string) []*localizedTrace {
+		t.Helper()
+
+		var f *traceFilter
+		assert.NoError(t, json.Unmarshal([]byte(input), &f))
+
+		res, err := endpoint.Filter(f)
+		assert.NoError(t, err)
+
+		traces, ok := res.([]*localizedTrace)
+		assert.True(t, ok)
+
+		return traces
+	}
+
+	// the latest block by default
+	assert.Len(t, filter(t, `{}`), 2)
+	assert.Len(t, filter(t, `{"fromBlock": "earliest"}`), 2)
+
+	traces := filter(t, `{"fromBlock": "0x0", "toBlock": "0x1", "toAddress": ["`+traceContract.String()+`"]}`)
+	if assert.Len(t, traces, 1) {
+		assert.Equal(t, block.Transactions[1].Hash(), *traces[0].TransactionHash)
+	}
+
+	assert.Len(t, filter(t, `{"fromAddress": ["`+traceSender.String()+`"]}`), 2)
+	assert.Len(t, filter(t, `{"fromAddress": ["`+traceReceiver.String()+`"]}`), 0)
+
+	// pagination
+	traces = filter(t, `{"after": 1, "count": 1}`)
+	if assert.Len(t, traces, 1) {
+		assert.Equal(t, uint64(1), *traces[0].TransactionPosition)
+	}
+
+	assert.Len(t, filter(t, `{"count": 1}`), 1)
+
+	// no filter at all
+	_, err := endpoint.Filter(nil)
+	assert.NoError(t, err)
+
+	// invalid block range
+	from, to := LatestBlockNumber, EarliestBlockNumber
+
+	_, err = endpoint.Filter(&traceFilter{FromBlock: &from, ToBlock: &to})
+	assert.ErrorIs(t, err, ErrIncorrectBlockRange)
+}
+
+func TestTrace_FilterBlockRangeLimit(t *testing.T) {
+	store := newMockFlatTraceStore(t)
+	from, to := BlockNumber(0), BlockNumber(1)
+
+	_, err := newTestTraceEndpoint(store, 0).Filter(&traceFilter{FromBlock: &from, ToBlock: &to})
+	assert.NoError(t, err)
+
+	store.blocks = append(store.blocks, store.blocks[1])
+	to = BlockNumber(2)
+
+	_, err = newTestTraceEndpoint(store, 1).Filter(&traceFilter{FromBlock: &from, ToBlock: &to})
+	assert.ErrorIs(t, err, ErrBlockRangeTooHigh)
+}
+
+func TestTrace_ReplayBlockTransactions(t *testing.T) {
+	store := newMockFlatTraceStore(t)
+	endpoint := newTestTraceEndpoint(store, 0)
+	block := store.blocks[1]
+
+	// the recorded traces are ignored by the replay
+	store.traces[block.Hash()] = []*flat.TxTraces{}
+
+	res, err := endpoint.ReplayBlockTransactions(BlockNumber(1), []string{"trace"})
+	assert.NoError(t, err)
+
+	results, ok := res.([]*replayResult)
+	assert.True(t, ok)
+
+	if assert.Len(t, results, 2) {
+		assert.Empty(t, results[0].Output)
+		assert.Equal(t, types.BytesToHash([]byte{0x2a}).Bytes(), []byte(results[1].Output))
+
+		for i, result := range results {
+			assert.Equal(t, block.Transactions[i].Hash(), result.TransactionHash)
+			assert.Len(t, result.Trace, 1)
+			assert.Nil(t, result.Trace[0].BlockHash)
+			assert.Nil(t, result.Trace[0].TransactionHash)
+		}
+	}
+
+	// the result is in the parity style
+	data, err := json.Marshal(results[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"output": "0x",
+		"stateDiff": null,
+		"trace": [{
+			"action": {
+				"callType": "call",
+				"from": "`+traceSender.String()+`",
+				"to": "`+traceReceiver.String()+`",
+				"gas": "0x0",
+				"input": "0x",
+				"value": "0x1"
+			},
+			"result": {"gasUsed": "0x0", "output": "0x"},
+			"subtraces": 0,
+			"traceAddress": [],
+			"type": "call"
+		}],
+		"vmTrace": null,
+		"transactionHash": "`+block.Transactions[0].Hash().String()+`"
+	}`, string(data))
+
+	_, err = endpoint.ReplayBlockTransactions(BlockNumber(1), []string{"trace", "vmTrace"})
+	assert.ErrorIs(t, err, ErrUnsupportedTraceType)
+
+	_, err = endpoint.ReplayBlockTransactions(EarliestBlockNumber, []string{"trace"})
+	assert.ErrorIs(t, err, ErrGenesisNotTracable)
+}
@@ -5,7 +5,11 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/helper/gasprice"
 	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/helper/progress"
+	"github.com/dogechain-lab/dogechain/state"
 	"github.com/dogechain-lab/dogechain/types"
 )
 
@@ -15,20 +19,25 @@ type transactionOrHash interface {
 }
 
 type transaction struct {
-	Nonce       argUint64      `json:"nonce"`
-	GasPrice    argBig         `json:"gasPrice"`
-	Gas         argUint64      `json:"gas"`
-	To          *types.Address `json:"to"`
-	Value       argBig         `json:"value"`
-	Input       argBytes       `json:"input"`
-	V           argBig         `json:"v"`
-	R           argBig         `json:"r"`
-	S           argBig         `json:"s"`
-	Hash        types.Hash     `json:"hash"`
-	From        types.Address  `json:"from"`
-	BlockHash   *types.Hash    `json:"blockHash"`
-	BlockNumber *argUint64     `json:"blockNumber"`
-	TxIndex     *argUint64     `json:"transactionIndex"`
+	Type        argUint64         `json:"type"`
+	ChainID     *argBig           `json:"chainId,omitempty"`
+	Nonce       argUint64         `json:"nonce"`
+	GasPrice    argBig            `json:"gasPrice"`
+	GasFeeCap   *argBig           `json:"maxFeePerGas,omitempty"`
+	GasTipCap   *argBig           `json:"maxPriorityFeePerGas,omitempty"`
+	Gas         argUint64         `json:"gas"`
+	To          *types.Address    `json:"to"`
+	Value       argBig            `json:"value"`
+	Input       argBytes          `json:"input"`
+	AccessList  *types.AccessList `json:"accessList,omitempty"`
+	V           argBig            `json:"v"`
+	R           argBig            `json:"r"`
+	S           argBig            `json:"s"`
+	Hash        types.Hash        `json:"hash"`
+	From        types.Address     `json:"from"`
+	BlockHash   *types.Hash       `json:"blockHash"`
+	BlockNumber *argUint64        `json:"blockNumber"`
+	TxIndex     *argUint64        `json:"transactionIndex"`
 }
 
 func (t transaction) getHash() types.Hash { return t.Hash }
@@ -43,7 +52,7 @@ func (h transactionHash) MarshalText() ([]byte, error) {
 }
 
 func toPendingTransaction(t *types.Transaction) *transaction {
-	return toTransaction(t, nil, nil, nil)
+	return toTransaction(t, nil, nil, nil, nil)
 }
 
 func toTransaction(
@@ -51,8 +60,10 @@ func toTransaction(
 	blockNumber *argUint64,
 	blockHash *types.Hash,
 	txIndex *int,
+	baseFee *big.Int,
 ) *transaction {
 	res := &transaction{
+		Type:     argUint64(t.Type),
 		Nonce:    argUint64(t.Nonce),
 		GasPrice: argBig(*t.GasPrice),
 		Gas:      argUint64(t.Gas),
@@ -66,6 +77,30 @@ func toTransaction(
 		From:     t.From,
 	}
 
+	if t.Type.IsTyped() {
+		// typed transactions always carry their chain id and access list
+		accessList := t.AccessList
+		if accessList == nil {
+			accessList = types.AccessList{}
+		}
+
+		res.AccessList = &accessList
+
+		if t.ChainID != nil {
+			res.ChainID = argBigPtr(t.ChainID)
+		}
+	}
+
+	if t.Type == types.DynamicFeeTx {
+		res.GasFeeCap = argBigPtr(t.GetGasFeeCap())
+		res.GasTipCap = argBigPtr(t.GetGasTipCap())
+
+		// sealed transactions show the price actually paid
+		if baseFee != nil {
+			res.GasPrice = argBig(*t.EffectiveGasPrice(baseFee))
+		}
+	}
+
 	if blockNumber != nil {
 		res.BlockNumber = blockNumber
 	}
@@ -98,10 +133,11 @@ type jsonHeader struct {
 	MixHash      types.Hash    `json:"mixHash"`
 	Nonce        types.Nonce   `json:"nonce"`
 	Hash         types.Hash    `json:"hash"`
+	BaseFee      *argBig       `json:"baseFeePerGas,omitempty"`
 }
 
 func toJSONHeader(h *types.Header) *jsonHeader {
-	return &jsonHeader{
+	jh := &jsonHeader{
 		ParentHash:   h.ParentHash,
 		Sha3Uncles:   h.Sha3Uncles,
 		Miner:        h.Miner,
@@ -119,6 +155,12 @@ func toJSONHeader(h *types.Header) *jsonHeader {
 		Nonce:        h.Nonce,
 		Hash:         h.Hash,
 	}
+
+	if h.BaseFee != nil {
+		jh.BaseFee = argBigPtr(h.BaseFee)
+	}
+
+	return jh
 }
 
 type block struct {
@@ -150,6 +192,7 @@ func toBlock(b *types.Block, fullTx bool) *block {
 					argUintPtr(b.Number()),
 					argHashPtr(b.Hash()),
 					&idx,
+					h.BaseFee,
 				),
 			)
 		} else {
@@ -181,6 +224,132 @@ type receipt struct {
 	ContractAddress   *types.Address `json:"contractAddress"`
 	FromAddr          types.Address  `json:"from"`
 	ToAddr            *types.Address `json:"to"`
+	Type              argUint64      `json:"type"`
+}
+
+func toReceipt(raw *types.Receipt, txn *types.Transaction, txIndex int, header *types.Header) *receipt {
+	logs := make([]*Log, len(raw.Logs))
+	for indx, elem := range raw.Logs {
+		logs[indx] = &Log{
+			Address:     elem.Address,
+			Topics:      elem.Topics,
+			Data:        argBytes(elem.Data),
+			BlockHash:   header.Hash,
+			BlockNumber: argUint64(header.Number),
+			TxHash:      txn.Hash(),
+			TxIndex:     argUint64(txIndex),
+			LogIndex:    argUint64(indx),
+			Removed:     false,
+		}
+	}
+
+	res := &receipt{
+		Root:              raw.Root,
+		CumulativeGasUsed: argUint64(raw.CumulativeGasUsed),
+		LogsBloom:         raw.LogsBloom,
+		TxHash:            txn.Hash(),
+		TxIndex:           argUint64(txIndex),
+		BlockHash:         header.Hash,
+		BlockNumber:       argUint64(header.Number),
+		GasUsed:           argUint64(raw.GasUsed),
+		ContractAddress:   raw.ContractAddress,
+		FromAddr:          txn.From,
+		ToAddr:            txn.To,
+		Logs:              logs,
+		Type:              argUint64(txn.Type),
+	}
+
+	if raw.Status != nil {
+		res.Status = argUint64(*raw.Status)
+	}
+
+	return res
+}
+
+type feeHistory struct {
+	OldestBlock  argUint64   `json:"oldestBlock"`
+	Reward       [][]*argBig `json:"reward,omitempty"`
+	BaseFee      []*argBig   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64   `json:"gasUsedRatio"`
+}
+
+func toFeeHistory(h *gasprice.FeeHistory) *feeHistory {
+	res := &feeHistory{
+		OldestBlock:  argUint64(h.OldestBlock),
+		GasUsedRatio: h.GasUsedRatio,
+	}
+
+	if res.GasUsedRatio == nil {
+		res.GasUsedRatio = []float64{}
+	}
+
+	for _, rewards := range h.Reward {
+		row := make([]*argBig, len(rewards))
+		for i, r := range rewards {
+			row[i] = argBigPtr(r)
+		}
+
+		res.Reward = append(res.Reward, row)
+	}
+
+	for _, baseFee := range h.BaseFee {
+		res.BaseFee = append(res.BaseFee, argBigPtr(baseFee))
+	}
+
+	return res
+}
+
+type storageProof struct {
+	Key   types.Hash `json:"key"`
+	Value argBig     `json:"value"`
+	Proof []argBytes `json:"proof"`
+}
+
+type accountProof struct {
+	Address      types.Address   `json:"address"`
+	AccountProof []argBytes      `json:"accountProof"`
+	Balance      argBig          `json:"balance"`
+	CodeHash     types.Hash      `json:"codeHash"`
+	Nonce        argUint64       `json:"nonce"`
+	StorageHash  types.Hash      `json:"storageHash"`
+	StorageProof []*storageProof `json:"storageProof"`
+}
+
+func toProofNodes(proof [][]byte) []argBytes {
+	res := make([]argBytes, len(proof))
+	for i, node := range proof {
+		res[i] = argBytes(node)
+	}
+
+	return res
+}
+
+func toAccountProof(p *state.AccountProof) *accountProof {
+	res := &accountProof{
+		Address:      p.Address,
+		AccountProof: toProofNodes(p.Proof),
+		// the empty account
+		CodeHash:     types.BytesToHash(crypto.Keccak256(nil)),
+		StorageHash:  types.EmptyRootHash,
+		StorageProof: make([]*storageProof, len(p.StorageProof)),
+	}
+
+	if acc := p.Account; acc != nil {
+		res.Balance = argBig(*acc.Balance)
+		res.CodeHash = types.BytesToHash(acc.CodeHash)
+		res.Nonce = argUint64(acc.Nonce)
+		res.StorageHash = acc.Root
+	}
+
+	for i, sp := range p.StorageProof {
+		res.StorageProof[i] = &storageProof{
+			Key:   sp.Key,
+			Value: argBig(*new(big.Int).SetBytes(sp.Value.Bytes())),
+			Proof: toProofNodes(sp.Proof),
+		}
+	}
+
+	return res
 }
 
 type Log struct {
@@ -304,6 +473,12 @@ func encodeToHex(b []byte) []byte {
 	return []byte("0x" + str)
 }
 
+// signTransactionResult is the result of eth_signTransaction
+type signTransactionResult struct {
+	Raw argBytes     `json:"raw"`
+	Tx  *transaction `json:"tx"`
+}
+
 // txnArgs is the transaction argument for the rpc endpoints
 type txnArgs struct {
 	From     *types.Address
@@ -314,6 +489,114 @@ type txnArgs struct {
 	Data     *argBytes
 	Input    *argBytes
 	Nonce    *argUint64
+	// eip-2930 optional fields
+	Type       *argUint64
+	ChainID    *argBig
+	AccessList *types.AccessList
+	// eip-1559 optional fields
+	MaxFeePerGas         *argBig
+	MaxPriorityFeePerGas *argBig
+}
+
+// overrideAccount is the account fields to override before a call, the omitted fields
+// are not overridden. State replaces the whole storage, while StateDiff only replaces
+// the given slots.
+type overrideAccount struct {
+	Nonce     *argUint64                `json:"nonce"`
+	Code      *argBytes                 `json:"code"`
+	Balance   *argBig                   `json:"balance"`
+	State     map[types.Hash]types.Hash `json:"state"`
+	StateDiff map[types.Hash]types.Hash `json:"stateDiff"`
+}
+
+// stateOverride is the set of accounts to override before a call
+type stateOverride map[types.Address]overrideAccount
+
+func (s stateOverride) toStateOverride() state.StateOverride {
+	res := make(state.StateOverride, len(s))
+
+	for addr, account := range s {
+		var override state.OverrideAccount
+
+		if account.Nonce != nil {
+			nonce := uint64(*account.Nonce)
+			override.Nonce = &nonce
+		}
+
+		if account.Code != nil {
+			// an empty code is still an override
+			override.Code = append([]byte{}, *account.Code...)
+		}
+
+		if account.Balance != nil {
+			override.Balance = new(big.Int).Set((*big.Int)(account.Balance))
+		}
+
+		override.State = account.State
+		override.StateDiff = account.StateDiff
+
+		res[addr] = override
+	}
+
+	return res
+}
+
+// balance returns the overridden balance of the address, nil if not overridden
+func (s *stateOverride) balance(addr types.Address) *big.Int {
+	if s == nil {
+		return nil
+	}
+
+	if account, ok := (*s)[addr]; ok && account.Balance != nil {
+		return new(big.Int).Set((*big.Int)(account.Balance))
+	}
+
+	return nil
+}
+
+// nonce returns the overridden nonce of the address, nil if not overridden
+func (s *stateOverride) nonce(addr types.Address) *argUint64 {
+	if s == nil {
+		return nil
+	}
+
+	if account, ok := (*s)[addr]; ok && account.Nonce != nil {
+		return argUintPtr(uint64(*account.Nonce))
+	}
+
+	return nil
+}
+
+// accessListResult is the result of eth_createAccessList
+type accessListResult struct {
+	AccessList types.AccessList `json:"accessList"`
+	Error      string           `json:"error,omitempty"`
+	GasUsed    argUint64        `json:"gasUsed"`
+}
+
+// blockOverride is the fields of the pending block to override before a bundle call
+type blockOverride struct {
+	Number    *argUint64     `json:"number"`
+	Timestamp *argUint64     `json:"timestamp"`
+	Coinbase  *types.Address `json:"coinbase"`
+}
+
+// bundleCallResult is the execution result of a transaction in the bundle
+type bundleCallResult struct {
+	TxHash      types.Hash `json:"txHash"`
+	GasUsed     argUint64  `json:"gasUsed"`
+	ReturnValue argBytes   `json:"returnValue"`
+	Error       string     `json:"error,omitempty"`
+	Logs        []*Log     `json:"logs"`
+}
+
+// bundleResult is the result of eth_callBundle
+type bundleResult struct {
+	Results          []*bundleCallResult `json:"results"`
+	GasUsed          argUint64           `json:"gasUsed"`
+	CoinbaseDiff     argBig              `json:"coinbaseDiff"`
+	StateBlockNumber argUint64           `json:"stateBlockNumber"`
+	BlockNumber      argUint64           `json:"blockNumber"`
 }
 
 type progression struct {
@@ -323,3 +606,27 @@ type progression struct {
 	CurrentBlock  string `json:"currentBlock"`
 	HighestBlock  string `json:"highestBlock"`
 }
+
+// toProgression returns the sync status of the progression, nil if not syncing
+func toProgression(p *progress.Progression) *progression {
+	if p == nil {
+		return nil
+	}
+
+	return &progression{
+		Type:          string(p.SyncType),
+		SyncingPeer:   p.SyncingPeer,
+		StartingBlock: hex.EncodeUint64(p.StartingBlock),
+		CurrentBlock:  hex.EncodeUint64(p.CurrentBlock),
+		HighestBlock:  hex.EncodeUint64(p.HighestBlock.Load()),
+	}
+}
+
+// equal checks whether the two sync status are the same, nil means not syncing
+func (p *progression) equal(other *progression) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+
+	return *p == *other
+}
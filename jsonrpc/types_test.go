@@ -115,7 +115,7 @@ func TestToTransaction_Returns_V_R_S_ValuesWithoutLeading0(t *testing.T) {
 
 	txn.Hash()
 
-	jsonTx := toTransaction(&txn, nil, nil, nil)
+	jsonTx := toTransaction(&txn, nil, nil, nil, nil)
 
 	jsonV, _ := jsonTx.V.MarshalText()
 	jsonR, _ := jsonTx.R.MarshalText()
@@ -0,0 +1,446 @@
+package jsonrpc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+)
+
+var (
+	ErrWSConnLimit = errors.New("too many websocket connections")
+)
+
+// WSSlowConsumerPolicy is the action taken once the outbound queue of a websocket
+// connection is full, since the peer does not read the messages in time
+type WSSlowConsumerPolicy string
+
+const (
+	// WSSlowConsumerDrop drops the notifications not fitting the queue
+	WSSlowConsumerDrop WSSlowConsumerPolicy = "drop"
+	// WSSlowConsumerDisconnect closes the connection of the slow consumer
+	WSSlowConsumerDisconnect WSSlowConsumerPolicy = "disconnect"
+)
+
+const (
+	// wsWriteTimeout is the deadline of writing a single message to the peer
+	wsWriteTimeout = 10 * time.Second
+
+	// wsMaxInflightRequests is the max number of the requests of a connection
+	// handled at the same time, the connection is not read until one finishes
+	wsMaxInflightRequests = 16
+)
+
+// the websocket events counted in the metrics
+const (
+	wsEventConnRejected         = "conn_rejected"
+	wsEventMessageDropped       = "message_dropped"
+	wsEventSlowConsumerEvicted  = "slow_consumer_evicted"
+	wsEventSubscriptionRejected = "subscription_rejected"
+	wsEventIdleTimeout          = "idle_timeout"
+)
+
+// wsUpgrader defines upgrade parameters for the WS connection
+var wsUpgrader = websocket.Upgrader{
+	// Uses the default HTTP buffer sizes for Read / Write buffers.
+	// Documentation specifies that they are 4096B in size.
+	// There is no need to have them be 4x in size when requests / responses
+	// shouldn't exceed 1024B
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// wsMessage is an outbound message waiting in the queue
+type wsMessage struct {
+	messageType int
+	data        []byte
+}
+
+// wsWrapper is a wrapping object for the web socket connection and logger.
+// The messages are queued and written by a single writer, so that a slow
+// peer never blocks the callers.
+type wsWrapper struct {
+	ws       *websocket.Conn // the actual WS connection
+	logger   hclog.Logger    // module logger
+	metrics  *Metrics        // module metrics
+	filterID string          // filter ID
+
+	policy    WSSlowConsumerPolicy // the action taken once the queue is full
+	sendCh    chan wsMessage       // the bounded outbound queue
+	closeCh   chan struct{}        // closed once the connection is closed
+	closeOnce sync.Once
+}
+
+func newWsWrapper(
+	ws *websocket.Conn,
+	logger hclog.Logger,
+	metrics *Metrics,
+	queueSize uint64,
+	policy WSSlowConsumerPolicy,
+) *wsWrapper {
+	if queueSize == 0 {
+		queueSize = DefaultWSSendQueueSize
+	}
+
+	return &wsWrapper{
+		ws:      ws,
+		logger:  logger,
+		metrics: metrics,
+		policy:  policy,
+		sendCh:  make(chan wsMessage, queueSize),
+		closeCh: make(chan struct{}),
+	}
+}
+
+func (w *wsWrapper) SetFilterID(filterID string) {
+	w.filterID = filterID
+}
+
+func (w *wsWrapper) GetFilterID() string {
+	return w.filterID
+}
+
+// WriteMessage queues the notification for the WS peer without blocking. Once
+// the queue is full, the notification is dropped, or the connection is closed,
+// depending on the slow consumer policy.
+func (w *wsWrapper) WriteMessage(messageType int, data []byte) error {
+	if w.isClosed() {
+		return websocket.ErrCloseSent
+	}
+
+	select {
+	case w.sendCh <- wsMessage{messageType: messageType, data: data}:
+		return nil
+	default:
+	}
+
+	if w.policy == WSSlowConsumerDisconnect {
+		w.metrics.WSEventInc(wsEventSlowConsumerEvicted)
+		w.logger.Warn("Closing WS connection of the slow consumer")
+		w.close()
+
+		return websocket.ErrCloseSent
+	}
+
+	w.metrics.WSEventInc(wsEventMessageDropped)
+	w.logger.Debug("Dropped WS message of the slow consumer")
+
+	return nil
+}
+
+// writeResponse queues the response for the WS peer, it blocks until the queue
+// has room, so that a slow peer stops the reading of its own requests
+func (w *wsWrapper) writeResponse(messageType int, data []byte) error {
+	select {
+	case w.sendCh <- wsMessage{messageType: messageType, data: data}:
+		return nil
+	case <-w.closeCh:
+		return websocket.ErrCloseSent
+	}
+}
+
+// writeLoop writes the queued messages and the keepalive pings to the WS peer,
+// until the connection is closed. The pings are disabled if the interval is zero.
+func (w *wsWrapper) writeLoop(pingInterval time.Duration) {
+	var pingCh <-chan time.Time
+
+	if pingInterval > 0 {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+
+		pingCh = ticker.C
+	}
+
+	for {
+		select {
+		case msg := <-w.sendCh:
+			_ = w.ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+
+			if err := w.ws.WriteMessage(msg.messageType, msg.data); err != nil {
+				w.logger.Error(fmt.Sprintf("Unable to write WS message, %s", err.Error()))
+				w.close()
+
+				return
+			}
+		case <-pingCh:
+			if err := w.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				w.logger.Error(fmt.Sprintf("Unable to write WS ping, %s", err.Error()))
+				w.close()
+
+				return
+			}
+		case <-w.closeCh:
+			return
+		}
+	}
+}
+
+// close closes the connection, which also stops the reading of the requests
+func (w *wsWrapper) close() {
+	w.closeOnce.Do(func() {
+		close(w.closeCh)
+
+		if err := w.ws.Close(); err != nil {
+			w.logger.Error(fmt.Sprintf("Unable to gracefully close WS connection, %s", err.Error()))
+		}
+	})
+}
+
+// isClosed returns whether the connection is closed
+func (w *wsWrapper) isClosed() bool {
+	select {
+	case <-w.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// wsConnLimiter counts the open websocket connections of each ip
+type wsConnLimiter struct {
+	sync.Mutex
+
+	limit          uint64 // unlimited if zero
+	trustedProxies map[string]struct{}
+	conns          map[string]uint64
+	total          uint64
+	metrics        *Metrics
+}
+
+func newWsConnLimiter(limit uint64, trustedProxies []string, metrics *Metrics) *wsConnLimiter {
+	proxies := make(map[string]struct{}, len(trustedProxies))
+
+	for _, proxy := range trustedProxies {
+		if ip := net.ParseIP(proxy); ip != nil {
+			proxies[ip.String()] = struct{}{}
+		}
+	}
+
+	return &wsConnLimiter{
+		limit:          limit,
+		trustedProxies: proxies,
+		conns:          make(map[string]uint64),
+		metrics:        metrics,
+	}
+}
+
+// acquire counts a new connection of the ip, unless the ip reached the limit
+func (l *wsConnLimiter) acquire(ip string) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.limit > 0 && l.conns[ip] >= l.limit {
+		return false
+	}
+
+	l.conns[ip]++
+	l.total++
+	l.metrics.WSConnectionsSet(float64(l.total))
+
+	return true
+}
+
+// release uncounts a closed connection of the ip
+func (l *wsConnLimiter) release(ip string) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.conns[ip] <= 1 {
+		delete(l.conns, ip)
+	} else {
+		l.conns[ip]--
+	}
+
+	l.total--
+	l.metrics.WSConnectionsSet(float64(l.total))
+}
+
+// remoteIP returns the ip of the request client. The X-Forwarded-For header is
+// spoofable, so it is only read if the peer is a trusted proxy, then the client
+// is the last forwarded address not from the trusted proxies. Otherwise the
+// clients behind a proxy share the limit of the proxy.
+func (l *wsConnLimiter) remoteIP(req *http.Request) string {
+	ip := peerIP(req.RemoteAddr)
+	if !l.isTrustedProxy(ip) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if addr == nil {
+			// the forwarded chain is broken, stop at the last trusted hop
+			break
+		}
+
+		ip = addr.String()
+
+		if !l.isTrustedProxy(ip) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func (l *wsConnLimiter) isTrustedProxy(ip string) bool {
+	_, ok := l.trustedProxies[ip]
+
+	return ok
+}
+
+// peerIP returns the ip of the request peer, without the port
+func peerIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+
+	return host
+}
+
+// isSupportedWSType returns a status indicating if the message type is supported
+func isSupportedWSType(messageType int) bool {
+	return messageType == websocket.TextMessage ||
+		messageType == websocket.BinaryMessage
+}
+
+// checkWsOrigin checks the origin of the websocket request against the allowed
+// origins. The request without origin is not from a browser, which is allowed.
+func (j *JSONRPC) checkWsOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowedOrigin := range j.config.AccessControlAllowOrigin {
+		if allowedOrigin == "*" || allowedOrigin == origin {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (j *JSONRPC) handleWs(w http.ResponseWriter, req *http.Request) {
+	// authenticate before upgrading, so that the client gets the status. The jwt
+	// token is only checked here, the privileges last as long as the connection.
+	c, err := j.auth.authenticate(req, "/ws")
+	if err != nil {
+		j.metrics.ErrorsCounterInc()
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+
+		return
+	}
+
+	// the connections of an ip are limited
+	ip := j.wsLimiter.remoteIP(req)
+	if !j.wsLimiter.acquire(ip) {
+		j.metrics.WSEventInc(wsEventConnRejected)
+		http.Error(w, ErrWSConnLimit.Error(), http.StatusTooManyRequests)
+
+		return
+	}
+
+	defer j.wsLimiter.release(ip)
+
+	// CORS rule - the same as the http requests
+	upgrader := wsUpgrader
+	upgrader.CheckOrigin = j.checkWsOrigin
+
+	// Upgrade the connection to a WS one
+	ws, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		j.logger.Error(fmt.Sprintf("Unable to upgrade to a WS connection, %s", err.Error()))
+
+		return
+	}
+
+	wrapConn := newWsWrapper(ws, j.logger, j.metrics, j.config.WSSendQueueSize, j.config.WSSlowConsumerPolicy)
+
+	// Defer WS closure
+	defer wrapConn.close()
+
+	go wrapConn.writeLoop(j.config.WSPingInterval)
+
+	// any message or pong read in time keeps the connection alive
+	idleTimeout := j.config.WSIdleTimeout
+	if idleTimeout > 0 {
+		_ = ws.SetReadDeadline(time.Now().Add(idleTimeout))
+
+		ws.SetPongHandler(func(string) error {
+			return ws.SetReadDeadline(time.Now().Add(idleTimeout))
+		})
+	}
+
+	// the requests in flight are bounded, so that a peer sending faster than
+	// it reads the responses can't pile up the goroutines
+	inflight := make(chan struct{}, wsMaxInflightRequests)
+
+	j.logger.Info("Websocket connection established")
+	// Run the listen loop
+	for {
+		// Read the incoming message
+		msgType, message, err := ws.ReadMessage()
+		if err != nil {
+			var netErr net.Error
+
+			switch {
+			case wrapConn.isClosed():
+				j.logger.Info("Closed WS connection of the slow consumer")
+			case websocket.IsCloseError(err,
+				websocket.CloseGoingAway,
+				websocket.CloseNormalClosure,
+				websocket.CloseAbnormalClosure,
+			):
+				// Accepted close codes
+				j.logger.Info("Closing WS connection gracefully")
+			case errors.As(err, &netErr) && netErr.Timeout():
+				j.metrics.WSEventInc(wsEventIdleTimeout)
+				j.logger.Info("Closing idle WS connection")
+			default:
+				j.logger.Error(fmt.Sprintf("Unable to read WS message, %s", err.Error()))
+				j.logger.Info("Closing WS connection with error")
+			}
+
+			// remove websocket connection when closed
+			j.dispatcher.RemoveFilterByWs(wrapConn)
+
+			break
+		}
+
+		if idleTimeout > 0 {
+			_ = ws.SetReadDeadline(time.Now().Add(idleTimeout))
+		}
+
+		if !isSupportedWSType(msgType) {
+			continue
+		}
+
+		inflight <- struct{}{}
+
+		go func() {
+			defer func() { <-inflight }()
+
+			resp, handleErr := j.dispatcher.handleWs(c, message, wrapConn)
+			if handleErr != nil {
+				j.logger.Error(fmt.Sprintf("Unable to handle WS request, %s", handleErr.Error()))
+
+				_ = wrapConn.writeResponse(msgType, handleErrorResponse(handleErr))
+			} else {
+				_ = wrapConn.writeResponse(msgType, resp)
+			}
+		}()
+	}
+}
@@ -0,0 +1,307 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/helper/tests"
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestWSServer starts the json-rpc server with websocket enabled, and returns the ws url
+func newTestWSServer(t *testing.T, config *Config) string {
+	t.Helper()
+
+	port, err := tests.GetFreePort()
+	if err != nil {
+		t.Fatalf("Unable to fetch free port, %v", err)
+	}
+
+	config.Store = newMockStore()
+	config.Addr = &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: port}
+	config.EnableWS = true
+	config.JSONNamespaces = []Namespace{NamespaceEth}
+
+	_, err = NewJSONRPC(hclog.NewNullLogger(), config)
+	assert.NoError(t, err)
+
+	return fmt.Sprintf("ws://127.0.0.1:%d/ws", port)
+}
+
+func dialTestWS(t *testing.T, url string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+
+	var (
+		conn *websocket.Conn
+		resp *http.Response
+		err  error
+	)
+
+	// the server might not listen yet
+	for i := 0; i < 20; i++ {
+		conn, resp, err = websocket.DefaultDialer.Dial(url, nil)
+		if resp != nil || err == nil {
+			break
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return conn, resp, err
+}
+
+func TestWebsocket_ConnLimitPerIP(t *testing.T) {
+	url := newTestWSServer(t, &Config{WSConnLimitPerIP: 2})
+
+	for i := 0; i < 2; i++ {
+		conn, _, err := dialTestWS(t, url)
+		assert.NoError(t, err)
+
+		defer conn.Close()
+	}
+
+	_, resp, err := dialTestWS(t, url)
+	assert.Error(t, err)
+
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	}
+}
+
+func TestWebsocket_SubscriptionLimit(t *testing.T) {
+	url := newTestWSServer(t, &Config{WSSubscriptionLimit: 1})
+
+	conn, _, err := dialTestWS(t, url)
+	assert.NoError(t, err)
+
+	defer conn.Close()
+
+	subscribe := func(id int) *SuccessResponse {
+		req := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"eth_subscribe","params":["newHeads"]}`, id)
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(req)))
+
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+		_, data, err := conn.ReadMessage()
+		assert.NoError(t, err)
+
+		resp := &SuccessResponse{}
+		assert.NoError(t, json.Unmarshal(data, resp))
+
+		return resp
+	}
+
+	assert.Nil(t, subscribe(1).Error)
+
+	resp := subscribe(2)
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, -32005, resp.Error.Code)
+	}
+}
+
+func TestWebsocket_HandleError(t *testing.T) {
+	url := newTestWSServer(t, &Config{})
+
+	conn, _, err := dialTestWS(t, url)
+	assert.NoError(t, err)
+
+	defer conn.Close()
+
+	req := `{"jsonrpc":"2.0","id":1,"method":"eth_unsubscribe","params":[]}`
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(req)))
+
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	_, data, err := conn.ReadMessage()
+	assert.NoError(t, err)
+
+	// the failed requests are answered with the json-rpc errors
+	resp := &ErrorResponse{}
+	assert.NoError(t, json.Unmarshal(data, resp))
+
+	if assert.NotNil(t, resp.Error) {
+		assert.Equal(t, -32602, resp.Error.Code)
+	}
+}
+
+func TestWsConnLimiter_RemoteIP(t *testing.T) {
+	limiter := newWsConnLimiter(0, []string{"10.0.0.1", "10.0.0.2"}, nil)
+
+	cases := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		ip         string
+	}{
+		{
+			"the peer is the client",
+			"1.2.3.4:5678",
+			nil,
+			"1.2.3.4",
+		},
+		{
+			"the forwarded header of an untrusted peer is ignored",
+			"1.2.3.4:5678",
+			[]string{"5.6.7.8"},
+			"1.2.3.4",
+		},
+		{
+			"the client is forwarded by a trusted proxy",
+			"10.0.0.1:5678",
+			[]string{"5.6.7.8"},
+			"5.6.7.8",
+		},
+		{
+			"the spoofed addresses before the client are ignored",
+			"10.0.0.1:5678",
+			[]string{"9.9.9.9, 5.6.7.8", "10.0.0.2"},
+			"5.6.7.8",
+		},
+		{
+			"the proxy is the client without the forwarded header",
+			"10.0.0.1:5678",
+			nil,
+			"10.0.0.1",
+		},
+		{
+			"the invalid forwarded address stops at the last trusted hop",
+			"10.0.0.1:5678",
+			[]string{"5.6.7.8, invalid, 10.0.0.2"},
+			"10.0.0.2",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+			req.RemoteAddr = c.remoteAddr
+
+			for _, forwarded := range c.forwarded {
+				req.Header.Add("X-Forwarded-For", forwarded)
+			}
+
+			assert.Equal(t, c.ip, limiter.remoteIP(req))
+		})
+	}
+}
+
+func TestWebsocket_IdleTimeout(t *testing.T) {
+	t.Run("the idle connection is closed", func(t *testing.T) {
+		url := newTestWSServer(t, &Config{WSIdleTimeout: 200 * time.Millisecond})
+
+		conn, _, err := dialTestWS(t, url)
+		assert.NoError(t, err)
+
+		defer conn.Close()
+
+		// the client sends nothing
+		time.Sleep(500 * time.Millisecond)
+
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+		_, _, err = conn.ReadMessage()
+		assert.Error(t, err)
+		assert.False(t, strings.Contains(err.Error(), "timeout"))
+	})
+
+	t.Run("the pongs keep the connection alive", func(t *testing.T) {
+		url := newTestWSServer(t, &Config{
+			WSPingInterval: 50 * time.Millisecond,
+			WSIdleTimeout:  200 * time.Millisecond,
+		})
+
+		conn, _, err := dialTestWS(t, url)
+		assert.NoError(t, err)
+
+		defer conn.Close()
+
+		// the default ping handler of the client answers the pongs while reading
+		msgCh := make(chan []byte, 1)
+
+		go func() {
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					close(msgCh)
+
+					return
+				}
+
+				msgCh <- data
+			}
+		}()
+
+		time.Sleep(500 * time.Millisecond)
+
+		req := `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(req)))
+
+		select {
+		case data, ok := <-msgCh:
+			assert.True(t, ok, "the connection is closed")
+			assert.Contains(t, string(data), `"id":1`)
+		case <-time.After(2 * time.Second):
+			t.Fatal("response not received in 2 seconds")
+		}
+	})
+}
+
+// newTestWsWrapper returns the wrapper of a server side connection, whose peer
+// never reads the messages
+func newTestWsWrapper(t *testing.T, policy WSSlowConsumerPolicy) *wsWrapper {
+	t.Helper()
+
+	connCh := make(chan *websocket.Conn, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := wsUpgrader.Upgrade(w, r, nil)
+		assert.NoError(t, err)
+
+		connCh <- ws
+	}))
+
+	t.Cleanup(srv.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	assert.NoError(t, err)
+
+	t.Cleanup(func() { client.Close() })
+
+	return newWsWrapper(<-connCh, hclog.NewNullLogger(), NilMetrics(), 2, policy)
+}
+
+func TestWsWrapper_SlowConsumer(t *testing.T) {
+	t.Run("the messages are dropped", func(t *testing.T) {
+		w := newTestWsWrapper(t, WSSlowConsumerDrop)
+
+		// the write loop is not running, so that the queue is never drained
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, w.WriteMessage(websocket.TextMessage, []byte("msg")))
+		}
+
+		assert.Len(t, w.sendCh, 2)
+		assert.False(t, w.isClosed())
+	})
+
+	t.Run("the consumer is disconnected", func(t *testing.T) {
+		w := newTestWsWrapper(t, WSSlowConsumerDisconnect)
+
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, w.WriteMessage(websocket.TextMessage, []byte("msg")))
+		}
+
+		assert.ErrorIs(t, w.WriteMessage(websocket.TextMessage, []byte("msg")), websocket.ErrCloseSent)
+		assert.True(t, w.isClosed())
+
+		// the blocked responses are released too
+		assert.ErrorIs(t, w.writeResponse(websocket.TextMessage, []byte("msg")), websocket.ErrCloseSent)
+	})
+}
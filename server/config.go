@@ -2,9 +2,11 @@ package server
 
 import (
 	"net"
+	"time"
 
 	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/helper/gasprice"
+	"github.com/dogechain-lab/dogechain/jsonrpc"
 	"github.com/dogechain-lab/dogechain/network"
 	"github.com/dogechain-lab/dogechain/secrets"
 	"github.com/hashicorp/go-hclog"
@@ -36,6 +38,7 @@ type Config struct {
 	Network   *network.Config
 
 	DataDir     string
+	KeystoreDir string
 	RestoreFile *string
 
 	LeveldbOptions *LeveldbOptions
@@ -52,6 +55,12 @@ type Config struct {
 	BlockBroadcast bool
 
 	GasPriceOracle gasprice.Config
+
+	EnableTraceStore bool
+
+	EnableAddressIndex bool
+
+	EnableBloomIndex bool
 }
 
 // LeveldbOptions holds the leveldb options
@@ -78,9 +87,23 @@ type JSONRPC struct {
 	AccessControlAllowOrigin []string
 	BatchLengthLimit         uint64
 	BlockRangeLimit          uint64
+	CacheEntries             int
+	CacheBytes               int
+	Recorder                 *jsonrpc.RecorderConfig
 	JSONNamespace            []string
 	EnableWS                 bool
+	WSConnLimitPerIP         uint64
+	WSTrustedProxies         []string
+	WSSubscriptionLimit      uint64
+	WSSendQueueSize          uint64
+	WSSlowConsumerPolicy     jsonrpc.WSSlowConsumerPolicy
+	WSPingInterval           time.Duration
+	WSIdleTimeout            time.Duration
+	IPCPath                  string
 	EnablePprof              bool
+	APIKeys                  []*jsonrpc.APIKey
+	JWTSecret                []byte
+	AllowInsecureUnlock      bool
 }
 
 type GraphQL struct {
@@ -88,4 +111,8 @@ type GraphQL struct {
 	AccessControlAllowOrigin []string
 	BlockRangeLimit          uint64
 	EnablePprof              bool
+	MaxQueryCost             uint64
+	MaxQueryDepth            uint64
+	MaxQueryBlocks           uint64
+	QueryTimeout             time.Duration
 }
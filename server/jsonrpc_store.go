@@ -1,21 +1,34 @@
 package server
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
+	"time"
 
+	"github.com/dogechain-lab/dogechain/accounts"
 	"github.com/dogechain-lab/dogechain/blockchain"
+	"github.com/dogechain-lab/dogechain/blockchain/addrindex"
 	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/consensus"
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/graphql"
 	"github.com/dogechain-lab/dogechain/helper/gasprice"
 	"github.com/dogechain-lab/dogechain/helper/progress"
 	"github.com/dogechain-lab/dogechain/jsonrpc"
 	"github.com/dogechain-lab/dogechain/network"
+	"github.com/dogechain-lab/dogechain/network/common"
+	"github.com/dogechain-lab/dogechain/network/event"
 	"github.com/dogechain-lab/dogechain/state"
 	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/tracer/flat"
 	"github.com/dogechain-lab/dogechain/txpool"
+	txpoolProto "github.com/dogechain-lab/dogechain/txpool/proto"
 	"github.com/dogechain-lab/dogechain/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
 )
 
 type jsonRPCStore struct {
@@ -31,6 +44,10 @@ type jsonRPCStore struct {
 	metrics *JSONRPCStoreMetrics
 
 	gpo *gasprice.Oracle
+
+	bloomIndexer *blockchain.BloomIndexer
+
+	accounts *accounts.Manager
 }
 
 func NewJSONRPCStore(
@@ -43,7 +60,67 @@ func NewJSONRPCStore(
 	network network.Server,
 	metrics *JSONRPCStoreMetrics,
 	gpo *gasprice.Oracle,
+	bloomIndexer *blockchain.BloomIndexer,
+	accounts *accounts.Manager,
 ) jsonrpc.JSONRPCStore {
+	return newJSONRPCStore(
+		state,
+		blockchain,
+		restoreProgression,
+		txpool,
+		executor,
+		consensus,
+		network,
+		metrics,
+		gpo,
+		bloomIndexer,
+		accounts,
+	)
+}
+
+// NewGraphQLStore returns the store of the graphql server, which also aborts the
+// calls once the query deadline is reached
+func NewGraphQLStore(
+	state state.State,
+	blockchain *blockchain.Blockchain,
+	restoreProgression *progress.ProgressionWrapper,
+	txpool *txpool.TxPool,
+	executor *state.Executor,
+	consensus consensus.Consensus,
+	network network.Server,
+	metrics *JSONRPCStoreMetrics,
+	gpo *gasprice.Oracle,
+	bloomIndexer *blockchain.BloomIndexer,
+	accounts *accounts.Manager,
+) graphql.GraphQLStore {
+	return newJSONRPCStore(
+		state,
+		blockchain,
+		restoreProgression,
+		txpool,
+		executor,
+		consensus,
+		network,
+		metrics,
+		gpo,
+		bloomIndexer,
+		accounts,
+	)
+}
+
+func newJSONRPCStore(
+	state state.State,
+	blockchain *blockchain.Blockchain,
+	restoreProgression *progress.ProgressionWrapper,
+	txpool *txpool.TxPool,
+	executor *state.Executor,
+	consensus consensus.Consensus,
+	network network.Server,
+	metrics *JSONRPCStoreMetrics,
+	gpo *gasprice.Oracle,
+	bloomIndexer *blockchain.BloomIndexer,
+	accounts *accounts.Manager,
+) *jsonRPCStore {
 	if metrics == nil {
 		metrics = JSONRPCStoreNilMetrics()
 	}
@@ -58,6 +135,8 @@ func NewJSONRPCStore(
 		state:              state,
 		metrics:            metrics,
 		gpo:                gpo,
+		bloomIndexer:       bloomIndexer,
+		accounts:           accounts,
 	}
 }
 
@@ -113,6 +192,22 @@ func (j *jsonRPCStore) GetStorage(root types.Hash, addr types.Address, slot type
 	return resp.Bytes(), nil
 }
 
+// GetProof returns the merkle proofs of the account and its storage slots (eip-1186)
+func (j *jsonRPCStore) GetProof(
+	root types.Hash,
+	addr types.Address,
+	slots []types.Hash,
+) (*state.AccountProof, error) {
+	j.metrics.GetProofInc()
+
+	prover, ok := j.state.(state.Prover)
+	if !ok {
+		return nil, errors.New("state proof is not supported")
+	}
+
+	return prover.GetProof(root, addr, slots)
+}
+
 // GetForksInTime returns the active forks at the given block height
 func (j *jsonRPCStore) GetForksInTime(blockNumber uint64) chain.ForksInTime {
 	j.metrics.GetForksInTimeInc()
@@ -146,6 +241,13 @@ func (j *jsonRPCStore) Header() *types.Header {
 	return j.blockchain.Header()
 }
 
+// FinalizedHeader returns the header of the last block with a verified commit quorum
+func (j *jsonRPCStore) FinalizedHeader() *types.Header {
+	j.metrics.FinalizedHeaderInc()
+
+	return j.blockchain.FinalizedHeader()
+}
+
 // GetHeaderByNumber returns the header by number
 func (j *jsonRPCStore) GetHeaderByNumber(n uint64) (*types.Header, bool) {
 	j.metrics.GetHeaderByNumberInc()
@@ -200,29 +302,112 @@ func (j *jsonRPCStore) GetAvgGasPrice() *big.Int {
 	return v
 }
 
+// FeeHistory returns the fee market history of the blocks up to the last block
+func (j *jsonRPCStore) FeeHistory(
+	blocks, lastBlock uint64,
+	rewardPercentiles []float64,
+) (*gasprice.FeeHistory, error) {
+	j.metrics.FeeHistoryInc()
+
+	return j.gpo.FeeHistory(blocks, lastBlock, rewardPercentiles)
+}
+
 // ApplyTxn applies a transaction object to the blockchain
 func (j *jsonRPCStore) ApplyTxn(
 	header *types.Header,
 	txn *types.Transaction,
+) (result *runtime.ExecutionResult, err error) {
+	return j.ApplyTxnContext(context.Background(), header, txn)
+}
+
+// ApplyTxnContext applies a transaction object to the blockchain, the execution
+// is aborted once the context is done
+func (j *jsonRPCStore) ApplyTxnContext(
+	ctx context.Context,
+	header *types.Header,
+	txn *types.Transaction,
 ) (result *runtime.ExecutionResult, err error) {
 	j.metrics.ApplyTxnInc()
 
-	blockCreator, err := j.consensus.GetBlockCreator(header)
-	if err != nil {
-		return nil, err
+	if err = ctx.Err(); err != nil {
+		return
 	}
 
-	transition, err := j.executor.BeginTxn(header.StateRoot, header, blockCreator)
-
+	transition, err := j.beginTxn(header)
 	if err != nil {
 		return
 	}
 
+	// zero priced calls are exempted from the base fee
+	transition.SetNoBaseFee(true)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			transition.Cancel()
+		case <-done:
+		}
+	}()
+
 	result, err = transition.Apply(txn)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// the result of the aborted execution is meaningless
+		return nil, ctxErr
+	}
 
 	return
 }
 
+// BeginTxn returns the execution environment on top of the state of the given header.
+// The transition should not commit, it shall be collected by GC.
+func (j *jsonRPCStore) BeginTxn(header *types.Header) (*state.Transition, error) {
+	j.metrics.BeginTxnInc()
+
+	return j.beginTxn(header)
+}
+
+// BeginPendingTxn returns the execution environment of a pending block on top of the
+// state of its parent. The base fee of the pending block is derived from the parent,
+// and the fees go to the coinbase, or the creator of the parent if it's nil.
+// The transition should not commit, it shall be collected by GC.
+func (j *jsonRPCStore) BeginPendingTxn(
+	parent *types.Header,
+	pending *types.Header,
+	coinbase *types.Address,
+) (*state.Transition, error) {
+	j.metrics.BeginPendingTxnInc()
+
+	var receiver types.Address
+
+	if coinbase != nil {
+		receiver = *coinbase
+	} else {
+		creator, err := j.consensus.GetBlockCreator(parent)
+		if err != nil {
+			return nil, err
+		}
+
+		receiver = creator
+	}
+
+	header := pending.Copy()
+	header.BaseFee = j.blockchain.CalculateBaseFee(parent)
+
+	return j.executor.BeginTxn(parent.StateRoot, header, receiver)
+}
+
+func (j *jsonRPCStore) beginTxn(header *types.Header) (*state.Transition, error) {
+	blockCreator, err := j.consensus.GetBlockCreator(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.executor.BeginTxn(header.StateRoot, header, blockCreator)
+}
+
 // GetSyncProgression retrieves the current sync progression, if any
 func (j *jsonRPCStore) GetSyncProgression() *progress.Progression {
 	j.metrics.GetSyncProgressionInc()
@@ -265,8 +450,8 @@ func (j *jsonRPCStore) StateAtTransaction(block *types.Block, txIndex int) (*sta
 		return nil, err
 	}
 
-	// begin transition, use parent block
-	txn, err := j.executor.BeginTxn(parent.StateRoot, parent, blockCreator)
+	// begin transition on top of the parent state, within the block context
+	txn, err := j.executor.BeginTxn(parent.StateRoot, block.Header, blockCreator)
 	if err != nil {
 		return nil, err
 	}
@@ -322,6 +507,274 @@ func (j *jsonRPCStore) SubscribeEvents() blockchain.Subscription {
 	return j.blockchain.SubscribeEvents()
 }
 
+func (j *jsonRPCStore) SubscribeTxPoolEvents(
+	eventTypes ...txpoolProto.EventType,
+) (<-chan *txpoolProto.TxPoolEvent, func()) {
+	j.metrics.SubscribeTxPoolEventsInc()
+
+	return j.txpool.SubscribeEvents(eventTypes...)
+}
+
+// FilterBlocksByBloom returns the blocks in the range which might match the filters
+// by the bloom bits index, and the first block not covered by the index
+func (j *jsonRPCStore) FilterBlocksByBloom(from, to uint64, filters [][][]byte) ([]uint64, uint64, error) {
+	j.metrics.FilterBlocksByBloomInc()
+
+	if j.bloomIndexer == nil {
+		return nil, from, nil
+	}
+
+	return j.bloomIndexer.FilterBlocks(from, to, filters)
+}
+
+// jsonrpc.traceStore interface
+
+// ReadBlockTraces returns the flat call traces recorded when the block was written
+func (j *jsonRPCStore) ReadBlockTraces(hash types.Hash) ([]*flat.TxTraces, bool) {
+	j.metrics.ReadBlockTracesInc()
+
+	return j.blockchain.ReadBlockTraces(hash)
+}
+
+// jsonrpc.otsStore interface
+
+// SearchAddressTransactions returns a page of the transactions touching the address
+// from the given block on, and whether there are more transactions after the page
+func (j *jsonRPCStore) SearchAddressTransactions(
+	addr types.Address,
+	from uint64,
+	backward bool,
+	pageSize int,
+) ([]addrindex.TxRef, bool, error) {
+	j.metrics.SearchAddressTransactionsInc()
+
+	return j.blockchain.SearchAddressTransactions(addr, from, backward, pageSize)
+}
+
 func (j *jsonRPCStore) GetDDosContractList() map[string]map[types.Address]int {
 	return j.txpool.GetDDosContractList()
 }
+
+// jsonrpc.adminStore interface
+
+// peerEventsBufferSize is the buffer size of the peer events subscription, the
+// events are dropped once the buffer is full
+const peerEventsBufferSize = 64
+
+var (
+	ErrRemoveStaticPeer = errors.New("static peer can not be removed")
+)
+
+// GetNodeInfo returns the id and the addresses of the node
+func (j *jsonRPCStore) GetNodeInfo() *jsonrpc.NodeInfo {
+	j.metrics.GetNodeInfoInc()
+
+	addrInfo := j.server.AddrInfo()
+
+	listenAddrs := make([]string, 0, len(addrInfo.Addrs))
+	for _, addr := range addrInfo.Addrs {
+		listenAddrs = append(listenAddrs, addr.String())
+	}
+
+	info := &jsonrpc.NodeInfo{
+		ID:          addrInfo.ID.String(),
+		ListenAddrs: listenAddrs,
+	}
+
+	if len(addrInfo.Addrs) > 0 {
+		info.P2PAddr = common.AddrInfoToString(addrInfo)
+	}
+
+	return info
+}
+
+// GetPeers returns the connected peers
+func (j *jsonRPCStore) GetPeers() []*jsonrpc.PeerInfo {
+	j.metrics.GetPeersInc()
+
+	conns := j.server.Peers()
+	peers := make([]*jsonrpc.PeerInfo, 0, len(conns))
+
+	for _, conn := range conns {
+		id := conn.Info.ID
+
+		// the protocols are gone once the peer is disconnected
+		protocols, err := j.server.GetProtocols(id)
+		if err != nil {
+			continue
+		}
+
+		addrs := make([]string, 0, len(conn.Info.Addrs))
+		for _, addr := range conn.Info.Addrs {
+			addrs = append(addrs, addr.String())
+		}
+
+		peers = append(peers, &jsonrpc.PeerInfo{
+			ID:        id.String(),
+			Addrs:     addrs,
+			Protocols: protocols,
+			Static:    j.server.IsStaticPeer(id),
+		})
+	}
+
+	return peers
+}
+
+// AddPeer dials the peer of the multiaddr, a static peer is kept connected
+func (j *jsonRPCStore) AddPeer(rawMultiaddr string, static bool) error {
+	j.metrics.AddPeerInc()
+
+	return j.server.JoinPeer(rawMultiaddr, static)
+}
+
+// RemovePeer disconnects the peer of the id or the multiaddr
+func (j *jsonRPCStore) RemovePeer(rawPeer string) error {
+	j.metrics.RemovePeerInc()
+
+	id, err := decodePeerID(rawPeer)
+	if err != nil {
+		return err
+	}
+
+	if j.server.IsStaticPeer(id) {
+		return ErrRemoveStaticPeer
+	}
+
+	j.server.DisconnectFromPeer(id, "removed by admin")
+
+	return nil
+}
+
+// SubscribePeerEvents subscribes for the peer connections and disconnections
+func (j *jsonRPCStore) SubscribePeerEvents() (<-chan *jsonrpc.PeerEvent, func()) {
+	j.metrics.SubscribePeerEventsInc()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan *jsonrpc.PeerEvent, peerEventsBufferSize)
+
+	if err := j.server.SubscribeFn(ctx, func(evnt *event.PeerEvent) {
+		var typ string
+
+		switch evnt.Type {
+		case event.PeerConnected:
+			typ = jsonrpc.PeerEventAdd
+		case event.PeerDisconnected:
+			typ = jsonrpc.PeerEventDrop
+		case event.PeerFailedToConnect:
+			typ = jsonrpc.PeerEventFailed
+		default:
+			// the dialing events are internal
+			return
+		}
+
+		select {
+		case ch <- &jsonrpc.PeerEvent{Type: typ, Peer: evnt.PeerID.String()}:
+		default:
+			// never block the network
+		}
+	}); err != nil {
+		cancel()
+
+		// no events at all
+		return nil, func() {}
+	}
+
+	return ch, cancel
+}
+
+// decodePeerID decodes the peer id, or the peer id of the p2p multiaddr
+func decodePeerID(rawPeer string) (peer.ID, error) {
+	if !strings.HasPrefix(rawPeer, "/") {
+		return peer.Decode(rawPeer)
+	}
+
+	addr, err := multiaddr.NewMultiaddr(rawPeer)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := peer.AddrInfoFromP2pAddr(addr)
+	if err != nil {
+		return "", err
+	}
+
+	return info.ID, nil
+}
+
+// jsonrpc.accountStore interface
+
+var (
+	ErrNoKeystore = errors.New("keystore is not enabled")
+)
+
+// ListAccounts returns the addresses of the keystore accounts
+func (j *jsonRPCStore) ListAccounts() ([]types.Address, error) {
+	j.metrics.ListAccountsInc()
+
+	if j.accounts == nil {
+		return nil, ErrNoKeystore
+	}
+
+	return j.accounts.Accounts()
+}
+
+// NewAccount creates a new account encrypted by the password
+func (j *jsonRPCStore) NewAccount(password string) (types.Address, error) {
+	j.metrics.NewAccountInc()
+
+	if j.accounts == nil {
+		return types.ZeroAddress, ErrNoKeystore
+	}
+
+	return j.accounts.NewAccount(password)
+}
+
+// UnlockAccount decrypts the account by the password for the duration
+func (j *jsonRPCStore) UnlockAccount(addr types.Address, password string, duration time.Duration) error {
+	j.metrics.UnlockAccountInc()
+
+	if j.accounts == nil {
+		return ErrNoKeystore
+	}
+
+	return j.accounts.Unlock(addr, password, duration)
+}
+
+// LockAccount removes the decrypted key of the account from the memory
+func (j *jsonRPCStore) LockAccount(addr types.Address) error {
+	j.metrics.LockAccountInc()
+
+	if j.accounts == nil {
+		return ErrNoKeystore
+	}
+
+	j.accounts.Lock(addr)
+
+	return nil
+}
+
+// SignHash signs the hash by the unlocked account
+func (j *jsonRPCStore) SignHash(addr types.Address, hash []byte) ([]byte, error) {
+	j.metrics.SignHashInc()
+
+	if j.accounts == nil {
+		return nil, ErrNoKeystore
+	}
+
+	return j.accounts.SignHash(addr, hash)
+}
+
+// SignTx signs the transaction by the unlocked account
+func (j *jsonRPCStore) SignTx(
+	addr types.Address,
+	tx *types.Transaction,
+	signer crypto.TxSigner,
+) (*types.Transaction, error) {
+	j.metrics.SignTxInc()
+
+	if j.accounts == nil {
+		return nil, ErrNoKeystore
+	}
+
+	return j.accounts.SignTx(addr, tx, signer)
+}
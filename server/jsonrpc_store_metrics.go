@@ -44,6 +44,13 @@ func (m *JSONRPCStoreMetrics) GetStorageInc() {
 	}
 }
 
+// GetProof api calls
+func (m *JSONRPCStoreMetrics) GetProofInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "GetProof"}).Inc()
+	}
+}
+
 // GetForksInTime api calls
 func (m *JSONRPCStoreMetrics) GetForksInTimeInc() {
 	if m.counter != nil {
@@ -65,6 +72,13 @@ func (m *JSONRPCStoreMetrics) HeaderInc() {
 	}
 }
 
+// FinalizedHeader api calls
+func (m *JSONRPCStoreMetrics) FinalizedHeaderInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "FinalizedHeader"}).Inc()
+	}
+}
+
 // GetHeaderByNumber api calls
 func (m *JSONRPCStoreMetrics) GetHeaderByNumberInc() {
 	if m.counter != nil {
@@ -114,6 +128,13 @@ func (m *JSONRPCStoreMetrics) GetAvgGasPriceInc() {
 	}
 }
 
+// FeeHistory api calls
+func (m *JSONRPCStoreMetrics) FeeHistoryInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "FeeHistory"}).Inc()
+	}
+}
+
 // ApplyTxn api calls
 func (m *JSONRPCStoreMetrics) ApplyTxnInc() {
 	if m.counter != nil {
@@ -135,6 +156,20 @@ func (m *JSONRPCStoreMetrics) StateAtTransactionInc() {
 	}
 }
 
+// BeginTxn api calls
+func (m *JSONRPCStoreMetrics) BeginTxnInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "BeginTxn"}).Inc()
+	}
+}
+
+// BeginPendingTxn api calls
+func (m *JSONRPCStoreMetrics) BeginPendingTxnInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "BeginPendingTxn"}).Inc()
+	}
+}
+
 // PeerCount api calls
 func (m *JSONRPCStoreMetrics) PeerCountInc() {
 	if m.counter != nil {
@@ -163,6 +198,111 @@ func (m *JSONRPCStoreMetrics) SubscribeEventsInc() {
 	}
 }
 
+// SubscribeTxPoolEvents api calls
+func (m *JSONRPCStoreMetrics) SubscribeTxPoolEventsInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "SubscribeTxPoolEvents"}).Inc()
+	}
+}
+
+// FilterBlocksByBloom api calls
+func (m *JSONRPCStoreMetrics) FilterBlocksByBloomInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "FilterBlocksByBloom"}).Inc()
+	}
+}
+
+// ReadBlockTraces api calls
+func (m *JSONRPCStoreMetrics) ReadBlockTracesInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "ReadBlockTraces"}).Inc()
+	}
+}
+
+// SearchAddressTransactions api calls
+func (m *JSONRPCStoreMetrics) SearchAddressTransactionsInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "SearchAddressTransactions"}).Inc()
+	}
+}
+
+// GetNodeInfo api calls
+func (m *JSONRPCStoreMetrics) GetNodeInfoInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "GetNodeInfo"}).Inc()
+	}
+}
+
+// GetPeers api calls
+func (m *JSONRPCStoreMetrics) GetPeersInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "GetPeers"}).Inc()
+	}
+}
+
+// AddPeer api calls
+func (m *JSONRPCStoreMetrics) AddPeerInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "AddPeer"}).Inc()
+	}
+}
+
+// RemovePeer api calls
+func (m *JSONRPCStoreMetrics) RemovePeerInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "RemovePeer"}).Inc()
+	}
+}
+
+// SubscribePeerEvents api calls
+func (m *JSONRPCStoreMetrics) SubscribePeerEventsInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "SubscribePeerEvents"}).Inc()
+	}
+}
+
+// ListAccounts api calls
+func (m *JSONRPCStoreMetrics) ListAccountsInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "ListAccounts"}).Inc()
+	}
+}
+
+// NewAccount api calls
+func (m *JSONRPCStoreMetrics) NewAccountInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "NewAccount"}).Inc()
+	}
+}
+
+// UnlockAccount api calls
+func (m *JSONRPCStoreMetrics) UnlockAccountInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "UnlockAccount"}).Inc()
+	}
+}
+
+// LockAccount api calls
+func (m *JSONRPCStoreMetrics) LockAccountInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "LockAccount"}).Inc()
+	}
+}
+
+// SignHash api calls
+func (m *JSONRPCStoreMetrics) SignHashInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "SignHash"}).Inc()
+	}
+}
+
+// SignTx api calls
+func (m *JSONRPCStoreMetrics) SignTxInc() {
+	if m.counter != nil {
+		m.counter.With(prometheus.Labels{"method": "SignTx"}).Inc()
+	}
+}
+
 // NewJSONRPCStoreMetrics return the JSONRPCStore metrics instance
 func NewJSONRPCStoreMetrics(namespace string, labelsWithValues ...string) *JSONRPCStoreMetrics {
 	constLabels := metrics.ParseLables(labelsWithValues...)
@@ -11,15 +11,19 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/dogechain-lab/dogechain/accounts"
 	"github.com/dogechain-lab/dogechain/archive"
 	"github.com/dogechain-lab/dogechain/blockchain"
+	"github.com/dogechain-lab/dogechain/blockchain/addrindex"
 	"github.com/dogechain-lab/dogechain/blockchain/storage/kvstorage"
+	"github.com/dogechain-lab/dogechain/blockchain/tracestore"
 	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/consensus"
 	"github.com/dogechain-lab/dogechain/crypto"
 	"github.com/dogechain-lab/dogechain/graphql"
 	"github.com/dogechain-lab/dogechain/helper/common"
 	"github.com/dogechain-lab/dogechain/helper/gasprice"
+	"github.com/dogechain-lab/dogechain/helper/keystore"
 	"github.com/dogechain-lab/dogechain/helper/kvdb"
 	"github.com/dogechain-lab/dogechain/helper/progress"
 	"github.com/dogechain-lab/dogechain/helper/telemetry"
@@ -54,8 +58,12 @@ type Server struct {
 	consensus consensus.Consensus
 
 	// blockchain stack
-	blockchain *blockchain.Blockchain
-	chain      *chain.Chain
+	blockchain     *blockchain.Blockchain
+	chain          *chain.Chain
+	bloomIndexer   *blockchain.BloomIndexer
+	traceStore     *tracestore.Store
+	addressIndex   *addrindex.Store
+	addressIndexer *blockchain.AddressIndexer
 
 	// state executor
 	executor *state.Executor
@@ -87,6 +95,9 @@ type Server struct {
 
 	// gas price oracle
 	gpo *gasprice.Oracle
+
+	// keystore accounts
+	accounts *accounts.Manager
 }
 
 const (
@@ -284,6 +295,34 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	if m.config.EnableTraceStore {
+		db, err := newLevelDBBuilder(
+			logger,
+			config,
+			filepath.Join(m.config.DataDir, "traces"),
+		).Build()
+		if err != nil {
+			return nil, err
+		}
+
+		m.traceStore = tracestore.NewStore(db)
+		m.blockchain.SetTraceStore(m.traceStore)
+	}
+
+	if m.config.EnableAddressIndex {
+		db, err := newLevelDBBuilder(
+			logger,
+			config,
+			filepath.Join(m.config.DataDir, "addrindex"),
+		).Build()
+		if err != nil {
+			return nil, err
+		}
+
+		m.addressIndex = addrindex.NewStore(db)
+		m.blockchain.SetAddressIndex(m.addressIndex)
+	}
+
 	{ // gas price oracle
 		if m.config.GasPriceOracle.Default == nil {
 			m.config.GasPriceOracle.Default = big.NewInt(int64(m.config.PriceLimit))
@@ -317,7 +356,7 @@ func NewServer(config *Config) (*Server, error) {
 		// start transaction pool
 		m.txpool, err = txpool.NewTxPool(
 			logger,
-			m.chain.Params.Forks.At(0),
+			m.chain.Params.Forks,
 			hub,
 			m.grpcServer,
 			m.network,
@@ -337,8 +376,8 @@ func NewServer(config *Config) (*Server, error) {
 			return nil, err
 		}
 
-		// use the eip155 signer
-		signer := crypto.NewEIP155Signer(uint64(m.config.Chain.Params.ChainID))
+		// use the london signer, which also accepts eip2930 and eip155 legacy transactions
+		signer := crypto.NewLondonSigner(uint64(m.config.Chain.Params.ChainID))
 		m.txpool.SetSigner(signer)
 	}
 
@@ -362,6 +401,36 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	// build the bloom bits index of the logs in background, the log queries
+	// scan all the blocks if disabled
+	if m.config.EnableBloomIndex {
+		m.bloomIndexer = blockchain.NewBloomIndexer(
+			logger,
+			m.blockchain,
+			blockchain.BloomBitsSectionSize,
+			blockchain.BloomBitsConfirms,
+		)
+		if err := m.bloomIndexer.Start(); err != nil {
+			return nil, err
+		}
+	}
+
+	// build the address index in background, never blocking the block import
+	if m.addressIndex != nil {
+		m.addressIndexer = blockchain.NewAddressIndexer(logger, m.blockchain, m.addressIndex)
+		if err := m.addressIndexer.Start(); err != nil {
+			return nil, err
+		}
+	}
+
+	// the keystore accounts of the personal namespace
+	keystoreDir := m.config.KeystoreDir
+	if keystoreDir == "" {
+		keystoreDir = filepath.Join(m.config.DataDir, accounts.DefaultKeystoreDir)
+	}
+
+	m.accounts = accounts.NewManager(keystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+
 	// setup and start jsonrpc server
 	if err := m.setupJSONRPC(); err != nil {
 		return nil, err
@@ -547,6 +616,8 @@ func (s *Server) setupJSONRPC() error {
 		s.network,
 		s.serverMetrics.jsonrpcStore,
 		s.gpo,
+		s.bloomIndexer,
+		s.accounts,
 	)
 
 	// format the jsonrpc endpoint namespaces
@@ -562,11 +633,25 @@ func (s *Server) setupJSONRPC() error {
 		AccessControlAllowOrigin: s.config.JSONRPC.AccessControlAllowOrigin,
 		BatchLengthLimit:         s.config.JSONRPC.BatchLengthLimit,
 		BlockRangeLimit:          s.config.JSONRPC.BlockRangeLimit,
+		CacheEntries:             s.config.JSONRPC.CacheEntries,
+		CacheBytes:               s.config.JSONRPC.CacheBytes,
+		Recorder:                 s.config.JSONRPC.Recorder,
 		JSONNamespaces:           namespaces,
 		EnableWS:                 s.config.JSONRPC.EnableWS,
+		WSConnLimitPerIP:         s.config.JSONRPC.WSConnLimitPerIP,
+		WSTrustedProxies:         s.config.JSONRPC.WSTrustedProxies,
+		WSSubscriptionLimit:      s.config.JSONRPC.WSSubscriptionLimit,
+		WSSendQueueSize:          s.config.JSONRPC.WSSendQueueSize,
+		WSSlowConsumerPolicy:     s.config.JSONRPC.WSSlowConsumerPolicy,
+		WSPingInterval:           s.config.JSONRPC.WSPingInterval,
+		WSIdleTimeout:            s.config.JSONRPC.WSIdleTimeout,
+		IPCPath:                  s.config.JSONRPC.IPCPath,
 		PriceLimit:               s.config.PriceLimit,
 		EnablePProf:              s.config.JSONRPC.EnablePprof,
 		Metrics:                  s.serverMetrics.jsonrpc,
+		APIKeys:                  s.config.JSONRPC.APIKeys,
+		JWTSecret:                s.config.JSONRPC.JWTSecret,
+		AllowInsecureUnlock:      s.config.JSONRPC.AllowInsecureUnlock,
 	}
 
 	srv, err := jsonrpc.NewJSONRPC(s.logger, conf)
@@ -585,7 +670,7 @@ func (s *Server) setupGraphQL() error {
 		return nil
 	}
 
-	hub := NewJSONRPCStore(
+	hub := NewGraphQLStore(
 		s.state,
 		s.blockchain,
 		s.restoreProgression,
@@ -595,6 +680,8 @@ func (s *Server) setupGraphQL() error {
 		s.network,
 		s.serverMetrics.jsonrpcStore,
 		s.gpo,
+		s.bloomIndexer,
+		s.accounts,
 	)
 
 	conf := &graphql.Config{
@@ -605,6 +692,11 @@ func (s *Server) setupGraphQL() error {
 		AccessControlAllowOrigin: s.config.GraphQL.AccessControlAllowOrigin,
 		BlockRangeLimit:          s.config.GraphQL.BlockRangeLimit,
 		EnablePProf:              s.config.GraphQL.EnablePprof,
+		MaxQueryCost:             s.config.GraphQL.MaxQueryCost,
+		MaxQueryDepth:            s.config.GraphQL.MaxQueryDepth,
+		MaxQueryBlocks:           s.config.GraphQL.MaxQueryBlocks,
+		QueryTimeout:             s.config.GraphQL.QueryTimeout,
+		Metrics:                  s.serverMetrics.graphql,
 	}
 
 	srv, err := graphql.NewGraphQLService(s.logger, conf)
@@ -655,6 +747,7 @@ func (s *Server) JoinPeer(rawPeerMultiaddr string, static bool) error {
 //		stop write any block to blockchain storage and
 //		stop write any state to state storage
 //
+//	jsonrpc: stop the ipc server
 //	txpool: stop accepting new transactions
 //	networking: stop transport
 //	stateStorage: safe close state storage
@@ -667,6 +760,31 @@ func (s *Server) Close() {
 		s.logger.Error("failed to close consensus", "err", err.Error())
 	}
 
+	s.logger.Info("close jsonrpc server")
+
+	if s.jsonrpcServer != nil {
+		if err := s.jsonrpcServer.Close(); err != nil {
+			s.logger.Error("failed to close jsonrpc server", "err", err.Error())
+		}
+	}
+
+	// lock the unlocked accounts
+	if s.accounts != nil {
+		s.accounts.Close()
+	}
+
+	s.logger.Info("close bloom indexer")
+
+	if s.bloomIndexer != nil {
+		s.bloomIndexer.Close()
+	}
+
+	s.logger.Info("close address indexer")
+
+	if s.addressIndexer != nil {
+		s.addressIndexer.Close()
+	}
+
 	s.logger.Info("close txpool")
 
 	// close the txpool's main loop
@@ -693,6 +811,20 @@ func (s *Server) Close() {
 		s.logger.Error("failed to close blockchain", "err", err.Error())
 	}
 
+	// close it after the blockchain, which writes the traces
+	if s.traceStore != nil {
+		if err := s.traceStore.Close(); err != nil {
+			s.logger.Error("failed to close trace store", "err", err.Error())
+		}
+	}
+
+	// close it after the blockchain, which updates the index
+	if s.addressIndex != nil {
+		if err := s.addressIndex.Close(); err != nil {
+			s.logger.Error("failed to close address index", "err", err.Error())
+		}
+	}
+
 	if s.prometheusServer != nil {
 		if err := s.prometheusServer.Shutdown(context.Background()); err != nil {
 			s.logger.Error("Prometheus server shutdown error", err)
@@ -3,6 +3,7 @@ package server
 import (
 	"github.com/dogechain-lab/dogechain/blockchain"
 	"github.com/dogechain-lab/dogechain/consensus"
+	"github.com/dogechain-lab/dogechain/graphql"
 	"github.com/dogechain-lab/dogechain/jsonrpc"
 	"github.com/dogechain-lab/dogechain/network"
 	"github.com/dogechain-lab/dogechain/txpool"
@@ -18,6 +19,7 @@ type serverMetrics struct {
 	txpool       *txpool.Metrics
 	jsonrpc      *jsonrpc.Metrics
 	jsonrpcStore *JSONRPCStoreMetrics
+	graphql      *graphql.Metrics
 	trie         itrie.Metrics
 }
 
@@ -31,6 +33,7 @@ func metricProvider(nameSpace string, chainID string, metricsRequired bool, trac
 			txpool:       txpool.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
 			jsonrpc:      jsonrpc.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
 			jsonrpcStore: NewJSONRPCStoreMetrics(nameSpace, "chain_id", chainID),
+			graphql:      graphql.GetPrometheusMetrics(nameSpace, "chain_id", chainID),
 			trie:         itrie.GetPrometheusMetrics(nameSpace, trackingIOTimer, "chain_id", chainID),
 		}
 	}
@@ -42,6 +45,7 @@ func metricProvider(nameSpace string, chainID string, metricsRequired bool, trac
 		txpool:       txpool.NilMetrics(),
 		jsonrpc:      jsonrpc.NilMetrics(),
 		jsonrpcStore: JSONRPCStoreNilMetrics(),
+		graphql:      graphql.NilMetrics(),
 		trie:         itrie.NilMetrics(),
 	}
 }
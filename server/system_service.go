@@ -27,9 +27,12 @@ type systemService struct {
 //
 // Current: { Number: <blockNumber>; Hash: <headerHash> }
 //
+// Finalized: { Number: <blockNumber>; Hash: <headerHash> }
+//
 // P2PAddr: <libp2pAddress>
 func (s *systemService) GetStatus(ctx context.Context, req *empty.Empty) (*proto.ServerStatus, error) {
 	header := s.server.blockchain.Header()
+	finalized := s.server.blockchain.FinalizedHeader()
 
 	status := &proto.ServerStatus{
 		Network: int64(s.server.chain.Params.ChainID),
@@ -37,6 +40,10 @@ func (s *systemService) GetStatus(ctx context.Context, req *empty.Empty) (*proto
 			Number: int64(header.Number),
 			Hash:   header.Hash.String(),
 		},
+		Finalized: &proto.ServerStatus_Block{
+			Number: int64(finalized.Number),
+			Hash:   finalized.Hash.String(),
+		},
 		P2PAddr: common.AddrInfoToString(s.server.network.AddrInfo()),
 	}
 
@@ -14,6 +14,7 @@ import (
 	"github.com/dogechain-lab/dogechain/crypto"
 	"github.com/dogechain-lab/dogechain/state/runtime"
 	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/state/runtime/precompiled"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/hashicorp/go-hclog"
 )
@@ -23,6 +24,9 @@ const (
 
 	TxGas                 uint64 = 21000 // Per transaction not creating a contract
 	TxGasContractCreation uint64 = 53000 // Per transaction that creates a contract
+
+	TxAccessListAddressGas    uint64 = 2400 // Per address specified in the access list
+	TxAccessListStorageKeyGas uint64 = 1900 // Per storage key specified in the access list
 )
 
 var emptyCodeHashTwo = types.BytesToHash(crypto.Keccak256(nil))
@@ -206,6 +210,10 @@ func (e *Executor) BeginTxn(
 		ChainID:    int64(e.config.ChainID),
 	}
 
+	if header.BaseFee != nil {
+		env2.BaseFee = types.BytesToHash(header.BaseFee.Bytes())
+	}
+
 	txn := &Transition{
 		logger:   e.logger,
 		r:        e,
@@ -240,15 +248,24 @@ type Transition struct {
 	ctx     runtime.TxContext
 	gasPool uint64
 
+	// zero priced transactions do not pay the base fee, only used in calls
+	noBaseFee bool
+
 	// result
 	receipts     []*types.Receipt
 	totalGas     uint64
 	totalGasHook func() uint64 // for testing
 
+	// start time of the topmost call, only used in tracing
+	callStartTime time.Time
+
 	// evmLogger for debugging, set a dummy logger to 'collect' tracing,
 	// then we wouldn't have to judge any tracing flag
 	evmLogger runtime.EVMLogger
 	needDebug bool
+
+	// cancelled aborts the execution, only used in tracing
+	cancelled uint32
 }
 
 // SetEVMLogger sets a non nil tracer to it
@@ -304,6 +321,7 @@ func (t *Transition) WriteFailedReceipt(txn *types.Transaction) error {
 	}
 
 	receipt := &types.Receipt{
+		Type:              txn.Type,
 		CumulativeGasUsed: t.totalGas,
 		TxHash:            txn.Hash(),
 		Logs:              t.txn.Logs(),
@@ -349,6 +367,7 @@ func (t *Transition) Write(txn *types.Transaction) error {
 	logs := t.txn.Logs()
 
 	receipt := &types.Receipt{
+		Type:              txn.Type,
 		CumulativeGasUsed: t.totalGas,
 		TxHash:            txn.Hash(),
 		GasUsed:           result.GasUsed,
@@ -498,10 +517,72 @@ func (t *Transition) ContextPtr() *runtime.TxContext {
 	return &t.ctx
 }
 
-func (t *Transition) subGasLimitPrice(msg *types.Transaction) error {
-	// deduct the upfront max gas cost
-	upfrontGasCost := new(big.Int).Set(msg.GasPrice)
-	upfrontGasCost.Mul(upfrontGasCost, new(big.Int).SetUint64(msg.Gas))
+// SetNoBaseFee exempts the zero priced transactions from the base fee,
+// which is used by calls on behalf of the rpc endpoints
+func (t *Transition) SetNoBaseFee(noBaseFee bool) {
+	t.noBaseFee = noBaseFee
+}
+
+// baseFee returns the base fee the transaction should pay, nil before the london hardfork
+func (t *Transition) baseFee(msg *types.Transaction) *big.Int {
+	if !t.config.London || t.isBaseFeeExempted(msg) {
+		return nil
+	}
+
+	return new(big.Int).SetBytes(t.ctx.BaseFee.Bytes())
+}
+
+// isBaseFeeExempted returns whether the transaction is zero priced and exempted from the base fee.
+// Only system transactions from the block producer and calls of the rpc endpoints are exempted.
+func (t *Transition) isBaseFeeExempted(msg *types.Transaction) bool {
+	if msg.GetGasFeeCap().Sign() != 0 || msg.GetGasTipCap().Sign() != 0 {
+		return false
+	}
+
+	if t.noBaseFee {
+		return true
+	}
+
+	return msg.To != nil &&
+		*msg.To == systemcontracts.AddrValidatorSetContract &&
+		msg.From == t.ctx.Coinbase
+}
+
+// feeCapCheck checks the fee fields of the transaction against the base fee (eip-1559)
+func (t *Transition) feeCapCheck(msg *types.Transaction, baseFee *big.Int) error {
+	if msg.Type == types.DynamicFeeTx {
+		if msg.GasFeeCap == nil || msg.GasTipCap == nil {
+			return ErrFeeCapMissing
+		}
+
+		if msg.GasFeeCap.Cmp(msg.GasTipCap) < 0 {
+			return NewTransitionApplicationError(ErrTipAboveFeeCap, false)
+		}
+	}
+
+	if baseFee != nil && msg.GetGasFeeCap().Cmp(baseFee) < 0 {
+		return NewTransitionApplicationError(
+			fmt.Errorf("%w, feeCap: %s, baseFee: %s", ErrFeeCapTooLow, msg.GetGasFeeCap(), baseFee),
+			true,
+		)
+	}
+
+	return nil
+}
+
+func (t *Transition) subGasLimitPrice(msg *types.Transaction, gasPrice *big.Int) error {
+	gas := new(big.Int).SetUint64(msg.Gas)
+
+	// the sender should afford the max gas cost
+	if t.config.London {
+		maxGasCost := new(big.Int).Mul(msg.GetGasFeeCap(), gas)
+		if t.txn.GetBalance(msg.From).Cmp(maxGasCost) < 0 {
+			return ErrNotEnoughFundsForGas
+		}
+	}
+
+	// deduct the upfront gas cost
+	upfrontGasCost := new(big.Int).Mul(gasPrice, gas)
 
 	if err := t.txn.SubBalance(msg.From, upfrontGasCost); err != nil {
 		if errors.Is(err, runtime.ErrNotEnoughFunds) {
@@ -538,6 +619,9 @@ var (
 	ErrNotEnoughFunds        = errors.New("not enough funds for transfer with given value")
 	ErrAllGasUsed            = errors.New("all gas used")
 	ErrExecutionStop         = errors.New("execution stop")
+	ErrFeeCapMissing         = errors.New("dynamic fee transaction without fee cap or tip cap")
+	ErrTipAboveFeeCap        = errors.New("max priority fee per gas higher than max fee per gas")
+	ErrFeeCapTooLow          = errors.New("max fee per gas less than block base fee")
 )
 
 type TransitionApplicationError struct {
@@ -549,6 +633,10 @@ func (e *TransitionApplicationError) Error() string {
 	return e.Err.Error()
 }
 
+func (e *TransitionApplicationError) Unwrap() error {
+	return e.Err
+}
+
 func NewTransitionApplicationError(err error, isRecoverable bool) *TransitionApplicationError {
 	return &TransitionApplicationError{
 		Err:           err,
@@ -606,13 +694,20 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	//
 	// 0. the basic amount of gas is required
 	// 1. the nonce of the message caller is correct
-	// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice)
+	// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice),
+	//    and the fee cap covers the base fee after the london hardfork
 	// 3. the amount of gas required is available in the block
 	// 4. there is no overflow when calculating intrinsic gas
 	// 5. the purchased gas is enough to cover intrinsic usage
 	// 6. caller has enough balance to cover asset transfer for **topmost** call
 	txn := t.txn
 
+	// the tracer reads the pre-transaction state from an independent copy
+	var preTxn *Txn
+	if t.needDebug {
+		preTxn = txn.Copy()
+	}
+
 	t.logger.Debug("try to apply transaction",
 		"hash", msg.Hash(), "from", msg.From, "nonce", msg.Nonce, "price", msg.GasPrice.String(),
 		"remainingGas", t.gasPool, "wantGas", msg.Gas)
@@ -628,7 +723,22 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	}
 
 	// 2. caller has enough balance to cover transaction fee(gaslimit * gasprice)
-	if err := t.subGasLimitPrice(msg); err != nil {
+	baseFee := t.baseFee(msg)
+	if t.config.London {
+		if err := t.feeCapCheck(msg, baseFee); err != nil {
+			//nolint:errorlint
+			if appErr, ok := err.(*TransitionApplicationError); ok {
+				return nil, appErr
+			}
+
+			return nil, NewTransitionApplicationError(err, false)
+		}
+	}
+
+	// the price the sender pays per gas
+	gasPrice := msg.EffectiveGasPrice(baseFee)
+
+	if err := t.subGasLimitPrice(msg, gasPrice); err != nil {
 		// It is not recoverable. All the transactions after that should be dropped
 		return nil, NewTransitionApplicationError(err, true)
 	}
@@ -639,11 +749,16 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 	}
 
 	// 4. there is no overflow when calculating intrinsic gas
-	intrinsicGasCost, err := TransactionGasCost(msg, t.config.Homestead, t.config.Istanbul)
+	intrinsicGasCost, err := TransactionGasCost(msg, t.config.Homestead, t.config.Istanbul, t.config.Shanghai)
 	if err != nil {
 		return nil, NewTransitionApplicationError(err, false)
 	}
 
+	// the initcode size of the contract creation is limited after shanghai (eip-3860)
+	if t.config.Shanghai && msg.IsContractCreation() && len(msg.Input) > runtime.MaxInitCodeSize {
+		return nil, NewTransitionApplicationError(runtime.ErrMaxInitCodeSizeExceeded, false)
+	}
+
 	t.logger.Debug("apply transaction would uses gas", "hash", msg.Hash(), "gas", intrinsicGasCost)
 
 	// 5. the purchased gas is enough to cover intrinsic usage
@@ -659,13 +774,20 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		return nil, NewTransitionApplicationError(ErrNotEnoughFunds, true)
 	}
 
-	gasPrice := new(big.Int).Set(msg.GasPrice)
 	value := new(big.Int).Set(msg.Value)
 
 	// Set the specific transaction fields in the context
 	t.ctx.GasPrice = types.BytesToHash(gasPrice.Bytes())
 	t.ctx.Origin = msg.From
 
+	if t.config.Berlin {
+		t.prepareAccessList(msg)
+	}
+
+	if t.needDebug {
+		t.evmLogger.CaptureTxStart(preTxn, &t.ctx, msg.Gas)
+	}
+
 	var result *runtime.ExecutionResult
 	if msg.IsContractCreation() {
 		result = t.Create2(msg.From, msg.Input, value, gasLeft)
@@ -674,23 +796,75 @@ func (t *Transition) apply(msg *types.Transaction) (*runtime.ExecutionResult, er
 		result = t.Call2(msg.From, *msg.To, msg.Input, value, gasLeft)
 	}
 
+	// eip-3529 reduces the max refund after london
+	refundQuotient := runtime.RefundQuotient
+	if t.config.London {
+		refundQuotient = runtime.RefundQuotientEIP3529
+	}
+
 	refund := txn.GetRefund()
-	result.UpdateGasUsed(msg.Gas, refund)
+	result.UpdateGasUsed(msg.Gas, refund, refundQuotient)
 
 	// refund the sender
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(result.GasLeft), gasPrice)
 	txn.AddBalance(msg.From, remaining)
 
-	// pay the coinbase
-	coinbaseFee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasUsed), gasPrice)
+	gasUsed := new(big.Int).SetUint64(result.GasUsed)
+
+	// pay the coinbase the tip
+	tip := gasPrice
+	if baseFee != nil {
+		tip = new(big.Int).Sub(gasPrice, baseFee)
+	}
+
+	coinbaseFee := new(big.Int).Mul(gasUsed, tip)
 	txn.AddBalance(t.ctx.Coinbase, coinbaseFee)
 
+	// burn the base fee, or send it to the vault contract
+	if baseFee != nil && t.r.config.BaseFeeToVault {
+		txn.AddBalance(systemcontracts.AddrVaultContract, new(big.Int).Mul(gasUsed, baseFee))
+	}
+
 	// return gas to the pool
 	t.addGasPool(result.GasLeft)
 
+	if t.needDebug {
+		t.evmLogger.CaptureTxEnd(result.GasLeft)
+	}
+
 	return result, nil
 }
 
+// prepareAccessList warms up the addresses and slots that are accessed
+// by default in the transaction (eip-2929), plus the optional ones
+// declared in its access list (eip-2930)
+func (t *Transition) prepareAccessList(msg *types.Transaction) {
+	t.txn.ClearAccessList()
+
+	t.txn.AddAddressToAccessList(msg.From)
+
+	if msg.To != nil {
+		t.txn.AddAddressToAccessList(*msg.To)
+	}
+
+	for _, addr := range precompiled.ActiveAddresses(&t.config) {
+		t.txn.AddAddressToAccessList(addr)
+	}
+
+	// the coinbase is warm after shanghai (eip-3651)
+	if t.config.Shanghai {
+		t.txn.AddAddressToAccessList(t.ctx.Coinbase)
+	}
+
+	for _, tuple := range msg.AccessList {
+		t.txn.AddAddressToAccessList(tuple.Address)
+
+		for _, key := range tuple.StorageKeys {
+			t.txn.AddSlotToAccessList(tuple.Address, key)
+		}
+	}
+}
+
 func (t *Transition) Create2(
 	caller types.Address,
 	code []byte,
@@ -716,7 +890,31 @@ func (t *Transition) Call2(
 	return t.applyCall(c, runtime.Call, t)
 }
 
+// Cancel aborts the running execution at the first opportune moment, the
+// cancelled calls fail and consume all of their gas. It is safe to be called
+// concurrently.
+func (t *Transition) Cancel() {
+	atomic.StoreUint32(&t.cancelled, 1)
+}
+
+// ResetCancel clears the cancellation, so that the transition is reused
+func (t *Transition) ResetCancel() {
+	atomic.StoreUint32(&t.cancelled, 0)
+}
+
+// Cancelled returns whether the execution is cancelled
+func (t *Transition) Cancelled() bool {
+	return atomic.LoadUint32(&t.cancelled) > 0
+}
+
 func (t *Transition) run(contract *runtime.Contract, host runtime.Host) *runtime.ExecutionResult {
+	// the cancelled execution never enters the call frames
+	if t.Cancelled() {
+		return &runtime.ExecutionResult{
+			Err: runtime.ErrExecutionCancelled,
+		}
+	}
+
 	for _, r := range t.r.runtimes {
 		if r.CanRun(contract, host, &t.config) {
 			return r.Run(contract, host, &t.config)
@@ -761,23 +959,12 @@ func (t *Transition) applyCall(
 	var result *runtime.ExecutionResult
 
 	if t.needDebug {
-		if c.Depth == 0 {
-			t.evmLogger.CaptureStart(t.Txn(), c.Caller, c.Address, false, c.Input, c.Gas, c.Value)
-
-			defer func(result *runtime.ExecutionResult) {
-				if result != nil {
-					t.evmLogger.CaptureEnd(result.ReturnValue, result.GasUsed, time.Since(time.Now()), result.Err)
-				}
-			}(result)
-		} else {
-			t.evmLogger.CaptureEnter(int(evm.RuntimeType2OpCode(callType)), c.Caller, c.Address, c.Input, c.Gas, c.Value)
-
-			defer func(result *runtime.ExecutionResult) {
-				if result != nil {
-					t.evmLogger.CaptureExit(result.ReturnValue, result.GasUsed, result.Err)
-				}
-			}(result)
-		}
+		t.captureCallStart(c, callType, false)
+
+		// the result is only available when the call returns
+		defer func() {
+			t.captureCallEnd(c, result)
+		}()
 	}
 
 	//nolint:ifshort
@@ -804,6 +991,37 @@ func (t *Transition) applyCall(
 	return result
 }
 
+// captureCallStart notifies the tracer that a call or creation begins
+func (t *Transition) captureCallStart(c *runtime.Contract, callType runtime.CallType, create bool) {
+	// the topmost call starts at depth 1
+	if c.Depth == 1 {
+		t.callStartTime = time.Now()
+		t.evmLogger.CaptureStart(t.Txn(), c.Caller, c.Address, create, c.Input, c.Gas, c.Value)
+
+		return
+	}
+
+	t.evmLogger.CaptureEnter(int(evm.RuntimeType2OpCode(callType)), c.Caller, c.Address, c.Input, c.Gas, c.Value)
+}
+
+// captureCallEnd notifies the tracer that a call or creation ends
+func (t *Transition) captureCallEnd(c *runtime.Contract, result *runtime.ExecutionResult) {
+	if result == nil {
+		return
+	}
+
+	// the gas used is not settled until the transaction ends
+	gasUsed := c.Gas - result.GasLeft
+
+	if c.Depth == 1 {
+		t.evmLogger.CaptureEnd(result.ReturnValue, gasUsed, time.Since(t.callStartTime), result.Err)
+
+		return
+	}
+
+	t.evmLogger.CaptureExit(result.ReturnValue, gasUsed, result.Err)
+}
+
 var emptyHash types.Hash
 
 func (t *Transition) hasCodeOrNonce(addr types.Address) bool {
@@ -834,6 +1052,11 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 	// Increment the nonce of the caller
 	t.txn.IncrNonce(c.Caller)
 
+	if t.config.Berlin {
+		// The created address is warm even if the creation fails (eip-2929)
+		t.txn.AddAddressToAccessList(c.Address)
+	}
+
 	// Check if there if there is a collision and the address already exists
 	if t.hasCodeOrNonce(c.Address) {
 		return &runtime.ExecutionResult{
@@ -842,6 +1065,17 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		}
 	}
 
+	var result *runtime.ExecutionResult
+
+	if t.needDebug {
+		t.captureCallStart(c, c.Type, true)
+
+		// the result is only available when the call returns
+		defer func() {
+			t.captureCallEnd(c, result)
+		}()
+	}
+
 	// Take snapshot of the current state
 	snapshot := t.txn.Snapshot()
 
@@ -851,28 +1085,6 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		t.txn.IncrNonce(c.Address)
 	}
 
-	var result *runtime.ExecutionResult
-
-	if t.needDebug {
-		if c.Depth == 0 {
-			t.evmLogger.CaptureStart(t.Txn(), c.Caller, c.Address, true, c.Input, c.Gas, c.Value)
-
-			defer func(result *runtime.ExecutionResult) {
-				if result != nil {
-					t.evmLogger.CaptureEnd(result.ReturnValue, result.GasUsed, time.Since(time.Now()), result.Err)
-				}
-			}(result)
-		} else {
-			t.evmLogger.CaptureEnter(int(evm.RuntimeType2OpCode(c.Type)), c.Caller, c.Address, c.Input, c.Gas, c.Value)
-
-			defer func(result *runtime.ExecutionResult) {
-				if result != nil {
-					t.evmLogger.CaptureExit(result.ReturnValue, result.GasUsed, result.Err)
-				}
-			}(result)
-		}
-	}
-
 	// Transfer the value
 	if err := t.transfer(c.Caller, c.Address, c.Value); err != nil {
 		result = &runtime.ExecutionResult{
@@ -895,10 +1107,24 @@ func (t *Transition) applyCreate(c *runtime.Contract, host runtime.Host) *runtim
 		// Contract size exceeds 'SpuriousDragon' size limit
 		t.txn.RevertToSnapshot(snapshot)
 
-		return &runtime.ExecutionResult{
+		result = &runtime.ExecutionResult{
 			GasLeft: 0,
 			Err:     runtime.ErrMaxCodeSizeExceeded,
 		}
+
+		return result
+	}
+
+	// the new code starting with the 0xef byte is rejected after london (eip-3541)
+	if t.config.London && len(result.ReturnValue) > 0 && result.ReturnValue[0] == 0xef {
+		t.txn.RevertToSnapshot(snapshot)
+
+		result = &runtime.ExecutionResult{
+			GasLeft: 0,
+			Err:     runtime.ErrInvalidCode,
+		}
+
+		return result
 	}
 
 	gasCost := uint64(len(result.ReturnValue)) * 200
@@ -976,8 +1202,25 @@ func (t *Transition) GetNonce(addr types.Address) uint64 {
 	return t.txn.GetNonce(addr)
 }
 
+func (t *Transition) AddressInAccessList(addr types.Address) bool {
+	return t.txn.AddressInAccessList(addr)
+}
+
+func (t *Transition) SlotInAccessList(addr types.Address, slot types.Hash) (bool, bool) {
+	return t.txn.SlotInAccessList(addr, slot)
+}
+
+func (t *Transition) AddAddressToAccessList(addr types.Address) {
+	t.txn.AddAddressToAccessList(addr)
+}
+
+func (t *Transition) AddSlotToAccessList(addr types.Address, slot types.Hash) {
+	t.txn.AddSlotToAccessList(addr, slot)
+}
+
 func (t *Transition) Selfdestruct(addr types.Address, beneficiary types.Address) {
-	if !t.txn.HasSuicided(addr) {
+	// eip-3529 removes the refund after london
+	if !t.config.London && !t.txn.HasSuicided(addr) {
 		t.txn.AddRefund(24000)
 	}
 
@@ -1012,7 +1255,7 @@ func (t *Transition) SetAccountDirectly(addr types.Address, account *chain.Genes
 	return nil
 }
 
-func TransactionGasCost(msg *types.Transaction, isHomestead, isIstanbul bool) (uint64, error) {
+func TransactionGasCost(msg *types.Transaction, isHomestead, isIstanbul, isShanghai bool) (uint64, error) {
 	cost := uint64(0)
 
 	// Contract creation is only paid on the homestead fork
@@ -1050,6 +1293,22 @@ func TransactionGasCost(msg *types.Transaction, isHomestead, isIstanbul bool) (u
 		}
 
 		cost += zeros * 4
+
+		// eip-3860
+		if msg.IsContractCreation() && isShanghai {
+			words := (uint64(len(payload)) + 31) / 32
+			if (math.MaxUint64-cost)/runtime.InitCodeWordGas < words {
+				return 0, ErrIntrinsicGasOverflow
+			}
+
+			cost += words * runtime.InitCodeWordGas
+		}
+	}
+
+	// eip-2930
+	if len(msg.AccessList) > 0 {
+		cost += uint64(len(msg.AccessList)) * TxAccessListAddressGas
+		cost += uint64(msg.AccessList.StorageKeys()) * TxAccessListStorageKeyGas
 	}
 
 	return cost, nil
@@ -0,0 +1,172 @@
+package itrie
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/state"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	ErrProofNodeMissing = errors.New("proof node missing")
+)
+
+// proofRecorder records the rlp encoded nodes resolved from the storage
+type proofRecorder struct {
+	StorageReader
+	proof [][]byte
+}
+
+func (r *proofRecorder) Get(k []byte) ([]byte, bool, error) {
+	v, ok, err := r.StorageReader.Get(k)
+	if err == nil && ok {
+		node := make([]byte, len(v))
+		copy(node, v)
+
+		r.proof = append(r.proof, node)
+	}
+
+	return v, ok, err
+}
+
+// Prove returns the value of the key in the trie of the given root, and the merkle
+// proof of it, which is the list of the rlp encoded nodes on the path from the root
+// to the key. The nodes are collected while lookupNode resolves them from the storage,
+// so the nodes embedded in their parents are not part of the proof.
+//
+// The key is the raw trie key, it should be hashed by the caller if needed. The proof
+// of a missing key shows its absence.
+func Prove(root types.Hash, key []byte, storage StorageReader) ([]byte, [][]byte, error) {
+	if root == types.EmptyRootHash {
+		return nil, [][]byte{}, nil
+	}
+
+	recorder := &proofRecorder{
+		StorageReader: storage,
+		proof:         [][]byte{},
+	}
+
+	n, ok, err := GetNode(root.Bytes(), recorder)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get storage root %s: %w", root, err)
+	} else if !ok {
+		return nil, nil, fmt.Errorf("state not found at hash %s", root)
+	}
+
+	_, val, err := lookupNode(recorder, n, bytesToHexNibbles(key))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return val, recorder.proof, nil
+}
+
+// proofStorage is the storage of the proof nodes keyed by their hashes
+type proofStorage struct {
+	nodes   map[string][]byte
+	missing []byte // the first hash not found in the proof
+}
+
+func newProofStorage(proof [][]byte) *proofStorage {
+	s := &proofStorage{
+		nodes: make(map[string][]byte, len(proof)),
+	}
+
+	for _, node := range proof {
+		s.nodes[hex.EncodeToHex(hashit(node))] = node
+	}
+
+	return s
+}
+
+func (s *proofStorage) Get(k []byte) ([]byte, bool, error) {
+	v, ok := s.nodes[hex.EncodeToHex(k)]
+	if !ok && s.missing == nil {
+		s.missing = append([]byte{}, k...)
+	}
+
+	return v, ok, nil
+}
+
+// VerifyProof checks the merkle proof of the key against the root. It returns the
+// value of the key, or nil if the proof shows the absence of the key. An error is
+// returned if the proof is incomplete or the nodes don't match the hashes.
+func VerifyProof(root types.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	if root == types.EmptyRootHash {
+		// nothing exists in the empty trie
+		return nil, nil
+	}
+
+	storage := newProofStorage(proof)
+
+	n, ok, err := GetNode(root.Bytes(), storage)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("%w: root %s", ErrProofNodeMissing, root)
+	}
+
+	_, val, err := lookupNode(storage, n, bytesToHexNibbles(key))
+	if err != nil {
+		return nil, err
+	}
+
+	// a node on the path is missing, the proof is incomplete
+	if storage.missing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrProofNodeMissing, hex.EncodeToHex(storage.missing))
+	}
+
+	return val, nil
+}
+
+// GetProof returns the account and storage proofs at the given state root (eip-1186)
+func (db *stateDBImpl) GetProof(
+	root types.Hash,
+	addr types.Address,
+	slots []types.Hash,
+) (*state.AccountProof, error) {
+	data, proof, err := Prove(root, hashit(addr.Bytes()), db)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &state.AccountProof{
+		Address:      addr,
+		Proof:        proof,
+		StorageProof: make([]*state.StorageProof, len(slots)),
+	}
+
+	storageRoot := types.EmptyRootHash
+
+	if len(data) > 0 {
+		var account state.Account
+		if err := account.UnmarshalRlp(data); err != nil {
+			return nil, err
+		}
+
+		res.Account = &account
+		storageRoot = account.Root
+	}
+
+	for i, slot := range slots {
+		val, proof, err := Prove(storageRoot, hashit(slot.Bytes()), db)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := decodeStorageValue(val)
+		if err != nil {
+			return nil, err
+		}
+
+		res.StorageProof[i] = &state.StorageProof{
+			Key:   slot,
+			Value: value,
+			Proof: proof,
+		}
+	}
+
+	return res, nil
+}
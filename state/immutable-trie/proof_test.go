@@ -0,0 +1,186 @@
+package itrie
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/state"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+func proofTestKey(i int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(i))
+
+	return hashit(key)
+}
+
+// newProofTestTrie writes a trie of n keys to the storage, and returns its root
+func newProofTestTrie(t *testing.T, n int) (types.Hash, StateDB) {
+	t.Helper()
+
+	storage := NewMemoryStorage()
+	db := NewStateDB(storage, hclog.NewNullLogger(), nil)
+
+	txn := NewTrie().Txn(db)
+	for i := 0; i < n; i++ {
+		assert.NoError(t, txn.Insert(proofTestKey(i), []byte{byte(i), byte(i >> 8)}))
+	}
+
+	root, err := txn.Hash(storage)
+	assert.NoError(t, err)
+
+	return types.BytesToHash(root), db
+}
+
+func TestProof(t *testing.T) {
+	root, db := newProofTestTrie(t, 500)
+
+	t.Run("Existing keys", func(t *testing.T) {
+		for i := 0; i < 500; i++ {
+			val, proof, err := Prove(root, proofTestKey(i), db)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte{byte(i), byte(i >> 8)}, val)
+			assert.NotEmpty(t, proof)
+
+			res, err := VerifyProof(root, proofTestKey(i), proof)
+			assert.NoError(t, err)
+			assert.Equal(t, val, res)
+		}
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		key := proofTestKey(1000)
+
+		val, proof, err := Prove(root, key, db)
+		assert.NoError(t, err)
+		assert.Nil(t, val)
+		assert.NotEmpty(t, proof)
+
+		res, err := VerifyProof(root, key, proof)
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Incomplete proof", func(t *testing.T) {
+		_, proof, err := Prove(root, proofTestKey(1), db)
+		assert.NoError(t, err)
+
+		_, err = VerifyProof(root, proofTestKey(1), proof[:len(proof)-1])
+		assert.ErrorIs(t, err, ErrProofNodeMissing)
+
+		_, err = VerifyProof(root, proofTestKey(1), nil)
+		assert.ErrorIs(t, err, ErrProofNodeMissing)
+	})
+
+	t.Run("Tampered proof", func(t *testing.T) {
+		_, proof, err := Prove(root, proofTestKey(1), db)
+		assert.NoError(t, err)
+
+		last := proof[len(proof)-1]
+		tampered := append([]byte{}, last...)
+		tampered[len(tampered)-1]++
+		proof[len(proof)-1] = tampered
+
+		_, err = VerifyProof(root, proofTestKey(1), proof)
+		assert.ErrorIs(t, err, ErrProofNodeMissing)
+	})
+
+	t.Run("Wrong root", func(t *testing.T) {
+		_, proof, err := Prove(root, proofTestKey(1), db)
+		assert.NoError(t, err)
+
+		_, err = VerifyProof(types.StringToHash("0x1"), proofTestKey(1), proof)
+		assert.ErrorIs(t, err, ErrProofNodeMissing)
+	})
+
+	t.Run("Empty trie", func(t *testing.T) {
+		val, proof, err := Prove(types.EmptyRootHash, proofTestKey(1), db)
+		assert.NoError(t, err)
+		assert.Nil(t, val)
+		assert.Empty(t, proof)
+
+		res, err := VerifyProof(types.EmptyRootHash, proofTestKey(1), proof)
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestStateDB_GetProof(t *testing.T) {
+	db := NewStateDB(NewMemoryStorage(), hclog.NewNullLogger(), nil)
+
+	var (
+		addr1 = types.StringToAddress("0x1")
+		addr2 = types.StringToAddress("0x2")
+		slot1 = types.StringToHash("0x1")
+		slot2 = types.StringToHash("0x2")
+	)
+
+	_, root, err := db.NewSnapshot().Commit([]*state.Object{
+		{
+			Address:  addr1,
+			Balance:  big.NewInt(100),
+			Nonce:    1,
+			Root:     types.EmptyRootHash,
+			CodeHash: types.BytesToHash(hashit(nil)),
+			Storage: []*state.StorageObject{
+				{Key: slot1.Bytes(), Val: types.StringToHash("0x2a").Bytes()},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	stateRoot := types.BytesToHash(root)
+
+	prover, ok := db.(state.Prover)
+	assert.True(t, ok)
+
+	t.Run("Existing account", func(t *testing.T) {
+		res, err := prover.GetProof(stateRoot, addr1, []types.Hash{slot1, slot2})
+		assert.NoError(t, err)
+
+		assert.Equal(t, addr1, res.Address)
+		assert.Equal(t, uint64(1), res.Account.Nonce)
+		assert.Equal(t, big.NewInt(100), res.Account.Balance)
+
+		data, err := VerifyProof(stateRoot, hashit(addr1.Bytes()), res.Proof)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, data)
+
+		assert.Len(t, res.StorageProof, 2)
+
+		// the existing slot
+		assert.Equal(t, slot1, res.StorageProof[0].Key)
+		assert.Equal(t, types.StringToHash("0x2a"), res.StorageProof[0].Value)
+
+		val, err := VerifyProof(res.Account.Root, hashit(slot1.Bytes()), res.StorageProof[0].Proof)
+		assert.NoError(t, err)
+
+		value, err := decodeStorageValue(val)
+		assert.NoError(t, err)
+		assert.Equal(t, types.StringToHash("0x2a"), value)
+
+		// the empty slot
+		assert.Equal(t, types.ZeroHash, res.StorageProof[1].Value)
+
+		val, err = VerifyProof(res.Account.Root, hashit(slot2.Bytes()), res.StorageProof[1].Proof)
+		assert.NoError(t, err)
+		assert.Nil(t, val)
+	})
+
+	t.Run("Missing account", func(t *testing.T) {
+		res, err := prover.GetProof(stateRoot, addr2, []types.Hash{slot1})
+		assert.NoError(t, err)
+
+		assert.Nil(t, res.Account)
+		assert.Equal(t, types.ZeroHash, res.StorageProof[0].Value)
+		assert.Empty(t, res.StorageProof[0].Proof)
+
+		data, err := VerifyProof(stateRoot, hashit(addr2.Bytes()), res.Proof)
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+	})
+}
@@ -43,7 +43,14 @@ func (s *Snapshot) GetStorage(addr types.Address, root types.Hash, rawkey types.
 	if err != nil {
 		// something bad happen, should not continue
 		return types.Hash{}, err
-	} else if len(val) == 0 {
+	}
+
+	return decodeStorageValue(val)
+}
+
+// decodeStorageValue decodes the rlp encoded value of a storage slot
+func decodeStorageValue(val []byte) (types.Hash, error) {
+	if len(val) == 0 {
 		// not found
 		return types.Hash{}, nil
 	}
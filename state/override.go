@@ -0,0 +1,139 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+var (
+	ErrStateOverrideConflict = errors.New("both state and stateDiff overridden")
+)
+
+// OverrideAccount is the set of the account fields to override before a call,
+// the nil fields are not overridden. State replaces the whole storage of the
+// account, while StateDiff only replaces the given slots.
+type OverrideAccount struct {
+	Nonce     *uint64
+	Code      []byte
+	Balance   *big.Int
+	State     map[types.Hash]types.Hash
+	StateDiff map[types.Hash]types.Hash
+}
+
+// StateOverride is the set of the accounts to override before a call
+type StateOverride map[types.Address]OverrideAccount
+
+// overrideReader is an overlay of the overridden accounts on top of a snapshot.
+// The txn reads the overrides as the committed state, so they behave the same
+// as the real state, while the underlying snapshot is never modified.
+type overrideReader struct {
+	snapshotReader
+
+	accounts StateOverride
+	codes    map[types.Hash][]byte
+}
+
+func newOverrideReader(snapshot snapshotReader, override StateOverride) (*overrideReader, error) {
+	r := &overrideReader{
+		snapshotReader: snapshot,
+		accounts:       override,
+		codes:          make(map[types.Hash][]byte),
+	}
+
+	for addr, account := range override {
+		if account.State != nil && account.StateDiff != nil {
+			return nil, fmt.Errorf("%w: %s", ErrStateOverrideConflict, addr)
+		}
+
+		if account.Code != nil {
+			r.codes[types.BytesToHash(crypto.Keccak256(account.Code))] = account.Code
+		}
+	}
+
+	return r, nil
+}
+
+func (r *overrideReader) GetAccount(addr types.Address) (*Account, error) {
+	account, err := r.snapshotReader.GetAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	override, ok := r.accounts[addr]
+	if !ok {
+		return account, nil
+	}
+
+	if account == nil {
+		account = &Account{
+			Balance:  big.NewInt(0),
+			CodeHash: emptyCodeHash,
+			Root:     emptyStateHash,
+		}
+	} else {
+		account = account.Copy()
+	}
+
+	if override.Nonce != nil {
+		account.Nonce = *override.Nonce
+	}
+
+	if override.Balance != nil {
+		account.Balance = new(big.Int).Set(override.Balance)
+	}
+
+	if override.Code != nil {
+		account.CodeHash = crypto.Keccak256(override.Code)
+	}
+
+	if override.State != nil {
+		// the slots are all served by the overlay
+		account.Root = emptyStateHash
+	}
+
+	return account, nil
+}
+
+func (r *overrideReader) GetStorage(addr types.Address, root types.Hash, key types.Hash) (types.Hash, error) {
+	if override, ok := r.accounts[addr]; ok {
+		if override.State != nil {
+			return override.State[key], nil
+		}
+
+		if val, ok := override.StateDiff[key]; ok {
+			return val, nil
+		}
+	}
+
+	return r.snapshotReader.GetStorage(addr, root, key)
+}
+
+func (r *overrideReader) GetCode(hash types.Hash) ([]byte, bool) {
+	if code, ok := r.codes[hash]; ok {
+		return code, true
+	}
+
+	return r.snapshotReader.GetCode(hash)
+}
+
+// ApplyStateOverride puts the overrides under the state of the transition, they
+// are never written to the underlying snapshot. It should be called before any
+// transaction applied, since the accounts modified already hide the overrides.
+func (t *Transition) ApplyStateOverride(override StateOverride) error {
+	if len(override) == 0 {
+		return nil
+	}
+
+	reader, err := newOverrideReader(t.txn.snapshot, override)
+	if err != nil {
+		return err
+	}
+
+	t.txn.snapshot = reader
+
+	return nil
+}
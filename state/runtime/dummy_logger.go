@@ -14,6 +14,10 @@ func NewDummyLogger() EVMLogger {
 	return &DummyLogger{}
 }
 
+func (d *DummyLogger) CaptureTxStart(pre Txn, ctx *TxContext, gasLimit uint64) {
+}
+func (d *DummyLogger) CaptureTxEnd(restGas uint64) {
+}
 func (d *DummyLogger) CaptureStart(txn Txn, from, to types.Address, create bool,
 	input []byte, gas uint64, value *big.Int) {
 }
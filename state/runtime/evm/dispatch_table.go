@@ -1,6 +1,10 @@
 package evm
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/dogechain-lab/dogechain/chain"
+)
 
 type handler struct {
 	inst  instruction
@@ -8,14 +12,32 @@ type handler struct {
 	gas   uint64
 }
 
+// dispatchTable is the instruction set before the shanghai hardfork,
+// older hardforks gate their instructions within the instructions themselves
 var dispatchTable [256]handler
 
+// shanghaiDispatchTable is the instruction set after the shanghai hardfork
+var shanghaiDispatchTable [256]handler
+
+// getDispatchTable returns the instruction set of the forks
+func getDispatchTable(config *chain.ForksInTime) *[256]handler {
+	if config != nil && config.Shanghai {
+		return &shanghaiDispatchTable
+	}
+
+	return &dispatchTable
+}
+
 func register(op OpCode, h handler) {
-	if dispatchTable[op].inst != nil {
+	registerTo(&dispatchTable, op, h)
+}
+
+func registerTo(table *[256]handler, op OpCode, h handler) {
+	if table[op].inst != nil {
 		panic(fmt.Errorf("instruction already exists"))
 	}
 
-	dispatchTable[op] = h
+	table[op] = h
 }
 
 func registerRange(from, to OpCode, factory func(n int) instruction, gas uint64) {
@@ -106,6 +128,7 @@ func init() {
 	register(GASPRICE, handler{opGasPrice, 0, 2})
 	register(RETURNDATASIZE, handler{opReturnDataSize, 0, 2})
 	register(CHAINID, handler{opChainID, 0, 2})
+	register(BASEFEE, handler{opBaseFee, 0, 2})
 	register(PC, handler{opPC, 0, 2})
 	register(MSIZE, handler{opMSize, 0, 2})
 	register(GAS, handler{opGas, 0, 2})
@@ -130,4 +153,8 @@ func init() {
 	register(JUMP, handler{opJump, 1, 8})
 	register(JUMPI, handler{opJumpi, 2, 10})
 	register(JUMPDEST, handler{opJumpDest, 0, 1})
+
+	// shanghai hardfork
+	shanghaiDispatchTable = dispatchTable
+	registerTo(&shanghaiDispatchTable, PUSH0, handler{opPush0, 0, 2})
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,3 +33,16 @@ func TestPushOpcodes(t *testing.T) {
 		c++
 	}
 }
+
+func TestDispatchTableByFork(t *testing.T) {
+	// push0 is only available after shanghai
+	assert.Nil(t, getDispatchTable(&chain.ForksInTime{})[PUSH0].inst)
+	assert.NotNil(t, getDispatchTable(&chain.ForksInTime{Shanghai: true})[PUSH0].inst)
+
+	// shanghai inherits the former instructions
+	for i := range dispatchTable {
+		if dispatchTable[i].inst != nil {
+			assert.NotNil(t, shanghaiDispatchTable[i].inst)
+		}
+	}
+}
@@ -91,6 +91,26 @@ func (m *mockHost) GetEVMLogger() runtime.EVMLogger {
 	return runtime.NewDummyLogger()
 }
 
+func (m *mockHost) Cancelled() bool {
+	return false
+}
+
+func (m *mockHost) AddressInAccessList(addr types.Address) bool {
+	panic("Not implemented in tests")
+}
+
+func (m *mockHost) SlotInAccessList(addr types.Address, slot types.Hash) (bool, bool) {
+	panic("Not implemented in tests")
+}
+
+func (m *mockHost) AddAddressToAccessList(addr types.Address) {
+	panic("Not implemented in tests")
+}
+
+func (m *mockHost) AddSlotToAccessList(addr types.Address, slot types.Hash) {
+	panic("Not implemented in tests")
+}
+
 func TestRun(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -459,13 +459,53 @@ func opMStore8(c *state) {
 	c.memory[offset.Uint64()] = byte(val.Uint64() & 0xff)
 }
 
+// --- access list ---
+
+const (
+	// eip-2929 access costs
+	coldAccountAccessCost uint64 = 2600
+	coldSloadCost         uint64 = 2100
+	warmStorageReadCost   uint64 = 100
+)
+
+// accountAccessGas returns the eip-2929 cost of touching the given address
+// and adds it to the access list
+func (c *state) accountAccessGas(addr types.Address) uint64 {
+	if c.host.AddressInAccessList(addr) {
+		return warmStorageReadCost
+	}
+
+	c.host.AddAddressToAccessList(addr)
+
+	return coldAccountAccessCost
+}
+
+// slotAccessGas returns the eip-2929 extra cost of touching the given storage slot
+// of the current contract and adds it to the access list
+func (c *state) slotAccessGas(slot types.Hash) (gas uint64, cold bool) {
+	if _, slotOk := c.host.SlotInAccessList(c.msg.Address, slot); slotOk {
+		return 0, false
+	}
+
+	c.host.AddSlotToAccessList(c.msg.Address, slot)
+
+	return coldSloadCost, true
+}
+
 // --- storage ---
 
 func opSload(c *state) {
 	loc := c.top()
 
 	var gas uint64
-	if c.config.Istanbul {
+	if c.config.Berlin {
+		// eip-2929
+		if extra, cold := c.slotAccessGas(bigToHash(loc)); cold {
+			gas = extra
+		} else {
+			gas = warmStorageReadCost
+		}
+	} else if c.config.Istanbul {
 		// eip-1884
 		gas = 800
 	} else if c.config.EIP150 {
@@ -506,12 +546,20 @@ func opSStore(c *state) {
 
 	legacyGasMetering := !c.config.Istanbul && (c.config.Petersburg || !c.config.Constantinople)
 
-	status := c.host.SetStorage(c.msg.Address, key, val, c.config)
 	cost := uint64(0)
 
+	if c.config.Berlin {
+		// eip-2929, cold slot surcharge
+		cost, _ = c.slotAccessGas(key)
+	}
+
+	status := c.host.SetStorage(c.msg.Address, key, val, c.config)
+
 	switch status {
 	case runtime.StorageUnchanged:
-		if c.config.Istanbul {
+		if c.config.Berlin {
+			cost += warmStorageReadCost
+		} else if c.config.Istanbul {
 			// eip-2200
 			cost = 800
 		} else if legacyGasMetering {
@@ -521,10 +569,16 @@ func opSStore(c *state) {
 		}
 
 	case runtime.StorageModified:
-		cost = 5000
+		if c.config.Berlin {
+			cost += 5000 - coldSloadCost
+		} else {
+			cost = 5000
+		}
 
 	case runtime.StorageModifiedAgain:
-		if c.config.Istanbul {
+		if c.config.Berlin {
+			cost += warmStorageReadCost
+		} else if c.config.Istanbul {
 			// eip-2200
 			cost = 800
 		} else if legacyGasMetering {
@@ -534,10 +588,14 @@ func opSStore(c *state) {
 		}
 
 	case runtime.StorageAdded:
-		cost = 20000
+		cost += 20000
 
 	case runtime.StorageDeleted:
-		cost = 5000
+		if c.config.Berlin {
+			cost += 5000 - coldSloadCost
+		} else {
+			cost = 5000
+		}
 
 	case runtime.StorageReadFailed:
 		c.exit(errStorageReadFailed)
@@ -586,7 +644,10 @@ func opBalance(c *state) {
 	addr, _ := c.popAddr()
 
 	var gas uint64
-	if c.config.Istanbul {
+	if c.config.Berlin {
+		// eip-2929
+		gas = c.accountAccessGas(addr)
+	} else if c.config.Istanbul {
 		// eip-1884
 		gas = 700
 	} else if c.config.EIP150 {
@@ -622,6 +683,16 @@ func opChainID(c *state) {
 	c.push1().SetUint64(uint64(c.host.GetTxContext().ChainID))
 }
 
+func opBaseFee(c *state) {
+	if !c.config.London {
+		c.exit(errOpCodeNotFound)
+
+		return
+	}
+
+	c.push1().SetBytes(c.host.GetTxContext().BaseFee.Bytes())
+}
+
 func opOrigin(c *state) {
 	c.push1().SetBytes(c.host.GetTxContext().Origin.Bytes())
 }
@@ -669,7 +740,10 @@ func opExtCodeSize(c *state) {
 	addr, _ := c.popAddr()
 
 	var gas uint64
-	if c.config.EIP150 {
+	if c.config.Berlin {
+		// eip-2929
+		gas = c.accountAccessGas(addr)
+	} else if c.config.EIP150 {
 		gas = 700
 	} else {
 		gas = 20
@@ -704,7 +778,10 @@ func opExtCodeHash(c *state) {
 	address, _ := c.popAddr()
 
 	var gas uint64
-	if c.config.Istanbul {
+	if c.config.Berlin {
+		// eip-2929
+		gas = c.accountAccessGas(address)
+	} else if c.config.Istanbul {
 		gas = 700
 	} else {
 		gas = 400
@@ -778,7 +855,10 @@ func opExtCodeCopy(c *state) {
 	}
 
 	var gas uint64
-	if c.config.EIP150 {
+	if c.config.Berlin {
+		// eip-2929
+		gas = c.accountAccessGas(address)
+	} else if c.config.EIP150 {
 		gas = 700
 	} else {
 		gas = 20
@@ -938,6 +1018,13 @@ func opSelfDestruct(c *state) {
 	if c.config.EIP150 {
 		gas = 5000
 
+		if c.config.Berlin && !c.host.AddressInAccessList(address) {
+			// eip-2929
+			c.host.AddAddressToAccessList(address)
+
+			gas += coldAccountAccessCost
+		}
+
 		if c.config.EIP158 {
 			// if empty and transfers value
 			if c.host.Empty(address) && c.host.GetBalance(c.msg.Address).Sign() != 0 {
@@ -980,6 +1067,10 @@ func opJumpi(c *state) {
 func opJumpDest(c *state) {
 }
 
+func opPush0(c *state) {
+	c.push1().Set(zero)
+}
+
 func opPush(n int) instruction {
 	return func(c *state) {
 		ins := c.code
@@ -1210,7 +1301,10 @@ func (c *state) buildCallContract(op OpCode) (*runtime.Contract, uint64, uint64,
 	}
 
 	var gasCost uint64
-	if c.config.EIP150 {
+	if c.config.Berlin {
+		// eip-2929
+		gasCost = c.accountAccessGas(addr)
+	} else if c.config.EIP150 {
 		gasCost = 700
 	} else {
 		gasCost = 40
@@ -1348,6 +1442,20 @@ func (c *state) buildCreateContract(op OpCode) (*runtime.Contract, error) {
 		}
 	}
 
+	// Limit and meter the initcode (eip-3860)
+	if c.config.Shanghai {
+		size := length.Uint64()
+		if size > runtime.MaxInitCodeSize {
+			c.exit(errOutOfGas)
+
+			return nil, nil
+		}
+
+		if !c.consumeGas(((size + 31) / 32) * runtime.InitCodeWordGas) {
+			return nil, nil
+		}
+	}
+
 	// Calculate and consume gas for the call
 	gas := c.gas
 
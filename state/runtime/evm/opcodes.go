@@ -163,6 +163,9 @@ const (
 	// SELFBALANCE returns the balance of the current account
 	SELFBALANCE = 0x47
 
+	// BASEFEE returns the current block's base fee
+	BASEFEE = 0x48
+
 	// POP pops a (u)int256 off the stack and discards it
 	POP = 0x50
 
@@ -199,6 +202,9 @@ const (
 	// JUMPDEST corresponds to a possible jump destination
 	JUMPDEST = 0x5B
 
+	// PUSH0 pushes a zero value onto the stack
+	PUSH0 = 0x5F
+
 	// PUSH1 pushes a 1-byte value onto the stack
 	PUSH1 = 0x60
 
@@ -333,6 +339,8 @@ var opCodeToString = map[OpCode]string{
 	SELFDESTRUCT:   "SELFDESTRUCT",
 	CHAINID:        "CHAINID",
 	SELFBALANCE:    "SELFBALANCE",
+	BASEFEE:        "BASEFEE",
+	PUSH0:          "PUSH0",
 }
 
 func opCodesToString(from, to OpCode, str string) {
@@ -285,6 +285,7 @@ func (c *state) Run() (ret []byte, vmerr error) {
 	}(needDebug, &vmerr)
 
 	codeSize := len(c.code)
+	table := getDispatchTable(c.config)
 
 	for !c.stop {
 		if needDebug {
@@ -303,9 +304,16 @@ func (c *state) Run() (ret []byte, vmerr error) {
 			break
 		}
 
+		// the cancellation bounds the cpu time of the long running loops
+		if c.host != nil && c.host.Cancelled() {
+			c.exit(runtime.ErrExecutionCancelled)
+
+			break
+		}
+
 		op := OpCode(c.code[c.ip])
 
-		inst := dispatchTable[op]
+		inst := table[op]
 		if inst.inst == nil {
 			c.exit(errOpCodeNotFound)
 
@@ -359,6 +367,9 @@ func (c *state) captureState(ip uint64, op int, memory []byte, stack []*big.Int,
 			Memory:          memory,
 			Stack:           stack,
 			ContractAddress: c.msg.Address,
+			Caller:          c.msg.Caller,
+			Value:           c.msg.Value,
+			Input:           c.msg.Input,
 		},
 		ip,
 		op,
@@ -376,6 +387,9 @@ func (c *state) captureFault(ip uint64, op int, memory []byte, stack []*big.Int,
 			Memory:          memory,
 			Stack:           stack,
 			ContractAddress: c.msg.Address,
+			Caller:          c.msg.Caller,
+			Value:           c.msg.Value,
+			Input:           c.msg.Input,
 		},
 		ip,
 		op,
@@ -11,6 +11,10 @@ import (
 type Txn interface {
 	GetState(addr types.Address, key types.Hash) (types.Hash, error)
 	GetRefund() uint64
+	GetBalance(addr types.Address) *big.Int
+	GetNonce(addr types.Address) uint64
+	GetCode(addr types.Address) []byte
+	Exist(addr types.Address) bool
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -19,13 +23,21 @@ type ScopeContext struct {
 	Memory          []byte
 	Stack           []*big.Int
 	ContractAddress types.Address
+	Caller          types.Address
+	Value           *big.Int
+	Input           []byte
 }
 
 // EVMLogger is used to collect execution traces from an EVM transaction execution.
 // CaptureState is called for each step of the VM with the current VM state.
 // Note that reference types are actual VM data structures; make copies if you need to
 // retain them beyond the current call.
+//
+// CaptureTxStart is called before the topmost call of a transaction with a read-only
+// view of the state before the transaction, and CaptureTxEnd after the gas is settled.
 type EVMLogger interface {
+	CaptureTxStart(pre Txn, ctx *TxContext, gasLimit uint64)
+	CaptureTxEnd(restGas uint64)
 	CaptureStart(txn Txn, from, to types.Address, create bool, input []byte, gas uint64, value *big.Int)
 	CaptureState(ctx *ScopeContext, pc uint64, opCode int, gas, cost uint64, rData []byte, depth int, err error)
 	CaptureEnter(opCode int, from, to types.Address, input []byte, gas uint64, value *big.Int)
@@ -14,6 +14,8 @@ type modExp struct {
 
 var (
 	big1      = big.NewInt(1)
+	big3      = big.NewInt(3)
+	big7      = big.NewInt(7)
 	big4      = big.NewInt(4)
 	big8      = big.NewInt(8)
 	big16     = big.NewInt(16)
@@ -21,6 +23,7 @@ var (
 	big64     = big.NewInt(64)
 	big96     = big.NewInt(96)
 	big480    = big.NewInt(480)
+	big200    = big.NewInt(200)
 	big1024   = big.NewInt(1024)
 	big3072   = big.NewInt(3072)
 	big199680 = big.NewInt(199680)
@@ -116,6 +119,10 @@ func (m *modExp) gas(input []byte, config *chain.ForksInTime) uint64 {
 		gasCost.Set(baseLen)
 	}
 
+	if config.Berlin {
+		return berlinModExpGas(gasCost, adjustedExponentLength(expLen, expHead))
+	}
+
 	gasCost = multComplexity(gasCost)
 
 	// a = a * max(ADJUSTED_EXPONENT_LENGTH, 1)
@@ -137,6 +144,33 @@ func (m *modExp) gas(input []byte, config *chain.ForksInTime) uint64 {
 	return gasCost.Uint64()
 }
 
+// berlinModExpGas calculates the gas cost repriced by eip-2565
+func berlinModExpGas(maxLen, adjExpLen *big.Int) uint64 {
+	// words = ceil(max(length_of_MODULUS, length_of_BASE) / 8)
+	words := new(big.Int).Add(maxLen, big7)
+	words.Div(words, big8)
+
+	// a := words ** 2 * max(ADJUSTED_EXPONENT_LENGTH, 1) / 3
+	gasCost := words.Mul(words, words)
+	if adjExpLen.Cmp(big1) >= 0 {
+		gasCost.Mul(gasCost, adjExpLen)
+	}
+
+	gasCost.Div(gasCost, big3)
+
+	// cap to the max uint64
+	if !gasCost.IsUint64() {
+		return math.MaxUint64
+	}
+
+	// with a minimum of 200
+	if gasCost.Cmp(big200) < 0 {
+		return big200.Uint64()
+	}
+
+	return gasCost.Uint64()
+}
+
 func (m *modExp) run(input []byte) ([]byte, error) {
 	// get the lengths
 	var baseLen, exponentLen, modulusLen uint64
@@ -88,6 +88,24 @@ func (p *Precompiled) CanRun(c *runtime.Contract, _ runtime.Host, config *chain.
 	return true
 }
 
+// ActiveAddresses returns the addresses of the precompiled contracts enabled in the given forks
+func ActiveAddresses(config *chain.ForksInTime) []types.Address {
+	last := 4
+
+	if config.Istanbul {
+		last = 9
+	} else if config.Byzantium {
+		last = 8
+	}
+
+	addrs := make([]types.Address, 0, last)
+	for i := 1; i <= last; i++ {
+		addrs = append(addrs, types.BytesToAddress([]byte{byte(i)}))
+	}
+
+	return addrs
+}
+
 // Name implements the runtime interface
 func (p *Precompiled) Name() string {
 	return "precompiled"
@@ -18,8 +18,16 @@ type TxContext struct {
 	GasLimit   int64
 	ChainID    int64
 	Difficulty types.Hash
+	BaseFee    types.Hash
 }
 
+const (
+	// MaxInitCodeSize is the max size of the contract creation code (eip-3860)
+	MaxInitCodeSize = 2 * 24576
+	// InitCodeWordGas is the gas per word of the contract creation code (eip-3860)
+	InitCodeWordGas uint64 = 2
+)
+
 // StorageStatus is the status of the storage access
 type StorageStatus int
 
@@ -72,6 +80,15 @@ type Host interface {
 	Empty(addr types.Address) bool
 	GetNonce(addr types.Address) uint64
 	GetEVMLogger() EVMLogger
+	// Cancelled returns whether the execution is cancelled, such as the tracing
+	// timeout, so that it is aborted at the next instruction
+	Cancelled() bool
+
+	// access list (eip-2929)
+	AddressInAccessList(addr types.Address) bool
+	SlotInAccessList(addr types.Address, slot types.Hash) (addressOk bool, slotOk bool)
+	AddAddressToAccessList(addr types.Address)
+	AddSlotToAccessList(addr types.Address, slot types.Hash)
 }
 
 // ExecutionResult includes all output after executing given evm
@@ -107,11 +124,17 @@ func (r *ExecutionResult) Revert() []byte {
 	return r.ReturnValue
 }
 
-func (r *ExecutionResult) UpdateGasUsed(gasLimit uint64, refund uint64) {
+// The max refunds are the gas used divided by the quotients
+const (
+	RefundQuotient        uint64 = 2
+	RefundQuotientEIP3529 uint64 = 5 // after london
+)
+
+func (r *ExecutionResult) UpdateGasUsed(gasLimit uint64, refund uint64, refundQuotient uint64) {
 	r.GasUsed = gasLimit - r.GasLeft
 
-	// Refund can go up to half the gas used
-	if maxRefund := r.GasUsed / 2; refund > maxRefund {
+	// Refund can go up to the gas used divided by the quotient
+	if maxRefund := r.GasUsed / refundQuotient; refund > maxRefund {
 		refund = maxRefund
 	}
 
@@ -126,12 +149,15 @@ var (
 	ErrNotEnoughFunds           = errors.New("not enough funds")
 	ErrInsufficientBalance      = errors.New("insufficient balance for transfer")
 	ErrMaxCodeSizeExceeded      = errors.New("evm: max code size exceeded")
+	ErrMaxInitCodeSizeExceeded  = errors.New("evm: max initcode size exceeded")
+	ErrInvalidCode              = errors.New("evm: invalid code: must not begin with 0xef")
 	ErrContractAddressCollision = errors.New("contract address collision")
 	ErrDepth                    = errors.New("max call depth exceeded")
 	ErrExecutionReverted        = errors.New("execution was reverted")
 	ErrCodeStoreOutOfGas        = errors.New("contract creation code storage out of gas")
 	ErrCodeEmpty                = errors.New("contract code empty")
 	ErrStorageReadFailed        = errors.New("storage read failed")
+	ErrExecutionCancelled       = errors.New("execution cancelled")
 )
 
 type CallType int
@@ -24,6 +24,28 @@ type Snapshot interface {
 	Commit(objs []*Object) (Snapshot, []byte, error)
 }
 
+// Prover is implemented by the states which could generate the merkle proofs
+type Prover interface {
+	// GetProof returns the account and storage proofs at the given state root (eip-1186)
+	GetProof(root types.Hash, addr types.Address, slots []types.Hash) (*AccountProof, error)
+}
+
+// AccountProof is the merkle proof of an account and some of its storage slots
+type AccountProof struct {
+	Address types.Address
+	// Account is nil if the account doesn't exist, the proof shows its absence then
+	Account      *Account
+	Proof        [][]byte
+	StorageProof []*StorageProof
+}
+
+// StorageProof is the merkle proof of a storage slot
+type StorageProof struct {
+	Key   types.Hash
+	Value types.Hash
+	Proof [][]byte
+}
+
 // account trie
 type accountTrie interface {
 	Get(k []byte) ([]byte, bool)
@@ -0,0 +1,191 @@
+package accesslist
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+// accessList is the set of the addresses and their storage slots
+type accessList map[types.Address]map[types.Hash]struct{}
+
+func newAccessList(acl types.AccessList) accessList {
+	list := make(accessList, len(acl))
+
+	for _, tuple := range acl {
+		list.addAddress(tuple.Address)
+
+		for _, slot := range tuple.StorageKeys {
+			list.addSlot(tuple.Address, slot)
+		}
+	}
+
+	return list
+}
+
+func (al accessList) addAddress(addr types.Address) {
+	if _, ok := al[addr]; !ok {
+		al[addr] = make(map[types.Hash]struct{})
+	}
+}
+
+func (al accessList) addSlot(addr types.Address, slot types.Hash) {
+	al.addAddress(addr)
+	al[addr][slot] = struct{}{}
+}
+
+// equal checks whether the two lists contain the same addresses and slots
+func (al accessList) equal(other accessList) bool {
+	if len(al) != len(other) {
+		return false
+	}
+
+	for addr, slots := range al {
+		otherSlots, ok := other[addr]
+		if !ok || len(slots) != len(otherSlots) {
+			return false
+		}
+
+		for slot := range slots {
+			if _, ok := otherSlots[slot]; !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// toAccessList converts the list to the eip-2930 form, sorted by the addresses and slots
+func (al accessList) toAccessList() types.AccessList {
+	acl := make(types.AccessList, 0, len(al))
+
+	for addr, slots := range al {
+		tuple := types.AccessTuple{
+			Address:     addr,
+			StorageKeys: make([]types.Hash, 0, len(slots)),
+		}
+
+		for slot := range slots {
+			tuple.StorageKeys = append(tuple.StorageKeys, slot)
+		}
+
+		sort.Slice(tuple.StorageKeys, func(i, j int) bool {
+			return bytes.Compare(tuple.StorageKeys[i].Bytes(), tuple.StorageKeys[j].Bytes()) < 0
+		})
+
+		acl = append(acl, tuple)
+	}
+
+	sort.Slice(acl, func(i, j int) bool {
+		return bytes.Compare(acl[i].Address.Bytes(), acl[j].Address.Bytes()) < 0
+	})
+
+	return acl
+}
+
+// Tracer is an EVM logger collecting the addresses and storage slots touched by
+// a transaction. The excluded addresses are always warm, such as the sender, the
+// receiver and the precompiled contracts, so they are not collected.
+type Tracer struct {
+	excluded map[types.Address]struct{}
+	list     accessList
+}
+
+// NewTracer returns a tracer starting with the given access list. The excluded
+// addresses are not collected unless they are in the initial list.
+func NewTracer(acl types.AccessList, excluded []types.Address) *Tracer {
+	excl := make(map[types.Address]struct{}, len(excluded))
+	for _, addr := range excluded {
+		excl[addr] = struct{}{}
+	}
+
+	list := newAccessList(acl)
+	for addr := range list {
+		delete(excl, addr)
+	}
+
+	return &Tracer{
+		excluded: excl,
+		list:     list,
+	}
+}
+
+// AccessList returns the collected access list
+func (t *Tracer) AccessList() types.AccessList {
+	return t.list.toAccessList()
+}
+
+// Equal checks whether the two tracers collected the same access list
+func (t *Tracer) Equal(other *Tracer) bool {
+	return t.list.equal(other.list)
+}
+
+func (t *Tracer) addAddress(addr types.Address) {
+	if _, ok := t.excluded[addr]; !ok {
+		t.list.addAddress(addr)
+	}
+}
+
+// CaptureTxStart implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureTxStart(pre runtime.Txn, ctx *runtime.TxContext, gasLimit uint64) {}
+
+// CaptureTxEnd implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureTxEnd(restGas uint64) {}
+
+// CaptureStart implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureStart(txn runtime.Txn, from, to types.Address,
+	create bool, input []byte, gas uint64, value *big.Int) {
+}
+
+// CaptureState collects the addresses and slots accessed by the opcodes
+func (t *Tracer) CaptureState(
+	ctx *runtime.ScopeContext,
+	pc uint64,
+	opCode int,
+	gas, cost uint64,
+	rData []byte,
+	depth int,
+	err error,
+) {
+	stack := ctx.Stack
+	stackLen := len(stack)
+
+	switch {
+	case (opCode == evm.SLOAD || opCode == evm.SSTORE) && stackLen >= 1:
+		slot := types.BytesToHash(stack[stackLen-1].Bytes())
+		t.list.addSlot(ctx.ContractAddress, slot)
+	case (opCode == evm.EXTCODECOPY || opCode == evm.EXTCODEHASH || opCode == evm.EXTCODESIZE ||
+		opCode == evm.BALANCE || opCode == evm.SELFDESTRUCT) && stackLen >= 1:
+		t.addAddress(types.BytesToAddress(stack[stackLen-1].Bytes()))
+	case (opCode == evm.DELEGATECALL || opCode == evm.CALL ||
+		opCode == evm.STATICCALL || opCode == evm.CALLCODE) && stackLen >= 5:
+		t.addAddress(types.BytesToAddress(stack[stackLen-2].Bytes()))
+	}
+}
+
+// CaptureEnter implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureEnter(opCode int, from, to types.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+// CaptureExit implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+// CaptureFault implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureFault(
+	ctx *runtime.ScopeContext,
+	pc uint64,
+	opCode int,
+	gas, cost uint64,
+	depth int,
+	err error,
+) {
+}
+
+// CaptureEnd implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {}
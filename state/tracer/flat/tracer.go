@@ -0,0 +1,212 @@
+package flat
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+const (
+	TraceTypeCall   = "call"
+	TraceTypeCreate = "create"
+
+	// revertedError is the parity style error of the reverted calls
+	revertedError = "Reverted"
+)
+
+// Action is the call or the creation of a trace
+type Action struct {
+	CallType string         `json:"callType,omitempty"` // lower case call opcode, empty for creation
+	From     types.Address  `json:"from"`
+	To       *types.Address `json:"to,omitempty"` // nil for creation
+	Gas      uint64         `json:"gas"`
+	Input    []byte         `json:"input,omitempty"` // init code for creation
+	Value    *big.Int       `json:"value"`
+}
+
+// Result is the result of a succeeded trace
+type Result struct {
+	GasUsed uint64         `json:"gasUsed"`
+	Output  []byte         `json:"output,omitempty"`  // deployed code for creation
+	Address *types.Address `json:"address,omitempty"` // created contract
+}
+
+// Trace is a single call frame of the flat call traces, the frames of a transaction
+// are listed in the depth first order, located by their trace addresses
+type Trace struct {
+	Type         string  `json:"type"`
+	Action       Action  `json:"action"`
+	Result       *Result `json:"result,omitempty"` // nil if failed
+	Error        string  `json:"error,omitempty"`
+	Subtraces    int     `json:"subtraces"`
+	TraceAddress []int   `json:"traceAddress"`
+
+	created types.Address // the contract address of the creation
+}
+
+// TxTraces are the flat call traces of a transaction
+type TxTraces struct {
+	TxHash     types.Hash `json:"txHash"`
+	TxPosition uint64     `json:"txPosition"`
+	Traces     []*Trace   `json:"traces"`
+}
+
+// Tracer is an EVM logger collecting the flat call traces of the transactions, one
+// list per transaction in the order of execution. It is reused across the
+// transactions of a block.
+type Tracer struct {
+	txs   [][]*Trace
+	stack []*Trace // open frames
+}
+
+// NewTracer returns an empty tracer
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Traces returns the traces of the executed transactions
+func (t *Tracer) Traces() [][]*Trace {
+	return t.txs
+}
+
+// CaptureTxStart starts the traces of a new transaction
+func (t *Tracer) CaptureTxStart(pre runtime.Txn, ctx *runtime.TxContext, gasLimit uint64) {
+	t.txs = append(t.txs, make([]*Trace, 0, 1))
+	t.stack = t.stack[:0]
+}
+
+// CaptureTxEnd implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureTxEnd(restGas uint64) {}
+
+// CaptureStart adds the topmost call of the transaction
+func (t *Tracer) CaptureStart(txn runtime.Txn, from, to types.Address,
+	create bool, input []byte, gas uint64, value *big.Int) {
+	opCode := evm.OpCode(evm.CALL)
+	if create {
+		opCode = evm.CREATE
+	}
+
+	t.push(newTrace(opCode, from, to, input, gas, value), nil)
+}
+
+// CaptureEnd finishes the topmost call
+func (t *Tracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	t.pop(output, gasUsed, err)
+}
+
+// CaptureState implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureState(ctx *runtime.ScopeContext, pc uint64, opCode int,
+	gas, cost uint64, rData []byte, depth int, err error) {
+}
+
+// CaptureEnter adds an inner call under the current one
+func (t *Tracer) CaptureEnter(opCode int, from, to types.Address,
+	input []byte, gas uint64, value *big.Int) {
+	if len(t.stack) == 0 {
+		return
+	}
+
+	parent := t.stack[len(t.stack)-1]
+
+	trace := newTrace(evm.OpCode(opCode), from, to, input, gas, value)
+	trace.TraceAddress = append(append(make([]int, 0, len(parent.TraceAddress)+1), parent.TraceAddress...),
+		parent.Subtraces)
+	parent.Subtraces++
+
+	t.push(trace, parent)
+}
+
+// CaptureExit finishes the inner call
+func (t *Tracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.pop(output, gasUsed, err)
+}
+
+// CaptureFault implements the EVMLogger interface, nothing to do.
+func (t *Tracer) CaptureFault(ctx *runtime.ScopeContext, pc uint64, opCode int,
+	gas, cost uint64, depth int, err error) {
+}
+
+func (t *Tracer) push(trace *Trace, parent *Trace) {
+	if len(t.txs) == 0 {
+		// not started by a transaction, such as a bare call
+		t.txs = append(t.txs, nil)
+	}
+
+	last := len(t.txs) - 1
+	t.txs[last] = append(t.txs[last], trace)
+	t.stack = append(t.stack, trace)
+}
+
+func (t *Tracer) pop(output []byte, gasUsed uint64, err error) {
+	size := len(t.stack)
+	if size == 0 {
+		return
+	}
+
+	trace := t.stack[size-1]
+	t.stack = t.stack[:size-1]
+
+	if err != nil {
+		trace.Error = err.Error()
+		if errors.Is(err, runtime.ErrExecutionReverted) {
+			trace.Error = revertedError
+		}
+
+		return
+	}
+
+	trace.Result = &Result{
+		GasUsed: gasUsed,
+		Output:  copyBytes(output),
+	}
+
+	if trace.Type == TraceTypeCreate {
+		trace.Result.Address = &trace.created
+	}
+}
+
+func newTrace(opCode evm.OpCode, from, to types.Address, input []byte, gas uint64, value *big.Int) *Trace {
+	trace := &Trace{
+		Type: TraceTypeCall,
+		Action: Action{
+			From:  from,
+			To:    &to,
+			Gas:   gas,
+			Input: copyBytes(input),
+			Value: new(big.Int),
+		},
+		TraceAddress: []int{},
+	}
+
+	// static calls carry no value
+	if value != nil && opCode != evm.STATICCALL {
+		trace.Action.Value.Set(value)
+	}
+
+	switch opCode {
+	case evm.CREATE, evm.CREATE2:
+		trace.Type = TraceTypeCreate
+		trace.Action.To = nil
+		trace.created = to
+	default:
+		trace.Action.CallType = strings.ToLower(opCode.String())
+	}
+
+	return trace
+}
+
+func copyBytes(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+
+	cpy := make([]byte, len(b))
+	copy(cpy, b)
+
+	return cpy
+}
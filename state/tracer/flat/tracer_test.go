@@ -0,0 +1,160 @@
+package flat
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/state"
+	itrie "github.com/dogechain-lab/dogechain/state/immutable-trie"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/state/runtime/precompiled"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testSender   = types.StringToAddress("0x1000")
+	testCaller   = types.StringToAddress("0x2000")
+	testCallee   = types.StringToAddress("0x3000")
+	testCoinbase = types.StringToAddress("0x4000")
+
+	// MSTORE(0, 0x2a), then RETURN(0, 0x20)
+	testCalleeCode = "602a60005260206000f3"
+	// CALL(0xffff, callee, 0, 0, 0, 0, 0x20) twice, then RETURN(0, 0x20)
+	testCallerCode = "60206000600060006000" +
+		"73" + hex.EncodeToString(testCallee.Bytes()) +
+		"61fffff150" +
+		"60206000600060006000" +
+		"73" + hex.EncodeToString(testCallee.Bytes()) +
+		"61fffff150" +
+		"60206000f3"
+)
+
+func newTestTransition(t *testing.T) *state.Transition {
+	t.Helper()
+
+	executor := state.NewExecutor(
+		&chain.Params{Forks: chain.AllForksEnabled, ChainID: 100},
+		itrie.NewStateDB(itrie.NewMemoryStorage(), hclog.NewNullLogger(), nil),
+		hclog.NewNullLogger(),
+	)
+	executor.SetRuntime(precompiled.NewPrecompiled())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) func(i uint64) types.Hash {
+		return func(i uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		testSender: {
+			Balance: big.NewInt(1000000000),
+		},
+		testCaller: {
+			Code: hex.MustDecodeHex(testCallerCode),
+		},
+		testCallee: {
+			Code: hex.MustDecodeHex(testCalleeCode),
+		},
+	})
+	assert.NoError(t, err)
+
+	txn, err := executor.BeginTxn(root, &types.Header{
+		Number:   1,
+		GasLimit: 10000000,
+		Miner:    testCoinbase,
+	}, testCoinbase)
+	assert.NoError(t, err)
+
+	return txn
+}
+
+func TestTracer(t *testing.T) {
+	txn := newTestTransition(t)
+
+	tracer := NewTracer()
+	txn.SetEVMLogger(tracer)
+
+	// a nested call, then a creation which reverts
+	txs := []*types.Transaction{
+		{
+			From:     testSender,
+			To:       &testCaller,
+			Gas:      200000,
+			GasPrice: big.NewInt(1),
+			Value:    big.NewInt(10),
+		},
+		{
+			From:     testSender,
+			Nonce:    1,
+			Gas:      100000,
+			GasPrice: big.NewInt(1),
+			Value:    big.NewInt(0),
+			// REVERT(0, 0)
+			Input: hex.MustDecodeHex("60006000fd"),
+		},
+		{
+			From:     testSender,
+			Nonce:    2,
+			Gas:      100000,
+			GasPrice: big.NewInt(1),
+			Value:    big.NewInt(0),
+			// RETURN(0, 0)
+			Input: hex.MustDecodeHex("60006000f3"),
+		},
+	}
+
+	for _, tx := range txs {
+		_, err := txn.Apply(tx)
+		assert.NoError(t, err)
+	}
+
+	traces := tracer.Traces()
+	assert.Len(t, traces, 3)
+
+	// the topmost call and the two inner calls in order
+	calls := traces[0]
+	assert.Len(t, calls, 3)
+
+	top := calls[0]
+	assert.Equal(t, TraceTypeCall, top.Type)
+	assert.Equal(t, "call", top.Action.CallType)
+	assert.Equal(t, testSender, top.Action.From)
+	assert.Equal(t, testCaller, *top.Action.To)
+	assert.Equal(t, big.NewInt(10), top.Action.Value)
+	assert.Equal(t, 2, top.Subtraces)
+	assert.Equal(t, []int{}, top.TraceAddress)
+	assert.Empty(t, top.Error)
+	assert.NotNil(t, top.Result)
+
+	for i, call := range calls[1:] {
+		assert.Equal(t, testCaller, call.Action.From)
+		assert.Equal(t, testCallee, *call.Action.To)
+		assert.Equal(t, uint64(0xffff), call.Action.Gas)
+		assert.Equal(t, []int{i}, call.TraceAddress)
+		assert.Equal(t, 0, call.Subtraces)
+		assert.Equal(t, 32, len(call.Result.Output))
+	}
+
+	// the failed creation
+	creates := traces[1]
+	assert.Len(t, creates, 1)
+
+	create := creates[0]
+	assert.Equal(t, TraceTypeCreate, create.Type)
+	assert.Empty(t, create.Action.CallType)
+	assert.Nil(t, create.Action.To)
+	assert.Nil(t, create.Result)
+	assert.Equal(t, revertedError, create.Error)
+
+	// the succeeded creation
+	assert.Len(t, traces[2], 1)
+
+	create = traces[2][0]
+	assert.Nil(t, create.Action.To)
+	assert.Equal(t, crypto.CreateAddress(testSender, 2), *create.Result.Address)
+}
@@ -0,0 +1,224 @@
+package js
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/dop251/goja"
+)
+
+// maxBits limits the shifts and exponents, the evm words are 256 bits at most
+const maxBits = 4096
+
+// bigIntLib exposes arbitrary precision integers to the tracers. It implements
+// the commonly used subset of the BigInteger.js api, which the geth tracers rely on.
+type bigIntLib struct {
+	vm  *goja.Runtime
+	key *goja.Symbol // hidden property holding the go value
+}
+
+func newBigIntLib(vm *goja.Runtime) *bigIntLib {
+	return &bigIntLib{
+		vm:  vm,
+		key: goja.NewSymbol("bigInt"),
+	}
+}
+
+// constructor is the js `bigInt(value, base)` function
+func (b *bigIntLib) constructor(call goja.FunctionCall) goja.Value {
+	x, err := b.parse(call.Argument(0), call.Argument(1))
+	if err != nil {
+		panic(b.vm.NewTypeError(err.Error()))
+	}
+
+	return b.wrap(x)
+}
+
+// parse converts a js value to a big integer
+func (b *bigIntLib) parse(v, base goja.Value) (*big.Int, error) {
+	if goja.IsUndefined(v) || goja.IsNull(v) {
+		return new(big.Int), nil
+	}
+
+	if obj, ok := v.(*goja.Object); ok {
+		if x, ok := obj.GetSymbol(b.key).Export().(*big.Int); ok {
+			return x, nil
+		}
+	}
+
+	switch val := v.Export().(type) {
+	case int64:
+		return big.NewInt(val), nil
+	case float64:
+		x, _ := big.NewFloat(val).Int(nil)
+
+		return x, nil
+	case string:
+		radix := 10
+		if !goja.IsUndefined(base) {
+			radix = int(base.ToInteger())
+		}
+
+		str := strings.TrimSpace(val)
+		if radix == 10 && (strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X")) {
+			str, radix = str[2:], 16
+		}
+
+		x, ok := new(big.Int).SetString(str, radix)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", val)
+		}
+
+		return x, nil
+	}
+
+	return nil, fmt.Errorf("invalid integer %v", v)
+}
+
+// arg parses the argument of a method, throwing a js error on failure
+func (b *bigIntLib) arg(call goja.FunctionCall, idx int) *big.Int {
+	x, err := b.parse(call.Argument(idx), goja.Undefined())
+	if err != nil {
+		panic(b.vm.NewTypeError(err.Error()))
+	}
+
+	return x
+}
+
+// wrap converts a big integer to an immutable js object
+func (b *bigIntLib) wrap(x *big.Int) goja.Value {
+	var (
+		vm  = b.vm
+		obj = vm.NewObject()
+	)
+
+	x = new(big.Int).Set(x)
+
+	binary := func(op func(z, x, y *big.Int) *big.Int) func(goja.FunctionCall) goja.Value {
+		return func(call goja.FunctionCall) goja.Value {
+			return b.wrap(op(new(big.Int), x, b.arg(call, 0)))
+		}
+	}
+
+	division := func(op func(z, x, y *big.Int) *big.Int) func(goja.FunctionCall) goja.Value {
+		return func(call goja.FunctionCall) goja.Value {
+			y := b.arg(call, 0)
+			if y.Sign() == 0 {
+				panic(vm.NewTypeError("division by zero"))
+			}
+
+			return b.wrap(op(new(big.Int), x, y))
+		}
+	}
+
+	// bits validates the bit count argument, which bounds the size of the result
+	bits := func(call goja.FunctionCall) uint {
+		n := call.Argument(0).ToInteger()
+		if n < 0 || n > maxBits {
+			panic(vm.NewTypeError(fmt.Sprintf("invalid bit count %d", n)))
+		}
+
+		return uint(n)
+	}
+
+	compare := func(call goja.FunctionCall) int {
+		return x.Cmp(b.arg(call, 0))
+	}
+
+	toString := func(call goja.FunctionCall) goja.Value {
+		radix := 10
+		if arg := call.Argument(0); !goja.IsUndefined(arg) {
+			radix = int(arg.ToInteger())
+		}
+
+		if radix < 2 || radix > 36 {
+			panic(vm.NewTypeError(fmt.Sprintf("invalid radix %d", radix)))
+		}
+
+		return vm.ToValue(x.Text(radix))
+	}
+
+	methods := map[string]func(goja.FunctionCall) goja.Value{
+		"add":      binary((*big.Int).Add),
+		"subtract": binary((*big.Int).Sub),
+		"multiply": binary((*big.Int).Mul),
+		"and":      binary((*big.Int).And),
+		"or":       binary((*big.Int).Or),
+		"xor":      binary((*big.Int).Xor),
+		"divide":   division((*big.Int).Quo),
+		"mod":      division((*big.Int).Rem),
+		"pow": func(call goja.FunctionCall) goja.Value {
+			y := b.arg(call, 0)
+			if y.Sign() < 0 || y.Cmp(big.NewInt(maxBits)) > 0 {
+				panic(vm.NewTypeError(fmt.Sprintf("invalid exponent %s", y)))
+			}
+
+			return b.wrap(new(big.Int).Exp(x, y, nil))
+		},
+		"shiftLeft": func(call goja.FunctionCall) goja.Value {
+			return b.wrap(new(big.Int).Lsh(x, bits(call)))
+		},
+		"shiftRight": func(call goja.FunctionCall) goja.Value {
+			return b.wrap(new(big.Int).Rsh(x, bits(call)))
+		},
+		"negate": func(call goja.FunctionCall) goja.Value {
+			return b.wrap(new(big.Int).Neg(x))
+		},
+		"abs": func(call goja.FunctionCall) goja.Value {
+			return b.wrap(new(big.Int).Abs(x))
+		},
+		"compare": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(compare(call))
+		},
+		"equals": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(compare(call) == 0)
+		},
+		"notEquals": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(compare(call) != 0)
+		},
+		"greater": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(compare(call) > 0)
+		},
+		"greaterOrEquals": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(compare(call) >= 0)
+		},
+		"lesser": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(compare(call) < 0)
+		},
+		"lesserOrEquals": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(compare(call) <= 0)
+		},
+		"isZero": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(x.Sign() == 0)
+		},
+		"isNegative": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(x.Sign() < 0)
+		},
+		"toJSNumber": func(call goja.FunctionCall) goja.Value {
+			f, _ := new(big.Float).SetInt(x).Float64()
+
+			return vm.ToValue(f)
+		},
+		"toString": toString,
+		"toJSON": func(call goja.FunctionCall) goja.Value {
+			return vm.ToValue(x.String())
+		},
+		"valueOf": func(call goja.FunctionCall) goja.Value {
+			f, _ := new(big.Float).SetInt(x).Float64()
+
+			return vm.ToValue(f)
+		},
+	}
+
+	for name, fn := range methods {
+		// the methods are not enumerable, so that they are skipped in the json output
+		_ = obj.DefineDataProperty(name, vm.ToValue(fn), goja.FLAG_FALSE, goja.FLAG_FALSE, goja.FLAG_FALSE)
+	}
+
+	// alias of the BigInteger.js api
+	_ = obj.DefineDataProperty("toNumber", obj.Get("toJSNumber"), goja.FLAG_FALSE, goja.FLAG_FALSE, goja.FLAG_FALSE)
+	_ = obj.DefineDataPropertySymbol(b.key, vm.ToValue(x), goja.FLAG_FALSE, goja.FLAG_FALSE, goja.FLAG_FALSE)
+
+	return obj
+}
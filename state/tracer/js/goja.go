@@ -0,0 +1,921 @@
+// Package js implements the tracers which are written in javascript. The user
+// supplied code is evaluated by an embedded pure go interpreter.
+//
+// A tracer is an object expression which must expose the `result(ctx, db)` and
+// `fault(log, db)` functions. The `step(log, db)` function and the pair of the
+// `enter(frame)` and `exit(frameResult)` functions are optional.
+package js
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/state/runtime/precompiled"
+	"github.com/dogechain-lab/dogechain/state/tracer"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/dop251/goja"
+)
+
+// init registers itself this packages as a lookup for tracers.
+func init() {
+	tracer.RegisterLookup(true, newJsTracer)
+}
+
+var (
+	errInvalidBuffer = errors.New("invalid buffer type")
+	errOutOfBound    = errors.New("tracer accessed out of bound")
+)
+
+type toBufFn = func(val []byte) (goja.Value, error)
+type fromBufFn = func(val goja.Value, allowString bool) ([]byte, error)
+
+// jsTracer is an implementation of the Tracer interface which evaluates
+// js functions in the relevant section of the execution.
+type jsTracer struct {
+	vm  *goja.Runtime
+	lib *bigIntLib
+	env *tracer.Context
+
+	toBuf   toBufFn
+	fromBuf fromBufFn
+
+	activePrecompiles []types.Address // Updated on CaptureStart based on the forks
+	traceStep         bool            // True if tracer object exposes a `step()` method
+	traceFrame        bool            // True if tracer object exposes the `enter()` and `exit()` methods
+	gasLimit          uint64          // Amount of gas bought for the whole tx
+	err               error           // Any error that should stop tracing
+	obj               *goja.Object    // Trace object
+
+	// Methods exposed by tracer
+	result goja.Callable
+	fault  goja.Callable
+	step   goja.Callable
+	enter  goja.Callable
+	exit   goja.Callable
+
+	// Underlying structs being passed into JS
+	ctx         map[string]goja.Value
+	db          *dbObj
+	log         *steplog
+	frame       *callframe
+	frameResult *callframeResult
+
+	// Goja-wrapping of types prepared for JS consumption
+	dbValue          goja.Value
+	logValue         goja.Value
+	frameValue       goja.Value
+	frameResultValue goja.Value
+}
+
+// newJsTracer instantiates a new JS tracer instance. code is a
+// Javascript snippet which evaluates to an expression returning
+// an object with certain methods:
+//
+// The methods `result` and `fault` are required to be present.
+// The methods `step`, `enter`, and `exit` are optional, but note that
+// `enter` and `exit` always go together.
+func newJsTracer(code string, ctx *tracer.Context, cfg json.RawMessage) (tracer.Tracer, error) {
+	if ctx == nil {
+		ctx = new(tracer.Context)
+	}
+
+	vm := goja.New()
+	// By default field names are exported to JS as is, i.e. capitalized.
+	vm.SetFieldNameMapper(goja.UncapFieldNameMapper())
+
+	t := &jsTracer{
+		vm:  vm,
+		lib: newBigIntLib(vm),
+		env: ctx,
+		ctx: make(map[string]goja.Value),
+	}
+
+	if err := t.setTypeConverters(); err != nil {
+		return nil, err
+	}
+
+	if err := t.setBuiltinFunctions(); err != nil {
+		return nil, err
+	}
+
+	if ctx.BlockHash != types.ZeroHash {
+		blockHash, err := t.toBuf(ctx.BlockHash.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		t.ctx["blockHash"] = blockHash
+
+		if ctx.TxHash != types.ZeroHash {
+			txHash, err := t.toBuf(ctx.TxHash.Bytes())
+			if err != nil {
+				return nil, err
+			}
+
+			t.ctx["txIndex"] = vm.ToValue(ctx.TxIndex)
+			t.ctx["txHash"] = txHash
+		}
+	}
+
+	ret, err := vm.RunString("(" + code + ")")
+	if err != nil {
+		return nil, err
+	}
+
+	// Check tracer's interface for required and optional methods.
+	obj := ret.ToObject(vm)
+
+	result, ok := goja.AssertFunction(obj.Get("result"))
+	if !ok {
+		return nil, errors.New("trace object must expose a function result()")
+	}
+
+	fault, ok := goja.AssertFunction(obj.Get("fault"))
+	if !ok {
+		return nil, errors.New("trace object must expose a function fault()")
+	}
+
+	step, ok := goja.AssertFunction(obj.Get("step"))
+	t.traceStep = ok
+
+	enter, hasEnter := goja.AssertFunction(obj.Get("enter"))
+	exit, hasExit := goja.AssertFunction(obj.Get("exit"))
+
+	if hasEnter != hasExit {
+		return nil, errors.New("trace object must expose either both or none of enter() and exit()")
+	}
+
+	t.traceFrame = hasEnter
+	t.obj = obj
+	t.step = step
+	t.enter = enter
+	t.exit = exit
+	t.result = result
+	t.fault = fault
+
+	// Setup objects carrying data to JS. These are created once and re-used.
+	t.log = &steplog{
+		vm:       vm,
+		op:       &opObj{vm: vm},
+		memory:   &memoryObj{vm: vm, toBig: t.lib.wrap, toBuf: t.toBuf},
+		stack:    &stackObj{vm: vm, toBig: t.lib.wrap},
+		contract: &contractObj{vm: vm, toBig: t.lib.wrap, toBuf: t.toBuf},
+	}
+	t.db = &dbObj{vm: vm, toBig: t.lib.wrap, toBuf: t.toBuf, fromBuf: t.fromBuf}
+	t.frame = &callframe{vm: vm, toBig: t.lib.wrap, toBuf: t.toBuf}
+	t.frameResult = &callframeResult{vm: vm, toBuf: t.toBuf}
+	t.logValue = t.log.setupObject()
+	t.dbValue = t.db.setupObject()
+	t.frameValue = t.frame.setupObject()
+	t.frameResultValue = t.frameResult.setupObject()
+
+	// Pass in the config
+	if setup, ok := goja.AssertFunction(obj.Get("setup")); ok {
+		cfgStr := "{}"
+		if cfg != nil {
+			cfgStr = string(cfg)
+		}
+
+		if _, err := setup(obj, vm.ToValue(cfgStr)); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// CaptureTxStart implements the EVMLogger interface and is invoked at the beginning of
+// transaction processing.
+func (t *jsTracer) CaptureTxStart(pre runtime.Txn, ctx *runtime.TxContext, gasLimit uint64) {
+	t.gasLimit = gasLimit
+
+	t.ctx["gasPrice"] = t.lib.wrap(new(big.Int).SetBytes(ctx.GasPrice.Bytes()))
+	t.ctx["block"] = t.vm.ToValue(ctx.Number)
+}
+
+// CaptureTxEnd implements the EVMLogger interface and is invoked at the end of
+// transaction processing.
+func (t *jsTracer) CaptureTxEnd(restGas uint64) {
+	t.ctx["gasUsed"] = t.vm.ToValue(t.gasLimit - restGas)
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *jsTracer) CaptureStart(txn runtime.Txn, from, to types.Address,
+	create bool, input []byte, gas uint64, value *big.Int) {
+	t.db.txn = txn
+	t.activePrecompiles = precompiled.ActiveAddresses(&t.env.Forks)
+
+	typ := "CALL"
+	if create {
+		typ = "CREATE"
+	}
+
+	t.ctx["type"] = t.vm.ToValue(typ)
+
+	if err := t.setBuf("from", from.Bytes()); err != nil {
+		return
+	}
+
+	if err := t.setBuf("to", to.Bytes()); err != nil {
+		return
+	}
+
+	if err := t.setBuf("input", input); err != nil {
+		return
+	}
+
+	t.ctx["gas"] = t.vm.ToValue(t.gasLimit)
+	t.ctx["value"] = t.lib.wrap(bigOrZero(value))
+	// the intrinsic gas is consumed before the execution
+	t.ctx["intrinsicGas"] = t.vm.ToValue(t.gasLimit - gas)
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *jsTracer) CaptureState(ctx *runtime.ScopeContext, pc uint64, opCode int,
+	gas, cost uint64, rData []byte, depth int, err error) {
+	if !t.traceStep || t.err != nil {
+		return
+	}
+
+	t.setLog(ctx, pc, opCode, gas, cost, depth, err)
+
+	if _, err := t.step(t.obj, t.logValue, t.dbValue); err != nil {
+		t.onError("step", err)
+	}
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *jsTracer) CaptureFault(ctx *runtime.ScopeContext, pc uint64, opCode int,
+	gas, cost uint64, depth int, err error) {
+	if t.err != nil {
+		return
+	}
+
+	t.setLog(ctx, pc, opCode, gas, cost, depth, err)
+
+	if _, err := t.fault(t.obj, t.logValue, t.dbValue); err != nil {
+		t.onError("fault", err)
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *jsTracer) CaptureEnd(output []byte, gasUsed uint64, duration time.Duration, err error) {
+	if err := t.setBuf("output", output); err != nil {
+		return
+	}
+
+	t.ctx["time"] = t.vm.ToValue(duration.String())
+
+	if err != nil {
+		t.ctx["error"] = t.vm.ToValue(err.Error())
+	}
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *jsTracer) CaptureEnter(opCode int, from, to types.Address,
+	input []byte, gas uint64, value *big.Int) {
+	if !t.traceFrame || t.err != nil {
+		return
+	}
+
+	op := evm.OpCode(opCode)
+
+	t.frame.typ = op.String()
+	t.frame.from = from
+	t.frame.to = to
+	t.frame.input = copyBytes(input)
+	t.frame.gas = gas
+	t.frame.value = nil
+
+	// static calls carry no value
+	if op != evm.STATICCALL && value != nil {
+		t.frame.value = new(big.Int).Set(value)
+	}
+
+	if _, err := t.enter(t.obj, t.frameValue); err != nil {
+		t.onError("enter", err)
+	}
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *jsTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if !t.traceFrame || t.err != nil {
+		return
+	}
+
+	t.frameResult.gasUsed = gasUsed
+	t.frameResult.output = copyBytes(output)
+	t.frameResult.err = err
+
+	if _, err := t.exit(t.obj, t.frameResultValue); err != nil {
+		t.onError("exit", err)
+	}
+}
+
+// GetResult calls the Javascript 'result' function and returns its value, or any accumulated error
+func (t *jsTracer) GetResult() (json.RawMessage, error) {
+	ctx := t.vm.ToValue(t.ctx)
+
+	res, err := t.result(t.obj, ctx, t.dbValue)
+	if err != nil {
+		return nil, wrapError("result", err)
+	}
+
+	encoded, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(encoded), t.err
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+// The running js code is interrupted as well, so that an endless loop
+// can't outlive the trace timeout, and the traced execution is aborted.
+func (t *jsTracer) Stop(err error) {
+	t.vm.Interrupt(err)
+	t.cancel()
+}
+
+// onError is called anytime the running JS code is interrupted
+// and returns an error. It in turn pings the EVM to cancel its
+// execution.
+func (t *jsTracer) onError(context string, err error) {
+	t.err = wrapError(context, err)
+	t.cancel()
+}
+
+// cancel aborts the traced execution, if supported by the caller
+func (t *jsTracer) cancel() {
+	if t.env.Cancel != nil {
+		t.env.Cancel()
+	}
+}
+
+func wrapError(context string, err error) error {
+	return fmt.Errorf("%w    in server-side tracer function '%v'", err, context)
+}
+
+// setLog updates the reused step log object
+func (t *jsTracer) setLog(ctx *runtime.ScopeContext, pc uint64, opCode int,
+	gas, cost uint64, depth int, err error) {
+	log := t.log
+	log.op.op = evm.OpCode(opCode)
+	log.memory.memory = ctx.Memory
+	log.stack.stack = ctx.Stack
+	log.contract.scope = ctx
+	log.pc = pc
+	log.gas = gas
+	log.cost = cost
+	log.depth = depth
+	log.err = err
+
+	if t.db.txn != nil {
+		log.refund = t.db.txn.GetRefund()
+	}
+}
+
+// setBuf sets a buffer field of the ctx object
+func (t *jsTracer) setBuf(name string, val []byte) error {
+	buf, err := t.toBuf(val)
+	if err != nil {
+		t.err = err
+
+		return err
+	}
+
+	t.ctx[name] = buf
+
+	return nil
+}
+
+func (t *jsTracer) setTypeConverters() error {
+	// Inject bigint logic.
+	if err := t.vm.Set("bigInt", t.lib.constructor); err != nil {
+		return err
+	}
+
+	// Used to create JS Uint8Array instances from go byte slices.
+	toBufVal, err := t.vm.RunString("(function(buf) { return new Uint8Array(buf) })")
+	if err != nil {
+		return err
+	}
+
+	toBuf, ok := goja.AssertFunction(toBufVal)
+	if !ok {
+		return errors.New("failed to create the buffer converter")
+	}
+
+	t.toBuf = func(val []byte) (goja.Value, error) {
+		// the array buffer shares the slice, so hand over a copy
+		return toBuf(goja.Undefined(), t.vm.ToValue(t.vm.NewArrayBuffer(copyBytes(val))))
+	}
+
+	bufType := t.vm.Get("Uint8Array")
+
+	t.fromBuf = func(val goja.Value, allowString bool) ([]byte, error) {
+		obj := val.ToObject(t.vm)
+
+		switch obj.ClassName() {
+		case "String":
+			if !allowString {
+				break
+			}
+
+			return hex.DecodeHex(obj.String())
+		case "Array":
+			var b []byte
+			if err := t.vm.ExportTo(val, &b); err != nil {
+				return nil, err
+			}
+
+			return b, nil
+		case "Object":
+			if !obj.Get("constructor").SameAs(bufType) {
+				break
+			}
+
+			buffer, ok := obj.Get("buffer").Export().(goja.ArrayBuffer)
+			if !ok {
+				break
+			}
+
+			return copyBytes(buffer.Bytes()), nil
+		}
+
+		return nil, errInvalidBuffer
+	}
+
+	return nil
+}
+
+func (t *jsTracer) setBuiltinFunctions() error {
+	vm := t.vm
+
+	// throw raises a js exception with the error
+	throw := func(err error) {
+		panic(vm.NewTypeError(err.Error()))
+	}
+
+	buf := func(val goja.Value, allowString bool) []byte {
+		b, err := t.fromBuf(val, allowString)
+		if err != nil {
+			throw(err)
+		}
+
+		return b
+	}
+
+	toBuf := func(val []byte) goja.Value {
+		res, err := t.toBuf(val)
+		if err != nil {
+			throw(err)
+		}
+
+		return res
+	}
+
+	builtins := map[string]interface{}{
+		"toHex": func(v goja.Value) string {
+			return hex.EncodeToHex(buf(v, false))
+		},
+		"toWord": func(v goja.Value) goja.Value {
+			return toBuf(types.BytesToHash(buf(v, true)).Bytes())
+		},
+		"toAddress": func(v goja.Value) goja.Value {
+			return toBuf(types.BytesToAddress(buf(v, true)).Bytes())
+		},
+		"toContract": func(from goja.Value, nonce uint64) goja.Value {
+			addr := types.BytesToAddress(buf(from, true))
+
+			return toBuf(crypto.CreateAddress(addr, nonce).Bytes())
+		},
+		"toContract2": func(from goja.Value, salt string, initcode goja.Value) goja.Value {
+			addr := types.BytesToAddress(buf(from, true))
+			code := buf(initcode, true)
+
+			return toBuf(crypto.CreateAddress2(addr, types.StringToHash(salt), code).Bytes())
+		},
+		"isPrecompiled": func(v goja.Value) bool {
+			addr := types.BytesToAddress(buf(v, true))
+			for _, p := range t.activePrecompiles {
+				if p == addr {
+					return true
+				}
+			}
+
+			return false
+		},
+		"slice": func(slice goja.Value, start, end int64) goja.Value {
+			b := buf(slice, false)
+			if start < 0 || start > end || end > int64(len(b)) {
+				throw(fmt.Errorf("%w slice: start %d, end %d, size %d", errOutOfBound, start, end, len(b)))
+			}
+
+			return toBuf(b[start:end])
+		},
+	}
+
+	for name, fn := range builtins {
+		if err := vm.Set(name, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type opObj struct {
+	vm *goja.Runtime
+	op evm.OpCode
+}
+
+func (o *opObj) ToNumber() int {
+	return int(o.op)
+}
+
+func (o *opObj) ToString() string {
+	return o.op.String()
+}
+
+func (o *opObj) IsPush() bool {
+	return o.op >= evm.PUSH0 && o.op <= evm.PUSH32
+}
+
+func (o *opObj) setupObject() *goja.Object {
+	obj := o.vm.NewObject()
+	_ = obj.Set("toNumber", o.vm.ToValue(o.ToNumber))
+	_ = obj.Set("toString", o.vm.ToValue(o.ToString))
+	_ = obj.Set("isPush", o.vm.ToValue(o.IsPush))
+
+	return obj
+}
+
+type memoryObj struct {
+	vm     *goja.Runtime
+	memory []byte
+	toBig  func(*big.Int) goja.Value
+	toBuf  toBufFn
+}
+
+func (mo *memoryObj) Slice(begin, end int64) goja.Value {
+	b, err := mo.slice(begin, end)
+	if err != nil {
+		panic(mo.vm.NewTypeError(err.Error()))
+	}
+
+	res, err := mo.toBuf(b)
+	if err != nil {
+		panic(mo.vm.NewTypeError(err.Error()))
+	}
+
+	return res
+}
+
+// slice returns the requested range of memory as a byte slice.
+func (mo *memoryObj) slice(begin, end int64) ([]byte, error) {
+	if end == begin {
+		return []byte{}, nil
+	}
+
+	if end < begin || begin < 0 {
+		return nil, fmt.Errorf("%w memory: begin %d, end %d", errOutOfBound, begin, end)
+	}
+
+	if int64(len(mo.memory)) < end {
+		return nil, fmt.Errorf("%w memory: available %d, offset %d, size %d",
+			errOutOfBound, len(mo.memory), begin, end-begin)
+	}
+
+	return copyBytes(mo.memory[begin:end]), nil
+}
+
+func (mo *memoryObj) GetUint(addr int64) goja.Value {
+	b, err := mo.slice(addr, addr+32)
+	if err != nil {
+		panic(mo.vm.NewTypeError(err.Error()))
+	}
+
+	return mo.toBig(new(big.Int).SetBytes(b))
+}
+
+func (mo *memoryObj) Length() int {
+	return len(mo.memory)
+}
+
+func (mo *memoryObj) setupObject() *goja.Object {
+	obj := mo.vm.NewObject()
+	_ = obj.Set("slice", mo.vm.ToValue(mo.Slice))
+	_ = obj.Set("getUint", mo.vm.ToValue(mo.GetUint))
+	_ = obj.Set("length", mo.vm.ToValue(mo.Length))
+
+	return obj
+}
+
+type stackObj struct {
+	vm    *goja.Runtime
+	stack []*big.Int
+	toBig func(*big.Int) goja.Value
+}
+
+// Peek returns the idx-th element from the top of the stack
+func (s *stackObj) Peek(idx int) goja.Value {
+	if idx < 0 || idx >= len(s.stack) {
+		panic(s.vm.NewTypeError(fmt.Sprintf("%v stack: length %d, index %d", errOutOfBound, len(s.stack), idx)))
+	}
+
+	return s.toBig(s.stack[len(s.stack)-idx-1])
+}
+
+func (s *stackObj) Length() int {
+	return len(s.stack)
+}
+
+func (s *stackObj) setupObject() *goja.Object {
+	obj := s.vm.NewObject()
+	_ = obj.Set("peek", s.vm.ToValue(s.Peek))
+	_ = obj.Set("length", s.vm.ToValue(s.Length))
+
+	return obj
+}
+
+type dbObj struct {
+	vm      *goja.Runtime
+	txn     runtime.Txn
+	toBig   func(*big.Int) goja.Value
+	toBuf   toBufFn
+	fromBuf fromBufFn
+}
+
+func (do *dbObj) address(v goja.Value) types.Address {
+	b, err := do.fromBuf(v, true)
+	if err != nil {
+		panic(do.vm.NewTypeError(err.Error()))
+	}
+
+	return types.BytesToAddress(b)
+}
+
+func (do *dbObj) buf(b []byte) goja.Value {
+	res, err := do.toBuf(b)
+	if err != nil {
+		panic(do.vm.NewTypeError(err.Error()))
+	}
+
+	return res
+}
+
+func (do *dbObj) GetBalance(addrSlice goja.Value) goja.Value {
+	return do.toBig(bigOrZero(do.txn.GetBalance(do.address(addrSlice))))
+}
+
+func (do *dbObj) GetNonce(addrSlice goja.Value) uint64 {
+	return do.txn.GetNonce(do.address(addrSlice))
+}
+
+func (do *dbObj) GetCode(addrSlice goja.Value) goja.Value {
+	return do.buf(do.txn.GetCode(do.address(addrSlice)))
+}
+
+func (do *dbObj) GetState(addrSlice goja.Value, hashSlice goja.Value) goja.Value {
+	b, err := do.fromBuf(hashSlice, false)
+	if err != nil {
+		panic(do.vm.NewTypeError(err.Error()))
+	}
+
+	state, err := do.txn.GetState(do.address(addrSlice), types.BytesToHash(b))
+	if err != nil {
+		panic(do.vm.NewGoError(err))
+	}
+
+	return do.buf(state.Bytes())
+}
+
+func (do *dbObj) Exists(addrSlice goja.Value) bool {
+	return do.txn.Exist(do.address(addrSlice))
+}
+
+func (do *dbObj) setupObject() *goja.Object {
+	obj := do.vm.NewObject()
+	_ = obj.Set("getBalance", do.vm.ToValue(do.GetBalance))
+	_ = obj.Set("getNonce", do.vm.ToValue(do.GetNonce))
+	_ = obj.Set("getCode", do.vm.ToValue(do.GetCode))
+	_ = obj.Set("getState", do.vm.ToValue(do.GetState))
+	_ = obj.Set("exists", do.vm.ToValue(do.Exists))
+
+	return obj
+}
+
+type contractObj struct {
+	vm    *goja.Runtime
+	scope *runtime.ScopeContext
+	toBig func(*big.Int) goja.Value
+	toBuf toBufFn
+}
+
+func (co *contractObj) buf(b []byte) goja.Value {
+	res, err := co.toBuf(b)
+	if err != nil {
+		panic(co.vm.NewTypeError(err.Error()))
+	}
+
+	return res
+}
+
+func (co *contractObj) GetCaller() goja.Value {
+	return co.buf(co.scope.Caller.Bytes())
+}
+
+func (co *contractObj) GetAddress() goja.Value {
+	return co.buf(co.scope.ContractAddress.Bytes())
+}
+
+func (co *contractObj) GetValue() goja.Value {
+	return co.toBig(bigOrZero(co.scope.Value))
+}
+
+func (co *contractObj) GetInput() goja.Value {
+	return co.buf(co.scope.Input)
+}
+
+func (co *contractObj) setupObject() *goja.Object {
+	obj := co.vm.NewObject()
+	_ = obj.Set("getCaller", co.vm.ToValue(co.GetCaller))
+	_ = obj.Set("getAddress", co.vm.ToValue(co.GetAddress))
+	_ = obj.Set("getValue", co.vm.ToValue(co.GetValue))
+	_ = obj.Set("getInput", co.vm.ToValue(co.GetInput))
+
+	return obj
+}
+
+type callframe struct {
+	vm    *goja.Runtime
+	toBig func(*big.Int) goja.Value
+	toBuf toBufFn
+
+	typ   string
+	from  types.Address
+	to    types.Address
+	input []byte
+	gas   uint64
+	value *big.Int
+}
+
+func (f *callframe) buf(b []byte) goja.Value {
+	res, err := f.toBuf(b)
+	if err != nil {
+		panic(f.vm.NewTypeError(err.Error()))
+	}
+
+	return res
+}
+
+func (f *callframe) GetType() string {
+	return f.typ
+}
+
+func (f *callframe) GetFrom() goja.Value {
+	return f.buf(f.from.Bytes())
+}
+
+func (f *callframe) GetTo() goja.Value {
+	return f.buf(f.to.Bytes())
+}
+
+func (f *callframe) GetInput() goja.Value {
+	return f.buf(f.input)
+}
+
+func (f *callframe) GetGas() uint64 {
+	return f.gas
+}
+
+func (f *callframe) GetValue() goja.Value {
+	if f.value == nil {
+		return goja.Undefined()
+	}
+
+	return f.toBig(f.value)
+}
+
+func (f *callframe) setupObject() *goja.Object {
+	obj := f.vm.NewObject()
+	_ = obj.Set("getType", f.vm.ToValue(f.GetType))
+	_ = obj.Set("getFrom", f.vm.ToValue(f.GetFrom))
+	_ = obj.Set("getTo", f.vm.ToValue(f.GetTo))
+	_ = obj.Set("getInput", f.vm.ToValue(f.GetInput))
+	_ = obj.Set("getGas", f.vm.ToValue(f.GetGas))
+	_ = obj.Set("getValue", f.vm.ToValue(f.GetValue))
+
+	return obj
+}
+
+type callframeResult struct {
+	vm    *goja.Runtime
+	toBuf toBufFn
+
+	gasUsed uint64
+	output  []byte
+	err     error
+}
+
+func (r *callframeResult) GetGasUsed() uint64 {
+	return r.gasUsed
+}
+
+func (r *callframeResult) GetOutput() goja.Value {
+	res, err := r.toBuf(r.output)
+	if err != nil {
+		panic(r.vm.NewTypeError(err.Error()))
+	}
+
+	return res
+}
+
+func (r *callframeResult) GetError() goja.Value {
+	if r.err != nil {
+		return r.vm.ToValue(r.err.Error())
+	}
+
+	return goja.Undefined()
+}
+
+func (r *callframeResult) setupObject() *goja.Object {
+	obj := r.vm.NewObject()
+	_ = obj.Set("getGasUsed", r.vm.ToValue(r.GetGasUsed))
+	_ = obj.Set("getOutput", r.vm.ToValue(r.GetOutput))
+	_ = obj.Set("getError", r.vm.ToValue(r.GetError))
+
+	return obj
+}
+
+type steplog struct {
+	vm *goja.Runtime
+
+	op       *opObj
+	memory   *memoryObj
+	stack    *stackObj
+	contract *contractObj
+
+	pc     uint64
+	gas    uint64
+	cost   uint64
+	depth  int
+	refund uint64
+	err    error
+}
+
+func (l *steplog) GetPC() uint64     { return l.pc }
+func (l *steplog) GetGas() uint64    { return l.gas }
+func (l *steplog) GetCost() uint64   { return l.cost }
+func (l *steplog) GetDepth() int     { return l.depth }
+func (l *steplog) GetRefund() uint64 { return l.refund }
+
+func (l *steplog) GetError() goja.Value {
+	if l.err != nil {
+		return l.vm.ToValue(l.err.Error())
+	}
+
+	return goja.Undefined()
+}
+
+func (l *steplog) setupObject() *goja.Object {
+	obj := l.vm.NewObject()
+	// Setup basic fields.
+	_ = obj.Set("getPC", l.vm.ToValue(l.GetPC))
+	_ = obj.Set("getGas", l.vm.ToValue(l.GetGas))
+	_ = obj.Set("getCost", l.vm.ToValue(l.GetCost))
+	_ = obj.Set("getDepth", l.vm.ToValue(l.GetDepth))
+	_ = obj.Set("getRefund", l.vm.ToValue(l.GetRefund))
+	_ = obj.Set("getError", l.vm.ToValue(l.GetError))
+	// Setup nested objects.
+	_ = obj.Set("op", l.op.setupObject())
+	_ = obj.Set("stack", l.stack.setupObject())
+	_ = obj.Set("memory", l.memory.setupObject())
+	_ = obj.Set("contract", l.contract.setupObject())
+
+	return obj
+}
+
+func copyBytes(b []byte) []byte {
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	return c
+}
+
+func bigOrZero(b *big.Int) *big.Int {
+	if b == nil {
+		return new(big.Int)
+	}
+
+	return b
+}
@@ -0,0 +1,374 @@
+package js
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/crypto"
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/state"
+	itrie "github.com/dogechain-lab/dogechain/state/immutable-trie"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/state/runtime/precompiled"
+	"github.com/dogechain-lab/dogechain/state/tracer"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testSender   = types.StringToAddress("0x1000")
+	testCaller   = types.StringToAddress("0x2000")
+	testCallee   = types.StringToAddress("0x3000")
+	testCoinbase = types.StringToAddress("0x4000")
+
+	// SSTORE(0, 0x2a), MSTORE(0, 0x2a), then RETURN(0, 0x20)
+	testCalleeCode = "602a600055602a60005260206000f3"
+	// CALL(0xffff, callee, 0, 0, 0, 0, 0x20), then RETURN(0, 0x20)
+	testCallerCode = "60206000600060006000" +
+		"73" + hex.EncodeToString(testCallee.Bytes()) +
+		"61fffff150" +
+		"60206000f3"
+)
+
+// newTestTransition returns a transition with the caller and callee contracts deployed
+func newTestTransition(t *testing.T) *state.Transition {
+	t.Helper()
+
+	executor := state.NewExecutor(
+		&chain.Params{Forks: chain.AllForksEnabled, ChainID: 100},
+		itrie.NewStateDB(itrie.NewMemoryStorage(), hclog.NewNullLogger(), nil),
+		hclog.NewNullLogger(),
+	)
+	executor.SetRuntime(precompiled.NewPrecompiled())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) func(i uint64) types.Hash {
+		return func(i uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		testSender: {
+			Balance: big.NewInt(1000000000),
+		},
+		testCaller: {
+			Code: hex.MustDecodeHex(testCallerCode),
+		},
+		testCallee: {
+			Code: hex.MustDecodeHex(testCalleeCode),
+			Storage: map[types.Hash]types.Hash{
+				types.ZeroHash: types.StringToHash("0x1"),
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	txn, err := executor.BeginTxn(root, &types.Header{
+		Number:   1,
+		GasLimit: 10000000,
+		Miner:    testCoinbase,
+	}, testCoinbase)
+	assert.NoError(t, err)
+
+	return txn
+}
+
+func newTestTracer(t *testing.T, code string, cfg json.RawMessage) tracer.Tracer {
+	t.Helper()
+
+	jsTracer, err := newJsTracer(code, &tracer.Context{Forks: chain.AllForksEnabled.At(1)}, cfg)
+	assert.NoError(t, err)
+
+	return jsTracer
+}
+
+// traceTestTx executes a transaction calling the caller contract with the js tracer
+func traceTestTx(t *testing.T, code string, cfg json.RawMessage) (json.RawMessage, error) {
+	t.Helper()
+
+	txn := newTestTransition(t)
+	jsTracer := newTestTracer(t, code, cfg)
+
+	txn.SetEVMLogger(jsTracer)
+
+	result, err := txn.Apply(&types.Transaction{
+		From:     testSender,
+		To:       &testCaller,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.Failed())
+
+	return jsTracer.GetResult()
+}
+
+func TestJsTracer_Invalid(t *testing.T) {
+	cases := []struct {
+		name string
+		code string
+	}{
+		{"syntax error", "{result: function() {"},
+		{"no result", "{fault: function() {}}"},
+		{"no fault", "{result: function() {}}"},
+		{"enter without exit", "{result: function() {}, fault: function() {}, enter: function() {}}"},
+		{"failed setup", "{result: function() {}, fault: function() {}, setup: function() { throw 'err' }}"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := newJsTracer(c.code, nil, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJsTracer_Step(t *testing.T) {
+	code := `{
+		count: 0, ops: {}, depths: {}, stored: [],
+		step: function(log, db) {
+			this.count++;
+			this.ops[log.op.toString()] = log.op.toNumber();
+			this.depths[log.getDepth()] = true;
+			if (log.op.toString() == "SSTORE") {
+				this.stored.push(log.stack.peek(1).toString(16));
+				this.stored.push(toHex(log.contract.getAddress()));
+				this.stored.push(toHex(log.contract.getCaller()));
+				this.stored.push(toHex(db.getState(log.contract.getAddress(), toWord("0x00"))));
+			}
+			if (log.op.toString() == "RETURN" && log.getDepth() == 2) {
+				this.returned = log.memory.getUint(0).toNumber();
+			}
+		},
+		fault: function(log, db) {},
+		result: function(ctx, db) {
+			return {
+				count: this.count, ops: this.ops, depths: this.depths, stored: this.stored, returned: this.returned,
+				type: ctx.type, from: toHex(ctx.from), to: toHex(ctx.to), output: toHex(ctx.output),
+				gasPrice: ctx.gasPrice, gasUsed: ctx.gasUsed, block: ctx.block,
+				intrinsicGas: ctx.intrinsicGas, nonce: db.getNonce(ctx.from)
+			};
+		}
+	}`
+
+	res, err := traceTestTx(t, code, nil)
+	assert.NoError(t, err)
+
+	var out struct {
+		Count        int             `json:"count"`
+		Ops          map[string]int  `json:"ops"`
+		Depths       map[string]bool `json:"depths"`
+		Stored       []string        `json:"stored"`
+		Returned     int             `json:"returned"`
+		Type         string          `json:"type"`
+		From         string          `json:"from"`
+		To           string          `json:"to"`
+		Output       string          `json:"output"`
+		GasPrice     string          `json:"gasPrice"`
+		GasUsed      uint64          `json:"gasUsed"`
+		Block        uint64          `json:"block"`
+		IntrinsicGas uint64          `json:"intrinsicGas"`
+		Nonce        uint64          `json:"nonce"`
+	}
+
+	assert.NoError(t, json.Unmarshal(res, &out))
+
+	assert.Greater(t, out.Count, 10)
+	assert.Equal(t, int(evm.SSTORE), out.Ops["SSTORE"])
+	assert.Equal(t, map[string]bool{"1": true, "2": true}, out.Depths)
+	assert.Equal(t, []string{
+		"2a",
+		hex.EncodeToHex(testCallee.Bytes()),
+		hex.EncodeToHex(testCaller.Bytes()),
+		// the step is captured after the execution
+		hex.EncodeToHex(types.StringToHash("0x2a").Bytes()),
+	}, out.Stored)
+	assert.Equal(t, 0x2a, out.Returned)
+
+	assert.Equal(t, "CALL", out.Type)
+	assert.Equal(t, hex.EncodeToHex(testSender.Bytes()), out.From)
+	assert.Equal(t, hex.EncodeToHex(testCaller.Bytes()), out.To)
+	assert.Equal(t, hex.EncodeToHex(types.StringToHash("0x2a").Bytes()), out.Output)
+	assert.Equal(t, "1", out.GasPrice)
+	assert.Equal(t, uint64(1), out.Block)
+	assert.Equal(t, uint64(21000), out.IntrinsicGas)
+	assert.Greater(t, out.GasUsed, out.IntrinsicGas)
+	assert.Equal(t, uint64(1), out.Nonce)
+}
+
+func TestJsTracer_EnterExit(t *testing.T) {
+	code := `{
+		frames: [],
+		enter: function(frame) {
+			this.frames.push({
+				type: frame.getType(), from: toHex(frame.getFrom()), to: toHex(frame.getTo()),
+				gas: frame.getGas(), value: frame.getValue()
+			});
+		},
+		exit: function(res) {
+			var frame = this.frames[this.frames.length - 1];
+			frame.gasUsed = res.getGasUsed();
+			frame.output = toHex(res.getOutput());
+			frame.error = res.getError();
+		},
+		fault: function(log, db) {},
+		result: function(ctx, db) { return this.frames; }
+	}`
+
+	res, err := traceTestTx(t, code, nil)
+	assert.NoError(t, err)
+
+	var frames []struct {
+		Type    string  `json:"type"`
+		From    string  `json:"from"`
+		To      string  `json:"to"`
+		Gas     uint64  `json:"gas"`
+		Value   string  `json:"value"`
+		GasUsed uint64  `json:"gasUsed"`
+		Output  string  `json:"output"`
+		Error   *string `json:"error"`
+	}
+
+	assert.NoError(t, json.Unmarshal(res, &frames))
+	assert.Len(t, frames, 1)
+
+	frame := frames[0]
+	assert.Equal(t, "CALL", frame.Type)
+	assert.Equal(t, hex.EncodeToHex(testCaller.Bytes()), frame.From)
+	assert.Equal(t, hex.EncodeToHex(testCallee.Bytes()), frame.To)
+	assert.Equal(t, uint64(0xffff), frame.Gas)
+	assert.Equal(t, "0", frame.Value)
+	assert.Greater(t, frame.GasUsed, uint64(0))
+	assert.Equal(t, hex.EncodeToHex(types.StringToHash("0x2a").Bytes()), frame.Output)
+	assert.Nil(t, frame.Error)
+}
+
+func TestJsTracer_Setup(t *testing.T) {
+	code := `{
+		setup: function(cfg) { this.cfg = JSON.parse(cfg); },
+		fault: function(log, db) {},
+		result: function(ctx, db) { return this.cfg.name; }
+	}`
+
+	res, err := traceTestTx(t, code, json.RawMessage(`{"name":"doge"}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `"doge"`, string(res))
+}
+
+func TestJsTracer_StepError(t *testing.T) {
+	code := `{
+		step: function(log, db) { log.stack.peek(1024); },
+		fault: function(log, db) {},
+		result: function(ctx, db) { return "done"; }
+	}`
+
+	_, err := traceTestTx(t, code, nil)
+	assert.ErrorContains(t, err, "in server-side tracer function 'step'")
+}
+
+func TestJsTracer_Stop(t *testing.T) {
+	code := `{
+		step: function(log, db) { while (true) {} },
+		fault: function(log, db) {},
+		result: function(ctx, db) { return "done"; }
+	}`
+
+	errTimeout := errors.New("execution timeout")
+
+	txn := newTestTransition(t)
+	jsTracer := newTestTracer(t, code, nil)
+
+	txn.SetEVMLogger(jsTracer)
+
+	timer := time.AfterFunc(100*time.Millisecond, func() {
+		jsTracer.Stop(errTimeout)
+	})
+	defer timer.Stop()
+
+	_, err := txn.Apply(&types.Transaction{
+		From:     testSender,
+		To:       &testCaller,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+	})
+	assert.NoError(t, err)
+
+	_, err = jsTracer.GetResult()
+	assert.ErrorContains(t, err, errTimeout.Error())
+}
+
+func TestJsTracer_Builtins(t *testing.T) {
+	code := `{
+		fault: function(log, db) {},
+		result: function(ctx, db) {
+			return {
+				word: toHex(toWord("0x01")),
+				address: toHex(toAddress([0x10, 0x00])),
+				contract: toHex(toContract("0x1000", 1)),
+				contract2: toHex(toContract2("0x1000", "0x01", "0x00")),
+				precompiled: isPrecompiled(toAddress("0x01")),
+				notPrecompiled: isPrecompiled(toAddress("0x1000")),
+				slice: toHex(slice(toWord("0x0102"), 30, 32))
+			};
+		}
+	}`
+
+	res, err := traceTestTx(t, code, nil)
+	assert.NoError(t, err)
+
+	var salt [32]byte
+
+	salt[31] = 1
+
+	assert.JSONEq(t, `{
+		"word": "`+hex.EncodeToHex(types.StringToHash("0x01").Bytes())+`",
+		"address": "`+hex.EncodeToHex(testSender.Bytes())+`",
+		"contract": "`+hex.EncodeToHex(crypto.CreateAddress(testSender, 1).Bytes())+`",
+		"contract2": "`+hex.EncodeToHex(crypto.CreateAddress2(testSender, salt, []byte{0}).Bytes())+`",
+		"precompiled": true,
+		"notPrecompiled": false,
+		"slice": "0x0102"
+	}`, string(res))
+}
+
+func TestJsTracer_BigInt(t *testing.T) {
+	code := `{
+		fault: function(log, db) {},
+		result: function(ctx, db) {
+			var a = bigInt("0x10"), b = bigInt(3);
+			return {
+				add: a.add(b), sub: b.subtract(a), mul: a.multiply(b), div: a.divide(b), mod: a.mod(b),
+				pow: b.pow(2), shl: a.shiftLeft(4), shr: a.shiftRight(4), and: a.and(b), or: a.or(b),
+				cmp: [a.compare(b), a.equals(16), a.greater(b), b.lesser(a), a.isZero(), b.subtract(a).isNegative()],
+				hex: a.toString(16), num: a.toJSNumber() + 1, base: bigInt("ff", 16)
+			};
+		}
+	}`
+
+	res, err := traceTestTx(t, code, nil)
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"add": "19", "sub": "-13", "mul": "48", "div": "5", "mod": "1",
+		"pow": "9", "shl": "256", "shr": "1", "and": "0", "or": "19",
+		"cmp": [1, true, true, true, false, true],
+		"hex": "10", "num": 17, "base": "255"
+	}`, string(res))
+
+	t.Run("Division by zero", func(t *testing.T) {
+		code := `{
+			fault: function(log, db) {},
+			result: function(ctx, db) { return bigInt(1).divide(0); }
+		}`
+
+		_, err := traceTestTx(t, code, nil)
+		assert.ErrorContains(t, err, "division by zero")
+	})
+}
@@ -0,0 +1,289 @@
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/state/tracer"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/umbracle/go-web3/abi"
+)
+
+func init() {
+	register("callTracer", newCallTracer)
+}
+
+type callFrame struct {
+	Type         evm.OpCode
+	From         types.Address
+	Gas          uint64
+	GasUsed      uint64
+	To           *types.Address
+	Input        []byte
+	Output       []byte
+	Error        string
+	RevertReason string
+	Calls        []callFrame
+	Value        *big.Int
+}
+
+// callFrameJSON is the geth compatible json form of the call frame
+type callFrameJSON struct {
+	Type         string          `json:"type"`
+	From         string          `json:"from"`
+	Gas          string          `json:"gas"`
+	GasUsed      string          `json:"gasUsed"`
+	To           string          `json:"to,omitempty"`
+	Input        string          `json:"input"`
+	Output       string          `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []callFrameJSON `json:"calls,omitempty"`
+	Value        string          `json:"value,omitempty"`
+}
+
+func (f *callFrame) toJSON() callFrameJSON {
+	res := callFrameJSON{
+		Type:         f.Type.String(),
+		From:         hex.EncodeToHex(f.From.Bytes()),
+		Gas:          hex.EncodeUint64(f.Gas),
+		GasUsed:      hex.EncodeUint64(f.GasUsed),
+		Input:        hex.EncodeToHex(f.Input),
+		Error:        f.Error,
+		RevertReason: f.RevertReason,
+	}
+
+	if f.To != nil {
+		res.To = hex.EncodeToHex(f.To.Bytes())
+	}
+
+	if len(f.Output) > 0 {
+		res.Output = hex.EncodeToHex(f.Output)
+	}
+
+	if f.Value != nil {
+		res.Value = hex.EncodeBig(f.Value)
+	}
+
+	for i := range f.Calls {
+		res.Calls = append(res.Calls, f.Calls[i].toJSON())
+	}
+
+	return res
+}
+
+// MarshalJSON marshals as JSON.
+func (f *callFrame) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.toJSON())
+}
+
+func (f *callFrame) processOutput(output []byte, err error) {
+	output = copyBytes(output)
+	if err == nil {
+		f.Output = output
+
+		return
+	}
+
+	f.Error = err.Error()
+
+	if f.Type == evm.CREATE || f.Type == evm.CREATE2 {
+		f.To = nil
+	}
+
+	if !errors.Is(err, runtime.ErrExecutionReverted) || len(output) == 0 {
+		return
+	}
+
+	f.Output = output
+
+	if len(output) < 4 {
+		return
+	}
+
+	if unpacked, err := abi.UnpackRevertError(output); err == nil {
+		f.RevertReason = unpacked
+	}
+}
+
+type callTracer struct {
+	callstack []callFrame
+	config    callTracerConfig
+	gasLimit  uint64
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+type callTracerConfig struct {
+	OnlyTopCall bool `json:"onlyTopCall"` // If true, call tracer won't collect any subcalls
+}
+
+// newCallTracer returns a native go tracer which tracks
+// call frames of a tx, and implements tracer.Tracer.
+func newCallTracer(ctx *tracer.Context, cfg json.RawMessage) (tracer.Tracer, error) {
+	var config callTracerConfig
+
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	// First callframe contains tx context info
+	// and is populated on start and end.
+	return &callTracer{
+		callstack: make([]callFrame, 1),
+		config:    config,
+	}, nil
+}
+
+// CaptureTxStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *callTracer) CaptureTxStart(pre runtime.Txn, ctx *runtime.TxContext, gasLimit uint64) {
+	t.gasLimit = gasLimit
+}
+
+// CaptureTxEnd implements the EVMLogger interface to finalize the tracing operation.
+func (t *callTracer) CaptureTxEnd(restGas uint64) {
+	t.callstack[0].GasUsed = t.gasLimit - restGas
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *callTracer) CaptureStart(txn runtime.Txn, from, to types.Address,
+	create bool, input []byte, gas uint64, value *big.Int) {
+	toCopy := to
+
+	t.callstack[0] = callFrame{
+		Type:  evm.CALL,
+		From:  from,
+		To:    &toCopy,
+		Input: copyBytes(input),
+		Gas:   t.gasLimit,
+		Value: copyBig(value),
+	}
+
+	if create {
+		t.callstack[0].Type = evm.CREATE
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *callTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	t.callstack[0].processOutput(output, err)
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *callTracer) CaptureState(ctx *runtime.ScopeContext, pc uint64, opCode int,
+	gas, cost uint64, rData []byte, depth int, err error) {
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *callTracer) CaptureEnter(opCode int, from, to types.Address,
+	input []byte, gas uint64, value *big.Int) {
+	if t.config.OnlyTopCall {
+		return
+	}
+
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+
+	toCopy := to
+	call := callFrame{
+		Type:  evm.OpCode(opCode),
+		From:  from,
+		To:    &toCopy,
+		Input: copyBytes(input),
+		Gas:   gas,
+	}
+
+	// static calls carry no value
+	if call.Type != evm.STATICCALL {
+		call.Value = copyBig(value)
+	}
+
+	t.callstack = append(t.callstack, call)
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *callTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.config.OnlyTopCall {
+		return
+	}
+
+	// Skip if tracing was interrupted, the enters are skipped as well
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+
+	size := len(t.callstack)
+	if size <= 1 {
+		return
+	}
+
+	// pop call
+	call := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+	size--
+
+	call.GasUsed = gasUsed
+	call.processOutput(output, err)
+	t.callstack[size-1].Calls = append(t.callstack[size-1].Calls, call)
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *callTracer) CaptureFault(ctx *runtime.ScopeContext, pc uint64, opCode int,
+	gas, cost uint64, depth int, err error) {
+}
+
+// GetResult returns the json-encoded nested list of call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *callTracer) GetResult() (json.RawMessage, error) {
+	if len(t.callstack) != 1 {
+		// the frames entered before the interruption are never exited
+		if t.reason != nil {
+			return nil, t.reason
+		}
+
+		return nil, errors.New("incorrect number of top-level calls")
+	}
+
+	res, err := json.Marshal(&t.callstack[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *callTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	c := make([]byte, len(b))
+	copy(c, b)
+
+	return c
+}
+
+func copyBig(b *big.Int) *big.Int {
+	if b == nil {
+		return nil
+	}
+
+	return new(big.Int).Set(b)
+}
@@ -0,0 +1,245 @@
+package native
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/state"
+	itrie "github.com/dogechain-lab/dogechain/state/immutable-trie"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/state/runtime/precompiled"
+	"github.com/dogechain-lab/dogechain/state/tracer"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testSender   = types.StringToAddress("0x1000")
+	testCaller   = types.StringToAddress("0x2000")
+	testCallee   = types.StringToAddress("0x3000")
+	testCoinbase = types.StringToAddress("0x4000")
+
+	// SSTORE(0, 0x2a), MSTORE(0, 0x2a), then RETURN(0, 0x20)
+	testCalleeCode = "602a600055602a60005260206000f3"
+	// CALL(0xffff, callee, 0, 0, 0, 0, 0x20), then RETURN(0, 0x20)
+	testCallerCode = "60206000600060006000" +
+		"73" + hex.EncodeToString(testCallee.Bytes()) +
+		"61fffff150" +
+		"60206000f3"
+)
+
+// traceTestTx executes a transaction calling the caller contract with the named tracer
+func traceTestTx(t *testing.T, name string, cfg json.RawMessage) json.RawMessage {
+	t.Helper()
+
+	executor := state.NewExecutor(
+		&chain.Params{Forks: chain.AllForksEnabled, ChainID: 100},
+		itrie.NewStateDB(itrie.NewMemoryStorage(), hclog.NewNullLogger(), nil),
+		hclog.NewNullLogger(),
+	)
+	executor.SetRuntime(precompiled.NewPrecompiled())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) func(i uint64) types.Hash {
+		return func(i uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	root, err := executor.WriteGenesis(map[types.Address]*chain.GenesisAccount{
+		testSender: {
+			Balance: big.NewInt(1000000000),
+		},
+		testCaller: {
+			Code: hex.MustDecodeHex(testCallerCode),
+		},
+		testCallee: {
+			Code: hex.MustDecodeHex(testCalleeCode),
+			Storage: map[types.Hash]types.Hash{
+				types.ZeroHash: types.StringToHash("0x1"),
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	txn, err := executor.BeginTxn(root, &types.Header{
+		Number:   1,
+		GasLimit: 10000000,
+		Miner:    testCoinbase,
+	}, testCoinbase)
+	assert.NoError(t, err)
+
+	namedTracer, err := tracer.New(name, &tracer.Context{}, cfg)
+	assert.NoError(t, err)
+
+	txn.SetEVMLogger(namedTracer)
+
+	result, err := txn.Apply(&types.Transaction{
+		From:     testSender,
+		To:       &testCaller,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+		Value:    big.NewInt(0),
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.Failed())
+
+	res, err := namedTracer.GetResult()
+	assert.NoError(t, err)
+
+	return res
+}
+
+func TestNativeTracer_NotFound(t *testing.T) {
+	_, err := tracer.New("unknownTracer", &tracer.Context{}, nil)
+	assert.Error(t, err)
+
+	// the invalid config is not hidden by the lookup
+	_, err = tracer.New("callTracer", &tracer.Context{}, json.RawMessage(`{"onlyTopCall": "yes"}`))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, tracer.ErrTracerNotFound)
+
+	var typeErr *json.UnmarshalTypeError
+
+	assert.ErrorAs(t, err, &typeErr)
+}
+
+func TestCallTracer_Stop(t *testing.T) {
+	named, err := tracer.New("callTracer", &tracer.Context{}, nil)
+	assert.NoError(t, err)
+
+	errStop := errors.New("stopped")
+
+	named.CaptureTxStart(nil, nil, 100000)
+	named.CaptureStart(nil, testSender, testCaller, false, nil, 100000, big.NewInt(0))
+	named.CaptureEnter(int(evm.CALL), testCaller, testCallee, nil, 50000, big.NewInt(0))
+
+	// the enters and the exits after the interruption are both skipped
+	named.Stop(errStop)
+	named.CaptureEnter(int(evm.CALL), testCallee, testCaller, nil, 10000, big.NewInt(0))
+	named.CaptureExit(nil, 100, nil)
+	named.CaptureExit(nil, 100, nil)
+	named.CaptureEnd(nil, 100, 0, nil)
+
+	// the frame entered before the interruption is never popped
+	//nolint:forcetypeassert
+	assert.Len(t, named.(*callTracer).callstack, 2)
+
+	_, err = named.GetResult()
+	assert.ErrorIs(t, err, errStop)
+}
+
+func TestCallTracer(t *testing.T) {
+	type frame struct {
+		Type    string  `json:"type"`
+		From    string  `json:"from"`
+		To      string  `json:"to"`
+		Gas     string  `json:"gas"`
+		GasUsed string  `json:"gasUsed"`
+		Input   string  `json:"input"`
+		Output  string  `json:"output"`
+		Value   string  `json:"value"`
+		Calls   []frame `json:"calls"`
+	}
+
+	output := "0x000000000000000000000000000000000000000000000000000000000000002a"
+
+	t.Run("Nested calls", func(t *testing.T) {
+		var res frame
+
+		assert.NoError(t, json.Unmarshal(traceTestTx(t, "callTracer", nil), &res))
+
+		assert.Equal(t, "CALL", res.Type)
+		assert.Equal(t, hex.EncodeToHex(testSender.Bytes()), res.From)
+		assert.Equal(t, hex.EncodeToHex(testCaller.Bytes()), res.To)
+		assert.Equal(t, "0x186a0", res.Gas)
+		assert.Equal(t, "0x", res.Input)
+		assert.Equal(t, output, res.Output)
+		assert.Equal(t, "0x0", res.Value)
+
+		assert.Len(t, res.Calls, 1)
+
+		call := res.Calls[0]
+		assert.Equal(t, "CALL", call.Type)
+		assert.Equal(t, hex.EncodeToHex(testCaller.Bytes()), call.From)
+		assert.Equal(t, hex.EncodeToHex(testCallee.Bytes()), call.To)
+		assert.Equal(t, "0xffff", call.Gas)
+		assert.Equal(t, output, call.Output)
+		assert.Empty(t, call.Calls)
+
+		// the top call includes the intrinsic gas
+		gasUsed, err := types.ParseUint64orHex(&res.GasUsed)
+		assert.NoError(t, err)
+
+		callGasUsed, err := types.ParseUint64orHex(&call.GasUsed)
+		assert.NoError(t, err)
+
+		assert.Greater(t, gasUsed, callGasUsed+21000)
+	})
+
+	t.Run("Only top call", func(t *testing.T) {
+		var res frame
+
+		assert.NoError(t, json.Unmarshal(traceTestTx(t, "callTracer", json.RawMessage(`{"onlyTopCall":true}`)), &res))
+
+		assert.Equal(t, output, res.Output)
+		assert.Empty(t, res.Calls)
+	})
+}
+
+func TestPrestateTracer(t *testing.T) {
+	type account struct {
+		Balance string            `json:"balance"`
+		Code    string            `json:"code"`
+		Nonce   uint64            `json:"nonce"`
+		Storage map[string]string `json:"storage"`
+	}
+
+	var (
+		sender   = hex.EncodeToHex(testSender.Bytes())
+		caller   = hex.EncodeToHex(testCaller.Bytes())
+		callee   = hex.EncodeToHex(testCallee.Bytes())
+		coinbase = hex.EncodeToHex(testCoinbase.Bytes())
+		slot     = hex.EncodeToHex(types.ZeroHash.Bytes())
+	)
+
+	t.Run("Prestate", func(t *testing.T) {
+		var res map[string]*account
+
+		assert.NoError(t, json.Unmarshal(traceTestTx(t, "prestateTracer", nil), &res))
+
+		assert.Len(t, res, 4)
+		assert.Contains(t, res, coinbase)
+
+		// the balance before buying gas
+		assert.Equal(t, "0x3b9aca00", res[sender].Balance)
+		assert.Equal(t, uint64(0), res[sender].Nonce)
+		assert.Equal(t, "0x"+testCallerCode, res[caller].Code)
+		// the storage before the transaction
+		assert.Equal(t, map[string]string{slot: hex.EncodeToHex(types.StringToHash("0x1").Bytes())}, res[callee].Storage)
+	})
+
+	t.Run("Diff mode", func(t *testing.T) {
+		var res struct {
+			Pre  map[string]*account `json:"pre"`
+			Post map[string]*account `json:"post"`
+		}
+
+		assert.NoError(t, json.Unmarshal(traceTestTx(t, "prestateTracer", json.RawMessage(`{"diffMode":true}`)), &res))
+
+		// the caller is not modified
+		assert.NotContains(t, res.Pre, caller)
+		assert.NotContains(t, res.Post, caller)
+
+		assert.Equal(t, uint64(1), res.Post[sender].Nonce)
+		assert.NotEmpty(t, res.Post[sender].Balance)
+		assert.NotEmpty(t, res.Post[coinbase].Balance)
+
+		assert.Equal(t, map[string]string{slot: hex.EncodeToHex(types.StringToHash("0x1").Bytes())}, res.Pre[callee].Storage)
+		assert.Equal(t, map[string]string{slot: hex.EncodeToHex(types.StringToHash("0x2a").Bytes())}, res.Post[callee].Storage)
+	})
+}
@@ -0,0 +1,335 @@
+package native
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/state/tracer"
+	"github.com/dogechain-lab/dogechain/types"
+)
+
+func init() {
+	register("prestateTracer", newPrestateTracer)
+}
+
+type prestate = map[types.Address]*account
+
+type account struct {
+	Balance *big.Int
+	Code    []byte
+	Nonce   uint64
+	Storage map[types.Hash]types.Hash
+}
+
+// accountJSON is the geth compatible json form of the account
+type accountJSON struct {
+	Balance string            `json:"balance,omitempty"`
+	Code    string            `json:"code,omitempty"`
+	Nonce   uint64            `json:"nonce,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+func (a *account) exists() bool {
+	return a.Nonce > 0 || len(a.Code) > 0 || len(a.Storage) > 0 || (a.Balance != nil && a.Balance.Sign() != 0)
+}
+
+// MarshalJSON marshals as JSON.
+func (a *account) MarshalJSON() ([]byte, error) {
+	res := accountJSON{
+		Nonce: a.Nonce,
+	}
+
+	if a.Balance != nil {
+		res.Balance = hex.EncodeBig(a.Balance)
+	}
+
+	if len(a.Code) > 0 {
+		res.Code = hex.EncodeToHex(a.Code)
+	}
+
+	if len(a.Storage) > 0 {
+		res.Storage = make(map[string]string, len(a.Storage))
+		for k, v := range a.Storage {
+			res.Storage[hex.EncodeToHex(k.Bytes())] = hex.EncodeToHex(v.Bytes())
+		}
+	}
+
+	return json.Marshal(res)
+}
+
+func marshalState(s prestate) map[string]*account {
+	res := make(map[string]*account, len(s))
+	for addr, acc := range s {
+		res[hex.EncodeToHex(addr.Bytes())] = acc
+	}
+
+	return res
+}
+
+type prestateTracer struct {
+	// pre is the read-only view of the state before the transaction
+	preTxn runtime.Txn
+	// txn is the live state of the transaction
+	txn runtime.Txn
+
+	pre       prestate
+	post      prestate
+	create    bool
+	to        types.Address
+	config    prestateTracerConfig
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+	created   map[types.Address]bool
+	deleted   map[types.Address]bool
+}
+
+type prestateTracerConfig struct {
+	DiffMode bool `json:"diffMode"` // If true, this tracer will return state modifications
+}
+
+// newPrestateTracer returns a native go tracer which tracks the state
+// of all the accounts touched by a tx, and implements tracer.Tracer.
+func newPrestateTracer(ctx *tracer.Context, cfg json.RawMessage) (tracer.Tracer, error) {
+	var config prestateTracerConfig
+
+	if cfg != nil {
+		if err := json.Unmarshal(cfg, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	return &prestateTracer{
+		pre:     prestate{},
+		post:    prestate{},
+		config:  config,
+		created: make(map[types.Address]bool),
+		deleted: make(map[types.Address]bool),
+	}, nil
+}
+
+// CaptureTxStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *prestateTracer) CaptureTxStart(pre runtime.Txn, ctx *runtime.TxContext, gasLimit uint64) {
+	t.preTxn = pre
+
+	t.lookupAccount(ctx.Origin)
+	t.lookupAccount(ctx.Coinbase)
+}
+
+// CaptureStart implements the EVMLogger interface to initialize the tracing operation.
+func (t *prestateTracer) CaptureStart(txn runtime.Txn, from, to types.Address,
+	create bool, input []byte, gas uint64, value *big.Int) {
+	t.txn = txn
+	t.create = create
+	t.to = to
+
+	t.lookupAccount(from)
+	t.lookupAccount(to)
+
+	if create && t.config.DiffMode {
+		t.created[to] = true
+	}
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	if t.config.DiffMode {
+		return
+	}
+
+	if t.create {
+		// Keep existing account prior to contract creation at that address
+		if s := t.pre[t.to]; s != nil && !s.exists() {
+			// Exclude newly created contract.
+			delete(t.pre, t.to)
+		}
+	}
+}
+
+// CaptureState implements the EVMLogger interface to trace a single step of VM execution.
+func (t *prestateTracer) CaptureState(ctx *runtime.ScopeContext, pc uint64, opCode int,
+	gas, cost uint64, rData []byte, depth int, err error) {
+	if err != nil {
+		return
+	}
+
+	// Skip if tracing was interrupted
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		return
+	}
+
+	stack := ctx.Stack
+	stackLen := len(stack)
+	caller := ctx.ContractAddress
+
+	switch op := evm.OpCode(opCode); {
+	case stackLen >= 1 && (op == evm.SLOAD || op == evm.SSTORE):
+		slot := types.BytesToHash(stack[stackLen-1].Bytes())
+		t.lookupStorage(caller, slot)
+	case stackLen >= 1 && (op == evm.EXTCODECOPY || op == evm.EXTCODEHASH ||
+		op == evm.EXTCODESIZE || op == evm.BALANCE || op == evm.SELFDESTRUCT):
+		addr := types.BytesToAddress(stack[stackLen-1].Bytes())
+		t.lookupAccount(addr)
+
+		if op == evm.SELFDESTRUCT {
+			t.deleted[caller] = true
+		}
+	}
+}
+
+// CaptureEnter is called when EVM enters a new scope (via call, create or selfdestruct).
+func (t *prestateTracer) CaptureEnter(opCode int, from, to types.Address,
+	input []byte, gas uint64, value *big.Int) {
+	t.lookupAccount(to)
+
+	if op := evm.OpCode(opCode); op == evm.CREATE || op == evm.CREATE2 {
+		t.created[to] = true
+	}
+}
+
+// CaptureExit is called when EVM exits a scope, even if the scope didn't
+// execute any code.
+func (t *prestateTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+}
+
+// CaptureFault implements the EVMLogger interface to trace an execution fault.
+func (t *prestateTracer) CaptureFault(ctx *runtime.ScopeContext, pc uint64, opCode int,
+	gas, cost uint64, depth int, err error) {
+}
+
+// CaptureTxEnd implements the EVMLogger interface to finalize the tracing operation.
+func (t *prestateTracer) CaptureTxEnd(restGas uint64) {
+	if !t.config.DiffMode || t.txn == nil {
+		return
+	}
+
+	for addr, state := range t.pre {
+		// The deleted account's state is pruned from `post` but kept in `pre`
+		if _, ok := t.deleted[addr]; ok {
+			continue
+		}
+
+		modified := false
+		postAccount := &account{Storage: make(map[types.Hash]types.Hash)}
+		newBalance := t.txn.GetBalance(addr)
+		newNonce := t.txn.GetNonce(addr)
+		newCode := t.txn.GetCode(addr)
+
+		if newBalance.Cmp(state.Balance) != 0 {
+			modified = true
+			postAccount.Balance = newBalance
+		}
+
+		if newNonce != state.Nonce {
+			modified = true
+			postAccount.Nonce = newNonce
+		}
+
+		if !bytes.Equal(newCode, state.Code) {
+			modified = true
+			postAccount.Code = newCode
+		}
+
+		for key, val := range state.Storage {
+			// don't include the empty slot
+			if val == (types.Hash{}) {
+				delete(state.Storage, key)
+			}
+
+			newVal, _ := t.txn.GetState(addr, key)
+			if val == newVal {
+				// Omit unchanged slots
+				delete(state.Storage, key)
+			} else {
+				modified = true
+				if newVal != (types.Hash{}) {
+					postAccount.Storage[key] = newVal
+				}
+			}
+		}
+
+		if modified {
+			t.post[addr] = postAccount
+		} else {
+			// if state is not modified, then no need to include into the pre state
+			delete(t.pre, addr)
+		}
+	}
+
+	// the new created contracts' prestate were empty, so delete them
+	for a := range t.created {
+		// the created contract maybe exists in statedb before the creating tx
+		if s := t.pre[a]; s != nil && !s.exists() {
+			delete(t.pre, a)
+		}
+	}
+}
+
+// GetResult returns the json-encoded nested list of call traces, and any
+// error arising from the encoding or forceful termination (via `Stop`).
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	var (
+		res []byte
+		err error
+	)
+
+	if t.config.DiffMode {
+		res, err = json.Marshal(struct {
+			Post map[string]*account `json:"post"`
+			Pre  map[string]*account `json:"pre"`
+		}{marshalState(t.post), marshalState(t.pre)})
+	} else {
+		res, err = json.Marshal(marshalState(t.pre))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(res), t.reason
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *prestateTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// lookupAccount fetches details of an account and adds it to the prestate
+// if it doesn't exist there.
+func (t *prestateTracer) lookupAccount(addr types.Address) {
+	if _, ok := t.pre[addr]; ok || t.preTxn == nil {
+		return
+	}
+
+	t.pre[addr] = &account{
+		Balance: t.preTxn.GetBalance(addr),
+		Nonce:   t.preTxn.GetNonce(addr),
+		Code:    t.preTxn.GetCode(addr),
+		Storage: make(map[types.Hash]types.Hash),
+	}
+}
+
+// lookupStorage fetches the requested storage slot and adds
+// it to the prestate of the given contract. It assumes `lookupAccount`
+// has been performed on the contract before.
+func (t *prestateTracer) lookupStorage(addr types.Address, key types.Hash) {
+	// the contract is looked up on entering, but be defensive
+	t.lookupAccount(addr)
+
+	acc, ok := t.pre[addr]
+	if !ok {
+		return
+	}
+
+	if _, ok := acc.Storage[key]; ok {
+		return
+	}
+
+	acc.Storage[key], _ = t.preTxn.GetState(addr, key)
+}
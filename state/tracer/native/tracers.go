@@ -0,0 +1,51 @@
+// Package native is a collection of tracers written in go.
+//
+// In order to add a native tracer and have it compiled into the binary, a new
+// file needs to be added to this folder, containing an implementation of the
+// `tracer.Tracer` interface.
+//
+// Aside from implementing the tracer, it also needs to register itself, using the
+// `register` method -- and this needs to be done in the package initialization.
+//
+// Example:
+//
+//	func init() {
+//		register("noopTracer", newNoopTracer)
+//	}
+package native
+
+import (
+	"encoding/json"
+
+	"github.com/dogechain-lab/dogechain/state/tracer"
+)
+
+// init registers itself this packages as a lookup for tracers.
+func init() {
+	tracer.RegisterLookup(false, lookup)
+}
+
+// ctorFn is the constructor signature of a native tracer.
+type ctorFn = func(*tracer.Context, json.RawMessage) (tracer.Tracer, error)
+
+// ctors is a map of package-local tracer constructors.
+var ctors map[string]ctorFn
+
+// register is used by native tracers to register their presence.
+func register(name string, ctor ctorFn) {
+	if ctors == nil {
+		ctors = make(map[string]ctorFn)
+	}
+
+	ctors[name] = ctor
+}
+
+// lookup returns a tracer, if one can be matched to the given name. The error
+// of the matched tracer, such as the invalid config, is returned as is.
+func lookup(name string, ctx *tracer.Context, cfg json.RawMessage) (tracer.Tracer, error) {
+	if ctor, ok := ctors[name]; ok {
+		return ctor(ctx, cfg)
+	}
+
+	return nil, tracer.ErrTracerNotFound
+}
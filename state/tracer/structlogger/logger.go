@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"sync/atomic"
 	"time"
 
 	"github.com/dogechain-lab/dogechain/state/runtime"
@@ -66,27 +67,43 @@ func (s *StructLog) GetErrorString() string {
 	return ""
 }
 
+// Config are the configuration options of the structured logger
+type Config struct {
+	EnableMemory     bool // enable memory capture
+	DisableStack     bool // disable stack capture
+	DisableStorage   bool // disable storage capture
+	EnableReturnData bool // enable return data capture
+}
+
 // StructLogger is an EVM state logger and implements EVMLogger.
 //
 // StructLogger can capture state based on the given Log configuration and also keeps
 // a track record of modified storage which is used in reporting snapshots of the
 // contract their storage.
 type StructLogger struct {
+	cfg Config
 	txn runtime.Txn
 
 	storage map[types.Address]Storage
 	logs    []*StructLog
 	output  []byte
 	err     error
+
+	interrupt uint32 // atomic flag to signal execution interruption
+	reason    error  // textual reason for the interruption
 }
 
-// NewStructLogger returns a new logger
-func NewStructLogger(txn runtime.Txn) *StructLogger {
+// NewStructLogger returns a new logger, the default options are used when cfg is nil
+func NewStructLogger(txn runtime.Txn, cfg *Config) *StructLogger {
 	logger := &StructLogger{
 		txn:     txn,
 		storage: make(map[types.Address]Storage),
 	}
 
+	if cfg != nil {
+		logger.cfg = *cfg
+	}
+
 	return logger
 }
 
@@ -98,6 +115,27 @@ func (l *StructLogger) Reset() {
 	l.err = nil
 }
 
+// Stop terminates the capturing at the first opportune moment
+func (l *StructLogger) Stop(err error) {
+	l.reason = err
+	atomic.StoreUint32(&l.interrupt, 1)
+}
+
+// Reason returns the reason of the interruption, nil if not interrupted
+func (l *StructLogger) Reason() error {
+	if atomic.LoadUint32(&l.interrupt) == 0 {
+		return nil
+	}
+
+	return l.reason
+}
+
+// CaptureTxStart implements the EVMLogger interface, nothing to do.
+func (l *StructLogger) CaptureTxStart(pre runtime.Txn, ctx *runtime.TxContext, gasLimit uint64) {}
+
+// CaptureTxEnd implements the EVMLogger interface, nothing to do.
+func (l *StructLogger) CaptureTxEnd(restGas uint64) {}
+
 // CaptureStart implements the EVMLogger interface to initialize the tracing operation.
 func (l *StructLogger) CaptureStart(txn runtime.Txn, from, to types.Address,
 	create bool, input []byte, gas uint64, value *big.Int) {
@@ -116,18 +154,29 @@ func (l *StructLogger) CaptureState(
 	depth int,
 	err error,
 ) {
-	// memory := ctx.Memory
+	// Stop capturing once the tracing is interrupted
+	if atomic.LoadUint32(&l.interrupt) > 0 {
+		return
+	}
+
+	memory := ctx.Memory
 	stack := ctx.Stack
 	contractAddress := ctx.ContractAddress
 
-	// // Copy a snapshot of the current memory state to a new buffer
-	// mem := make([]byte, len(memory))
-	// copy(mem, memory)
+	// Copy a snapshot of the current memory state to a new buffer
+	var mem []byte
+	if l.cfg.EnableMemory {
+		mem = make([]byte, len(memory))
+		copy(mem, memory)
+	}
 
 	// Copy a snapshot of the current stack state to a new buffer
-	stck := make([]*big.Int, len(stack))
-	for i, item := range stack {
-		stck[i] = new(big.Int).SetBytes(item.Bytes())
+	var stck []*big.Int
+	if !l.cfg.DisableStack {
+		stck = make([]*big.Int, len(stack))
+		for i, item := range stack {
+			stck[i] = new(big.Int).SetBytes(item.Bytes())
+		}
 	}
 
 	// Copy stack data
@@ -136,7 +185,7 @@ func (l *StructLogger) CaptureState(
 
 	// Copy a snapshot of the current storage to a new container
 	var storage Storage
-	if opCode == evm.SLOAD || opCode == evm.SSTORE {
+	if !l.cfg.DisableStorage && (opCode == evm.SLOAD || opCode == evm.SSTORE) {
 		// initialise new changed values storage container for this contract
 		// if not present.
 		if l.storage[contractAddress] == nil {
@@ -173,17 +222,20 @@ func (l *StructLogger) CaptureState(
 	}
 
 	// Copy return data
-	rdata := make([]byte, len(rData))
-	copy(rdata, rData)
+	var rdata []byte
+	if l.cfg.EnableReturnData {
+		rdata = make([]byte, len(rData))
+		copy(rdata, rData)
+	}
 
 	// create a new snapshot of the EVM.
 	l.logs = append(l.logs, &StructLog{
-		Pc:      pc,
-		Op:      opCode,
-		Gas:     gas,
-		GasCost: cost,
-		// Memory:        mem,
-		// MemorySize:    len(memory),
+		Pc:            pc,
+		Op:            opCode,
+		Gas:           gas,
+		GasCost:       cost,
+		Memory:        mem,
+		MemorySize:    len(memory),
 		Stack:         stck,
 		ReturnData:    rdata,
 		Storage:       storage,
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 
+	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/state/runtime"
 	"github.com/dogechain-lab/dogechain/types"
 )
@@ -14,6 +15,10 @@ type Context struct {
 	BlockHash types.Hash // Hash of the block the tx is contained within (zero if dangling tx or call)
 	TxIndex   int        // Index of the transaction within a block (zero if dangling tx or call)
 	TxHash    types.Hash // Hash of the transaction being traced (zero if dangling call)
+
+	Forks chain.ForksInTime // Forks enabled at the block of the transaction
+
+	Cancel func() // Aborts the traced execution, nil if not supported
 }
 
 // Tracer interface extends evm.EVMLogger and additionally
@@ -25,10 +30,13 @@ type Tracer interface {
 	Stop(err error)
 }
 
-type lookupFunc func(string, *Context) (Tracer, error)
+type lookupFunc func(string, *Context, json.RawMessage) (Tracer, error)
 
 var (
 	lookups []lookupFunc
+
+	// ErrTracerNotFound is returned by the lookup if the tracer is not matched
+	ErrTracerNotFound = errors.New("tracer not found")
 )
 
 // RegisterLookup registers a method as a lookup for tracers, meaning that
@@ -44,13 +52,17 @@ func RegisterLookup(wildcard bool, lookup lookupFunc) {
 }
 
 // New returns a new instance of a tracer, by iterating through the
-// registered lookups.
-func New(code string, ctx *Context) (Tracer, error) {
+// registered lookups. The lookup matching the tracer stops the iteration,
+// even if the tracer fails to be created.
+func New(code string, ctx *Context, cfg json.RawMessage) (Tracer, error) {
 	for _, lookup := range lookups {
-		if tracer, err := lookup(code, ctx); err == nil {
+		tracer, err := lookup(code, ctx, cfg)
+		if err == nil {
 			return tracer, nil
+		} else if !errors.Is(err, ErrTracerNotFound) {
+			return nil, err
 		}
 	}
 
-	return nil, errors.New("tracer not found")
+	return nil, ErrTracerNotFound
 }
@@ -4,7 +4,9 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
@@ -69,7 +71,7 @@ func TestSubGasLimitPrice(t *testing.T) {
 				GasPrice: big.NewInt(tt.gasPrice),
 			}
 
-			err := transition.subGasLimitPrice(msg)
+			err := transition.subGasLimitPrice(msg, msg.GasPrice)
 
 			assert.Equal(t, tt.expectedErr, err)
 			if err == nil {
@@ -149,3 +151,202 @@ func TestTransfer(t *testing.T) {
 		})
 	}
 }
+
+func TestFeeCapCheck(t *testing.T) {
+	baseFee := big.NewInt(10)
+
+	tests := []struct {
+		name        string
+		msg         *types.Transaction
+		expectedErr error
+	}{
+		{
+			name: "should succeed when fee cap covers base fee",
+			msg: &types.Transaction{
+				Type:      types.DynamicFeeTx,
+				GasPrice:  big.NewInt(20),
+				GasFeeCap: big.NewInt(20),
+				GasTipCap: big.NewInt(1),
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "should fail by ErrTipAboveFeeCap",
+			msg: &types.Transaction{
+				Type:      types.DynamicFeeTx,
+				GasPrice:  big.NewInt(20),
+				GasFeeCap: big.NewInt(20),
+				GasTipCap: big.NewInt(21),
+			},
+			expectedErr: ErrTipAboveFeeCap,
+		},
+		{
+			name: "should fail by ErrFeeCapTooLow",
+			msg: &types.Transaction{
+				GasPrice: big.NewInt(9),
+			},
+			expectedErr: ErrFeeCapTooLow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transition := newTestTransition(nil)
+
+			err := transition.feeCapCheck(tt.msg, baseFee)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestApplyStateOverride(t *testing.T) {
+	var (
+		nonce = uint64(5)
+		code  = []byte{0x60, 0x01}
+	)
+
+	t.Run("should override the account fields", func(t *testing.T) {
+		transition := newTestTransition(map[types.Address]*PreState{
+			addr1: {
+				State: map[types.Hash]types.Hash{hash1: hash1},
+			},
+			addr2: {},
+		})
+
+		assert.NoError(t, transition.ApplyStateOverride(StateOverride{
+			addr1: {
+				Nonce:     &nonce,
+				Balance:   big.NewInt(100),
+				StateDiff: map[types.Hash]types.Hash{hash2: hash2},
+			},
+			addr2: {
+				Code:  code,
+				State: map[types.Hash]types.Hash{hash1: hash2},
+			},
+		}))
+
+		assert.Equal(t, nonce, transition.GetNonce(addr1))
+		assert.Equal(t, big.NewInt(100), transition.GetBalance(addr1))
+
+		// the slots not in the diff are kept
+		val, err := transition.GetStorage(addr1, hash1)
+		assert.NoError(t, err)
+		assert.Equal(t, hash1, val)
+
+		val, err = transition.GetStorage(addr1, hash2)
+		assert.NoError(t, err)
+		assert.Equal(t, hash2, val)
+
+		assert.Equal(t, code, transition.GetCode(addr2))
+
+		val, err = transition.GetStorage(addr2, hash1)
+		assert.NoError(t, err)
+		assert.Equal(t, hash2, val)
+
+		// the overrides are committed state
+		val, err = transition.Txn().GetCommittedState(addr1, hash2)
+		assert.NoError(t, err)
+		assert.Equal(t, hash2, val)
+	})
+
+	t.Run("should replace the whole storage", func(t *testing.T) {
+		transition := newTestTransition(nil)
+
+		assert.NoError(t, transition.ApplyStateOverride(StateOverride{
+			addr1: {
+				State: map[types.Hash]types.Hash{hash2: hash2},
+			},
+		}))
+
+		val, err := transition.GetStorage(addr1, hash1)
+		assert.NoError(t, err)
+		assert.Equal(t, types.ZeroHash, val)
+	})
+
+	t.Run("should not leak into the snapshot", func(t *testing.T) {
+		snapshot := newStateWithPreState(defaultPreState)
+		transition := &Transition{
+			logger: hclog.NewNullLogger(),
+			txn:    newTxn(snapshot),
+		}
+
+		assert.NoError(t, transition.ApplyStateOverride(StateOverride{
+			addr1: {Balance: big.NewInt(100)},
+		}))
+		assert.Equal(t, big.NewInt(100), transition.GetBalance(addr1))
+
+		account, err := snapshot.GetAccount(addr1)
+		assert.NoError(t, err)
+		assert.Zero(t, account.Balance.Sign())
+	})
+
+	t.Run("should fail on both state and stateDiff", func(t *testing.T) {
+		transition := newTestTransition(nil)
+
+		err := transition.ApplyStateOverride(StateOverride{
+			addr1: {
+				State:     map[types.Hash]types.Hash{},
+				StateDiff: map[types.Hash]types.Hash{},
+			},
+		})
+		assert.ErrorIs(t, err, ErrStateOverrideConflict)
+	})
+}
+
+func TestLondonRefundsAndCode(t *testing.T) {
+	var (
+		berlin = chain.ForksInTime{
+			Homestead: true, EIP150: true, EIP155: true, EIP158: true, Byzantium: true,
+			Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true,
+		}
+		london = berlin
+	)
+
+	london.London = true
+
+	newTransition := func(config chain.ForksInTime) *Transition {
+		executor := &Executor{}
+		executor.SetRuntime(evm.NewEVM())
+
+		transition := newTestTransition(nil)
+		transition.r = executor
+		transition.config = config
+		transition.evmLogger = runtime.NewDummyLogger()
+
+		return transition
+	}
+
+	// PUSH1 0xef PUSH1 0 MSTORE8 PUSH1 1 PUSH1 0 RETURN
+	initCode := []byte{0x60, 0xef, 0x60, 0x00, 0x53, 0x60, 0x01, 0x60, 0x00, 0xf3}
+
+	t.Run("should deploy the code starting with 0xef before london", func(t *testing.T) {
+		result := newTransition(berlin).Create2(addr1, initCode, big.NewInt(0), 100000)
+		assert.NoError(t, result.Err)
+	})
+
+	t.Run("should reject the code starting with 0xef after london (eip-3541)", func(t *testing.T) {
+		result := newTransition(london).Create2(addr1, initCode, big.NewInt(0), 100000)
+		assert.ErrorIs(t, result.Err, runtime.ErrInvalidCode)
+		assert.Equal(t, uint64(0), result.GasLeft)
+	})
+
+	t.Run("should not refund the selfdestruct after london (eip-3529)", func(t *testing.T) {
+		transition := newTransition(berlin)
+		transition.Selfdestruct(addr1, addr2)
+		assert.Equal(t, uint64(24000), transition.txn.GetRefund())
+
+		transition = newTransition(london)
+		transition.Selfdestruct(addr1, addr2)
+		assert.Equal(t, uint64(0), transition.txn.GetRefund())
+	})
+
+	t.Run("should cap the refund by the quotient", func(t *testing.T) {
+		result := &runtime.ExecutionResult{GasLeft: 0}
+		result.UpdateGasUsed(100000, 50000, runtime.RefundQuotient)
+		assert.Equal(t, uint64(50000), result.GasUsed)
+
+		result = &runtime.ExecutionResult{GasLeft: 0}
+		result.UpdateGasUsed(100000, 50000, runtime.RefundQuotientEIP3529)
+		assert.Equal(t, uint64(80000), result.GasUsed)
+	})
+}
@@ -19,6 +19,9 @@ var (
 
 	// refundIndex is the index of the refund
 	refundIndex = types.BytesToHash([]byte{3}).Bytes()
+
+	// accessListIndex is the key prefix of the warm addresses and slots (EIP-2929) in the trie
+	accessListIndex = types.BytesToHash([]byte{4}).Bytes()
 )
 
 // snapshotReader is snapshot read only APIs
@@ -63,6 +66,16 @@ func (txn *Txn) Snapshot() int {
 	return id
 }
 
+// Copy returns an independent copy of the txn at this point in time,
+// the later changes of either one are invisible to the other
+func (txn *Txn) Copy() *Txn {
+	return &Txn{
+		snapshot:  txn.snapshot,
+		snapshots: []*iradix.Tree{},
+		txn:       txn.txn.CommitOnly().Txn(),
+	}
+}
+
 // RevertToSnapshot reverts to a given snapshot
 func (txn *Txn) RevertToSnapshot(id int) {
 	if id > len(txn.snapshots) {
@@ -244,13 +257,19 @@ func (txn *Txn) SetStorage(
 
 	txn.SetState(addr, key, value)
 
+	// the refund of clearing a slot, reduced after london (eip-3529)
+	clearsRefund := uint64(15000)
+	if config.London {
+		clearsRefund = 4800
+	}
+
 	legacyGasMetering := !config.Istanbul && (config.Petersburg || !config.Constantinople)
 
 	if legacyGasMetering {
 		if oldValue == zeroHash {
 			return runtime.StorageAdded
 		} else if value == zeroHash {
-			txn.AddRefund(15000)
+			txn.AddRefund(clearsRefund)
 
 			return runtime.StorageDeleted
 		}
@@ -264,7 +283,7 @@ func (txn *Txn) SetStorage(
 		}
 
 		if value == zeroHash { // delete slot (2.1.2b)
-			txn.AddRefund(15000)
+			txn.AddRefund(clearsRefund)
 
 			return runtime.StorageDeleted
 		}
@@ -274,22 +293,28 @@ func (txn *Txn) SetStorage(
 
 	if original != zeroHash { // Storage slot was populated before this transaction started
 		if current == zeroHash { // recreate slot (2.2.1.1)
-			txn.SubRefund(15000)
+			txn.SubRefund(clearsRefund)
 		} else if value == zeroHash { // delete slot (2.2.1.2)
-			txn.AddRefund(15000)
+			txn.AddRefund(clearsRefund)
 		}
 	}
 
 	if original == value {
 		if original == zeroHash { // reset to original nonexistent slot (2.2.2.1)
 			// Storage was used as memory (allocation and deallocation occurred within the same contract)
-			if config.Istanbul {
+			if config.Berlin {
+				// eip-2929
+				txn.AddRefund(19900)
+			} else if config.Istanbul {
 				txn.AddRefund(19200)
 			} else {
 				txn.AddRefund(19800)
 			}
 		} else { // reset to original existing slot (2.2.2.2)
-			if config.Istanbul {
+			if config.Berlin {
+				// eip-2929
+				txn.AddRefund(2800)
+			} else if config.Istanbul {
 				txn.AddRefund(4200)
 			} else {
 				txn.AddRefund(4800)
@@ -446,6 +471,51 @@ func (txn *Txn) SubRefund(gas uint64) {
 	txn.txn.Insert(refundIndex, refund)
 }
 
+// Access list
+
+func accessListAddressKey(addr types.Address) []byte {
+	key := make([]byte, 0, len(accessListIndex)+types.AddressLength)
+	key = append(key, accessListIndex...)
+
+	return append(key, addr.Bytes()...)
+}
+
+func accessListSlotKey(addr types.Address, slot types.Hash) []byte {
+	return append(accessListAddressKey(addr), slot.Bytes()...)
+}
+
+// AddressInAccessList returns true if the address is warm in the current transaction
+func (txn *Txn) AddressInAccessList(addr types.Address) bool {
+	_, ok := txn.txn.Get(accessListAddressKey(addr))
+
+	return ok
+}
+
+// SlotInAccessList returns whether the address and the slot are warm in the current transaction
+func (txn *Txn) SlotInAccessList(addr types.Address, slot types.Hash) (addressOk bool, slotOk bool) {
+	addressOk = txn.AddressInAccessList(addr)
+	_, slotOk = txn.txn.Get(accessListSlotKey(addr, slot))
+
+	return addressOk, slotOk
+}
+
+// AddAddressToAccessList marks the address warm. The change is reverted together
+// with the snapshot it happens in.
+func (txn *Txn) AddAddressToAccessList(addr types.Address) {
+	txn.txn.Insert(accessListAddressKey(addr), struct{}{})
+}
+
+// AddSlotToAccessList marks the address and the slot warm
+func (txn *Txn) AddSlotToAccessList(addr types.Address, slot types.Hash) {
+	txn.AddAddressToAccessList(addr)
+	txn.txn.Insert(accessListSlotKey(addr, slot), struct{}{})
+}
+
+// ClearAccessList turns every address and slot cold again
+func (txn *Txn) ClearAccessList() {
+	txn.txn.DeletePrefix(accessListIndex)
+}
+
 func (txn *Txn) Logs() []*types.Log {
 	data, exists := txn.txn.Get(logIndex)
 	if !exists {
@@ -561,6 +631,8 @@ func (txn *Txn) CleanDeleteObjects(deleteEmptyObjects bool) {
 
 	// delete refunds
 	txn.txn.Delete(refundIndex)
+	// the access list only lives in one transaction
+	txn.txn.DeletePrefix(accessListIndex)
 }
 
 // func (txn *Txn) Commit(deleteEmptyObjects bool) (Snapshot, []byte) {
@@ -5,6 +5,8 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/state/runtime"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -66,3 +68,62 @@ func TestSnapshotUpdateData(t *testing.T) {
 	txn.RevertToSnapshot(ss)
 	assert.Equal(t, hash1, getState(t, txn, addr1, hash1))
 }
+
+func TestAccessListRevertToSnapshot(t *testing.T) {
+	txn := newTestTxn(defaultPreState)
+
+	txn.AddAddressToAccessList(addr1)
+
+	ss := txn.Snapshot()
+	txn.AddSlotToAccessList(addr2, hash1)
+
+	addressOk, slotOk := txn.SlotInAccessList(addr2, hash1)
+	assert.True(t, addressOk)
+	assert.True(t, slotOk)
+
+	txn.RevertToSnapshot(ss)
+
+	assert.True(t, txn.AddressInAccessList(addr1))
+	addressOk, slotOk = txn.SlotInAccessList(addr2, hash1)
+	assert.False(t, addressOk)
+	assert.False(t, slotOk)
+
+	txn.ClearAccessList()
+	assert.False(t, txn.AddressInAccessList(addr1))
+}
+
+func TestSetStorageClearsRefund(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *chain.ForksInTime
+		refund uint64
+	}{
+		{
+			"berlin",
+			&chain.ForksInTime{Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true},
+			15000,
+		},
+		{
+			"london (eip-3529)",
+			&chain.ForksInTime{Constantinople: true, Petersburg: true, Istanbul: true, Berlin: true, London: true},
+			4800,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			txn := newTestTxn(map[types.Address]*PreState{
+				addr1: {
+					State: map[types.Hash]types.Hash{hash1: hash1},
+				},
+			})
+
+			assert.Equal(t, runtime.StorageDeleted, txn.SetStorage(addr1, hash1, types.ZeroHash, c.config))
+			assert.Equal(t, c.refund, txn.GetRefund())
+
+			// the refund is taken back once the slot is recreated
+			assert.Equal(t, runtime.StorageModifiedAgain, txn.SetStorage(addr1, hash1, hash2, c.config))
+			assert.Equal(t, uint64(0), txn.GetRefund())
+		})
+	}
+}
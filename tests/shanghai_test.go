@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dogechain-lab/dogechain/chain"
+	"github.com/dogechain-lab/dogechain/helper/hex"
+	"github.com/dogechain-lab/dogechain/state"
+	"github.com/dogechain-lab/dogechain/state/runtime"
+	"github.com/dogechain-lab/dogechain/state/runtime/evm"
+	"github.com/dogechain-lab/dogechain/state/runtime/precompiled"
+	"github.com/dogechain-lab/dogechain/types"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	shanghaiCoinbase = types.StringToAddress("0x2adc25665018aa1fe0e6bc666dac8fc2697ff9ba")
+	shanghaiSender   = types.StringToAddress("0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b")
+	shanghaiContract = types.StringToAddress("0x1000000000000000000000000000000000000000")
+)
+
+// shanghaiVector is a test vector which runs the code of the contract
+// in a transaction, then checks the gas used and the return value
+type shanghaiVector struct {
+	name    string
+	fork    string
+	code    string
+	gasUsed uint64
+	out     string
+	failed  bool
+}
+
+var shanghaiVectors = []shanghaiVector{
+	{
+		// PUSH1 0x2a, PUSH0, MSTORE, PUSH1 0x20, PUSH0, RETURN
+		name:    "push0",
+		fork:    "Shanghai",
+		code:    "0x602a5f5260205ff3",
+		gasUsed: 21000 + 3 + 2 + 6 + 3 + 2,
+		out:     "0x000000000000000000000000000000000000000000000000000000000000002a",
+	},
+	{
+		// PUSH0
+		name:    "push0 before shanghai",
+		fork:    "London",
+		code:    "0x5f",
+		gasUsed: 100000,
+		failed:  true,
+	},
+	{
+		// COINBASE, BALANCE, STOP
+		name:    "warm coinbase",
+		fork:    "Shanghai",
+		code:    "0x413100",
+		gasUsed: 21000 + 2 + 100,
+	},
+	{
+		// COINBASE, BALANCE, STOP
+		name:    "cold coinbase before shanghai",
+		fork:    "London",
+		code:    "0x413100",
+		gasUsed: 21000 + 2 + 2600,
+	},
+	{
+		// PUSH3 0x00c001 (max initcode size + 1), PUSH1 0x00, PUSH1 0x00, CREATE
+		name:    "create exceeds max initcode size",
+		fork:    "Shanghai",
+		code:    "0x6200c00160006000f0",
+		gasUsed: 100000,
+		failed:  true,
+	},
+	{
+		// PUSH3 0x00c000 (max initcode size), PUSH1 0x00, PUSH1 0x00, CREATE, STOP
+		name: "create max initcode size",
+		fork: "Shanghai",
+		code: "0x6200c00060006000f000",
+		// 32000 create gas, 9216 memory expansion gas, 3072 initcode word gas,
+		// and the empty initcode returns all the forwarded gas
+		gasUsed: 21000 + 3*3 + 32000 + 9216 + 1536*runtime.InitCodeWordGas,
+	},
+	{
+		// PUSH3 0x00c001 (max initcode size + 1), PUSH1 0x00, PUSH1 0x00, CREATE, STOP
+		name: "create large initcode before shanghai",
+		fork: "London",
+		code: "0x6200c00160006000f000",
+		// 32000 create gas, 9225 memory expansion gas, and the empty initcode
+		// returns all the forwarded gas
+		gasUsed: 21000 + 3*3 + 32000 + 9225,
+	},
+}
+
+func runShanghaiVector(t *testing.T, v shanghaiVector) {
+	t.Helper()
+
+	config, ok := Forks[v.fork]
+	if !ok {
+		t.Fatalf("config %s not found", v.fork)
+	}
+
+	code, err := hex.DecodeHex(v.code)
+	assert.NoError(t, err)
+
+	s, _, root, err := buildState(map[types.Address]*chain.GenesisAccount{
+		shanghaiSender: {
+			Balance: big.NewInt(1000000000000000000),
+		},
+		shanghaiContract: {
+			Balance: big.NewInt(0),
+			Code:    code,
+		},
+	})
+	assert.NoError(t, err)
+
+	executor := state.NewExecutor(&chain.Params{Forks: config, ChainID: 1}, s, hclog.NewNullLogger())
+	executor.SetRuntime(precompiled.NewPrecompiled())
+	executor.SetRuntime(evm.NewEVM())
+	executor.GetHash = func(*types.Header) func(i uint64) types.Hash {
+		return vmTestBlockHash
+	}
+
+	header := &types.Header{
+		Number:   1,
+		GasLimit: 10000000,
+		Miner:    shanghaiCoinbase,
+	}
+
+	transition, err := executor.BeginTxn(root, header, shanghaiCoinbase)
+	assert.NoError(t, err)
+
+	result, err := transition.Apply(&types.Transaction{
+		From:     shanghaiSender,
+		To:       &shanghaiContract,
+		Gas:      100000,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, v.failed, result.Failed())
+	assert.Equal(t, v.gasUsed, result.GasUsed)
+
+	if v.out != "" {
+		assert.Equal(t, v.out, hex.EncodeToHex(result.ReturnValue))
+	}
+}
+
+func TestShanghai(t *testing.T) {
+	for _, v := range shanghaiVectors {
+		v := v
+
+		t.Run(v.name, func(t *testing.T) {
+			runShanghaiVector(t, v)
+		})
+	}
+}
+
+func TestShanghai_InitCodeIntrinsicGas(t *testing.T) {
+	msg := &types.Transaction{
+		Input: make([]byte, 33),
+	}
+
+	londonCost, err := state.TransactionGasCost(msg, true, true, false)
+	assert.NoError(t, err)
+
+	shanghaiCost, err := state.TransactionGasCost(msg, true, true, true)
+	assert.NoError(t, err)
+
+	// two words of initcode
+	assert.Equal(t, londonCost+2*runtime.InitCodeWordGas, shanghaiCost)
+}
@@ -37,6 +37,7 @@ type env struct {
 	GasLimit   string `json:"currentGasLimit"`
 	Number     string `json:"currentNumber"`
 	Timestamp  string `json:"currentTimestamp"`
+	BaseFee    string `json:"currentBaseFee"`
 }
 
 func remove0xPrefix(str string) string {
@@ -83,6 +84,17 @@ func stringToBigInt(str string) (*big.Int, error) {
 	return n, nil
 }
 
+func stringToBigIntT(t *testing.T, str string) *big.Int {
+	t.Helper()
+
+	n, err := stringToBigInt(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return n
+}
+
 func stringToAddressT(t *testing.T, str string) types.Address {
 	t.Helper()
 
@@ -157,7 +169,19 @@ func (e *env) ToEnv(t *testing.T) runtime.TxContext {
 		GasLimit:   stringToInt64T(t, e.GasLimit),
 		Number:     stringToInt64T(t, e.Number),
 		Timestamp:  stringToInt64T(t, e.Timestamp),
+		BaseFee:    e.baseFee(t),
+	}
+}
+
+// baseFee returns the base fee of the environment, which is only set after london
+func (e *env) baseFee(t *testing.T) types.Hash {
+	t.Helper()
+
+	if e.BaseFee == "" {
+		return types.Hash{}
 	}
+
+	return types.BytesToHash(stringToBigIntT(t, e.BaseFee).Bytes())
 }
 
 type exec struct {
@@ -295,6 +319,11 @@ type stTransaction struct {
 	Nonce    uint64         `json:"nonce"`
 	From     types.Address  `json:"secretKey"`
 	To       *types.Address `json:"to"`
+
+	AccessLists []types.AccessList `json:"accessLists"`
+
+	MaxFeePerGas         *big.Int `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *big.Int `json:"maxPriorityFeePerGas"`
 }
 
 func (t *stTransaction) At(i indexes) (*types.Transaction, error) {
@@ -322,6 +351,22 @@ func (t *stTransaction) At(i indexes) (*types.Transaction, error) {
 
 	msg.From = t.From
 
+	if i.Data < len(t.AccessLists) && t.AccessLists[i.Data] != nil {
+		msg.Type = types.AccessListTx
+		msg.AccessList = t.AccessLists[i.Data].Copy()
+	}
+
+	if t.MaxFeePerGas != nil {
+		msg.Type = types.DynamicFeeTx
+		msg.GasPrice = new(big.Int).Set(t.MaxFeePerGas)
+		msg.GasFeeCap = new(big.Int).Set(t.MaxFeePerGas)
+		msg.GasTipCap = new(big.Int)
+
+		if t.MaxPriorityFeePerGas != nil {
+			msg.GasTipCap.Set(t.MaxPriorityFeePerGas)
+		}
+	}
+
 	return msg, nil
 }
 
@@ -334,6 +379,11 @@ func (t *stTransaction) UnmarshalJSON(input []byte) error {
 		Nonce     string   `json:"nonce"`
 		SecretKey string   `json:"secretKey"`
 		To        string   `json:"to"`
+
+		AccessLists []types.AccessList `json:"accessLists"`
+
+		MaxFeePerGas         string `json:"maxFeePerGas"`
+		MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
 	}
 
 	var dec txUnmarshall
@@ -344,6 +394,7 @@ func (t *stTransaction) UnmarshalJSON(input []byte) error {
 	}
 
 	t.Data = dec.Data
+	t.AccessLists = dec.AccessLists
 
 	for _, i := range dec.GasLimit {
 		if j, err := stringToUint64(i); err != nil {
@@ -369,6 +420,21 @@ func (t *stTransaction) UnmarshalJSON(input []byte) error {
 		t.Value = append(t.Value, value)
 	}
 
+	if dec.MaxFeePerGas != "" {
+		// dynamic fee transactions have no gas price
+		if t.MaxFeePerGas, err = stringToBigInt(dec.MaxFeePerGas); err != nil {
+			return err
+		}
+
+		if dec.MaxPriorityFeePerGas != "" {
+			if t.MaxPriorityFeePerGas, err = stringToBigInt(dec.MaxPriorityFeePerGas); err != nil {
+				return err
+			}
+		}
+
+		dec.GasPrice = dec.MaxFeePerGas
+	}
+
 	t.GasPrice, err = stringToBigInt(dec.GasPrice)
 	if err != nil {
 		return err
@@ -445,6 +511,42 @@ var Forks = map[string]*chain.Forks{
 		Petersburg:     chain.NewFork(0),
 		Istanbul:       chain.NewFork(0),
 	},
+	"Berlin": {
+		Homestead:      chain.NewFork(0),
+		EIP150:         chain.NewFork(0),
+		EIP155:         chain.NewFork(0),
+		EIP158:         chain.NewFork(0),
+		Byzantium:      chain.NewFork(0),
+		Constantinople: chain.NewFork(0),
+		Petersburg:     chain.NewFork(0),
+		Istanbul:       chain.NewFork(0),
+		Berlin:         chain.NewFork(0),
+	},
+	"London": {
+		Homestead:      chain.NewFork(0),
+		EIP150:         chain.NewFork(0),
+		EIP155:         chain.NewFork(0),
+		EIP158:         chain.NewFork(0),
+		Byzantium:      chain.NewFork(0),
+		Constantinople: chain.NewFork(0),
+		Petersburg:     chain.NewFork(0),
+		Istanbul:       chain.NewFork(0),
+		Berlin:         chain.NewFork(0),
+		London:         chain.NewFork(0),
+	},
+	"Shanghai": {
+		Homestead:      chain.NewFork(0),
+		EIP150:         chain.NewFork(0),
+		EIP155:         chain.NewFork(0),
+		EIP158:         chain.NewFork(0),
+		Byzantium:      chain.NewFork(0),
+		Constantinople: chain.NewFork(0),
+		Petersburg:     chain.NewFork(0),
+		Istanbul:       chain.NewFork(0),
+		Berlin:         chain.NewFork(0),
+		London:         chain.NewFork(0),
+		Shanghai:       chain.NewFork(0),
+	},
 	"FrontierToHomesteadAt5": {
 		Homestead: chain.NewFork(5),
 	},
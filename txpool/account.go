@@ -439,6 +439,9 @@ func (a *account) IsOutdated(outdateTimeBound time.Time) bool {
 	return a.lastPromoted.Before(outdateTimeBound)
 }
 
+// txPriceReplacable returns whether the new transaction pays more than the old one,
+// both the fee cap and the tip cap should be bumped
 func txPriceReplacable(newTx, oldTx *types.Transaction) bool {
-	return newTx.GasPrice.Cmp(oldTx.GasPrice) > 0
+	return newTx.GetGasFeeCap().Cmp(oldTx.GetGasFeeCap()) > 0 &&
+		newTx.GetGasTipCap().Cmp(oldTx.GetGasTipCap()) > 0
 }
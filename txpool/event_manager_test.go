@@ -76,6 +76,20 @@ func TestEventManager_SubscribeClose(t *testing.T) {
 	}
 }
 
+func TestEventManager_CancelAfterClose(t *testing.T) {
+	em := newEventManager(hclog.NewNullLogger())
+
+	subscription := em.subscribe([]proto.EventType{proto.EventType_PROMOTED})
+
+	em.Close()
+
+	// cancelling a closed subscription should be a no-op
+	assert.NotPanics(t, func() {
+		em.cancelSubscription(subscription.subscriptionID)
+	})
+	assert.Equal(t, int64(0), em.numSubscriptions)
+}
+
 func TestEventManager_SignalEvent(t *testing.T) {
 	totalEvents := 10
 	invalidEvents := 3
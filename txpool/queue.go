@@ -292,7 +292,7 @@ func (q *pricedQueue) length() uint64 {
 	return uint64(q.queue.Len())
 }
 
-// transactions sorted by gas price (descending)
+// transactions sorted by gas tip cap (descending), which is the gas price of non-dynamic fee transactions
 type maxPriceQueue []*types.Transaction
 
 /* Queue methods required by the heap interface */
@@ -314,7 +314,7 @@ func (q *maxPriceQueue) Swap(i, j int) {
 }
 
 func (q *maxPriceQueue) Less(i, j int) bool {
-	return (*q)[i].GasPrice.Uint64() > (*q)[j].GasPrice.Uint64()
+	return (*q)[i].GetGasTipCap().Cmp((*q)[j].GetGasTipCap()) > 0
 }
 
 func (q *maxPriceQueue) Push(x interface{}) {
@@ -13,6 +13,7 @@ import (
 	"github.com/dogechain-lab/dogechain/chain"
 	"github.com/dogechain-lab/dogechain/network"
 	"github.com/dogechain-lab/dogechain/state"
+	"github.com/dogechain-lab/dogechain/state/runtime"
 	"github.com/dogechain-lab/dogechain/txpool/proto"
 	"github.com/dogechain-lab/dogechain/types"
 	"github.com/hashicorp/go-hclog"
@@ -52,6 +53,9 @@ var (
 	ErrContractDDOSList    = errors.New("contract in ddos list")
 	ErrTxPoolClosed        = errors.New("txpool is close")
 	ErrContractDestructive = errors.New("contract is destructive")
+	ErrTxTypeNotSupported  = errors.New("transaction type not supported")
+	ErrTipAboveFeeCap      = errors.New("max priority fee per gas higher than max fee per gas")
+	ErrInitCodeTooLarge    = errors.New("max initcode size exceeded")
 )
 
 // indicates origin of a transaction
@@ -147,7 +151,7 @@ type promoteRequest struct {
 type TxPool struct {
 	logger hclog.Logger
 	signer signer
-	forks  chain.ForksInTime
+	forks  *chain.Forks
 	store  store
 
 	// map of all accounts registered by the pool
@@ -213,7 +217,7 @@ type TxPool struct {
 // NewTxPool returns a new pool for processing incoming transactions.
 func NewTxPool(
 	logger hclog.Logger,
-	forks chain.ForksInTime,
+	forks *chain.Forks,
 	store store,
 	grpcServer *grpc.Server,
 	network network.Server,
@@ -381,6 +385,19 @@ func (p *TxPool) SetSigner(s signer) {
 	p.signer = s
 }
 
+// SubscribeEvents subscribes to the given types of the pool events. The events are
+// delivered through the returned channel, which is closed once the cancel function
+// is called or the pool is closed.
+func (p *TxPool) SubscribeEvents(eventTypes ...proto.EventType) (<-chan *proto.TxPoolEvent, func()) {
+	subscription := p.eventManager.subscribe(eventTypes)
+
+	cancel := func() {
+		p.eventManager.cancelSubscription(subscription.subscriptionID)
+	}
+
+	return subscription.subscriptionChannel, cancel
+}
+
 // AddTx adds a new transaction to the pool (sent from json-RPC/gRPC endpoints)
 // and broadcasts it to the network (if enabled).
 func (p *TxPool) AddTx(tx *types.Transaction) error {
@@ -656,6 +673,30 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 		return ErrNegativeValue
 	}
 
+	// The transaction should be valid in the next block
+	forks := p.forks.At(p.store.Header().Number + 1)
+
+	// Typed transactions are only accepted after the berlin hardfork
+	if !tx.Type.IsSupported() || (tx.Type.IsTyped() && !forks.Berlin) {
+		return ErrTxTypeNotSupported
+	}
+
+	// Dynamic fee transactions are only accepted after the london hardfork
+	if tx.Type == types.DynamicFeeTx {
+		if !forks.London || tx.GasFeeCap == nil || tx.GasTipCap == nil {
+			return ErrTxTypeNotSupported
+		}
+
+		if tx.GasFeeCap.Cmp(tx.GasTipCap) < 0 {
+			return ErrTipAboveFeeCap
+		}
+	}
+
+	// The contract creation code is limited after the shanghai hardfork
+	if forks.Shanghai && tx.IsContractCreation() && len(tx.Input) > runtime.MaxInitCodeSize {
+		return ErrInitCodeTooLarge
+	}
+
 	// Check if the transaction is signed properly
 
 	// Extract the sender
@@ -704,7 +745,7 @@ func (p *TxPool) validateTx(tx *types.Transaction) error {
 	}
 
 	// Make sure the transaction has more gas than the basic transaction fee
-	intrinsicGas, err := state.TransactionGasCost(tx, p.forks.Homestead, p.forks.Istanbul)
+	intrinsicGas, err := state.TransactionGasCost(tx, forks.Homestead, forks.Istanbul, forks.Shanghai)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,126 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/dogechain-lab/fastrlp"
+)
+
+// AccessTuple is the element type of an EIP-2930 access list
+type AccessTuple struct {
+	Address     Address `json:"address"`
+	StorageKeys []Hash  `json:"storageKeys"`
+}
+
+// AccessList is an EIP-2930 access list
+type AccessList []AccessTuple
+
+// StorageKeys returns the total number of storage keys in the access list
+func (al AccessList) StorageKeys() int {
+	sum := 0
+	for _, tuple := range al {
+		sum += len(tuple.StorageKeys)
+	}
+
+	return sum
+}
+
+// Copy returns a deep copy of the access list
+func (al AccessList) Copy() AccessList {
+	if al == nil {
+		return nil
+	}
+
+	cpy := make(AccessList, len(al))
+
+	for i, tuple := range al {
+		cpy[i].Address = tuple.Address
+
+		if tuple.StorageKeys != nil {
+			cpy[i].StorageKeys = make([]Hash, len(tuple.StorageKeys))
+			copy(cpy[i].StorageKeys, tuple.StorageKeys)
+		}
+	}
+
+	return cpy
+}
+
+// MarshalRLPWith marshals the access list to RLP with a specific fastrlp.Arena
+func (al AccessList) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	if len(al) == 0 {
+		return arena.NewNullArray()
+	}
+
+	vv := arena.NewArray()
+
+	for _, tuple := range al {
+		tv := arena.NewArray()
+		tv.Set(arena.NewCopyBytes(tuple.Address.Bytes()))
+
+		if len(tuple.StorageKeys) == 0 {
+			tv.Set(arena.NewNullArray())
+		} else {
+			keys := arena.NewArray()
+			for _, key := range tuple.StorageKeys {
+				keys.Set(arena.NewCopyBytes(key.Bytes()))
+			}
+
+			tv.Set(keys)
+		}
+
+		vv.Set(tv)
+	}
+
+	return vv
+}
+
+// UnmarshalRLPFrom unmarshals the access list in RLP format
+func (al *AccessList) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	elems, err := v.GetElems()
+	if err != nil {
+		return err
+	}
+
+	if len(elems) == 0 {
+		*al = nil
+
+		return nil
+	}
+
+	list := make(AccessList, len(elems))
+
+	for i, elem := range elems {
+		tuple, err := elem.GetElems()
+		if err != nil {
+			return err
+		}
+
+		if len(tuple) != 2 {
+			return fmt.Errorf("incorrect number of elements to decode access tuple, expected 2 but found %d",
+				len(tuple))
+		}
+
+		// address
+		if err := tuple[0].GetAddr(list[i].Address[:]); err != nil {
+			return err
+		}
+
+		// storage keys
+		keys, err := tuple[1].GetElems()
+		if err != nil {
+			return err
+		}
+
+		list[i].StorageKeys = make([]Hash, len(keys))
+
+		for j, key := range keys {
+			if err := key.GetHash(list[i].StorageKeys[j][:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	*al = list
+
+	return nil
+}
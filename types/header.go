@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math/big"
 	"sync/atomic"
 
 	"github.com/dogechain-lab/dogechain/helper/hex"
@@ -27,6 +28,7 @@ type Header struct {
 	ExtraData    []byte
 	MixHash      Hash
 	Nonce        Nonce
+	BaseFee      *big.Int // only set after the london hardfork
 	Hash         Hash
 }
 
@@ -101,6 +103,10 @@ func (h *Header) Copy() *Header {
 		Hash:         h.Hash,
 	}
 
+	if h.BaseFee != nil {
+		newHeader.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
+
 	newHeader.ExtraData = make([]byte, len(h.ExtraData))
 	copy(newHeader.ExtraData[:], h.ExtraData[:])
 
@@ -18,10 +18,15 @@ const (
 	ReceiptSuccess
 )
 
+var (
+	ErrTypedReceiptTooShort = errors.New("typed receipt too short")
+)
+
 type Receipts []*Receipt
 
 type Receipt struct {
 	// consensus fields
+	Type              TxType // the type of the transaction, typed receipts are enveloped as the transactions
 	Root              Hash
 	CumulativeGasUsed uint64
 	LogsBloom         Bloom
@@ -48,7 +53,10 @@ type Log struct {
 	Data    []byte
 }
 
-const BloomByteLength = 256
+const (
+	BloomByteLength = 256
+	BloomBitLength  = 8 * BloomByteLength
+)
 
 type Bloom [BloomByteLength]byte
 
@@ -125,6 +133,28 @@ func (b *Bloom) setEncode(hasher *keccak.Keccak, h []byte) {
 	}
 }
 
+// HasBit checks whether the global bit location is set in the bloom filter
+func (b *Bloom) HasBit(bit uint) bool {
+	return b[BloomByteLength-1-bit/8]&(1<<(bit%8)) != 0
+}
+
+// BloomBits returns the global bit locations of the data in the bloom filter,
+// the same as the ones set by CreateBloom
+func BloomBits(data []byte) (bits [3]uint) {
+	hasher := keccak.DefaultKeccakPool.Get()
+	defer keccak.DefaultKeccakPool.Put(hasher)
+
+	hasher.Reset()
+	hasher.Write(data)
+	buf := hasher.Read()
+
+	for i := 0; i < 3; i++ {
+		bits[i] = (uint(buf[2*i+1]) + (uint(buf[2*i]) << 8)) & (BloomBitLength - 1)
+	}
+
+	return bits
+}
+
 // IsLogInBloom checks if the log has a possible presence in the bloom filter
 func (b *Bloom) IsLogInBloom(log *Log) bool {
 	hasher := keccak.DefaultKeccakPool.Get()
@@ -62,6 +62,92 @@ func TestRLPMarshall_And_Unmarshall_Transaction(t *testing.T) {
 	assert.Equal(t, txn, unmarshalledTxn)
 }
 
+func TestRLPMarshall_And_Unmarshall_AccessListTransaction(t *testing.T) {
+	addrTo := StringToAddress("11")
+	txn := &Transaction{
+		Type:     AccessListTx,
+		ChainID:  big.NewInt(2000),
+		Nonce:    1,
+		GasPrice: big.NewInt(11),
+		Gas:      11,
+		To:       &addrTo,
+		Value:    big.NewInt(1),
+		Input:    []byte{1, 2},
+		AccessList: AccessList{
+			{Address: addrTo, StorageKeys: []Hash{StringToHash("1"), StringToHash("2")}},
+			{Address: StringToAddress("12"), StorageKeys: []Hash{}},
+		},
+		V: big.NewInt(1),
+		S: big.NewInt(26),
+		R: big.NewInt(27),
+	}
+
+	marshaledRlp := txn.MarshalRLP()
+	// typed transaction envelope
+	assert.Equal(t, byte(AccessListTx), marshaledRlp[0])
+
+	unmarshalledTxn := new(Transaction)
+	if err := unmarshalledTxn.UnmarshalRLP(marshaledRlp); err != nil {
+		t.Fatal(err)
+	}
+
+	txn.Hash()
+
+	assert.Equal(t, txn, unmarshalledTxn)
+
+	// typed transactions are embedded as byte strings in the block body
+	body := &Body{Transactions: []*Transaction{txn}}
+	unmarshalledBody := new(Body)
+
+	if err := unmarshalledBody.UnmarshalRLP(body.MarshalRLPTo(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, txn.Hash(), unmarshalledBody.Transactions[0].Hash())
+	assert.Equal(t, txn.AccessList, unmarshalledBody.Transactions[0].AccessList)
+}
+
+func TestRLPMarshall_And_Unmarshall_DynamicFeeTransaction(t *testing.T) {
+	addrTo := StringToAddress("11")
+	txn := &Transaction{
+		Type:      DynamicFeeTx,
+		ChainID:   big.NewInt(2000),
+		Nonce:     1,
+		GasPrice:  big.NewInt(20),
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(20),
+		Gas:       11,
+		To:        &addrTo,
+		Value:     big.NewInt(1),
+		Input:     []byte{1, 2},
+		AccessList: AccessList{
+			{Address: addrTo, StorageKeys: []Hash{StringToHash("1")}},
+		},
+		V: big.NewInt(1),
+		S: big.NewInt(26),
+		R: big.NewInt(27),
+	}
+
+	marshaledRlp := txn.MarshalRLP()
+	// typed transaction envelope
+	assert.Equal(t, byte(DynamicFeeTx), marshaledRlp[0])
+
+	unmarshalledTxn := new(Transaction)
+	if err := unmarshalledTxn.UnmarshalRLP(marshaledRlp); err != nil {
+		t.Fatal(err)
+	}
+
+	txn.Hash()
+
+	assert.Equal(t, txn, unmarshalledTxn)
+}
+
+func TestRLPUnmarshal_UnsupportedTransactionType(t *testing.T) {
+	txn := new(Transaction)
+
+	assert.ErrorIs(t, txn.UnmarshalRLP([]byte{0x7f, 0xc0}), ErrTxTypeNotSupported)
+}
+
 func TestRLPStorage_Marshall_And_Unmarshall_Receipt(t *testing.T) {
 	addr := StringToAddress("11")
 	hash := StringToHash("10")
@@ -92,6 +178,17 @@ func TestRLPStorage_Marshall_And_Unmarshall_Receipt(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"Marshal typed receipt",
+			&Receipt{
+				Type:              DynamicFeeTx,
+				CumulativeGasUsed: 10,
+				GasUsed:           100,
+				ContractAddress:   &addr,
+				TxHash:            hash,
+			},
+			true,
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -116,6 +213,41 @@ func TestRLPStorage_Marshall_And_Unmarshall_Receipt(t *testing.T) {
 	}
 }
 
+func TestRLPMarshall_And_Unmarshall_TypedReceipt(t *testing.T) {
+	receipt := &Receipt{
+		Type:              AccessListTx,
+		CumulativeGasUsed: 10,
+		Logs: []*Log{
+			{
+				Address: StringToAddress("11"),
+				Topics:  []Hash{StringToHash("10")},
+				Data:    []byte{0x1},
+			},
+		},
+	}
+	receipt.SetStatus(ReceiptSuccess)
+
+	// the typed receipts are encoded as type || rlp(payload)
+	marshaledRlp := receipt.MarshalRLP()
+	assert.Equal(t, byte(AccessListTx), marshaledRlp[0])
+
+	legacy := *receipt
+	legacy.Type = LegacyTx
+	assert.Equal(t, legacy.MarshalRLP(), marshaledRlp[1:])
+
+	unmarshalledReceipt := new(Receipt)
+	assert.NoError(t, unmarshalledReceipt.UnmarshalRLP(marshaledRlp))
+	assert.Equal(t, receipt, unmarshalledReceipt)
+
+	// the typed receipts are wrapped into byte strings in the lists
+	receipts := Receipts{receipt, &legacy}
+	unmarshalledReceipts := Receipts{}
+	assert.NoError(t, unmarshalledReceipts.UnmarshalRLP(receipts.MarshalRLPTo(nil)))
+	assert.Equal(t, receipts, unmarshalledReceipts)
+
+	assert.ErrorIs(t, new(Receipt).UnmarshalRLP([]byte{0x02}), ErrTypedReceiptTooShort)
+}
+
 func TestRLPUnmarshal_Header_ComputeHash(t *testing.T) {
 	// header computes hash after unmarshalling
 	h := &Header{}
@@ -126,3 +258,22 @@ func TestRLPUnmarshal_Header_ComputeHash(t *testing.T) {
 	assert.NoError(t, h2.UnmarshalRLP(data))
 	assert.Equal(t, h.Hash, h2.Hash)
 }
+
+func TestRLPMarshall_And_Unmarshall_Header_BaseFee(t *testing.T) {
+	h := &Header{
+		Number:  1,
+		BaseFee: big.NewInt(1000000000),
+	}
+	h.ComputeHash()
+
+	h2 := new(Header)
+	assert.NoError(t, h2.UnmarshalRLP(h.MarshalRLP()))
+	assert.Equal(t, h.BaseFee, h2.BaseFee)
+	assert.Equal(t, h.Hash, h2.Hash)
+
+	// the base fee changes the header hash
+	h3 := h.Copy()
+	h3.BaseFee = nil
+	h3.ComputeHash()
+	assert.NotEqual(t, h.Hash, h3.Hash)
+}
@@ -83,6 +83,11 @@ func (h *Header) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 	vv.Set(arena.NewBytes(h.MixHash.Bytes()))
 	vv.Set(arena.NewCopyBytes(h.Nonce[:]))
 
+	// optional field appended by the london hardfork
+	if h.BaseFee != nil {
+		vv.Set(arena.NewBigInt(h.BaseFee))
+	}
+
 	return vv
 }
 
@@ -103,12 +108,30 @@ func (r *Receipt) MarshalRLP() []byte {
 	return r.MarshalRLPTo(nil)
 }
 
+// MarshalRLPTo marshals the receipt into its canonical binary format.
+// Typed receipts are encoded as type || rlp(payload), as specified by EIP-2718.
 func (r *Receipt) MarshalRLPTo(dst []byte) []byte {
+	if r.Type.IsTyped() {
+		dst = append(dst, byte(r.Type))
+
+		return MarshalRLPTo(r.marshalPayloadRLPWith, dst)
+	}
+
 	return MarshalRLPTo(r.MarshalRLPWith, dst)
 }
 
-// MarshalRLPWith marshals a receipt with a specific fastrlp.Arena
+// MarshalRLPWith marshals a receipt with a specific fastrlp.Arena. Typed receipts
+// are wrapped into an opaque byte string, as the typed transactions.
 func (r *Receipt) MarshalRLPWith(a *fastrlp.Arena) *fastrlp.Value {
+	if r.Type.IsTyped() {
+		return a.NewBytes(r.MarshalRLPTo(nil))
+	}
+
+	return r.marshalPayloadRLPWith(a)
+}
+
+// marshalPayloadRLPWith marshals the receipt payload, without any envelope
+func (r *Receipt) marshalPayloadRLPWith(a *fastrlp.Arena) *fastrlp.Value {
 	vv := a.NewArray()
 	if r.Status != nil {
 		vv.Set(a.NewUint(uint64(*r.Status)))
@@ -158,16 +181,46 @@ func (t *Transaction) MarshalRLP() []byte {
 	return t.MarshalRLPTo(nil)
 }
 
+// MarshalRLPTo marshals the transaction into its canonical binary format.
+// Typed transactions are encoded as type || rlp(payload), as specified by EIP-2718.
 func (t *Transaction) MarshalRLPTo(dst []byte) []byte {
+	if t.Type.IsTyped() {
+		dst = append(dst, byte(t.Type))
+
+		return MarshalRLPTo(t.marshalPayloadRLPWith, dst)
+	}
+
 	return MarshalRLPTo(t.MarshalRLPWith, dst)
 }
 
-// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena
+// MarshalRLPWith marshals the transaction to RLP with a specific fastrlp.Arena.
+// Typed transactions are wrapped into an opaque byte string, so that they could
+// be embedded in the block body.
 func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
+	if t.Type.IsTyped() {
+		return arena.NewBytes(t.MarshalRLPTo(nil))
+	}
+
+	return t.marshalPayloadRLPWith(arena)
+}
+
+// marshalPayloadRLPWith marshals the transaction payload, without any envelope
+func (t *Transaction) marshalPayloadRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 	vv := arena.NewArray()
 
+	if t.Type.IsTyped() {
+		vv.Set(arena.NewBigInt(t.ChainID))
+	}
+
 	vv.Set(arena.NewUint(t.Nonce))
-	vv.Set(arena.NewBigInt(t.GasPrice))
+
+	if t.Type == DynamicFeeTx {
+		vv.Set(arena.NewBigInt(t.GasTipCap))
+		vv.Set(arena.NewBigInt(t.GasFeeCap))
+	} else {
+		vv.Set(arena.NewBigInt(t.GasPrice))
+	}
+
 	vv.Set(arena.NewUint(t.Gas))
 
 	// Address may be empty
@@ -180,6 +233,10 @@ func (t *Transaction) MarshalRLPWith(arena *fastrlp.Arena) *fastrlp.Value {
 	vv.Set(arena.NewBigInt(t.Value))
 	vv.Set(arena.NewCopyBytes(t.Input))
 
+	if t.Type.IsTyped() {
+		vv.Set(t.AccessList.MarshalRLPWith(arena))
+	}
+
 	// signature values
 	vv.Set(arena.NewBigInt(t.V))
 	vv.Set(arena.NewBigInt(t.R))
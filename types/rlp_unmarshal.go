@@ -167,6 +167,16 @@ func (h *Header) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 
 	h.SetNonce(nonce)
 
+	// baseFee
+	if len(elems) > 15 {
+		h.BaseFee = new(big.Int)
+		if err = elems[15].GetBigInt(h.BaseFee); err != nil {
+			return err
+		}
+	} else {
+		h.BaseFee = nil
+	}
+
 	// compute the hash after the decoding
 	h.ComputeHash()
 
@@ -195,12 +205,50 @@ func (r *Receipts) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	return nil
 }
 
+// UnmarshalRLP unmarshals a Receipt from its canonical binary format
 func (r *Receipt) UnmarshalRLP(input []byte) error {
-	return UnmarshalRlp(r.UnmarshalRLPFrom, input)
+	if len(input) > 0 && input[0] <= 0x7f {
+		return r.unmarshalTypedRLP(input)
+	}
+
+	r.Type = LegacyTx
+
+	return UnmarshalRlp(r.unmarshalPayloadRLPFrom, input)
 }
 
-// UnmarshalRLP unmarshals a Receipt in RLP format
+// UnmarshalRLPFrom unmarshals a Receipt in RLP format. Typed receipts embedded
+// in a list come as an opaque byte string.
 func (r *Receipt) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	if v.Type() == fastrlp.TypeBytes {
+		buf, err := v.Bytes()
+		if err != nil {
+			return err
+		}
+
+		return r.unmarshalTypedRLP(buf)
+	}
+
+	r.Type = LegacyTx
+
+	return r.unmarshalPayloadRLPFrom(p, v)
+}
+
+// unmarshalTypedRLP unmarshals the type || rlp(payload) envelope
+func (r *Receipt) unmarshalTypedRLP(input []byte) error {
+	if len(input) <= 1 {
+		return ErrTypedReceiptTooShort
+	}
+
+	r.Type = TxType(input[0])
+	if !r.Type.IsTyped() || !r.Type.IsSupported() {
+		return ErrTxTypeNotSupported
+	}
+
+	return UnmarshalRlp(r.unmarshalPayloadRLPFrom, input[1:])
+}
+
+// unmarshalPayloadRLPFrom unmarshals the receipt payload
+func (r *Receipt) unmarshalPayloadRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	elems, err := v.GetElems()
 	if err != nil {
 		return err
@@ -292,37 +340,117 @@ func (l *Log) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	return nil
 }
 
+// UnmarshalRLP unmarshals a Transaction from its canonical binary format,
+// which is either a legacy RLP list or an EIP-2718 typed envelope
 func (t *Transaction) UnmarshalRLP(input []byte) error {
-	return UnmarshalRlp(t.UnmarshalRLPFrom, input)
+	if len(input) > 0 && input[0] <= 0x7f {
+		return t.unmarshalTypedRLP(input)
+	}
+
+	t.Type = LegacyTx
+
+	return UnmarshalRlp(t.unmarshalPayloadRLPFrom, input)
 }
 
-// UnmarshalRLP unmarshals a Transaction in RLP format
+// UnmarshalRLPFrom unmarshals a Transaction in RLP format. Typed transactions
+// embedded in a block body come as an opaque byte string.
 func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
+	if v.Type() == fastrlp.TypeBytes {
+		buf, err := v.Bytes()
+		if err != nil {
+			return err
+		}
+
+		return t.unmarshalTypedRLP(buf)
+	}
+
+	t.Type = LegacyTx
+
+	return t.unmarshalPayloadRLPFrom(p, v)
+}
+
+// unmarshalTypedRLP unmarshals the type || rlp(payload) envelope
+func (t *Transaction) unmarshalTypedRLP(input []byte) error {
+	if len(input) <= 1 {
+		return ErrTypedTxTooShort
+	}
+
+	t.Type = TxType(input[0])
+	if !t.Type.IsTyped() || !t.Type.IsSupported() {
+		return ErrTxTypeNotSupported
+	}
+
+	return UnmarshalRlp(t.unmarshalPayloadRLPFrom, input[1:])
+}
+
+// unmarshalPayloadRLPFrom unmarshals the transaction payload of its type
+func (t *Transaction) unmarshalPayloadRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) error {
 	elems, err := v.GetElems()
 	if err != nil {
 		return err
 	}
 
-	if len(elems) < 9 {
-		return fmt.Errorf("incorrect number of elements to decode transaction, expected at least 9 but found %d",
-			len(elems))
+	// typed transactions have chain id and access list fields,
+	// dynamic fee transactions split the gas price into tip cap and fee cap
+	num := 9
+	if t.Type.IsTyped() {
+		num = 11
+	}
+
+	if t.Type == DynamicFeeTx {
+		num++
+	}
+
+	if len(elems) < num {
+		return fmt.Errorf("incorrect number of elements to decode transaction, expected at least %d but found %d",
+			num, len(elems))
+	}
+
+	if t.Type.IsTyped() {
+		// chainID
+		t.ChainID = new(big.Int)
+		if err := elems[0].GetBigInt(t.ChainID); err != nil {
+			return err
+		}
+
+		elems = elems[1:]
+	} else {
+		t.ChainID = nil
 	}
 
 	// nonce
 	if t.Nonce, err = elems[0].GetUint64(); err != nil {
 		return err
 	}
-	// gasPrice
+
+	if t.Type == DynamicFeeTx {
+		// gasTipCap
+		t.GasTipCap = new(big.Int)
+		if err := elems[1].GetBigInt(t.GasTipCap); err != nil {
+			return err
+		}
+
+		elems = elems[1:]
+	} else {
+		t.GasTipCap = nil
+		t.GasFeeCap = nil
+	}
+
+	// gasPrice, or gasFeeCap of dynamic fee transactions
 	t.GasPrice = new(big.Int)
 	if err := elems[1].GetBigInt(t.GasPrice); err != nil {
 		return err
 	}
+
+	if t.Type == DynamicFeeTx {
+		t.GasFeeCap = new(big.Int).Set(t.GasPrice)
+	}
 	// gas
 	if t.Gas, err = elems[2].GetUint64(); err != nil {
 		return err
 	}
 	// to
-	if vv, _ := v.Get(3).Bytes(); len(vv) == 20 {
+	if vv, _ := elems[3].Bytes(); len(vv) == 20 {
 		// address
 		addr := BytesToAddress(vv)
 		t.To = &addr
@@ -340,6 +468,17 @@ func (t *Transaction) UnmarshalRLPFrom(p *fastrlp.Parser, v *fastrlp.Value) erro
 		return err
 	}
 
+	if t.Type.IsTyped() {
+		// accessList
+		if err := t.AccessList.UnmarshalRLPFrom(p, elems[6]); err != nil {
+			return err
+		}
+
+		elems = elems[1:]
+	} else {
+		t.AccessList = nil
+	}
+
 	// V
 	t.V = new(big.Int)
 	if err = elems[6].GetBigInt(t.V); err != nil {
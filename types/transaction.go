@@ -2,6 +2,7 @@ package types
 
 import (
 	"container/heap"
+	"errors"
 	"math/big"
 	"sync/atomic"
 	"time"
@@ -9,9 +10,41 @@ import (
 	"github.com/dogechain-lab/dogechain/helper/keccak"
 )
 
+// TxType is the EIP-2718 type of the transaction envelope
+type TxType byte
+
+const (
+	LegacyTx     TxType = 0x00
+	AccessListTx TxType = 0x01
+	DynamicFeeTx TxType = 0x02
+)
+
+var (
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+	ErrTypedTxTooShort    = errors.New("typed transaction too short")
+	ErrGasFeeCapTooLow    = errors.New("fee cap less than base fee")
+)
+
+// IsTyped returns whether the transaction type is wrapped in an EIP-2718 envelope
+func (t TxType) IsTyped() bool {
+	return t != LegacyTx
+}
+
+// IsSupported returns whether the transaction type could be decoded
+func (t TxType) IsSupported() bool {
+	switch t {
+	case LegacyTx, AccessListTx, DynamicFeeTx:
+		return true
+	}
+
+	return false
+}
+
 type Transaction struct {
+	Type     TxType
+	ChainID  *big.Int // only typed transactions carry it
 	Nonce    uint64
-	GasPrice *big.Int
+	GasPrice *big.Int // gas fee cap of dynamic fee transactions
 	Gas      uint64
 	To       *Address
 	Value    *big.Int
@@ -21,6 +54,12 @@ type Transaction struct {
 	S        *big.Int
 	From     Address
 
+	AccessList AccessList
+
+	// only dynamic fee transactions carry them
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+
 	// Cache
 	size atomic.Value
 	hash atomic.Value
@@ -46,6 +85,8 @@ func (t *Transaction) Hash() Hash {
 }
 
 // rlpHash encodes transaction hash.
+//
+// Typed transactions are hashed as keccak256(type || rlp(payload)).
 func (t *Transaction) rlpHash() (h Hash) {
 	ar := marshalArenaPool.Get()
 	hash := keccak.DefaultKeccakPool.Get()
@@ -55,6 +96,13 @@ func (t *Transaction) rlpHash() (h Hash) {
 		marshalArenaPool.Put(ar)
 	}()
 
+	if t.Type.IsTyped() {
+		hash.Write([]byte{byte(t.Type)}) //nolint:errcheck
+		hash.WriteRlp(h[:0], t.marshalPayloadRLPWith(ar))
+
+		return h
+	}
+
 	v := t.MarshalRLPWith(ar)
 	hash.WriteRlp(h[:0], v)
 
@@ -64,16 +112,29 @@ func (t *Transaction) rlpHash() (h Hash) {
 // Copy returns a deep copy
 func (t *Transaction) Copy() *Transaction {
 	tt := &Transaction{
+		Type:  t.Type,
 		Nonce: t.Nonce,
 		Gas:   t.Gas,
 		From:  t.From,
 	}
 
+	if t.ChainID != nil {
+		tt.ChainID = new(big.Int).Set(t.ChainID)
+	}
+
 	tt.GasPrice = new(big.Int)
 	if t.GasPrice != nil {
 		tt.GasPrice.Set(t.GasPrice)
 	}
 
+	if t.GasTipCap != nil {
+		tt.GasTipCap = new(big.Int).Set(t.GasTipCap)
+	}
+
+	if t.GasFeeCap != nil {
+		tt.GasFeeCap = new(big.Int).Set(t.GasFeeCap)
+	}
+
 	if t.To != nil {
 		toAddr := *t.To
 		tt.To = &toAddr
@@ -101,14 +162,60 @@ func (t *Transaction) Copy() *Transaction {
 		tt.S = new(big.Int).SetBits(t.S.Bits())
 	}
 
+	tt.AccessList = t.AccessList.Copy()
+
 	tt.ReceivedTime = t.ReceivedTime
 
 	return tt
 }
 
-// Cost returns gas * gasPrice + value
+// GetGasTipCap returns the max tip per gas the sender would pay to the validator.
+// It is the gas price of non dynamic fee transactions.
+func (t *Transaction) GetGasTipCap() *big.Int {
+	if t.Type == DynamicFeeTx {
+		return t.GasTipCap
+	}
+
+	return t.GasPrice
+}
+
+// GetGasFeeCap returns the max fee per gas the sender would pay.
+// It is the gas price of non dynamic fee transactions.
+func (t *Transaction) GetGasFeeCap() *big.Int {
+	if t.Type == DynamicFeeTx {
+		return t.GasFeeCap
+	}
+
+	return t.GasPrice
+}
+
+// EffectiveGasTip returns the tip per gas the validator gets under the given base fee.
+// The result is negative when the fee cap could not cover the base fee.
+func (t *Transaction) EffectiveGasTip(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return new(big.Int).Set(t.GetGasTipCap())
+	}
+
+	tip := new(big.Int).Sub(t.GetGasFeeCap(), baseFee)
+	if tipCap := t.GetGasTipCap(); tip.Cmp(tipCap) > 0 {
+		tip.Set(tipCap)
+	}
+
+	return tip
+}
+
+// EffectiveGasPrice returns the price per gas the sender pays under the given base fee
+func (t *Transaction) EffectiveGasPrice(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return new(big.Int).Set(t.GetGasFeeCap())
+	}
+
+	return new(big.Int).Add(baseFee, t.EffectiveGasTip(baseFee))
+}
+
+// Cost returns gas * gasFeeCap + value
 func (t *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(t.GasPrice, new(big.Int).SetUint64(t.Gas))
+	total := new(big.Int).Mul(t.GetGasFeeCap(), new(big.Int).SetUint64(t.Gas))
 	total.Add(total, t.Value)
 
 	return total
@@ -135,12 +242,41 @@ func (t *Transaction) ExceedsBlockGasLimit(blockGasLimit uint64) bool {
 }
 
 func (t *Transaction) IsUnderpriced(priceLimit uint64) bool {
-	return t.GasPrice.Cmp(big.NewInt(0).SetUint64(priceLimit)) < 0
+	return t.GetGasTipCap().Cmp(big.NewInt(0).SetUint64(priceLimit)) < 0
+}
+
+// TxWithMinerFee wraps a transaction with its effective tip under a base fee
+type TxWithMinerFee struct {
+	tx       *Transaction
+	minerFee *big.Int
+}
+
+// NewTxWithMinerFee creates a wrapped transaction, calculating the effective
+// miner gas tip if a base fee is provided.
+// Returns error in case of a negative effective miner gas tip.
+func NewTxWithMinerFee(tx *Transaction, baseFee *big.Int) (*TxWithMinerFee, error) {
+	// the miner gets the whole tip cap without a base fee
+	if baseFee == nil {
+		return &TxWithMinerFee{
+			tx:       tx,
+			minerFee: tx.GetGasTipCap(),
+		}, nil
+	}
+
+	minerFee := tx.EffectiveGasTip(baseFee)
+	if minerFee.Sign() < 0 {
+		return nil, ErrGasFeeCapTooLow
+	}
+
+	return &TxWithMinerFee{
+		tx:       tx,
+		minerFee: minerFee,
+	}, nil
 }
 
 // TxByPriceAndTime implements both the sort and the heap interface, making it useful
 // for all at once sorting as well as individually adding and removing elements.
-type TxByPriceAndTime []*Transaction
+type TxByPriceAndTime []*TxWithMinerFee
 
 func (s TxByPriceAndTime) Len() int {
 	return len(s)
@@ -148,9 +284,9 @@ func (s TxByPriceAndTime) Len() int {
 
 func (s TxByPriceAndTime) Less(i, j int) bool {
 	// If the prices are equal, use the time the transaction was first seen for deterministic sorting
-	cmp := s[i].GasPrice.Cmp(s[j].GasPrice)
+	cmp := s[i].minerFee.Cmp(s[j].minerFee)
 	if cmp == 0 {
-		return s[i].ReceivedTime.Before(s[j].ReceivedTime)
+		return s[i].tx.ReceivedTime.Before(s[j].tx.ReceivedTime)
 	}
 
 	return cmp > 0
@@ -161,7 +297,7 @@ func (s TxByPriceAndTime) Swap(i, j int) {
 }
 
 func (s *TxByPriceAndTime) Push(x interface{}) {
-	if v, ok := x.(*Transaction); ok {
+	if v, ok := x.(*TxWithMinerFee); ok {
 		*s = append(*s, v)
 	}
 }
@@ -188,21 +324,34 @@ func (s PoolTxByNonce) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 // transactions in a profit-maximizing sorted order, while supporting removing
 // entire batches of transactions for non-executable accounts.
 type TransactionsByPriceAndNonce struct {
-	txs   map[Address][]*Transaction // Per account nonce-sorted list of transactions
-	heads TxByPriceAndTime           // Next transaction for each unique account (price heap)
+	txs     map[Address][]*Transaction // Per account nonce-sorted list of transactions
+	heads   TxByPriceAndTime           // Next transaction for each unique account (price heap)
+	baseFee *big.Int                   // Current base fee
 }
 
 // NewTransactionsByPriceAndNonce creates a transaction set that can retrieve
-// price sorted transactions in a nonce-honouring way.
+// price sorted transactions in a nonce-honouring way. The transactions are sorted
+// by their effective tips under the base fee, which is nil before the london hardfork.
 //
 // Note, the input map is reowned so the caller should not interact any more with
 // if after providing it to the constructor.
-func NewTransactionsByPriceAndNonce(txs map[Address][]*Transaction) *TransactionsByPriceAndNonce {
+func NewTransactionsByPriceAndNonce(
+	txs map[Address][]*Transaction,
+	baseFee *big.Int,
+) *TransactionsByPriceAndNonce {
 	// Initialize a price and received time based heap with the head transactions
 	heads := make(TxByPriceAndTime, 0, len(txs))
 
 	for from, accTxs := range txs {
-		heads = append(heads, accTxs[0])
+		wrapped, err := NewTxWithMinerFee(accTxs[0], baseFee)
+		// Remove the account if its head transaction could not pay the base fee
+		if err != nil {
+			delete(txs, from)
+
+			continue
+		}
+
+		heads = append(heads, wrapped)
 		txs[from] = accTxs[1:]
 	}
 
@@ -210,8 +359,9 @@ func NewTransactionsByPriceAndNonce(txs map[Address][]*Transaction) *Transaction
 
 	// Assemble and return the transaction set
 	return &TransactionsByPriceAndNonce{
-		txs:   txs,
-		heads: heads,
+		txs:     txs,
+		heads:   heads,
+		baseFee: baseFee,
 	}
 }
 
@@ -221,17 +371,19 @@ func (t *TransactionsByPriceAndNonce) Peek() *Transaction {
 		return nil
 	}
 
-	return t.heads[0]
+	return t.heads[0].tx
 }
 
 // Shift replaces the current best head with the next one from the same account.
 func (t *TransactionsByPriceAndNonce) Shift() {
-	account := t.heads[0].From
+	account := t.heads[0].tx.From
 	if txs, ok := t.txs[account]; ok && len(txs) > 0 {
-		t.heads[0], t.txs[account] = txs[0], txs[1:]
-		heap.Fix(&t.heads, 0)
+		if wrapped, err := NewTxWithMinerFee(txs[0], t.baseFee); err == nil {
+			t.heads[0], t.txs[account] = wrapped, txs[1:]
+			heap.Fix(&t.heads, 0)
 
-		return
+			return
+		}
 	}
 
 	heap.Pop(&t.heads)
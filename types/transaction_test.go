@@ -56,7 +56,7 @@ func TestTransactionTimeSort(t *testing.T) {
 		groups[addr] = append(groups[addr], tx)
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := NewTransactionsByPriceAndNonce(groups)
+	txset := NewTransactionsByPriceAndNonce(groups, nil)
 
 	txs := []*Transaction{}
 
@@ -89,3 +89,51 @@ func TestTransactionTimeSort(t *testing.T) {
 		}
 	}
 }
+
+func TestTransactionBaseFeeSort(t *testing.T) {
+	var (
+		addr1   = StringToAddress("0x1")
+		addr2   = StringToAddress("0x2")
+		addr3   = StringToAddress("0x3")
+		baseFee = big.NewInt(10)
+	)
+
+	groups := map[Address][]*Transaction{
+		// legacy transaction, tip is 20 - 10 = 10
+		addr1: {{
+			From:     addr1,
+			GasPrice: big.NewInt(20),
+		}},
+		// dynamic fee transaction, tip is min(15, 40 - 10) = 15
+		addr2: {{
+			Type:      DynamicFeeTx,
+			From:      addr2,
+			GasPrice:  big.NewInt(40),
+			GasFeeCap: big.NewInt(40),
+			GasTipCap: big.NewInt(15),
+		}},
+		// could not afford the base fee
+		addr3: {{
+			From:     addr3,
+			GasPrice: big.NewInt(9),
+		}},
+	}
+
+	txset := NewTransactionsByPriceAndNonce(groups, baseFee)
+
+	txs := []*Transaction{}
+
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		txs = append(txs, tx)
+
+		txset.Shift()
+	}
+
+	if len(txs) != 2 {
+		t.Fatalf("expected %d transactions, found %d", 2, len(txs))
+	}
+
+	if txs[0].From != addr2 || txs[1].From != addr1 {
+		t.Errorf("invalid tip ordering: %s, %s", txs[0].From, txs[1].From)
+	}
+}